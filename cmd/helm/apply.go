@@ -0,0 +1,277 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/renderutil"
+)
+
+const applyDesc = `
+This command installs or upgrades a set of releases described by a file.
+
+The file lists the releases of the set, each with its chart, values and
+namespace, and the releases of the set it depends on:
+
+	releases:
+	- name: database
+	  namespace: data
+	  chart: stable/postgresql
+	  version: 3.1.0
+	  values:
+	  - values/database.yaml
+	- name: web
+	  namespace: apps
+	  chart: ./charts/web
+	  set:
+	  - image.tag=1.4.2
+	  dependsOn:
+	  - database
+
+Charts are looked up like in 'helm install': 'chart' is a chart reference, a
+path to a packaged chart or a path to an unpacked chart directory. Chart and
+values file paths are relative to the directory of the file. Releases without
+a namespace are installed in the current kube config namespace.
+
+Tiller installs the releases which are not deployed and upgrades the others.
+A release is applied once the releases it depends on are, the releases not
+depending on each other in parallel. Use '--parallelism' to limit the number
+of releases applied at once.
+
+Once a release fails, the releases not started yet are skipped. With
+'--atomic', the releases of the set already applied are rolled back as well:
+the releases installed are deleted, and the releases upgraded are rolled back
+to the revision they were upgraded from.
+`
+
+// releaseSetFile is the file describing a release set.
+type releaseSetFile struct {
+	Releases []releaseSetEntry `json:"releases"`
+}
+
+// releaseSetEntry describes a release of a release set.
+type releaseSetEntry struct {
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Chart     string   `json:"chart"`
+	Version   string   `json:"version,omitempty"`
+	Repo      string   `json:"repo,omitempty"`
+	Values    []string `json:"values,omitempty"`
+	Set       []string `json:"set,omitempty"`
+	SetString []string `json:"setString,omitempty"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+type applyCmd struct {
+	file        string
+	dryRun      bool
+	atomic      bool
+	timeout     int64
+	wait        bool
+	parallelism int32
+	verify      bool
+	keyring     string
+	out         io.Writer
+	client      helm.Interface
+}
+
+func newApplyCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	apply := &applyCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "apply [flags] FILE",
+		Short:   "Install or upgrade a set of releases described by a file",
+		Long:    applyDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release set file"); err != nil {
+				return err
+			}
+
+			apply.file = args[0]
+			apply.client = ensureHelmClient(apply.client)
+			return apply.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.BoolVar(&apply.dryRun, "dry-run", false, "Simulate applying the release set")
+	f.BoolVar(&apply.atomic, "atomic", false, "If set, the releases of the set already applied are rolled back when a release fails")
+	f.Int64Var(&apply.timeout, "timeout", 300, "Time in seconds to wait for any individual Kubernetes operation (like Jobs for hooks)")
+	f.BoolVar(&apply.wait, "wait", false, "If set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment of each release are in a ready state before marking it as successful. It will wait for as long as --timeout")
+	f.Int32Var(&apply.parallelism, "parallelism", 0, "Maximum number of releases applied at once, or 0 for no limit")
+	f.BoolVar(&apply.verify, "verify", false, "Verify the packages before using them")
+	f.StringVar(&apply.keyring, "keyring", defaultKeyring(), "Location of public keys used for verification")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (a *applyCmd) run() error {
+	set, err := loadReleaseSetFile(a.file)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(a.file)
+	members := make([]*services.ReleaseSetMember, 0, len(set.Releases))
+	for _, e := range set.Releases {
+		m, err := a.member(e, dir)
+		if err != nil {
+			return fmt.Errorf("release %q: %s", e.Name, err)
+		}
+		members = append(members, m)
+	}
+
+	res, err := a.client.ApplyReleaseSet(
+		members,
+		helm.ReleaseSetDryRun(a.dryRun),
+		helm.ReleaseSetAtomic(a.atomic),
+		helm.ReleaseSetTimeout(a.timeout),
+		helm.ReleaseSetWait(a.wait),
+		helm.ReleaseSetParallelism(a.parallelism))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	fmt.Fprintln(a.out, formatReleaseSet(res))
+	failed := 0
+	for _, r := range res.Results {
+		if r.Status != services.ReleaseSetResult_APPLIED {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("release set failed: %d of %d releases were not applied", failed, len(res.Results))
+	}
+	return nil
+}
+
+// member returns the release of the set described by e, whose relative paths
+// are relative to dir.
+func (a *applyCmd) member(e releaseSetEntry, dir string) (*services.ReleaseSetMember, error) {
+	if e.Name == "" {
+		return nil, fmt.Errorf("no name in %s", a.file)
+	}
+	if e.Chart == "" {
+		return nil, fmt.Errorf("no chart in %s", a.file)
+	}
+
+	// charts found relative to the file are local, others are references
+	ref := e.Chart
+	if !filepath.IsAbs(ref) {
+		if _, err := os.Stat(filepath.Join(dir, ref)); err == nil {
+			ref = filepath.Join(dir, ref)
+		}
+	}
+	chartPath, err := locateChartPath(e.Repo, "", "", ref, e.Version, a.verify, a.keyring, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	ch, err := chartutil.Load(chartPath)
+	if err != nil {
+		return nil, prettyError(err)
+	}
+	if req, err := chartutil.LoadRequirements(ch); err == nil {
+		if err := renderutil.CheckDependencies(ch, req); err != nil {
+			return nil, err
+		}
+	} else if err != chartutil.ErrRequirementsNotFound {
+		return nil, fmt.Errorf("cannot load requirements: %v", err)
+	}
+
+	var files valueFiles
+	for _, v := range e.Values {
+		if !filepath.IsAbs(v) && !strings.Contains(v, "://") {
+			v = filepath.Join(dir, v)
+		}
+		files = append(files, v)
+	}
+	rawVals, err := vals(files, e.Set, e.SetString, nil, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := e.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace()
+	}
+	return &services.ReleaseSetMember{
+		Name:      e.Name,
+		Namespace: namespace,
+		Chart:     ch,
+		Values:    &chart.Config{Raw: string(rawVals)},
+		DependsOn: e.DependsOn,
+	}, nil
+}
+
+// loadReleaseSetFile reads the release set file filename.
+func loadReleaseSetFile(filename string) (*releaseSetFile, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	set := &releaseSetFile{}
+	if err := yaml.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", filename, err)
+	}
+	if len(set.Releases) == 0 {
+		return nil, fmt.Errorf("no release in %s", filename)
+	}
+	return set, nil
+}
+
+func formatReleaseSet(res *services.ApplyReleaseSetResponse) string {
+	tbl := uitable.New()
+	tbl.MaxColWidth = 60
+	tbl.AddRow("NAME", "NAMESPACE", "ACTION", "REVISION", "STATUS", "ERROR")
+	for _, r := range res.Results {
+		action := "upgrade"
+		switch {
+		case r.Status == services.ReleaseSetResult_SKIPPED:
+			action = ""
+		case r.Installed:
+			action = "install"
+		}
+		revision := ""
+		if r.Release != nil {
+			revision = fmt.Sprint(r.Release.Version)
+		}
+		tbl.AddRow(r.Name, r.GetRelease().GetNamespace(), action, revision, r.Status, r.Error)
+	}
+	return tbl.String()
+}
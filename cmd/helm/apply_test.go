@@ -0,0 +1,69 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestApplyCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "apply a release set",
+			args:     []string{"testdata/releaseset.yaml"},
+			expected: `database\s+data\s+install\s+1\s+APPLIED`,
+		},
+		{
+			name:     "apply a release set upgrading a release",
+			args:     []string{"testdata/releaseset.yaml"},
+			expected: `web\s+\S+\s+upgrade\s+2\s+APPLIED`,
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "web"})},
+		},
+		{
+			name:     "apply a release set with dry run",
+			args:     []string{"testdata/releaseset.yaml"},
+			flags:    []string{"--dry-run", "--parallelism", "2"},
+			expected: `NAME\s+NAMESPACE\s+ACTION\s+REVISION\s+STATUS`,
+		},
+		{
+			name: "apply a file without releases",
+			args: []string{"testdata/testcharts/alpine/values.yaml"},
+			err:  true,
+		},
+		{
+			name: "apply a missing file",
+			args: []string{"testdata/nope.yaml"},
+			err:  true,
+		},
+		{
+			name: "apply without file",
+			err:  true,
+		},
+	}
+
+	cmd := func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newApplyCmd(c, out)
+	}
+
+	runReleaseCases(t, tests, cmd)
+}
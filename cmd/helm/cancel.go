@@ -0,0 +1,88 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const cancelDesc = `
+This command cancels the install, upgrade or rollback in progress on a release.
+
+An operation run with '--wait', or waiting for its hooks, holds the release in
+a pending state until it completes or its timeout runs out. Cancelling it
+interrupts the waits, and marks the revision it created as FAILED.
+
+With '--cleanup', the new resources created by the cancelled operation are
+deleted, as they are for a failed upgrade or rollback run with
+'--cleanup-on-fail'.
+`
+
+type cancelCmd struct {
+	name    string
+	cleanup bool
+	out     io.Writer
+	client  helm.Interface
+}
+
+func newCancelCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	cancel := &cancelCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "cancel [flags] RELEASE",
+		Short:   "Cancel the operation in progress on a release",
+		Long:    cancelDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+
+			cancel.name = args[0]
+			cancel.client = ensureHelmClient(cancel.client)
+			return cancel.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.BoolVar(&cancel.cleanup, "cleanup", false, "Delete the new resources created by the cancelled operation")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (c *cancelCmd) run() error {
+	res, err := c.client.CancelOperation(c.name, helm.CancelCleanup(c.cleanup))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	rel := res.GetRelease()
+	fmt.Fprintf(c.out, "Release %q revision %d: %s\n", rel.GetName(), rel.GetVersion(), rel.GetInfo().GetDescription())
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestCancelCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "cancel an upgrade",
+			args:     []string{"funny-honey"},
+			expected: `Release "funny-honey" revision 2: Upgrade cancelled`,
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey", Version: 2, StatusCode: release.Status_PENDING_UPGRADE})},
+		},
+		{
+			name:     "cancel an install with cleanup",
+			args:     []string{"funny-honey"},
+			flags:    []string{"--cleanup"},
+			expected: `Release "funny-honey" revision 1: Install cancelled`,
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey", StatusCode: release.Status_PENDING_INSTALL})},
+		},
+		{
+			name: "cancel a release without operation in progress",
+			args: []string{"funny-honey"},
+			rels: []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey"})},
+			err:  true,
+		},
+		{
+			name: "cancel a missing release",
+			args: []string{"zany-honey"},
+			err:  true,
+		},
+		{
+			name: "cancel without a release name",
+			err:  true,
+		},
+	}
+
+	cmd := func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newCancelCmd(c, out)
+	}
+
+	runReleaseCases(t, tests, cmd)
+}
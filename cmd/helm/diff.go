@@ -0,0 +1,134 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+const diffHelp = `
+This command consists of multiple subcommands to preview the changes an
+operation would make to a release, resource by resource, without making them.
+`
+
+func newDiffCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [command]",
+		Short: "Preview the changes of an operation on a release",
+		Long:  diffHelp,
+	}
+
+	cmd.AddCommand(newDiffUpgradeCmd(client, out))
+
+	return cmd
+}
+
+// resourceDiff is the printed form of a services.ResourceDiff, naming the
+// changes rather than numbering them.
+type resourceDiff struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Change     string `json:"change"`
+	Diff       string `json:"diff,omitempty"`
+	LiveChange string `json:"liveChange,omitempty"`
+	LiveDiff   string `json:"liveDiff,omitempty"`
+}
+
+// releaseDiff is the printed form of a services.DiffReleaseResponse.
+type releaseDiff struct {
+	Release   string         `json:"release"`
+	Revision  int32          `json:"revision"`
+	Resources []resourceDiff `json:"resources"`
+}
+
+// printDiff prints the resource diffs of res in the format outfmt, one of
+// json, yaml or, if empty, a human readable format. Live changes are printed
+// only if live is set.
+func printDiff(out io.Writer, res *services.DiffReleaseResponse, live bool, outfmt string) error {
+	rd := releaseDiff{
+		Release:   res.GetRelease().GetName(),
+		Revision:  res.GetRelease().GetVersion(),
+		Resources: make([]resourceDiff, 0, len(res.Resources)),
+	}
+	for _, r := range res.Resources {
+		d := resourceDiff{
+			Kind:      r.Kind,
+			Name:      r.Name,
+			Namespace: r.Namespace,
+			Change:    r.Change.String(),
+			Diff:      r.Diff,
+		}
+		if live {
+			d.LiveChange = r.LiveChange.String()
+			d.LiveDiff = r.LiveDiff
+		}
+		rd.Resources = append(rd.Resources, d)
+	}
+
+	switch outfmt {
+	case "":
+		printResourceDiffs(out, rd)
+		return nil
+	case "json":
+		data, err := json.Marshal(rd)
+		if err != nil {
+			return fmt.Errorf("Failed to Marshal JSON output: %s", err)
+		}
+		out.Write(data)
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(rd)
+		if err != nil {
+			return fmt.Errorf("Failed to Marshal YAML output: %s", err)
+		}
+		out.Write(data)
+		return nil
+	}
+
+	return fmt.Errorf("Unknown output format %q", outfmt)
+}
+
+func printResourceDiffs(out io.Writer, rd releaseDiff) {
+	unchanged := services.ResourceDiff_UNCHANGED.String()
+	counts := map[string]int{}
+	for _, d := range rd.Resources {
+		counts[d.Change]++
+		if d.Change != unchanged {
+			fmt.Fprintf(out, "%s %s %s/%s\n", d.Change, d.Kind, d.Namespace, d.Name)
+			fmt.Fprint(out, d.Diff)
+		}
+		if d.LiveChange != "" && d.LiveChange != unchanged {
+			fmt.Fprintf(out, "LIVE %s %s %s/%s\n", d.LiveChange, d.Kind, d.Namespace, d.Name)
+			fmt.Fprint(out, d.LiveDiff)
+		}
+	}
+	fmt.Fprintf(out, "Release %q revision %d: %d added, %d modified, %d removed, %d unchanged\n",
+		rd.Release, rd.Revision,
+		counts[services.ResourceDiff_ADDED.String()],
+		counts[services.ResourceDiff_MODIFIED.String()],
+		counts[services.ResourceDiff_REMOVED.String()],
+		counts[unchanged])
+}
@@ -0,0 +1,171 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/renderutil"
+)
+
+const diffUpgradeDesc = `
+This command shows the changes 'helm upgrade' would make to a release, without
+upgrading it.
+
+Tiller renders the chart with the values exactly as it does for an upgrade and
+compares every resource of the proposed manifest with the manifest of the
+deployed release. Resources are matched by kind, namespace and name, and each
+change is shown as a unified diff. Hooks are not compared.
+
+The arguments and the chart value flags are the ones of 'helm upgrade'.
+
+With '--live', the proposed resources are also compared with their state in
+the cluster, which reveals changes made outside of Helm. Only the fields set
+by the chart are compared, so that fields defaulted or maintained by
+Kubernetes are not reported.
+
+Use '--output json' or '--output yaml' to get the diff in a format fit for
+change review tools.
+`
+
+type diffUpgradeCmd struct {
+	release      string
+	chart        string
+	out          io.Writer
+	client       helm.Interface
+	valueFiles   valueFiles
+	values       []string
+	stringValues []string
+	fileValues   []string
+	verify       bool
+	keyring      string
+	version      string
+	resetValues  bool
+	reuseValues  bool
+	live         bool
+	context      int32
+	outfmt       string
+	repoURL      string
+	username     string
+	password     string
+	devel        bool
+
+	certFile string
+	keyFile  string
+	caFile   string
+}
+
+func newDiffUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	diff := &diffUpgradeCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "upgrade [RELEASE] [CHART]",
+		Short:   "Show the changes an upgrade would make to a release",
+		Long:    diffUpgradeDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name", "chart path"); err != nil {
+				return err
+			}
+
+			if diff.version == "" && diff.devel {
+				debug("setting version to >0.0.0-0")
+				diff.version = ">0.0.0-0"
+			}
+
+			diff.release = args[0]
+			diff.chart = args[1]
+			diff.client = ensureHelmClient(diff.client)
+
+			return diff.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.VarP(&diff.valueFiles, "values", "f", "Specify values in a YAML file or a URL(can specify multiple)")
+	f.StringArrayVar(&diff.values, "set", []string{}, "Set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&diff.stringValues, "set-string", []string{}, "Set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&diff.fileValues, "set-file", []string{}, "Set values from respective files specified via the command line (can specify multiple or separate values with commas: key1=path1,key2=path2)")
+	f.BoolVar(&diff.verify, "verify", false, "Verify the provenance of the chart before diffing")
+	f.StringVar(&diff.keyring, "keyring", defaultKeyring(), "Path to the keyring that contains public signing keys")
+	f.StringVar(&diff.version, "version", "", "Specify the exact chart version to use. If this is not specified, the latest version is used")
+	f.BoolVar(&diff.resetValues, "reset-values", false, "When upgrading, reset the values to the ones built into the chart")
+	f.BoolVar(&diff.reuseValues, "reuse-values", false, "When upgrading, reuse the last release's values and merge in any overrides from the command line via --set and -f. If '--reset-values' is specified, this is ignored.")
+	f.BoolVar(&diff.live, "live", false, "Also compare the proposed resources with their state in the cluster")
+	f.Int32Var(&diff.context, "context", 3, "Number of unchanged lines shown around each change")
+	f.StringVarP(&diff.outfmt, "output", "o", "", "Output the diff in the specified format (json or yaml)")
+	f.StringVar(&diff.repoURL, "repo", "", "Chart repository url where to locate the requested chart")
+	f.StringVar(&diff.username, "username", "", "Chart repository username where to locate the requested chart")
+	f.StringVar(&diff.password, "password", "", "Chart repository password where to locate the requested chart")
+	f.StringVar(&diff.certFile, "cert-file", "", "Identify HTTPS client using this SSL certificate file")
+	f.StringVar(&diff.keyFile, "key-file", "", "Identify HTTPS client using this SSL key file")
+	f.StringVar(&diff.caFile, "ca-file", "", "Verify certificates of HTTPS-enabled servers using this CA bundle")
+	f.BoolVar(&diff.devel, "devel", false, "Use development versions, too. Equivalent to version '>0.0.0-0'. If --version is set, this is ignored.")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (d *diffUpgradeCmd) run() error {
+	chartPath, err := locateChartPath(d.repoURL, d.username, d.password, d.chart, d.version, d.verify, d.keyring, d.certFile, d.keyFile, d.caFile)
+	if err != nil {
+		return err
+	}
+
+	rawVals, err := vals(d.valueFiles, d.values, d.stringValues, d.fileValues, d.certFile, d.keyFile, d.caFile)
+	if err != nil {
+		return err
+	}
+
+	// Check chart requirements to make sure all dependencies are present in /charts
+	ch, err := chartutil.Load(chartPath)
+	if err != nil {
+		return prettyError(err)
+	}
+	if req, err := chartutil.LoadRequirements(ch); err == nil {
+		if err := renderutil.CheckDependencies(ch, req); err != nil {
+			return err
+		}
+	} else if err != chartutil.ErrRequirementsNotFound {
+		return fmt.Errorf("cannot load requirements: %v", err)
+	}
+
+	res, err := d.client.DiffRelease(
+		d.release,
+		ch,
+		helm.DiffValueOverrides(rawVals),
+		helm.DiffResetValues(d.resetValues),
+		helm.DiffReuseValues(d.reuseValues),
+		helm.DiffLive(d.live),
+		helm.DiffContext(d.context))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	return printDiff(d.out, res, d.live, d.outfmt)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestDiffUpgradeCmd(t *testing.T) {
+	diffs := []*services.ResourceDiff{
+		{
+			Kind:      "ConfigMap",
+			Name:      "cm",
+			Namespace: "default",
+			Change:    services.ResourceDiff_MODIFIED,
+			Diff:      "--- deployed\n+++ proposed\n@@ -1 +1 @@\n-a: one\n+a: two\n",
+		},
+		{
+			Kind:       "Service",
+			Name:       "svc",
+			Namespace:  "default",
+			Change:     services.ResourceDiff_UNCHANGED,
+			LiveChange: services.ResourceDiff_MODIFIED,
+			LiveDiff:   "--- live\n+++ proposed\n@@ -1 +1 @@\n-port: 81\n+port: 80\n",
+		},
+		{
+			Kind:      "Secret",
+			Name:      "old",
+			Namespace: "default",
+			Change:    services.ResourceDiff_REMOVED,
+			Diff:      "--- deployed\n+++ proposed\n@@ -1 +0,0 @@\n-kind: Secret\n",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		flags    []string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "diff an upgrade",
+			args:     []string{"funny-bunny", "testdata/testcharts/alpine"},
+			expected: `^MODIFIED ConfigMap default/cm\n--- deployed\n(.*\n)*-a: one\n\+a: two\nREMOVED Secret default/old\n(.*\n)*Release "funny-bunny" revision 2: 0 added, 1 modified, 1 removed, 1 unchanged\n$`,
+		},
+		{
+			name:     "diff an upgrade against the cluster",
+			args:     []string{"funny-bunny", "testdata/testcharts/alpine"},
+			flags:    []string{"--live"},
+			expected: `MODIFIED ConfigMap default/cm\n(.*\n)*\+a: two\nLIVE MODIFIED Service default/svc\n--- live\n(.*\n)*-port: 81\n\+port: 80\n`,
+		},
+		{
+			name:     "diff an upgrade as json",
+			args:     []string{"funny-bunny", "testdata/testcharts/alpine"},
+			flags:    []string{"--output", "json"},
+			expected: `^\{"release":"funny-bunny","revision":2,"resources":\[\{"kind":"ConfigMap","name":"cm","namespace":"default","change":"MODIFIED",`,
+		},
+		{
+			name:     "diff an upgrade as yaml",
+			args:     []string{"funny-bunny", "testdata/testcharts/alpine"},
+			flags:    []string{"--output", "yaml"},
+			expected: `- change: REMOVED\n(.*\n)*  kind: Secret\n`,
+		},
+		{
+			name:  "diff an upgrade in an unknown format",
+			args:  []string{"funny-bunny", "testdata/testcharts/alpine"},
+			flags: []string{"--output", "xml"},
+			err:   true,
+		},
+		{
+			name: "diff an upgrade of a missing release",
+			args: []string{"zany-bunny", "testdata/testcharts/alpine"},
+			err:  true,
+		},
+		{
+			name: "diff an upgrade without a chart",
+			args: []string{"funny-bunny"},
+			err:  true,
+		},
+	}
+
+	var buf bytes.Buffer
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &helm.FakeClient{
+				Rels:  []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-bunny"})},
+				Diffs: diffs,
+			}
+			cmd := newDiffUpgradeCmd(c, &buf)
+			cmd.ParseFlags(tt.flags)
+			err := cmd.RunE(cmd, tt.args)
+			if (err != nil) != tt.err {
+				t.Errorf("expected error, got '%v'", err)
+			}
+			re := regexp.MustCompile(tt.expected)
+			if !re.Match(buf.Bytes()) {
+				t.Errorf("expected\n%q\ngot\n%q", tt.expected, buf.String())
+			}
+			buf.Reset()
+		})
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+const driftDesc = `
+This command reports the changes made to the resources of a release outside of
+Helm, e.g. with 'kubectl edit', since it was deployed.
+
+Tiller compares the manifest of the deployed release with the state of each of
+its resources in the cluster, field by field. Only the fields set by the
+manifest are compared, so that fields defaulted or maintained by Kubernetes,
+like the status of a resource, are not reported. Hooks are not compared.
+
+Each drifted field is shown with its value in the manifest and in the cluster,
+as JSON. '<none>' stands for a field that is not set.
+
+With '--reconcile', the manifest is applied again to the resources that
+drifted: the drifted fields are set back and the missing resources are
+created. The release must not have an operation in progress.
+
+Use '--output json' or '--output yaml' to get the report in a format fit for
+audit tools.
+`
+
+type driftCmd struct {
+	release   string
+	reconcile bool
+	outfmt    string
+	out       io.Writer
+	client    helm.Interface
+}
+
+func newDriftCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	drift := &driftCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "drift [flags] RELEASE",
+		Short:   "Show the changes made to the resources of a release outside of Helm",
+		Long:    driftDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+
+			drift.release = args[0]
+			drift.client = ensureHelmClient(drift.client)
+			return drift.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.BoolVar(&drift.reconcile, "reconcile", false, "Apply the deployed manifest again to the resources that drifted")
+	f.StringVarP(&drift.outfmt, "output", "o", "", "Output the report in the specified format (json or yaml)")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (d *driftCmd) run() error {
+	if d.outfmt != "" && d.outfmt != "json" && d.outfmt != "yaml" {
+		return fmt.Errorf("Unknown output format %q", d.outfmt)
+	}
+
+	res, err := d.client.CheckDrift(d.release, helm.DriftReconcile(d.reconcile))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	return printDrift(d.out, res, d.outfmt)
+}
+
+// fieldDrift is the printed form of a services.FieldDrift.
+type fieldDrift struct {
+	Path     string `json:"path"`
+	Expected string `json:"expected,omitempty"`
+	Live     string `json:"live,omitempty"`
+}
+
+// resourceDrift is the printed form of a services.ResourceDrift.
+type resourceDrift struct {
+	Kind      string       `json:"kind"`
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace"`
+	Missing   bool         `json:"missing,omitempty"`
+	Fields    []fieldDrift `json:"fields,omitempty"`
+}
+
+// releaseDrift is the printed form of a services.CheckDriftResponse.
+type releaseDrift struct {
+	Release    string          `json:"release"`
+	Revision   int32           `json:"revision"`
+	Checked    int32           `json:"checked"`
+	Reconciled bool            `json:"reconciled"`
+	Resources  []resourceDrift `json:"resources"`
+}
+
+// printDrift prints the resource drifts of res in the format outfmt, one of
+// json, yaml or, if empty, a human readable format.
+func printDrift(out io.Writer, res *services.CheckDriftResponse, outfmt string) error {
+	rd := releaseDrift{
+		Release:    res.GetRelease().GetName(),
+		Revision:   res.GetRelease().GetVersion(),
+		Checked:    res.Checked,
+		Reconciled: res.Reconciled,
+		Resources:  make([]resourceDrift, 0, len(res.Resources)),
+	}
+	for _, r := range res.Resources {
+		d := resourceDrift{
+			Kind:      r.Kind,
+			Name:      r.Name,
+			Namespace: r.Namespace,
+			Missing:   r.Missing,
+		}
+		for _, f := range r.Fields {
+			d.Fields = append(d.Fields, fieldDrift{Path: f.Path, Expected: f.Expected, Live: f.Live})
+		}
+		rd.Resources = append(rd.Resources, d)
+	}
+
+	switch outfmt {
+	case "":
+		printResourceDrifts(out, rd)
+		return nil
+	case "json":
+		data, err := json.Marshal(rd)
+		if err != nil {
+			return fmt.Errorf("Failed to Marshal JSON output: %s", err)
+		}
+		out.Write(data)
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(rd)
+		if err != nil {
+			return fmt.Errorf("Failed to Marshal YAML output: %s", err)
+		}
+		out.Write(data)
+		return nil
+	}
+
+	return fmt.Errorf("Unknown output format %q", outfmt)
+}
+
+func printResourceDrifts(out io.Writer, rd releaseDrift) {
+	for _, d := range rd.Resources {
+		if d.Missing {
+			fmt.Fprintf(out, "MISSING %s %s/%s\n", d.Kind, d.Namespace, d.Name)
+			continue
+		}
+		fmt.Fprintf(out, "DRIFTED %s %s/%s\n", d.Kind, d.Namespace, d.Name)
+		for _, f := range d.Fields {
+			fmt.Fprintf(out, "  %s: %s -> %s\n", f.Path, fieldValue(f.Expected), fieldValue(f.Live))
+		}
+	}
+	fmt.Fprintf(out, "Release %q revision %d: %d of %d resources drifted\n", rd.Release, rd.Revision, len(rd.Resources), rd.Checked)
+	if rd.Reconciled {
+		fmt.Fprintf(out, "Reconciled %d resources with the deployed manifest\n", len(rd.Resources))
+	}
+}
+
+// fieldValue returns the printed form of a JSON encoded field value.
+func fieldValue(v string) string {
+	if v == "" {
+		return "<none>"
+	}
+	return v
+}
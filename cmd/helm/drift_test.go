@@ -0,0 +1,125 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestDriftCmd(t *testing.T) {
+	drifts := []*services.ResourceDrift{
+		{
+			Kind:      "Deployment",
+			Name:      "web",
+			Namespace: "default",
+			Fields: []*services.FieldDrift{
+				{Path: "spec.replicas", Expected: "3", Live: "5"},
+				{Path: "spec.template.spec.containers[1]", Live: `{"name":"debug"}`},
+			},
+		},
+		{
+			Kind:      "Service",
+			Name:      "web",
+			Namespace: "default",
+			Missing:   true,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		flags    []string
+		drifts   []*services.ResourceDrift
+		expected string
+		err      bool
+	}{
+		{
+			name:     "check drift",
+			args:     []string{"funny-bunny"},
+			drifts:   drifts,
+			expected: `^DRIFTED Deployment default/web\n  spec.replicas: 3 -> 5\n  spec.template.spec.containers\[1\]: <none> -> \{"name":"debug"\}\nMISSING Service default/web\nRelease "funny-bunny" revision 1: 2 of 2 resources drifted\n$`,
+		},
+		{
+			name:     "check drift without changes",
+			args:     []string{"funny-bunny"},
+			expected: `^Release "funny-bunny" revision 1: 0 of 0 resources drifted\n$`,
+		},
+		{
+			name:     "reconcile drift",
+			args:     []string{"funny-bunny"},
+			flags:    []string{"--reconcile"},
+			drifts:   drifts,
+			expected: `resources drifted\nReconciled 2 resources with the deployed manifest\n$`,
+		},
+		{
+			name:     "check drift as json",
+			args:     []string{"funny-bunny"},
+			flags:    []string{"--output", "json"},
+			drifts:   drifts,
+			expected: `^\{"release":"funny-bunny","revision":1,"checked":2,"reconciled":false,"resources":\[\{"kind":"Deployment","name":"web","namespace":"default","fields":\[\{"path":"spec.replicas","expected":"3","live":"5"\}`,
+		},
+		{
+			name:     "check drift as yaml",
+			args:     []string{"funny-bunny"},
+			flags:    []string{"--output", "yaml"},
+			drifts:   drifts,
+			expected: `- kind: Service\n  missing: true\n`,
+		},
+		{
+			name:  "check drift in an unknown format",
+			args:  []string{"funny-bunny"},
+			flags: []string{"--output", "xml"},
+			err:   true,
+		},
+		{
+			name: "check drift of a missing release",
+			args: []string{"zany-bunny"},
+			err:  true,
+		},
+		{
+			name: "check drift without a release name",
+			err:  true,
+		},
+	}
+
+	var buf bytes.Buffer
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &helm.FakeClient{
+				Rels:   []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-bunny"})},
+				Drifts: tt.drifts,
+			}
+			cmd := newDriftCmd(c, &buf)
+			cmd.ParseFlags(tt.flags)
+			err := cmd.RunE(cmd, tt.args)
+			if (err != nil) != tt.err {
+				t.Errorf("expected error, got '%v'", err)
+			}
+			re := regexp.MustCompile(tt.expected)
+			if !re.Match(buf.Bytes()) {
+				t.Errorf("expected\n%q\ngot\n%q", tt.expected, buf.String())
+			}
+			buf.Reset()
+		})
+	}
+}
@@ -161,6 +161,8 @@ Environment:
 - $HELM_TLS_VERIFY:     Enable TLS connection between Helm and Tiller and verify Tiller server certificate (default "false")
 - $HELM_TLS_HOSTNAME:   The hostname or IP address used to verify the Tiller server certificate (default "127.0.0.1")
 - $HELM_KEY_PASSPHRASE: Set HELM_KEY_PASSPHRASE to the passphrase of your PGP private key. If set, you will not be prompted for the passphrase while signing helm charts
+- $HELM_TRACE_FILE:     Trace the command and the calls it makes to Tiller, appending the spans to this file as JSON
+- $HELM_TRACE_URL:      Trace the command and the calls it makes to Tiller, sending the spans to the collector at this URL
 
 `
 
@@ -170,7 +172,8 @@ func newRootCmd(args []string) *cobra.Command {
 		Short:        "The Helm package manager for Kubernetes.",
 		Long:         globalUsage,
 		SilenceUsage: true,
-		PersistentPreRun: func(*cobra.Command, []string) {
+		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			startTrace(cmd)
 			if settings.TLSCaCertFile == helm_env.DefaultTLSCaCert || settings.TLSCaCertFile == "" {
 				settings.TLSCaCertFile = settings.Home.TLSCaCert()
 			} else {
@@ -212,17 +215,24 @@ func newRootCmd(args []string) *cobra.Command {
 		newVerifyCmd(out),
 
 		// release commands
+		newApplyCmd(nil, out),
+		newCancelCmd(nil, out),
 		newDeleteCmd(nil, out),
+		newDiffCmd(nil, out),
+		newDriftCmd(nil, out),
 		newGetCmd(nil, out),
 		newHistoryCmd(nil, out),
 		newInstallCmd(nil, out),
 		newListCmd(nil, out),
+		newRenameCmd(nil, out),
 		newRollbackCmd(nil, out),
 		newStatusCmd(nil, out),
 		newUpgradeCmd(nil, out),
 
+		newReleaseCmd(nil, out),
 		newReleaseTestCmd(nil, out),
 		newResetCmd(nil, out),
+		newStorageCmd(out),
 		newVersionCmd(nil, out),
 
 		newCompletionCmd(out),
@@ -256,7 +266,9 @@ func init() {
 
 func main() {
 	cmd := newRootCmd(os.Args[1:])
-	if err := cmd.Execute(); err != nil {
+	err := cmd.Execute()
+	finishTrace(err)
+	if err != nil {
 		switch e := err.(type) {
 		case pluginError:
 			os.Exit(e.code)
@@ -378,5 +390,8 @@ func newClient() helm.Interface {
 		}
 		options = append(options, helm.WithTLS(tlscfg))
 	}
+	if commandSpan != nil {
+		options = append(options, helm.WithTrace(commandSpan.Context()))
+	}
 	return helm.NewClient(options...)
 }
@@ -35,6 +35,7 @@ import (
 	"k8s.io/helm/pkg/helm/environment"
 	"k8s.io/helm/pkg/helm/helmpath"
 	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/repo"
 )
 
@@ -49,6 +50,7 @@ func runReleaseCases(t *testing.T, tests []releaseCase, rcmd releaseCmd) {
 			c := &helm.FakeClient{
 				Rels:      tt.rels,
 				Responses: tt.responses,
+				Adopted:   tt.adopted,
 			}
 			cmd := rcmd(c, &buf)
 			cmd.ParseFlags(tt.flags)
@@ -77,6 +79,8 @@ type releaseCase struct {
 	// Rels are the available releases at the start of the test.
 	rels      []*release.Release
 	responses map[string]release.TestRun_Status
+	// adopted are the existing resources adopted by installs and upgrades.
+	adopted []*services.AdoptedResource
 }
 
 // tempHelmHome sets up a Helm Home in a temp dir.
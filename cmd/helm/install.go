@@ -39,6 +39,7 @@ import (
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/renderutil"
 	"k8s.io/helm/pkg/repo"
 	"k8s.io/helm/pkg/strvals"
@@ -86,6 +87,12 @@ To check the generated manifests of a release without installing the chart,
 the '--debug' and '--dry-run' flags can be combined. This will still require a
 round-trip to the Tiller server.
 
+Installing a chart whose resources already exist in the cluster fails. With
+'--adopt', the existing resources which do not belong to another release are
+adopted by the release instead: they are patched to the state rendered by the
+chart, and recorded in the release. Combine '--adopt' with '--dry-run' to list
+the resources that would be adopted.
+
 If --verify is set, the chart MUST have a provenance file, and the provenance
 file MUST pass all verification steps.
 
@@ -139,6 +146,7 @@ type installCmd struct {
 	depUp          bool
 	subNotes       bool
 	description    string
+	adopt          bool
 
 	certFile string
 	keyFile  string
@@ -226,6 +234,7 @@ func newInstallCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&inst.depUp, "dep-up", false, "Run helm dependency update before installing the chart")
 	f.BoolVar(&inst.subNotes, "render-subchart-notes", false, "Render subchart notes along with the parent")
 	f.StringVar(&inst.description, "description", "", "Specify a description for the release")
+	f.BoolVar(&inst.adopt, "adopt", false, "Adopt the resources of the chart which already exist in the cluster outside of any release")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -309,7 +318,8 @@ func (i *installCmd) run() error {
 		helm.InstallSubNotes(i.subNotes),
 		helm.InstallTimeout(i.timeout),
 		helm.InstallWait(i.wait),
-		helm.InstallDescription(i.description))
+		helm.InstallDescription(i.description),
+		helm.InstallAdoptExisting(i.adopt))
 	if err != nil {
 		if i.atomic {
 			fmt.Fprintf(os.Stdout, "INSTALL FAILED\nPURGING CHART\nError: %v\n", prettyError(err))
@@ -336,6 +346,7 @@ func (i *installCmd) run() error {
 		return nil
 	}
 	i.printRelease(rel)
+	printAdopted(i.out, res.Adopted, i.dryRun)
 
 	// If this is a dry run, we can't display status.
 	if i.dryRun {
@@ -450,6 +461,18 @@ func (i *installCmd) printRelease(rel *release.Release) {
 	}
 }
 
+// printAdopted prints the existing resources adopted by a release, or to be
+// adopted on dry run.
+func printAdopted(out io.Writer, adopted []*services.AdoptedResource, dryRun bool) {
+	action := "ADOPTED"
+	if dryRun {
+		action = "WOULD ADOPT"
+	}
+	for _, r := range adopted {
+		fmt.Fprintf(out, "%s %s %s/%s\n", action, r.Kind, r.Namespace, r.Name)
+	}
+}
+
 // locateChartPath looks for a chart directory in known places, and returns either the full path or an error.
 //
 // This does not ensure that the chart is well-formed; only that the requested filename exists.
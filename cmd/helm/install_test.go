@@ -25,8 +25,16 @@ import (
 
 	"github.com/spf13/cobra"
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
 )
 
+// adoptedFixture are the resources adopted by installs and upgrades adopting
+// existing resources.
+var adoptedFixture = []*services.AdoptedResource{
+	{Kind: "Deployment", Name: "virgil-web", Namespace: "default"},
+	{Kind: "Service", Name: "virgil", Namespace: "default"},
+}
+
 func TestInstall(t *testing.T) {
 	tests := []releaseCase{
 		// Install, base case
@@ -136,6 +144,22 @@ func TestInstall(t *testing.T) {
 			expected: "virgil",
 			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "virgil", Description: "foobar"}),
 		},
+		{
+			name:     "install adopting existing resources",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    []string{"--name", "virgil", "--adopt"},
+			expected: "ADOPTED Deployment default/virgil-web\nADOPTED Service default/virgil\n",
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "virgil"}),
+			adopted:  adoptedFixture,
+		},
+		{
+			name:     "install adopting existing resources, dry run",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    []string{"--name", "virgil", "--adopt", "--dry-run"},
+			expected: "WOULD ADOPT Deployment default/virgil-web\nWOULD ADOPT Service default/virgil\n",
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "virgil"}),
+			adopted:  adoptedFixture,
+		},
 		// Install, perform chart verification along the way.
 		{
 			name:  "install with verification, missing provenance",
@@ -0,0 +1,78 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/storage"
+)
+
+const releaseHelp = `
+This command consists of multiple subcommands to back up, restore and repair
+the records Tiller keeps of releases, including their whole revision history.
+
+The export and import commands access the storage backend of Tiller directly
+rather than through Tiller, so the storage flags must match the ones Tiller
+was started with.
+`
+
+func newReleaseCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release [command]",
+		Short: "Back up, restore and repair release records",
+		Long:  releaseHelp,
+	}
+
+	cmd.AddCommand(
+		newReleaseExportCmd(out),
+		newReleaseImportCmd(out),
+		newReleaseRepairCmd(c, out),
+	)
+
+	return cmd
+}
+
+// openStorage returns the release storage of Tiller described by opts and
+// encryption, storing releases in namespace. If client is nil, a client for
+// the current kubernetes context is created.
+func openStorage(opts storageOptions, encryption storageEncryptionOptions, client kubernetes.Interface, namespace string) (*storage.Storage, error) {
+	if err := encryption.set(); err != nil {
+		return nil, fmt.Errorf("could not set up storage encryption: %s", err)
+	}
+
+	if client == nil && opts.storage != storageSQL && opts.storage != storageFile {
+		_, c, err := getKubeClient(settings.KubeContext, settings.KubeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("could not get kubernetes client: %s", err)
+		}
+		client = c
+	}
+
+	d, err := newStorageDriver(opts, client, namespace)
+	if err != nil {
+		return nil, err
+	}
+	s := storage.Init(d)
+	s.Log = debug
+	return s, nil
+}
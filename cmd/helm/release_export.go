@@ -0,0 +1,104 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+const releaseExportDesc = `
+This command writes every revision of the given releases to an archive, so
+that they can later be restored with 'helm release import':
+
+    $ helm release export happy-panda --file happy-panda.tgz
+
+Use '--all' to export every release known to Tiller.
+
+The archive is a gzipped tarball holding a JSON manifest and the protobuf
+encoding of each release revision. It holds the values the releases were
+installed with, so it should be stored as securely as the storage backend.
+`
+
+type releaseExportCmd struct {
+	releases   []string
+	all        bool
+	file       string
+	storage    storageOptions
+	encryption storageEncryptionOptions
+	namespace  string
+	out        io.Writer
+	kubeClient kubernetes.Interface
+}
+
+func newReleaseExportCmd(out io.Writer) *cobra.Command {
+	e := &releaseExportCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "export [RELEASE...] --file FILE",
+		Short: "Export the history of releases to an archive",
+		Long:  releaseExportDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if e.file == "" {
+				return errors.New("--file must be set")
+			}
+			if e.all == (len(args) != 0) {
+				return errors.New("either release names or --all must be given")
+			}
+
+			e.releases = args
+			e.namespace = settings.TillerNamespace
+			return e.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&e.file, "file", "", "path of the archive to write")
+	f.BoolVar(&e.all, "all", false, "export every release")
+	e.storage.addFlags(f)
+	e.encryption.addFlags(f)
+
+	return cmd
+}
+
+func (e *releaseExportCmd) run() error {
+	s, err := openStorage(e.storage, e.encryption, e.kubeClient, e.namespace)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(e.file)
+	if err != nil {
+		return err
+	}
+	rels, err := s.Export(f, e.releases...)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(e.file)
+		return fmt.Errorf("could not export releases: %s", err)
+	}
+
+	fmt.Fprintf(e.out, "Exported %d release revision(s) to %s\n", len(rels), e.file)
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func TestReleaseExportImportCmd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-release-export-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "releases.tgz")
+
+	fc := fake.NewSimpleClientset()
+	cfgmaps := driver.NewConfigMaps(fc.CoreV1().ConfigMaps(v1.NamespaceDefault))
+	for _, rls := range []*release.Release{
+		helm.ReleaseMock(&helm.MockReleaseOptions{Name: "atlas-guide", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+		helm.ReleaseMock(&helm.MockReleaseOptions{Name: "atlas-guide", Version: 2, StatusCode: release.Status_DEPLOYED}),
+		helm.ReleaseMock(&helm.MockReleaseOptions{Name: "happy-panda", Version: 1, StatusCode: release.Status_DEPLOYED}),
+	} {
+		if err := cfgmaps.Create(fmt.Sprintf("%s.v%d", rls.Name, rls.Version), rls); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	export := &releaseExportCmd{
+		releases:   []string{"atlas-guide"},
+		file:       file,
+		storage:    storageOptions{storage: storageConfigMap},
+		out:        &buf,
+		kubeClient: fc,
+		namespace:  v1.NamespaceDefault,
+	}
+	if err := export.run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := "Exported 2 release revision(s) to " + file
+	if !strings.Contains(buf.String(), expect) {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+
+	buf.Reset()
+	imp := &releaseImportCmd{
+		file:       file,
+		storage:    storageOptions{storage: storageSecret},
+		out:        &buf,
+		kubeClient: fc,
+		namespace:  v1.NamespaceDefault,
+	}
+	if err := imp.run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect = "Imported 2 release revision(s) from " + file
+	if !strings.Contains(buf.String(), expect) {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+
+	secrets := driver.NewSecrets(fc.CoreV1().Secrets(v1.NamespaceDefault))
+	for _, key := range []string{"atlas-guide.v1", "atlas-guide.v2"} {
+		if _, err := secrets.Get(key); err != nil {
+			t.Errorf("expected %s to be imported: %v", key, err)
+		}
+	}
+	if _, err := secrets.Get("happy-panda.v1"); err == nil {
+		t.Error("expected happy-panda.v1 not to be imported")
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+const releaseImportDesc = `
+This command restores the release revisions stored in an archive written by
+'helm release export':
+
+    $ helm release import happy-panda.tgz
+
+The whole archive is verified before any release is restored. Revisions that
+Tiller already knows of are skipped if they are identical to the archived ones,
+and stop the import otherwise. The archive can be restored to any storage
+backend, regardless of the one it was exported from.
+
+Only the records of the releases are restored, not the Kubernetes resources
+they describe.
+`
+
+type releaseImportCmd struct {
+	file       string
+	storage    storageOptions
+	encryption storageEncryptionOptions
+	namespace  string
+	out        io.Writer
+	kubeClient kubernetes.Interface
+}
+
+func newReleaseImportCmd(out io.Writer) *cobra.Command {
+	i := &releaseImportCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Restore the history of releases from an archive",
+		Long:  releaseImportDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "archive path"); err != nil {
+				return err
+			}
+
+			i.file = args[0]
+			i.namespace = settings.TillerNamespace
+			return i.run()
+		},
+	}
+
+	f := cmd.Flags()
+	i.storage.addFlags(f)
+	i.encryption.addFlags(f)
+
+	return cmd
+}
+
+func (i *releaseImportCmd) run() error {
+	s, err := openStorage(i.storage, i.encryption, i.kubeClient, i.namespace)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(i.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rels, err := s.Import(f)
+	if err != nil {
+		return fmt.Errorf("could not import releases: %s", err)
+	}
+
+	fmt.Fprintf(i.out, "Imported %d release revision(s) from %s\n", len(rels), i.file)
+	return nil
+}
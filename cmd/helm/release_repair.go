@@ -0,0 +1,96 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const releaseRepairDesc = `
+This command repairs a release left pending by an install, upgrade or rollback
+that was interrupted, e.g. because Tiller crashed.
+
+Such a release cannot be upgraded or rolled back, as its last revision is
+still PENDING_INSTALL, PENDING_UPGRADE or PENDING_ROLLBACK. Repairing it marks
+that revision as FAILED and, if the release has no deployed revision any more,
+deploys the revision it superseded again. The resources of the release in the
+cluster are left as they are: upgrade or roll back the release to converge
+them.
+
+Unlike the other 'helm release' commands, this command goes through Tiller,
+which refuses to repair a release with an operation still in progress. Use
+'helm cancel' to stop such an operation.
+
+Tiller started with '--pending-releases=fail' repairs the releases left pending
+when it starts.
+`
+
+type releaseRepairCmd struct {
+	name   string
+	out    io.Writer
+	client helm.Interface
+}
+
+func newReleaseRepairCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	r := &releaseRepairCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "repair [flags] RELEASE",
+		Short:   "Fail a release left pending by an interrupted operation",
+		Long:    releaseRepairDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+
+			r.name = args[0]
+			r.client = ensureHelmClient(r.client)
+			return r.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (r *releaseRepairCmd) run() error {
+	res, err := r.client.RepairRelease(r.name)
+	if err != nil {
+		return prettyError(err)
+	}
+
+	rel := res.GetRelease()
+	fmt.Fprintf(r.out, "Release %q revision %d: %s\n", rel.GetName(), rel.GetVersion(), rel.GetInfo().GetDescription())
+	if restored := res.GetRestored(); restored != nil {
+		fmt.Fprintf(r.out, "Release %q revision %d deployed again\n", restored.GetName(), restored.GetVersion())
+	}
+	return nil
+}
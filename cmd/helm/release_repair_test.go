@@ -0,0 +1,59 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestReleaseRepairCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "repair an interrupted upgrade",
+			args:     []string{"funny-honey"},
+			expected: `Release "funny-honey" revision 2: Upgrade interrupted: repaired on request`,
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey", Version: 2, StatusCode: release.Status_PENDING_UPGRADE})},
+		},
+		{
+			name: "repair a deployed release",
+			args: []string{"funny-honey"},
+			rels: []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey"})},
+			err:  true,
+		},
+		{
+			name: "repair a missing release",
+			args: []string{"zany-honey"},
+			err:  true,
+		},
+		{
+			name: "repair without a release name",
+			err:  true,
+		},
+	}
+
+	cmd := func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newReleaseRepairCmd(c, out)
+	}
+
+	runReleaseCases(t, tests, cmd)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const renameDesc = `
+This command renames a release, or moves it to another namespace.
+
+The first argument of the rename command is the name of a release, and the
+second is its new name. The revision history of the release is kept under the
+new name. The deployed revision is rendered again with the new name, so that
+'.Release.Name' in templates takes the new name, and applied as an upgrade:
+the resources named after the release are replaced.
+
+Use '--namespace' to move the release to another namespace, with or without a
+new name. Its resources are created in the new namespace and deleted from the
+previous one.
+
+	$ helm rename angry-panda web
+	$ helm rename web --namespace production
+
+Use '--dry-run' to render the renamed release without changing it.
+`
+
+type renameCmd struct {
+	name         string
+	newName      string
+	namespace    string
+	dryRun       bool
+	disableHooks bool
+	timeout      int64
+	wait         bool
+	out          io.Writer
+	client       helm.Interface
+}
+
+func newRenameCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	rename := &renameCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "rename [flags] RELEASE [NEW_NAME]",
+		Short:   "Rename a release or move it to another namespace",
+		Long:    renameDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+			if len(args) > 2 {
+				return errors.New("This command accepts at most two arguments: the release name and its new name")
+			}
+
+			rename.name = args[0]
+			if len(args) == 2 {
+				rename.newName = args[1]
+			}
+			if rename.newName == "" && rename.namespace == "" {
+				return errors.New("This command needs a new name or a namespace to move the release to")
+			}
+			rename.client = ensureHelmClient(rename.client)
+			return rename.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.StringVar(&rename.namespace, "namespace", "", "Namespace to move the release to")
+	f.BoolVar(&rename.dryRun, "dry-run", false, "Simulate a rename")
+	f.BoolVar(&rename.disableHooks, "no-hooks", false, "Prevent hooks from running during rename")
+	f.Int64Var(&rename.timeout, "timeout", 300, "Time in seconds to wait for any individual Kubernetes operation (like Jobs for hooks)")
+	f.BoolVar(&rename.wait, "wait", false, "If set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (r *renameCmd) run() error {
+	res, err := r.client.RenameRelease(
+		r.name,
+		r.newName,
+		helm.RenameNamespace(r.namespace),
+		helm.RenameDryRun(r.dryRun),
+		helm.RenameDisableHooks(r.disableHooks),
+		helm.RenameTimeout(r.timeout),
+		helm.RenameWait(r.wait))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	rel := res.GetRelease()
+	verb := "has been"
+	if r.dryRun {
+		verb = "would be"
+	}
+	if rel.GetName() == r.name {
+		fmt.Fprintf(r.out, "Release %q %s moved to namespace %q.\n", r.name, verb, rel.GetNamespace())
+		return nil
+	}
+	fmt.Fprintf(r.out, "Release %q %s renamed to %q in namespace %q.\n", r.name, verb, rel.GetName(), rel.GetNamespace())
+	return nil
+}
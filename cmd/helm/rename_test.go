@@ -0,0 +1,80 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestRenameCmd(t *testing.T) {
+	rels := []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey", Namespace: "default"})}
+
+	tests := []releaseCase{
+		{
+			name:     "rename a release",
+			args:     []string{"funny-honey", "web"},
+			expected: `Release "funny-honey" has been renamed to "web" in namespace "default".`,
+			rels:     rels,
+		},
+		{
+			name:     "move a release to another namespace",
+			args:     []string{"funny-honey"},
+			flags:    []string{"--namespace", "production"},
+			expected: `Release "funny-honey" has been moved to namespace "production".`,
+			rels:     rels,
+		},
+		{
+			name:     "rename a release with dry run",
+			args:     []string{"funny-honey", "web"},
+			flags:    []string{"--dry-run"},
+			expected: `Release "funny-honey" would be renamed to "web" in namespace "default".`,
+			rels:     rels,
+		},
+		{
+			name: "rename a release to the name of another",
+			args: []string{"funny-honey", "web"},
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey"}),
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "web"}),
+			},
+			err: true,
+		},
+		{
+			name: "rename a release without new name or namespace",
+			args: []string{"funny-honey"},
+			rels: rels,
+			err:  true,
+		},
+		{
+			name: "rename a missing release",
+			args: []string{"funny-honey", "web"},
+			err:  true,
+		},
+	}
+
+	cmd := func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newRenameCmd(c, out)
+	}
+
+	runReleaseCases(t, tests, cmd)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/storage/encryption"
+)
+
+const storageHelp = `
+This command consists of multiple subcommands to manage the records Tiller
+keeps of releases in its storage backend.
+
+These commands access the storage backend directly rather than through Tiller.
+`
+
+const (
+	storageConfigMap = "configmap"
+	storageSecret    = "secret"
+	storageSQL       = "sql"
+	storageFile      = "file"
+)
+
+func newStorageCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage [command]",
+		Short: "Manage Tiller's release storage",
+		Long:  storageHelp,
+	}
+
+	cmd.AddCommand(newStorageMigrateCmd(out))
+
+	return cmd
+}
+
+// storageOptions describes how to reach one of the storage backends of Tiller.
+type storageOptions struct {
+	storage             string
+	sqlDialect          string
+	sqlConnectionString string
+	fileDir             string
+}
+
+// addFlags adds the flags describing the storage backend of Tiller to f.
+func (o *storageOptions) addFlags(f *pflag.FlagSet) {
+	f.StringVar(&o.storage, "storage", storageConfigMap, "storage backend of Tiller. One of 'configmap', 'secret', 'sql' or 'file'")
+	f.StringVar(&o.sqlDialect, "sql-dialect", "postgres", "SQL dialect of the storage backend")
+	f.StringVar(&o.sqlConnectionString, "sql-connection-string", "", "SQL connection string of the storage backend")
+	f.StringVar(&o.fileDir, "storage-file-dir", "", "directory holding the releases of the file storage backend")
+}
+
+// storageEncryptionOptions describes how Tiller encrypts releases at rest.
+type storageEncryptionOptions struct {
+	keyFile string
+	command string
+}
+
+func (o *storageEncryptionOptions) addFlags(f *pflag.FlagSet) {
+	f.StringVar(&o.keyFile, "storage-encryption-key-file", "", "path to the key file Tiller uses to encrypt releases at rest")
+	f.StringVar(&o.command, "storage-encryption-command", "", "command Tiller uses to wrap the keys encrypting releases at rest")
+}
+
+// newStorageDriver returns the driver for the storage backend described by
+// opts, storing releases in the namespace of Tiller.
+func newStorageDriver(opts storageOptions, client kubernetes.Interface, namespace string) (driver.Driver, error) {
+	switch opts.storage {
+	case storageConfigMap:
+		d := driver.NewConfigMaps(client.CoreV1().ConfigMaps(namespace))
+		d.Log = debug
+		return d, nil
+	case storageSecret:
+		d := driver.NewSecrets(client.CoreV1().Secrets(namespace))
+		d.Log = debug
+		return d, nil
+	case storageSQL:
+		return driver.NewSQL(opts.sqlDialect, opts.sqlConnectionString, debug)
+	case storageFile:
+		if opts.fileDir == "" {
+			return nil, fmt.Errorf("the directory of the %q storage must be set", storageFile)
+		}
+		d, err := driver.NewFile(opts.fileDir)
+		if err != nil {
+			return nil, err
+		}
+		d.Log = debug
+		return d, nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q, must be one of %q, %q, %q or %q", opts.storage, storageConfigMap, storageSecret, storageSQL, storageFile)
+	}
+}
+
+// set configures the decryption and encryption of releases stored by a
+// Tiller started with the equivalent storage encryption flag.
+func (o *storageEncryptionOptions) set() error {
+	switch {
+	case o.keyFile != "" && o.command != "":
+		return fmt.Errorf("only one of --storage-encryption-key-file and --storage-encryption-command can be set")
+	case o.keyFile != "":
+		kp, err := encryption.NewFileKeyProvider(o.keyFile)
+		if err != nil {
+			return err
+		}
+		driver.SetKeyProvider(kp)
+	case o.command != "":
+		args := strings.Fields(o.command)
+		if len(args) == 0 {
+			return fmt.Errorf("--storage-encryption-command has no command")
+		}
+		driver.SetKeyProvider(encryption.NewCommandKeyProvider(args[0], args[1:]...))
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/helm/pkg/storage/migration"
+)
+
+const storageMigrateDesc = `
+This command copies every revision of every release from one Tiller storage
+backend to another, e.g. from ConfigMaps to Secrets:
+
+    $ helm storage migrate --from configmap --to secret --checkpoint migrate.log
+
+Release names, versions and statuses are kept. Each copy is read back and
+compared with the original. Releases already present in the destination are
+skipped if they are identical, and stop the migration otherwise.
+
+With '--checkpoint', the releases migrated are recorded in a file, and listed
+releases are skipped, so that an interrupted migration can be run again to
+resume. With '--delete-source', each release is deleted from the source
+backend once its copy has been verified.
+
+Tiller should be scaled down during the migration, then restarted with the
+'--storage' flag matching the destination backend.
+`
+
+type storageMigrateCmd struct {
+	from       storageOptions
+	to         storageOptions
+	encryption storageEncryptionOptions
+	opts       migration.Options
+	namespace  string
+	out        io.Writer
+	kubeClient kubernetes.Interface
+}
+
+func newStorageMigrateCmd(out io.Writer) *cobra.Command {
+	m := &storageMigrateCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "migrate --from STORAGE --to STORAGE",
+		Short: "Copy releases from one storage backend to another",
+		Long:  storageMigrateDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("This command does not accept arguments")
+			}
+			if m.from.storage == "" || m.to.storage == "" {
+				return errors.New("both --from and --to must be set")
+			}
+			if m.from.storage == m.to.storage && m.from.storage != storageSQL && m.from.storage != storageFile {
+				return errors.New("source and destination storage must differ")
+			}
+
+			m.namespace = settings.TillerNamespace
+			return m.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&m.from.storage, "from", "", "storage backend to copy releases from. One of 'configmap', 'secret', 'sql' or 'file'")
+	f.StringVar(&m.from.sqlDialect, "from-sql-dialect", "postgres", "SQL dialect of the source backend")
+	f.StringVar(&m.from.sqlConnectionString, "from-sql-connection-string", "", "SQL connection string of the source backend")
+	f.StringVar(&m.from.fileDir, "from-file-dir", "", "directory of the source backend, if a file backend")
+	f.StringVar(&m.to.storage, "to", "", "storage backend to copy releases to. One of 'configmap', 'secret', 'sql' or 'file'")
+	f.StringVar(&m.to.sqlDialect, "to-sql-dialect", "postgres", "SQL dialect of the destination backend")
+	f.StringVar(&m.to.sqlConnectionString, "to-sql-connection-string", "", "SQL connection string of the destination backend")
+	f.StringVar(&m.to.fileDir, "to-file-dir", "", "directory of the destination backend, if a file backend")
+	m.encryption.addFlags(f)
+	f.BoolVar(&m.opts.DryRun, "dry-run", false, "only list the releases that would be migrated")
+	f.BoolVar(&m.opts.DeleteSource, "delete-source", false, "delete each release from the source backend once copied")
+	f.StringVar(&m.opts.Checkpoint, "checkpoint", "", "file recording the migrated releases, used to resume an interrupted migration")
+
+	return cmd
+}
+
+func (m *storageMigrateCmd) run() error {
+	if err := m.encryption.set(); err != nil {
+		return fmt.Errorf("could not set up storage encryption: %s", err)
+	}
+
+	if m.kubeClient == nil {
+		_, c, err := getKubeClient(settings.KubeContext, settings.KubeConfig)
+		if err != nil {
+			return fmt.Errorf("could not get kubernetes client: %s", err)
+		}
+		m.kubeClient = c
+	}
+
+	src, err := newStorageDriver(m.from, m.kubeClient, m.namespace)
+	if err != nil {
+		return fmt.Errorf("could not open source storage: %s", err)
+	}
+	dst, err := newStorageDriver(m.to, m.kubeClient, m.namespace)
+	if err != nil {
+		return fmt.Errorf("could not open destination storage: %s", err)
+	}
+
+	m.opts.Log = func(format string, args ...interface{}) {
+		fmt.Fprintf(m.out, format+"\n", args...)
+	}
+	res, err := migration.Migrate(src, dst, m.opts)
+	if res != nil {
+		verb := "Migrated"
+		if m.opts.DryRun {
+			verb = "Would migrate"
+		}
+		fmt.Fprintf(m.out, "%s %d release(s) from %s to %s, skipped %d, deleted %d from %s\n",
+			verb, res.Migrated, src.Name(), dst.Name(), res.Skipped, res.Deleted, src.Name())
+	}
+	return err
+}
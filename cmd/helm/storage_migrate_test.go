@@ -0,0 +1,134 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func TestStorageMigrateCmd(t *testing.T) {
+	fc := fake.NewSimpleClientset()
+	cfgmaps := driver.NewConfigMaps(fc.CoreV1().ConfigMaps(v1.NamespaceDefault))
+	for _, rls := range []*release.Release{
+		helm.ReleaseMock(&helm.MockReleaseOptions{Name: "atlas-guide", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+		helm.ReleaseMock(&helm.MockReleaseOptions{Name: "atlas-guide", Version: 2, StatusCode: release.Status_DEPLOYED}),
+	} {
+		if err := cfgmaps.Create(fmt.Sprintf("%s.v%d", rls.Name, rls.Version), rls); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	cmd := &storageMigrateCmd{
+		from:       storageOptions{storage: storageConfigMap},
+		to:         storageOptions{storage: storageSecret},
+		out:        &buf,
+		kubeClient: fc,
+		namespace:  v1.NamespaceDefault,
+	}
+	cmd.opts.DeleteSource = true
+
+	if err := cmd.run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expect := "Migrated 2 release(s) from ConfigMap to Secret, skipped 0, deleted 2 from ConfigMap"
+	if !strings.Contains(buf.String(), expect) {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+
+	secrets, err := fc.CoreV1().Secrets(v1.NamespaceDefault).List(metav1.ListOptions{LabelSelector: "OWNER=TILLER"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets.Items) != 2 {
+		t.Errorf("expected 2 secrets, got %d", len(secrets.Items))
+	}
+	cms, err := fc.CoreV1().ConfigMaps(v1.NamespaceDefault).List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cms.Items) != 0 {
+		t.Errorf("expected no configmap left, got %d", len(cms.Items))
+	}
+}
+
+func TestStorageMigrateCmd_file(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-storage-migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fc := fake.NewSimpleClientset()
+	rls := helm.ReleaseMock(&helm.MockReleaseOptions{Name: "atlas-guide", Version: 1, StatusCode: release.Status_DEPLOYED})
+	if err := driver.NewSecrets(fc.CoreV1().Secrets(v1.NamespaceDefault)).Create("atlas-guide.v1", rls); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &storageMigrateCmd{
+		from:       storageOptions{storage: storageSecret},
+		to:         storageOptions{storage: storageFile, fileDir: dir},
+		out:        &bytes.Buffer{},
+		kubeClient: fc,
+		namespace:  v1.NamespaceDefault,
+	}
+	if err := cmd.run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	file, err := driver.NewFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Get("atlas-guide.v1"); err != nil {
+		t.Errorf("expected the release to be migrated to the file storage: %v", err)
+	}
+}
+
+func TestStorageMigrateCmd_unknownStorage(t *testing.T) {
+	cmd := &storageMigrateCmd{
+		from:       storageOptions{storage: "etcd"},
+		to:         storageOptions{storage: storageSecret},
+		out:        &bytes.Buffer{},
+		kubeClient: fake.NewSimpleClientset(),
+		namespace:  v1.NamespaceDefault,
+	}
+	if err := cmd.run(); err == nil || !strings.Contains(err.Error(), "unknown storage") {
+		t.Errorf("expected an unknown storage error, got %v", err)
+	}
+}
+
+func TestStorageEncryptionOptions_emptyCommand(t *testing.T) {
+	o := &storageEncryptionOptions{command: " \t"}
+	if err := o.set(); err == nil || !strings.Contains(err.Error(), "has no command") {
+		t.Errorf("expected an empty command error, got %v", err)
+	}
+}
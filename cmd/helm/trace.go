@@ -0,0 +1,67 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/tracing"
+	"k8s.io/helm/pkg/version"
+)
+
+var (
+	// commandSpan is the span of the command being run, if it is traced.
+	commandSpan   *tracing.Span
+	traceExporter tracing.Exporter
+)
+
+// startTrace starts the span of cmd if $HELM_TRACE_FILE or $HELM_TRACE_URL
+// is set. The calls made to Tiller are traced as children of it.
+func startTrace(cmd *cobra.Command) {
+	switch {
+	case os.Getenv("HELM_TRACE_URL") != "":
+		traceExporter = tracing.NewCollectorExporter(os.Getenv("HELM_TRACE_URL"))
+	case os.Getenv("HELM_TRACE_FILE") != "":
+		e, err := tracing.NewFileExporter(os.Getenv("HELM_TRACE_FILE"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: tracing disabled: %s\n", err)
+			return
+		}
+		traceExporter = e
+	default:
+		return
+	}
+	commandSpan = tracing.NewTracer("helm", traceExporter).Start(cmd.CommandPath(), tracing.SpanContext{})
+	commandSpan.SetAttribute("helm.version", version.GetVersion())
+	debug("TRACE: %s\n", commandSpan.TraceID)
+}
+
+// finishTrace ends the span of the command, which failed with err, if any,
+// and exports it.
+func finishTrace(err error) {
+	if commandSpan == nil {
+		return
+	}
+	commandSpan.Finish(err)
+	if err := traceExporter.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to export trace: %s\n", err)
+	}
+	commandSpan = nil
+}
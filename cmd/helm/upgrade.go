@@ -50,6 +50,11 @@ To edit or append to the existing customized values, add the
 If no chart value arguments are provided on the command line, any existing customized values are carried
 forward. If you want to revert to just the values provided in the chart, use the '--reset-values' flag.
 
+Resources added to the chart which already exist in the cluster make the upgrade fail. With '--adopt',
+the existing resources which do not belong to another release are adopted by the release instead: they
+are patched to the state rendered by the chart, and recorded in the release. Combine '--adopt' with
+'--dry-run' to list the resources that would be adopted.
+
 You can specify any of the chart value flags multiple times. The priority will be given to the last
 (right-most) value specified. For example, if both myvalues.yaml and override.yaml contained a key
 called 'Test', the value set in override.yaml would take precedence:
@@ -113,6 +118,7 @@ type upgradeCmd struct {
 	subNotes      bool
 	description   string
 	cleanupOnFail bool
+	adopt         bool
 
 	certFile string
 	keyFile  string
@@ -181,6 +187,7 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&upgrade.subNotes, "render-subchart-notes", false, "Render subchart notes along with parent")
 	f.StringVar(&upgrade.description, "description", "", "Specify the description to use for the upgrade, rather than the default")
 	f.BoolVar(&upgrade.cleanupOnFail, "cleanup-on-fail", false, "Allow deletion of new resources created in this upgrade when upgrade failed")
+	f.BoolVar(&upgrade.adopt, "adopt", false, "Adopt the resources of the chart which already exist in the cluster outside of any release")
 
 	f.MarkDeprecated("disable-hooks", "Use --no-hooks instead")
 
@@ -239,6 +246,7 @@ func (u *upgradeCmd) run() error {
 				wait:         u.wait,
 				description:  u.description,
 				atomic:       u.atomic,
+				adopt:        u.adopt,
 			}
 			return ic.run()
 		}
@@ -277,7 +285,8 @@ func (u *upgradeCmd) run() error {
 		helm.UpgradeSubNotes(u.subNotes),
 		helm.UpgradeWait(u.wait),
 		helm.UpgradeDescription(u.description),
-		helm.UpgradeCleanupOnFail(u.cleanupOnFail))
+		helm.UpgradeCleanupOnFail(u.cleanupOnFail),
+		helm.UpgradeAdoptExisting(u.adopt))
 	if err != nil {
 		fmt.Fprintf(u.out, "UPGRADE FAILED\nError: %v\n", prettyError(err))
 		if u.atomic {
@@ -307,6 +316,7 @@ func (u *upgradeCmd) run() error {
 		printRelease(u.out, resp.Release)
 	}
 
+	printAdopted(u.out, resp.Adopted, u.dryRun)
 	fmt.Fprintf(u.out, "Release %q has been upgraded.\n", u.release)
 
 	// Print the status like status command does
@@ -171,6 +171,24 @@ func TestUpgradeCmd(t *testing.T) {
 			expected: "Release \"crazy-bunny\" has been upgraded.\n",
 			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2, Description: "foo"})},
 		},
+		{
+			name:     "upgrade a release adopting existing resources",
+			args:     []string{"crazy-bunny", chartPath},
+			flags:    []string{"--adopt"},
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2}),
+			expected: "ADOPTED Deployment default/virgil-web\nADOPTED Service default/virgil\nRelease \"crazy-bunny\" has been upgraded.\n",
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2})},
+			adopted:  adoptedFixture,
+		},
+		{
+			name:     "upgrade a release adopting existing resources, dry run",
+			args:     []string{"crazy-bunny", chartPath},
+			flags:    []string{"--adopt", "--dry-run"},
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2}),
+			expected: "WOULD ADOPT Deployment default/virgil-web\nWOULD ADOPT Service default/virgil\nRelease \"crazy-bunny\" has been upgraded.\n",
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2})},
+			adopted:  adoptedFixture,
+		},
 		{
 			name: "upgrade a release with missing dependencies",
 			args: []string{"bonkers-bunny", missingDepsPath},
@@ -18,10 +18,22 @@ package main
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller"
 )
 
+var storageOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "tiller_storage_operation_duration_seconds",
+	Help:    "Duration of the calls made to the storage driver, by driver and operation.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"driver", "operation"})
+
 func readinessProbe(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
@@ -41,3 +53,38 @@ func addPrometheusHandler(mux *http.ServeMux) {
 	// Register HTTP handler for the global Prometheus registry.
 	mux.Handle("/metrics", promhttp.Handler())
 }
+
+// registerStorageCacheMetrics exposes the hit rate and size of the storage
+// cache to the global Prometheus registry.
+func registerStorageCacheMetrics(cache *driver.Cache) {
+	prometheus.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "tiller_storage_cache_hits_total",
+			Help: "Number of storage reads served from the storage cache.",
+		}, func() float64 { return float64(cache.Stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "tiller_storage_cache_misses_total",
+			Help: "Number of storage reads that fetched releases from storage.",
+		}, func() float64 { return float64(cache.Stats().Misses) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "tiller_storage_cache_releases",
+			Help: "Number of releases held in the storage cache.",
+		}, func() float64 { return float64(cache.Stats().Releases) }),
+	)
+}
+
+// observeStorage wraps d with a driver exposing the latencies of the calls
+// made to it to the global Prometheus registry.
+func observeStorage(d driver.Driver) driver.Driver {
+	prometheus.MustRegister(storageOperationDuration)
+	name := d.Name()
+	return driver.NewObserved(d, func(op, _ string, elapsed time.Duration) {
+		storageOperationDuration.WithLabelValues(name, op).Observe(elapsed.Seconds())
+	})
+}
+
+// registerReleaseMetrics exposes the metrics of the releases stored in
+// releases and of the operations of Tiller to the global Prometheus registry.
+func registerReleaseMetrics(releases *storage.Storage) error {
+	return tiller.RegisterMetrics(prometheus.DefaultRegisterer, releases)
+}
@@ -45,9 +45,14 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/storage"
 	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/storage/encryption"
 	"k8s.io/helm/pkg/tiller"
+	"k8s.io/helm/pkg/tiller/admission"
+	"k8s.io/helm/pkg/tiller/audit"
 	"k8s.io/helm/pkg/tiller/environment"
+	"k8s.io/helm/pkg/tiller/webhook"
 	"k8s.io/helm/pkg/tlsutil"
+	"k8s.io/helm/pkg/tracing"
 	"k8s.io/helm/pkg/version"
 )
 
@@ -67,6 +72,10 @@ const (
 	storageConfigMap = "configmap"
 	storageSecret    = "secret"
 	storageSQL       = "sql"
+	storageFile      = "file"
+
+	pendingKeep = "keep"
+	pendingFail = "fail"
 
 	traceAddr = ":44136"
 
@@ -78,11 +87,19 @@ var (
 	grpcAddr      = flag.String("listen", fmt.Sprintf(":%v", environment.DefaultTillerPort), "address:port to listen on")
 	probeAddr     = flag.String("probe-listen", fmt.Sprintf(":%v", environment.DefaultTillerProbePort), "address:port to listen on for probes")
 	enableTracing = flag.Bool("trace", false, "enable rpc tracing")
-	store         = flag.String("storage", storageConfigMap, "storage driver to use. One of 'configmap', 'memory', 'sql' or 'secret'")
+	store         = flag.String("storage", storageConfigMap, "storage driver to use. One of 'configmap', 'memory', 'sql', 'file' or 'secret'")
 
-	sqlDialect          = flag.String("sql-dialect", "postgres", "SQL dialect to use (only postgres is supported for now")
+	sqlDialect          = flag.String("sql-dialect", "postgres", "SQL dialect to use. One of 'postgres', 'mysql' or 'sqlite3' (sqlite3 requires a build with cgo enabled)")
 	sqlConnectionString = flag.String("sql-connection-string", "", "SQL connection string to use")
 
+	storageFileDir = flag.String("storage-file-dir", "", "directory holding the releases of the 'file' storage driver")
+
+	encryptionKeyFile = flag.String("storage-encryption-key-file", "", "path to a file of AES-256 keys used to encrypt release data at rest")
+	encryptionCommand = flag.String("storage-encryption-command", "", "command wrapping the data keys used to encrypt release data at rest, e.g. a KMS client")
+
+	storageCache             = flag.Bool("storage-cache", false, "keep decoded releases in memory, refreshed by watching the storage, to speed up listing releases")
+	storageCachePollInterval = flag.Duration("storage-cache-poll-interval", driver.DefaultCachePollInterval, "interval at which the storage cache reloads releases from storage that cannot be watched, i.e. 'sql'")
+
 	remoteReleaseModules = flag.Bool("experimental-release", false, "enable experimental release modules")
 
 	tlsEnable    = flag.Bool("tls", tlsEnableEnvVarDefault(), "enable TLS")
@@ -93,6 +110,27 @@ var (
 	maxHistory   = flag.Int("history-max", historyMaxFromEnv(), "maximum number of releases kept in release history, with 0 meaning no limit")
 	printVersion = flag.Bool("version", false, "print the version number")
 
+	historyKeepNewerThan = flag.Duration("history-keep-newer-than", 0, "age under which releases are kept in release history regardless of --history-max, with 0 protecting none")
+	purgeDeletedAfter    = flag.Duration("purge-deleted-after", 0, "time after which the history of deleted releases is purged, with 0 meaning never")
+	historySweepInterval = flag.Duration("history-sweep-interval", time.Hour, "interval at which release history retention policies are applied, with 0 disabling the sweeps")
+
+	authzPolicy               = flag.String("authz-policy", "", "path to a file of rules allowing clients, identified by their verified TLS certificate, to call RPCs on the releases of namespaces. Requires --tls-verify")
+	authzPolicyReloadInterval = flag.Duration("authz-policy-reload-interval", 10*time.Second, "interval at which the authorization policy file is reloaded if it changed")
+
+	auditLogFile = flag.String("audit-log-file", "", "path of a file to which a JSON record of every call changing releases is appended")
+	auditEvents  = flag.Bool("audit-events", false, "record every call changing releases as a Kubernetes Event in the namespace of Tiller")
+
+	webhooksConfig    = flag.String("webhooks-config", "", "path to a file of HTTP endpoints notified of the installs, upgrades, rollbacks, deletions and renames of releases")
+	webhooksQueueSize = flag.Int("webhooks-queue-size", webhook.DefaultQueueSize, "number of notifications queued for each webhook endpoint, beyond which they are dropped")
+
+	traceCollector = flag.String("trace-collector", "", "URL of a collector to which the spans of the calls made to Tiller are sent")
+	traceFile      = flag.String("trace-file", "", "path of a file to which the spans of the calls made to Tiller are appended as JSON, as an alternative to --trace-collector")
+
+	admissionPolicy          = flag.String("admission-policy", "", "path to a file of rules the rendered resources of releases must comply with to be installed or upgraded")
+	admissionPolicyConfigMap = flag.String("admission-policy-configmap", "", "name of a ConfigMap in the namespace of Tiller holding the admission policy under the 'policy.yaml' key, as an alternative to --admission-policy")
+
+	pendingReleases = flag.String("pending-releases", pendingKeep, "what to do on startup with the releases left pending by interrupted operations. One of 'keep', leaving them to 'helm release repair', or 'fail'")
+
 	// rootServer is the root gRPC server.
 	//
 	// Each gRPC service registers itself to this server during start().
@@ -134,6 +172,23 @@ func start() {
 		logger.Fatalf("Cannot initialize Kubernetes connection: %s", err)
 	}
 
+	switch {
+	case *encryptionKeyFile != "" && *encryptionCommand != "":
+		logger.Fatalf("Only one of --storage-encryption-key-file and --storage-encryption-command can be set")
+	case *encryptionKeyFile != "":
+		kp, err := encryption.NewFileKeyProvider(*encryptionKeyFile)
+		if err != nil {
+			logger.Fatalf("Cannot initialize storage encryption: %v", err)
+		}
+		driver.SetKeyProvider(kp)
+	case *encryptionCommand != "":
+		args := strings.Fields(*encryptionCommand)
+		if len(args) == 0 {
+			logger.Fatalf("Cannot initialize storage encryption: --storage-encryption-command has no command")
+		}
+		driver.SetKeyProvider(encryption.NewCommandKeyProvider(args[0], args[1:]...))
+	}
+
 	switch *store {
 	case storageMemory:
 		env.Releases = storage.Init(driver.NewMemory())
@@ -161,11 +216,65 @@ func start() {
 
 		env.Releases = storage.Init(sqlDriver)
 		env.Releases.Log = newLogger("storage").Printf
+	case storageFile:
+		if *storageFileDir == "" {
+			logger.Fatalf("--storage-file-dir must be set with --storage=%s", storageFile)
+		}
+		fileDriver, err := driver.NewFile(*storageFileDir)
+		if err != nil {
+			logger.Fatalf("Cannot initialize file storage driver: %v", err)
+		}
+		fileDriver.Log = newLogger("storage/driver").Printf
+
+		env.Releases = storage.Init(fileDriver)
+		env.Releases.Log = newLogger("storage").Printf
+	}
+
+	env.Releases.Driver = observeStorage(env.Releases.Driver)
+	if err := registerReleaseMetrics(env.Releases); err != nil {
+		logger.Fatalf("Cannot register release metrics: %v", err)
+	}
+
+	if *storageCache && *store != storageMemory {
+		cache := driver.NewCache(env.Releases.Driver)
+		cache.Log = newLogger("storage/cache").Printf
+		cache.PollInterval = *storageCachePollInterval
+		cache.Start(nil)
+		registerStorageCacheMetrics(cache)
+
+		env.Releases.Driver = cache
 	}
 
 	if *maxHistory > 0 {
 		env.Releases.MaxHistory = *maxHistory
 	}
+	env.Releases.KeepHistoryNewerThan = *historyKeepNewerThan
+	env.Releases.PurgeDeletedAfter = *purgeDeletedAfter
+
+	if *pendingReleases != pendingKeep && *pendingReleases != pendingFail {
+		logger.Fatalf("--pending-releases must be one of '%s' or '%s'", pendingKeep, pendingFail)
+	}
+
+	var admissionSource admission.Source
+	switch {
+	case *admissionPolicy != "" && *admissionPolicyConfigMap != "":
+		logger.Fatalf("--admission-policy and --admission-policy-configmap are mutually exclusive")
+	case *admissionPolicy != "":
+		admissionSource = admission.FileSource(*admissionPolicy)
+	case *admissionPolicyConfigMap != "":
+		admissionSource = &admission.ConfigMapSource{
+			ConfigMaps: clientset.CoreV1(),
+			Namespace:  namespace(),
+			Name:       *admissionPolicyConfigMap,
+		}
+	}
+	// the policy is loaded again on every install and upgrade, but a policy
+	// that cannot be loaded is better reported right away
+	if admissionSource != nil {
+		if _, err := admissionSource.Load(); err != nil {
+			logger.Printf("Cannot load admission policy, installs and upgrades will be rejected until it is fixed: %s", err)
+		}
+	}
 
 	kubeClient := kube.New(nil)
 	kubeClient.Log = newLogger("kube").Printf
@@ -195,7 +304,69 @@ func start() {
 		MinTime: time.Duration(20) * time.Second, // For compatibility with the client keepalive.ClientParameters
 	}))
 
-	rootServer = tiller.NewServer(opts...)
+	var srvCfg tiller.ServerConfig
+	if *authzPolicy != "" {
+		if !*tlsVerify {
+			logger.Fatalf("--authz-policy requires --tls-verify, clients being identified by their certificate")
+		}
+		authz, err := tiller.NewAuthorizer(*authzPolicy, env.Releases)
+		if err != nil {
+			logger.Fatalf("Cannot load authorization policy: %s", err)
+		}
+		authz.Log = newLogger("authz").Printf
+		authz.AuditLog = newLogger("authz/audit").Printf
+		if *authzPolicyReloadInterval > 0 {
+			go authz.Watch(*authzPolicyReloadInterval)
+		}
+		srvCfg.Authorizer = authz
+	}
+
+	var auditWriters []audit.Writer
+	if *auditLogFile != "" {
+		w, err := audit.NewFileWriter(*auditLogFile)
+		if err != nil {
+			logger.Fatalf("Cannot open audit log: %s", err)
+		}
+		auditWriters = append(auditWriters, w)
+	}
+	if *auditEvents {
+		auditWriters = append(auditWriters, audit.NewEventWriter(clientset.CoreV1(), namespace()))
+	}
+	if len(auditWriters) > 0 {
+		auditor := tiller.NewAuditor(audit.MultiWriter(auditWriters...), env.Releases)
+		auditor.Log = newLogger("audit").Printf
+		srvCfg.Auditor = auditor
+	}
+
+	if *webhooksConfig != "" {
+		cfg, err := webhook.LoadConfig(*webhooksConfig)
+		if err != nil {
+			logger.Fatalf("Cannot load webhook configuration: %s", err)
+		}
+		dispatcher := webhook.NewDispatcher(cfg, *webhooksQueueSize)
+		dispatcher.Log = newLogger("webhook").Printf
+		dispatcher.Start(nil)
+		srvCfg.Notifier = tiller.NewNotifier(dispatcher, env.Releases)
+	}
+
+	switch {
+	case *traceCollector != "" && *traceFile != "":
+		logger.Fatalf("--trace-collector and --trace-file are mutually exclusive")
+	case *traceCollector != "":
+		exporter := tracing.NewCollectorExporter(*traceCollector)
+		exporter.Log = newLogger("tracing").Printf
+		go exporter.Start(0, nil)
+		srvCfg.Tracer = tracing.NewTracer("tiller", exporter)
+	case *traceFile != "":
+		exporter, err := tracing.NewFileExporter(*traceFile)
+		if err != nil {
+			logger.Fatalf("Cannot open trace file: %s", err)
+		}
+		exporter.Log = newLogger("tracing").Printf
+		srvCfg.Tracer = tracing.NewTracer("tiller", exporter)
+	}
+
+	rootServer = tiller.NewServerWithConfig(srvCfg, opts...)
 	healthpb.RegisterHealthServer(rootServer, healthSrv)
 
 	lstn, err := net.Listen("tcp", *grpcAddr)
@@ -207,17 +378,36 @@ func start() {
 	logger.Printf("GRPC listening on %s", *grpcAddr)
 	logger.Printf("Probes listening on %s", *probeAddr)
 	logger.Printf("Storage driver is %s", env.Releases.Name())
+	logger.Printf("Storage encryption is %t", *encryptionKeyFile != "" || *encryptionCommand != "")
+	logger.Printf("Storage cache is %t", *storageCache && *store != storageMemory)
 	logger.Printf("Max history per release is %d", *maxHistory)
+	logger.Printf("History sweep interval is %s", *historySweepInterval)
+	logger.Printf("Authorization policy is %q", *authzPolicy)
+	logger.Printf("Audit log file is %q, audit events are %t", *auditLogFile, *auditEvents)
+	logger.Printf("Webhook configuration is %q", *webhooksConfig)
+	logger.Printf("Trace collector is %q, trace file is %q", *traceCollector, *traceFile)
+	logger.Printf("Admission policy is %q, admission policy configmap is %q", *admissionPolicy, *admissionPolicyConfigMap)
+	logger.Printf("Pending releases of interrupted operations are handled with %q", *pendingReleases)
 
 	if *enableTracing {
 		startTracing(traceAddr)
 	}
 
+	svc := tiller.NewReleaseServer(env, clientset, *remoteReleaseModules)
+	svc.Log = newLogger("tiller").Printf
+	svc.AdmissionPolicy = admissionSource
+	if srvCfg.Tracer != nil {
+		svc.TraceStorage()
+	}
+
+	if *historySweepInterval > 0 {
+		go sweepHistory(*historySweepInterval)
+	}
+	go recoverPendingReleases(*pendingReleases == pendingFail)
+
 	srvErrCh := make(chan error)
 	probeErrCh := make(chan error)
 	go func() {
-		svc := tiller.NewReleaseServer(env, clientset, *remoteReleaseModules)
-		svc.Log = newLogger("tiller").Printf
 		services.RegisterReleaseServiceServer(rootServer, svc)
 		if err := rootServer.Serve(lstn); err != nil {
 			srvErrCh <- err
@@ -246,6 +436,54 @@ func start() {
 	}
 }
 
+// sweepHistory periodically removes the release revisions that the retention
+// policies no longer keep.
+func sweepHistory(interval time.Duration) {
+	sweeperLogger := newLogger("storage/sweeper")
+	for range time.Tick(interval) {
+		removed, err := env.Releases.SweepHistory()
+		for _, rls := range removed {
+			sweeperLogger.Printf("removed release %s revision %d (%s)", rls.Name, rls.Version, rls.GetInfo().GetStatus().GetCode())
+		}
+		if err != nil {
+			sweeperLogger.Printf("failed to sweep release history: %s", err)
+		}
+	}
+}
+
+// recoverPendingReleases looks for the releases left pending by operations
+// interrupted by a previous run of Tiller, and fails them with repair. The
+// releases still locked by another Tiller, or by a crashed one whose lock has
+// not expired yet, are looked at again once their lock may have expired.
+func recoverPendingReleases(repair bool) {
+	recoveryLogger := newLogger("storage/recovery")
+	ttl := env.Releases.LockTTL
+	if ttl <= 0 {
+		ttl = storage.DefaultLockTTL
+	}
+
+	var names []string
+	for {
+		orphaned, locked, err := env.Releases.RecoverPending(repair, "Tiller restarted", names...)
+		for _, rls := range orphaned {
+			if repair {
+				recoveryLogger.Printf("failed release %s revision %d left pending by an interrupted operation", rls.Name, rls.Version)
+				continue
+			}
+			recoveryLogger.Printf("release %s revision %d was left pending by an interrupted operation, run 'helm release repair %s' to fail it", rls.Name, rls.Version, rls.Name)
+		}
+		if err != nil {
+			recoveryLogger.Printf("failed to recover pending releases: %s", err)
+			return
+		}
+		if len(locked) == 0 {
+			return
+		}
+		names = locked
+		time.Sleep(ttl)
+	}
+}
+
 func newLogger(prefix string) *log.Logger {
 	if len(prefix) > 0 {
 		prefix = fmt.Sprintf("[%s] ", prefix)
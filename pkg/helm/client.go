@@ -25,11 +25,13 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	rls "k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tracing"
 )
 
 // maxMsgSize use 20MB as the default message size limit.
@@ -302,6 +304,139 @@ func (h *Client) RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (<-ch
 	return h.test(ctx, req)
 }
 
+// DiffRelease compares a release with the release an upgrade to a chart would create.
+func (h *Client) DiffRelease(rlsName string, chart *chart.Chart, opts ...DiffOption) (*rls.DiffReleaseResponse, error) {
+	reqOpts := h.opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.diffReq
+	req.Name = rlsName
+	req.Chart = chart
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	err := chartutil.ProcessRequirementsEnabled(req.Chart, req.Values)
+	if err != nil {
+		return nil, err
+	}
+	err = chartutil.ProcessRequirementsImportValues(req.Chart)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.diff(ctx, req)
+}
+
+// WatchReleases streams the events of releases until stop is closed or the
+// watch fails.
+func (h *Client) WatchReleases(stop <-chan struct{}, opts ...WatchOption) (<-chan *rls.ReleaseEvent, <-chan error) {
+	reqOpts := h.opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+
+	req := &reqOpts.watchReq
+	ctx := NewContext()
+
+	return h.watch(ctx, req, stop)
+}
+
+// CancelOperation cancels the install, upgrade or rollback in progress on a release.
+func (h *Client) CancelOperation(rlsName string, opts ...CancelOption) (*rls.CancelOperationResponse, error) {
+	reqOpts := h.opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.cancelReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.cancel(ctx, req)
+}
+
+// RepairRelease fails the revision of a release left pending by an interrupted operation.
+func (h *Client) RepairRelease(rlsName string, opts ...RepairOption) (*rls.RepairReleaseResponse, error) {
+	reqOpts := h.opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.repairReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.repair(ctx, req)
+}
+
+// CheckDrift compares the deployed manifest of a release with the live state of its resources.
+func (h *Client) CheckDrift(rlsName string, opts ...DriftOption) (*rls.CheckDriftResponse, error) {
+	reqOpts := h.opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.driftReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.drift(ctx, req)
+}
+
+// RenameRelease renames a release to newName, or moves it to another namespace if newName is empty.
+func (h *Client) RenameRelease(rlsName, newName string, opts ...RenameOption) (*rls.RenameReleaseResponse, error) {
+	reqOpts := h.opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.renameReq
+	req.Name = rlsName
+	req.NewName = newName
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.rename(ctx, req)
+}
+
+// ApplyReleaseSet installs or upgrades releases, in the order of their dependencies.
+func (h *Client) ApplyReleaseSet(releases []*rls.ReleaseSetMember, opts ...ReleaseSetOption) (*rls.ApplyReleaseSetResponse, error) {
+	reqOpts := h.opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.setReq
+	req.Releases = releases
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.applySet(ctx, req)
+}
+
 // PingTiller pings the Tiller pod and ensures that it is up and running
 func (h *Client) PingTiller() error {
 	ctx := NewContext()
@@ -326,6 +461,12 @@ func (h *Client) connect(ctx context.Context) (conn *grpc.ClientConn, err error)
 	default:
 		opts = append(opts, grpc.WithInsecure())
 	}
+	if h.opts.trace.IsValid() {
+		opts = append(opts,
+			grpc.WithUnaryInterceptor(h.traceUnary),
+			grpc.WithStreamInterceptor(h.traceStream),
+		)
+	}
 	ctx, cancel := context.WithTimeout(ctx, h.opts.connectTimeout)
 	defer cancel()
 	if conn, err = grpc.DialContext(ctx, h.opts.host, opts...); err != nil {
@@ -334,6 +475,19 @@ func (h *Client) connect(ctx context.Context) (conn *grpc.ClientConn, err error)
 	return conn, nil
 }
 
+// traceUnary sends the context of the span the calls are made in to Tiller,
+// next to the version of the client.
+func (h *Client) traceUnary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, tracing.MetadataKey, h.opts.trace.TraceParent())
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// traceStream is the stream counterpart of traceUnary.
+func (h *Client) traceStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, tracing.MetadataKey, h.opts.trace.TraceParent())
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
 // list executes tiller.ListReleases RPC.
 func (h *Client) list(ctx context.Context, req *rls.ListReleasesRequest) (*rls.ListReleasesResponse, error) {
 	c, err := h.connect(ctx)
@@ -401,6 +555,78 @@ func (h *Client) update(ctx context.Context, req *rls.UpdateReleaseRequest) (*rl
 	return rlc.UpdateRelease(ctx, req)
 }
 
+// diff executes tiller.DiffRelease RPC.
+func (h *Client) diff(ctx context.Context, req *rls.DiffReleaseRequest) (*rls.DiffReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.DiffRelease(ctx, req)
+}
+
+// cancel executes tiller.CancelOperation RPC.
+func (h *Client) cancel(ctx context.Context, req *rls.CancelOperationRequest) (*rls.CancelOperationResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.CancelOperation(ctx, req)
+}
+
+// repair executes tiller.RepairRelease RPC.
+func (h *Client) repair(ctx context.Context, req *rls.RepairReleaseRequest) (*rls.RepairReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.RepairRelease(ctx, req)
+}
+
+// drift executes tiller.CheckDrift RPC.
+func (h *Client) drift(ctx context.Context, req *rls.CheckDriftRequest) (*rls.CheckDriftResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.CheckDrift(ctx, req)
+}
+
+// rename executes tiller.RenameRelease RPC.
+func (h *Client) rename(ctx context.Context, req *rls.RenameReleaseRequest) (*rls.RenameReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.RenameRelease(ctx, req)
+}
+
+// applySet executes tiller.ApplyReleaseSet RPC.
+func (h *Client) applySet(ctx context.Context, req *rls.ApplyReleaseSetRequest) (*rls.ApplyReleaseSetResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.ApplyReleaseSet(ctx, req)
+}
+
 // rollback executes tiller.RollbackRelease RPC.
 func (h *Client) rollback(ctx context.Context, req *rls.RollbackReleaseRequest) (*rls.RollbackReleaseResponse, error) {
 	c, err := h.connect(ctx)
@@ -499,6 +725,63 @@ func (h *Client) test(ctx context.Context, req *rls.TestReleaseRequest) (<-chan
 	return ch, errc
 }
 
+// watch executes tiller.WatchReleases RPC.
+func (h *Client) watch(ctx context.Context, req *rls.WatchReleasesRequest, stop <-chan struct{}) (<-chan *rls.ReleaseEvent, <-chan error) {
+	errc := make(chan error, 1)
+	ctx, cancel := context.WithCancel(ctx)
+	c, err := h.connect(ctx)
+	if err != nil {
+		cancel()
+		errc <- err
+		return nil, errc
+	}
+
+	go func() {
+		select {
+		case <-stop:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	ch := make(chan *rls.ReleaseEvent, 1)
+	go func() {
+		defer close(errc)
+		defer close(ch)
+		defer c.Close()
+		defer cancel()
+
+		rlc := rls.NewReleaseServiceClient(c)
+		s, err := rlc.WatchReleases(ctx, req)
+		if err != nil {
+			errc <- err
+			return
+		}
+
+		for {
+			msg, err := s.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				// the watch was stopped
+				if ctx.Err() != nil {
+					return
+				}
+				errc <- err
+				return
+			}
+			select {
+			case ch <- msg.Event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, errc
+}
+
 // ping executes tiller.Ping RPC.
 func (h *Client) ping(ctx context.Context) error {
 	c, err := h.connect(ctx)
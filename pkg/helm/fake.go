@@ -19,6 +19,7 @@ package helm // import "k8s.io/helm/pkg/helm"
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math/rand"
 	"strings"
 	"sync"
@@ -40,6 +41,13 @@ type FakeClient struct {
 	Responses       map[string]release.TestRun_Status
 	Opts            options
 	RenderManifests bool
+	// Diffs are the resource diffs returned by DiffRelease.
+	Diffs []*rls.ResourceDiff
+	// Drifts are the resource drifts returned by CheckDrift.
+	Drifts []*rls.ResourceDrift
+	// Adopted are the existing resources returned as adopted by installs and
+	// upgrades adopting existing resources.
+	Adopted []*rls.AdoptedResource
 }
 
 // Option returns the fake release client
@@ -122,9 +130,11 @@ func (c *FakeClient) InstallReleaseFromChart(chart *chart.Chart, ns string, opts
 		c.Rels = append(c.Rels, release)
 	}
 
-	return &rls.InstallReleaseResponse{
-		Release: release,
-	}, nil
+	res := &rls.InstallReleaseResponse{Release: release}
+	if c.Opts.instReq.AdoptExisting {
+		res.Adopted = c.Adopted
+	}
+	return res, nil
 }
 
 // DeleteRelease deletes a release from the FakeClient
@@ -193,7 +203,11 @@ func (c *FakeClient) UpdateReleaseFromChart(rlsName string, newChart *chart.Char
 		*rel.Release = *newRelease
 	}
 
-	return &rls.UpdateReleaseResponse{Release: newRelease}, nil
+	res := &rls.UpdateReleaseResponse{Release: newRelease}
+	if c.Opts.updateReq.AdoptExisting {
+		res.Adopted = c.Adopted
+	}
+	return res, nil
 }
 
 // RollbackRelease returns nil, nil
@@ -257,6 +271,201 @@ func (c *FakeClient) RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (
 	return results, errc
 }
 
+// DiffRelease returns the release an upgrade of the matching release to newChart would create.
+func (c *FakeClient) DiffRelease(rlsName string, newChart *chart.Chart, opts ...DiffOption) (*rls.DiffReleaseResponse, error) {
+	for _, opt := range opts {
+		opt(&c.Opts)
+	}
+	rel, err := c.ReleaseContent(rlsName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	newRelease := ReleaseMock(&MockReleaseOptions{
+		Name:      rel.Release.Name,
+		Version:   rel.Release.Version + 1,
+		Chart:     newChart,
+		Config:    c.Opts.diffReq.Values,
+		Namespace: rel.Release.Namespace,
+	})
+	return &rls.DiffReleaseResponse{Release: newRelease, Resources: c.Diffs}, nil
+}
+
+// WatchReleases sends an event with the status of each matching release, then
+// waits for stop to be closed.
+func (c *FakeClient) WatchReleases(stop <-chan struct{}, opts ...WatchOption) (<-chan *rls.ReleaseEvent, <-chan error) {
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := reqOpts.watchReq
+
+	events := make(chan *rls.ReleaseEvent)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(events)
+
+		for _, rel := range c.Rels {
+			if (req.Name != "" && rel.Name != req.Name) || (req.Namespace != "" && rel.Namespace != req.Namespace) {
+				continue
+			}
+			ev := &rls.ReleaseEvent{
+				Type:      rls.ReleaseEvent_STATUS_CHANGED,
+				Name:      rel.Name,
+				Namespace: rel.Namespace,
+				Version:   rel.Version,
+				Status:    rel.GetInfo().GetStatus().GetCode(),
+			}
+			select {
+			case events <- ev:
+			case <-stop:
+				return
+			}
+		}
+		<-stop
+	}()
+
+	return events, errc
+}
+
+// CancelOperation marks the matching release as failed if an operation is in
+// progress on it.
+func (c *FakeClient) CancelOperation(rlsName string, opts ...CancelOption) (*rls.CancelOperationResponse, error) {
+	for _, opt := range opts {
+		opt(&c.Opts)
+	}
+	rel, err := c.ReleaseContent(rlsName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	code := rel.Release.Info.Status.Code
+	switch code {
+	case release.Status_PENDING_INSTALL, release.Status_PENDING_UPGRADE, release.Status_PENDING_ROLLBACK:
+	default:
+		return nil, fmt.Errorf("no operation is in progress for release %q", rlsName)
+	}
+	operation := strings.ToLower(strings.TrimPrefix(code.String(), "PENDING_"))
+	rel.Release.Info.Status.Code = release.Status_FAILED
+	rel.Release.Info.Description = strings.Title(operation) + " cancelled"
+	return &rls.CancelOperationResponse{Release: rel.Release}, nil
+}
+
+// RepairRelease marks the matching release as failed if it is pending.
+func (c *FakeClient) RepairRelease(rlsName string, opts ...RepairOption) (*rls.RepairReleaseResponse, error) {
+	for _, opt := range opts {
+		opt(&c.Opts)
+	}
+	rel, err := c.ReleaseContent(rlsName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	code := rel.Release.Info.Status.Code
+	switch code {
+	case release.Status_PENDING_INSTALL, release.Status_PENDING_UPGRADE, release.Status_PENDING_ROLLBACK:
+	default:
+		return nil, fmt.Errorf("release %q is not pending at revision %d: nothing to repair", rlsName, rel.Release.Version)
+	}
+	operation := strings.ToLower(strings.TrimPrefix(code.String(), "PENDING_"))
+	rel.Release.Info.Status.Code = release.Status_FAILED
+	rel.Release.Info.Description = strings.Title(operation) + " interrupted: repaired on request"
+	return &rls.RepairReleaseResponse{Release: rel.Release}, nil
+}
+
+// CheckDrift returns the drifts of the fake client for the matching release.
+func (c *FakeClient) CheckDrift(rlsName string, opts ...DriftOption) (*rls.CheckDriftResponse, error) {
+	for _, opt := range opts {
+		opt(&c.Opts)
+	}
+	rel, err := c.ReleaseContent(rlsName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rls.CheckDriftResponse{
+		Release:    rel.Release,
+		Resources:  c.Drifts,
+		Checked:    int32(len(c.Drifts)),
+		Reconciled: c.Opts.driftReq.Reconcile && len(c.Drifts) > 0,
+	}, nil
+}
+
+// RenameRelease renames the matching release, and moves it to the requested namespace, if any.
+func (c *FakeClient) RenameRelease(rlsName, newName string, opts ...RenameOption) (*rls.RenameReleaseResponse, error) {
+	for _, opt := range opts {
+		opt(&c.Opts)
+	}
+	rel, err := c.ReleaseContent(rlsName, nil)
+	if err != nil {
+		return nil, err
+	}
+	if newName == "" {
+		newName = rlsName
+	}
+	if newName != rlsName {
+		if _, err := c.ReleaseContent(newName, nil); err == nil {
+			return nil, fmt.Errorf("cannot rename %q: a release named %q already exists", rlsName, newName)
+		}
+	}
+
+	namespace := c.Opts.renameReq.Namespace
+	if namespace == "" {
+		namespace = rel.Release.Namespace
+	}
+	renamed := ReleaseMock(&MockReleaseOptions{
+		Name:      newName,
+		Version:   rel.Release.Version + 1,
+		Chart:     rel.Release.Chart,
+		Config:    rel.Release.Config,
+		Namespace: namespace,
+	})
+	if !c.Opts.renameReq.DryRun {
+		rels := make([]*release.Release, 0, len(c.Rels)+1)
+		for _, r := range c.Rels {
+			if r.Name == rlsName {
+				moved := *r
+				moved.Name = newName
+				r = &moved
+			}
+			rels = append(rels, r)
+		}
+		c.Rels = append(rels, renamed)
+	}
+	return &rls.RenameReleaseResponse{Release: renamed}, nil
+}
+
+// ApplyReleaseSet applies the releases of the set in order, installing the
+// ones not in the fake client.
+func (c *FakeClient) ApplyReleaseSet(releases []*rls.ReleaseSetMember, opts ...ReleaseSetOption) (*rls.ApplyReleaseSetResponse, error) {
+	for _, opt := range opts {
+		opt(&c.Opts)
+	}
+	res := &rls.ApplyReleaseSetResponse{}
+	for _, m := range releases {
+		result := &rls.ReleaseSetResult{Name: m.Name, Status: rls.ReleaseSetResult_APPLIED}
+		version := int32(1)
+		if rel, err := c.ReleaseContent(m.Name, nil); err == nil {
+			version = rel.Release.Version + 1
+		} else {
+			result.Installed = true
+		}
+		result.Release = ReleaseMock(&MockReleaseOptions{
+			Name:      m.Name,
+			Version:   version,
+			Chart:     m.Chart,
+			Namespace: m.Namespace,
+		})
+		if !c.Opts.setReq.DryRun && result.Installed {
+			c.Rels = append(c.Rels, result.Release)
+		}
+		res.Results = append(res.Results, result)
+	}
+	return res, nil
+}
+
 // PingTiller pings the Tiller pod and ensures that it is up and running
 func (c *FakeClient) PingTiller() error {
 	return nil
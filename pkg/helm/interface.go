@@ -35,5 +35,12 @@ type Interface interface {
 	ReleaseHistory(rlsName string, opts ...HistoryOption) (*rls.GetHistoryResponse, error)
 	GetVersion(opts ...VersionOption) (*rls.GetVersionResponse, error)
 	RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (<-chan *rls.TestReleaseResponse, <-chan error)
+	DiffRelease(rlsName string, chart *chart.Chart, opts ...DiffOption) (*rls.DiffReleaseResponse, error)
+	WatchReleases(stop <-chan struct{}, opts ...WatchOption) (<-chan *rls.ReleaseEvent, <-chan error)
+	CancelOperation(rlsName string, opts ...CancelOption) (*rls.CancelOperationResponse, error)
+	RepairRelease(rlsName string, opts ...RepairOption) (*rls.RepairReleaseResponse, error)
+	CheckDrift(rlsName string, opts ...DriftOption) (*rls.CheckDriftResponse, error)
+	RenameRelease(rlsName, newName string, opts ...RenameOption) (*rls.RenameReleaseResponse, error)
+	ApplyReleaseSet(releases []*rls.ReleaseSetMember, opts ...ReleaseSetOption) (*rls.ApplyReleaseSetResponse, error)
 	PingTiller() error
 }
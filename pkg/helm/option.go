@@ -27,6 +27,7 @@ import (
 	cpb "k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	rls "k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tracing"
 	"k8s.io/helm/pkg/version"
 )
 
@@ -83,6 +84,22 @@ type options struct {
 	testReq rls.TestReleaseRequest
 	// connectTimeout specifies the time duration Helm will wait to establish a connection to tiller
 	connectTimeout time.Duration
+	// release diff options are applied directly to the diff release request
+	diffReq rls.DiffReleaseRequest
+	// release watch options are applied directly to the watch releases request
+	watchReq rls.WatchReleasesRequest
+	// cancel operation options are applied directly to the cancel operation request
+	cancelReq rls.CancelOperationRequest
+	// release repair options are applied directly to the repair release request
+	repairReq rls.RepairReleaseRequest
+	// drift check options are applied directly to the check drift request
+	driftReq rls.CheckDriftRequest
+	// release rename options are applied directly to the rename release request
+	renameReq rls.RenameReleaseRequest
+	// release set options are applied directly to the apply release set request
+	setReq rls.ApplyReleaseSetRequest
+	// trace is the context of the span the calls are made in, if valid
+	trace tracing.SpanContext
 }
 
 // Host specifies the host address of the Tiller release server, (default = ":44134").
@@ -192,6 +209,14 @@ func ConnectTimeout(timeout int64) Option {
 	}
 }
 
+// WithTrace specifies the span the rpc's are made in. Its context is sent to
+// Tiller, which traces the calls as children of it.
+func WithTrace(span tracing.SpanContext) Option {
+	return func(opts *options) {
+		opts.trace = span
+	}
+}
+
 // InstallTimeout specifies the number of seconds before kubernetes calls timeout
 func InstallTimeout(timeout int64) InstallOption {
 	return func(opts *options) {
@@ -374,6 +399,22 @@ func UpgradeSubNotes(enable bool) UpdateOption {
 	}
 }
 
+// InstallAdoptExisting will (if true) instruct Tiller to adopt the resources
+// of the chart which already exist in the cluster outside of any release.
+func InstallAdoptExisting(adopt bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.AdoptExisting = adopt
+	}
+}
+
+// UpgradeAdoptExisting will (if true) instruct Tiller to adopt the resources
+// of the chart which already exist in the cluster outside of any release.
+func UpgradeAdoptExisting(adopt bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.AdoptExisting = adopt
+	}
+}
+
 // RollbackDisableHooks will disable hooks for a rollback operation
 func RollbackDisableHooks(disable bool) RollbackOption {
 	return func(opts *options) {
@@ -514,3 +555,165 @@ func NewContext() context.Context {
 // ReleaseTestOption allows configuring optional request data for
 // issuing a TestRelease rpc.
 type ReleaseTestOption func(*options)
+
+// DiffOption allows configuring optional request data for
+// issuing a DiffRelease rpc.
+type DiffOption func(*options)
+
+// DiffValueOverrides specifies a list of values to include when diffing an upgrade.
+func DiffValueOverrides(raw []byte) DiffOption {
+	return func(opts *options) {
+		opts.diffReq.Values = &cpb.Config{Raw: string(raw)}
+	}
+}
+
+// DiffResetValues will (if true) diff against the values of the chart instead of the values of the release.
+func DiffResetValues(reset bool) DiffOption {
+	return func(opts *options) {
+		opts.diffReq.ResetValues = reset
+	}
+}
+
+// DiffReuseValues will cause Tiller to reuse the values from the last release in the diff.
+// This is ignored if DiffResetValues is true.
+func DiffReuseValues(reuse bool) DiffOption {
+	return func(opts *options) {
+		opts.diffReq.ReuseValues = reuse
+	}
+}
+
+// DiffLive will (if true) also diff the proposed resources against their state in the cluster.
+func DiffLive(live bool) DiffOption {
+	return func(opts *options) {
+		opts.diffReq.Live = live
+	}
+}
+
+// DiffContext specifies the number of unchanged lines shown around each change.
+func DiffContext(lines int32) DiffOption {
+	return func(opts *options) {
+		opts.diffReq.Context = lines
+	}
+}
+
+// WatchOption allows configuring optional request data for
+// issuing a WatchReleases rpc.
+type WatchOption func(*options)
+
+// WatchReleaseName restricts the events watched to the ones of the named release.
+func WatchReleaseName(name string) WatchOption {
+	return func(opts *options) {
+		opts.watchReq.Name = name
+	}
+}
+
+// WatchReleaseNamespace restricts the events watched to the ones of the releases in the namespace.
+func WatchReleaseNamespace(namespace string) WatchOption {
+	return func(opts *options) {
+		opts.watchReq.Namespace = namespace
+	}
+}
+
+// CancelOption allows configuring optional request data for
+// issuing a CancelOperation rpc.
+type CancelOption func(*options)
+
+// CancelCleanup will (if true) delete the new resources created by the cancelled operation.
+func CancelCleanup(cleanup bool) CancelOption {
+	return func(opts *options) {
+		opts.cancelReq.Cleanup = cleanup
+	}
+}
+
+// RepairOption allows configuring optional request data for
+// issuing a RepairRelease rpc.
+type RepairOption func(*options)
+
+// DriftOption allows configuring optional request data for
+// issuing a CheckDrift rpc.
+type DriftOption func(*options)
+
+// DriftReconcile will (if true) re-apply the deployed manifest to the resources that drifted.
+func DriftReconcile(reconcile bool) DriftOption {
+	return func(opts *options) {
+		opts.driftReq.Reconcile = reconcile
+	}
+}
+
+// RenameOption allows configuring optional request data for
+// issuing a RenameRelease rpc.
+type RenameOption func(*options)
+
+// RenameNamespace moves the release to namespace.
+func RenameNamespace(namespace string) RenameOption {
+	return func(opts *options) {
+		opts.renameReq.Namespace = namespace
+	}
+}
+
+// RenameDryRun will (if true) render the renamed release without changing it.
+func RenameDryRun(dry bool) RenameOption {
+	return func(opts *options) {
+		opts.renameReq.DryRun = dry
+	}
+}
+
+// RenameDisableHooks will disable the upgrade hooks of a rename.
+func RenameDisableHooks(disable bool) RenameOption {
+	return func(opts *options) {
+		opts.renameReq.DisableHooks = disable
+	}
+}
+
+// RenameTimeout specifies the number of seconds before kubernetes calls timeout
+func RenameTimeout(timeout int64) RenameOption {
+	return func(opts *options) {
+		opts.renameReq.Timeout = timeout
+	}
+}
+
+// RenameWait specifies whether or not to wait for all resources to be ready
+func RenameWait(wait bool) RenameOption {
+	return func(opts *options) {
+		opts.renameReq.Wait = wait
+	}
+}
+
+// ReleaseSetOption allows configuring optional request data for
+// issuing an ApplyReleaseSet rpc.
+type ReleaseSetOption func(*options)
+
+// ReleaseSetDryRun will (if true) render the releases of the set without applying them.
+func ReleaseSetDryRun(dry bool) ReleaseSetOption {
+	return func(opts *options) {
+		opts.setReq.DryRun = dry
+	}
+}
+
+// ReleaseSetTimeout specifies the number of seconds before kubernetes calls timeout
+func ReleaseSetTimeout(timeout int64) ReleaseSetOption {
+	return func(opts *options) {
+		opts.setReq.Timeout = timeout
+	}
+}
+
+// ReleaseSetWait specifies whether or not to wait for all resources to be ready
+func ReleaseSetWait(wait bool) ReleaseSetOption {
+	return func(opts *options) {
+		opts.setReq.Wait = wait
+	}
+}
+
+// ReleaseSetAtomic will (if true) roll back the releases of the set applied when one fails.
+func ReleaseSetAtomic(atomic bool) ReleaseSetOption {
+	return func(opts *options) {
+		opts.setReq.Atomic = atomic
+	}
+}
+
+// ReleaseSetParallelism limits the number of releases of the set applied at once.
+func ReleaseSetParallelism(n int32) ReleaseSetOption {
+	return func(opts *options) {
+		opts.setReq.Parallelism = n
+	}
+}
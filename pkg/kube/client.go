@@ -73,6 +73,9 @@ var metadataAccessor = meta.NewAccessor()
 type Client struct {
 	cmdutil.Factory
 	Log func(string, ...interface{})
+
+	// ctx interrupts the waits of the client once done.
+	ctx context.Context
 }
 
 // New creates a new Client.
@@ -94,6 +97,49 @@ func New(getter genericclioptions.RESTClientGetter) *Client {
 
 var nopLogger = func(_ string, _ ...interface{}) {}
 
+// WithContext returns a copy of the client whose waits for resources, hooks
+// and deletions end with an error as soon as ctx is done.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	cc := *c
+	cc.ctx = ctx
+	return &cc
+}
+
+// waitContext returns the context of a wait of the client ending after
+// timeout, or never if timeout is zero, unless the context of the client is
+// done first.
+func (c *Client) waitContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	parent := c.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	return watchtools.ContextWithOptionalTimeout(parent, timeout)
+}
+
+// poll runs condition every interval, starting immediately if immediate is
+// set, until it is done or fails, or until the wait of the client for timeout
+// ends.
+func (c *Client) poll(interval, timeout time.Duration, immediate bool, condition wait.ConditionFunc) error {
+	ctx, cancel := c.waitContext(timeout)
+	defer cancel()
+	var err error
+	if immediate {
+		err = wait.PollImmediateUntil(interval, condition, ctx.Done())
+	} else {
+		err = wait.PollUntil(interval, condition, ctx.Done())
+	}
+	return c.interrupted(err)
+}
+
+// interrupted returns the error of a wait ending with err, telling apart the
+// waits interrupted by the context of the client.
+func (c *Client) interrupted(err error) error {
+	if err != nil && c.ctx != nil && c.ctx.Err() != nil {
+		return fmt.Errorf("wait interrupted: %s", c.ctx.Err())
+	}
+	return err
+}
+
 // ResourceActorFunc performs an action on a single resource.
 type ResourceActorFunc func(*resource.Info) error
 
@@ -499,7 +545,7 @@ func (c *Client) DeleteWithTimeout(namespace string, reader io.Reader, timeout i
 
 	if shouldWait {
 		c.Log("Waiting for %d seconds for delete to be completed", timeout)
-		return waitUntilAllResourceDeleted(infos, time.Duration(timeout)*time.Second)
+		return c.waitUntilAllResourceDeleted(infos, time.Duration(timeout)*time.Second)
 	}
 
 	return nil
@@ -513,8 +559,8 @@ func (c *Client) skipIfNotFound(err error) error {
 	return err
 }
 
-func waitUntilAllResourceDeleted(infos Result, timeout time.Duration) error {
-	return wait.Poll(2*time.Second, timeout, func() (bool, error) {
+func (c *Client) waitUntilAllResourceDeleted(infos Result, timeout time.Duration) error {
+	return c.poll(2*time.Second, timeout, false, func() (bool, error) {
 		allDeleted := true
 		err := perform(infos, func(info *resource.Info) error {
 			innerErr := info.Get()
@@ -582,7 +628,7 @@ func (c *Client) pollCRDEstablished(t time.Duration) ResourceActorFunc {
 }
 
 func (c *Client) pollCRDUntilEstablished(timeout time.Duration, info *resource.Info) error {
-	return wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+	return c.poll(time.Second, timeout, true, func() (bool, error) {
 		err := info.Get()
 		if err != nil {
 			return false, fmt.Errorf("unable to get CRD: %v", err)
@@ -823,7 +869,7 @@ func (c *Client) watchUntilReady(timeout time.Duration, info *resource.Info) err
 	// In the future, we might want to add some special logic for types
 	// like Ingress, Volume, etc.
 
-	ctx, cancel := watchtools.ContextWithOptionalTimeout(context.Background(), timeout)
+	ctx, cancel := c.waitContext(timeout)
 	defer cancel()
 	_, err := watchtools.ListWatchUntil(ctx, lw, func(e watch.Event) (bool, error) {
 		switch e.Type {
@@ -848,7 +894,7 @@ func (c *Client) watchUntilReady(timeout time.Duration, info *resource.Info) err
 			return false, nil
 		}
 	})
-	return err
+	return c.interrupted(err)
 }
 
 // waitForJob is a helper that waits for a job to complete.
@@ -916,13 +962,13 @@ func (c *Client) watchPodUntilComplete(timeout time.Duration, info *resource.Inf
 	lw := cachetools.NewListWatchFromClient(info.Client, info.Mapping.Resource.Resource, info.Namespace, fields.Everything())
 
 	c.Log("Watching pod %s for completion with timeout of %v", info.Name, timeout)
-	ctx, cancel := watchtools.ContextWithOptionalTimeout(context.Background(), timeout)
+	ctx, cancel := c.waitContext(timeout)
 	defer cancel()
 	_, err := watchtools.ListWatchUntil(ctx, lw, func(e watch.Event) (bool, error) {
 		return isPodComplete(e)
 	})
 
-	return err
+	return c.interrupted(err)
 }
 
 func isPodComplete(event watch.Event) (bool, error) {
@@ -18,6 +18,7 @@ package kube
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -574,6 +575,32 @@ func TestWaitUntilCRDEstablished(t *testing.T) {
 	}
 }
 
+func TestWaitUntilCRDEstablishedInterrupted(t *testing.T) {
+	c := newTestClient()
+	defer c.Cleanup()
+
+	crd := newCrdWithStatus("name", apiextv1beta1.CustomResourceDefinitionStatus{})
+	c.TestFactory.UnstructuredClient = &fake.RESTClient{
+		GroupVersion:         schema.GroupVersion{Version: "v1"},
+		NegotiatedSerializer: unstructuredSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			return newResponse(200, &crd)
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := c.WithContext(ctx).WaitUntilCRDEstablished(strings.NewReader(crdManifest), time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "wait interrupted") {
+		t.Errorf("expected the wait to be interrupted, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the wait to end when its context is done, took %v", elapsed)
+	}
+}
+
 func newCrdWithStatus(name string, status apiextv1beta1.CustomResourceDefinitionStatus) apiextv1beta1.CustomResourceDefinition {
 	crd := apiextv1beta1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
@@ -27,7 +27,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	deploymentutil "k8s.io/kubernetes/pkg/controller/deployment/util"
 )
@@ -47,7 +46,7 @@ func (c *Client) waitForResources(timeout time.Duration, created Result) error {
 	if err != nil {
 		return err
 	}
-	return wait.Poll(2*time.Second, timeout, func() (bool, error) {
+	return c.poll(2*time.Second, timeout, false, func() (bool, error) {
 		pods := []v1.Pod{}
 		services := []v1.Service{}
 		pvc := []v1.PersistentVolumeClaim{}
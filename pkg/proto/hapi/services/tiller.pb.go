@@ -9,6 +9,7 @@ import math "math"
 import chart "k8s.io/helm/pkg/proto/hapi/chart"
 import release "k8s.io/helm/pkg/proto/hapi/release"
 import version "k8s.io/helm/pkg/proto/hapi/version"
+import timestamp "github.com/golang/protobuf/ptypes/timestamp"
 
 import (
 	context "golang.org/x/net/context"
@@ -53,7 +54,7 @@ func (x ListSort_SortBy) String() string {
 	return proto.EnumName(ListSort_SortBy_name, int32(x))
 }
 func (ListSort_SortBy) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{1, 0}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{1, 0}
 }
 
 // SortOrder defines sort orders to augment sorting operations.
@@ -77,7 +78,124 @@ func (x ListSort_SortOrder) String() string {
 	return proto.EnumName(ListSort_SortOrder_name, int32(x))
 }
 func (ListSort_SortOrder) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{1, 1}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{1, 1}
+}
+
+type ResourceDiff_Change int32
+
+const (
+	// UNCHANGED indicates that the resource is not changed.
+	ResourceDiff_UNCHANGED ResourceDiff_Change = 0
+	// ADDED indicates that the resource is created.
+	ResourceDiff_ADDED ResourceDiff_Change = 1
+	// REMOVED indicates that the resource is deleted.
+	ResourceDiff_REMOVED ResourceDiff_Change = 2
+	// MODIFIED indicates that the resource is changed.
+	ResourceDiff_MODIFIED ResourceDiff_Change = 3
+)
+
+var ResourceDiff_Change_name = map[int32]string{
+	0: "UNCHANGED",
+	1: "ADDED",
+	2: "REMOVED",
+	3: "MODIFIED",
+}
+var ResourceDiff_Change_value = map[string]int32{
+	"UNCHANGED": 0,
+	"ADDED":     1,
+	"REMOVED":   2,
+	"MODIFIED":  3,
+}
+
+func (x ResourceDiff_Change) String() string {
+	return proto.EnumName(ResourceDiff_Change_name, int32(x))
+}
+func (ResourceDiff_Change) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{22, 0}
+}
+
+type ReleaseEvent_Type int32
+
+const (
+	// UNKNOWN indicates an unknown change.
+	ReleaseEvent_UNKNOWN ReleaseEvent_Type = 0
+	// CREATED indicates that a revision was recorded, e.g. that an install, upgrade or rollback started.
+	ReleaseEvent_CREATED ReleaseEvent_Type = 1
+	// STATUS_CHANGED indicates that the status of a revision changed, e.g. to DEPLOYED or FAILED.
+	ReleaseEvent_STATUS_CHANGED ReleaseEvent_Type = 2
+	// SUPERSEDED indicates that a revision was superseded by a newer one.
+	ReleaseEvent_SUPERSEDED ReleaseEvent_Type = 3
+	// DELETED indicates that a revision was deleted from Kubernetes.
+	ReleaseEvent_DELETED ReleaseEvent_Type = 4
+	// PURGED indicates that the record of a revision was removed from storage.
+	ReleaseEvent_PURGED ReleaseEvent_Type = 5
+	// HOOK_RAN indicates that a hook of the release ran.
+	ReleaseEvent_HOOK_RAN ReleaseEvent_Type = 6
+)
+
+var ReleaseEvent_Type_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "CREATED",
+	2: "STATUS_CHANGED",
+	3: "SUPERSEDED",
+	4: "DELETED",
+	5: "PURGED",
+	6: "HOOK_RAN",
+}
+var ReleaseEvent_Type_value = map[string]int32{
+	"UNKNOWN":        0,
+	"CREATED":        1,
+	"STATUS_CHANGED": 2,
+	"SUPERSEDED":     3,
+	"DELETED":        4,
+	"PURGED":         5,
+	"HOOK_RAN":       6,
+}
+
+func (x ReleaseEvent_Type) String() string {
+	return proto.EnumName(ReleaseEvent_Type_name, int32(x))
+}
+func (ReleaseEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{25, 0}
+}
+
+type ReleaseSetResult_Status int32
+
+const (
+	// UNKNOWN indicates that the release was not applied.
+	ReleaseSetResult_UNKNOWN ReleaseSetResult_Status = 0
+	// APPLIED indicates that the release was installed or upgraded.
+	ReleaseSetResult_APPLIED ReleaseSetResult_Status = 1
+	// FAILED indicates that the release failed to install or upgrade.
+	ReleaseSetResult_FAILED ReleaseSetResult_Status = 2
+	// SKIPPED indicates that the release was not applied because a release
+	// of the set failed.
+	ReleaseSetResult_SKIPPED ReleaseSetResult_Status = 3
+	// ROLLED_BACK indicates that the release was applied, then rolled back
+	// because a release of the set failed.
+	ReleaseSetResult_ROLLED_BACK ReleaseSetResult_Status = 4
+)
+
+var ReleaseSetResult_Status_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "APPLIED",
+	2: "FAILED",
+	3: "SKIPPED",
+	4: "ROLLED_BACK",
+}
+var ReleaseSetResult_Status_value = map[string]int32{
+	"UNKNOWN":     0,
+	"APPLIED":     1,
+	"FAILED":      2,
+	"SKIPPED":     3,
+	"ROLLED_BACK": 4,
+}
+
+func (x ReleaseSetResult_Status) String() string {
+	return proto.EnumName(ReleaseSetResult_Status_name, int32(x))
+}
+func (ReleaseSetResult_Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{40, 0}
 }
 
 // ListReleasesRequest requests a list of releases.
@@ -114,7 +232,7 @@ func (m *ListReleasesRequest) Reset()         { *m = ListReleasesRequest{} }
 func (m *ListReleasesRequest) String() string { return proto.CompactTextString(m) }
 func (*ListReleasesRequest) ProtoMessage()    {}
 func (*ListReleasesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{0}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{0}
 }
 func (m *ListReleasesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListReleasesRequest.Unmarshal(m, b)
@@ -194,7 +312,7 @@ func (m *ListSort) Reset()         { *m = ListSort{} }
 func (m *ListSort) String() string { return proto.CompactTextString(m) }
 func (*ListSort) ProtoMessage()    {}
 func (*ListSort) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{1}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{1}
 }
 func (m *ListSort) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListSort.Unmarshal(m, b)
@@ -234,7 +352,7 @@ func (m *ListReleasesResponse) Reset()         { *m = ListReleasesResponse{} }
 func (m *ListReleasesResponse) String() string { return proto.CompactTextString(m) }
 func (*ListReleasesResponse) ProtoMessage()    {}
 func (*ListReleasesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{2}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{2}
 }
 func (m *ListReleasesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListReleasesResponse.Unmarshal(m, b)
@@ -297,7 +415,7 @@ func (m *GetReleaseStatusRequest) Reset()         { *m = GetReleaseStatusRequest
 func (m *GetReleaseStatusRequest) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseStatusRequest) ProtoMessage()    {}
 func (*GetReleaseStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{3}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{3}
 }
 func (m *GetReleaseStatusRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseStatusRequest.Unmarshal(m, b)
@@ -348,7 +466,7 @@ func (m *GetReleaseStatusResponse) Reset()         { *m = GetReleaseStatusRespon
 func (m *GetReleaseStatusResponse) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseStatusResponse) ProtoMessage()    {}
 func (*GetReleaseStatusResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{4}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{4}
 }
 func (m *GetReleaseStatusResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseStatusResponse.Unmarshal(m, b)
@@ -404,7 +522,7 @@ func (m *GetReleaseContentRequest) Reset()         { *m = GetReleaseContentReque
 func (m *GetReleaseContentRequest) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseContentRequest) ProtoMessage()    {}
 func (*GetReleaseContentRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{5}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{5}
 }
 func (m *GetReleaseContentRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseContentRequest.Unmarshal(m, b)
@@ -451,7 +569,7 @@ func (m *GetReleaseContentResponse) Reset()         { *m = GetReleaseContentResp
 func (m *GetReleaseContentResponse) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseContentResponse) ProtoMessage()    {}
 func (*GetReleaseContentResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{6}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{6}
 }
 func (m *GetReleaseContentResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseContentResponse.Unmarshal(m, b)
@@ -509,7 +627,10 @@ type UpdateReleaseRequest struct {
 	// Render subchart notes if enabled
 	SubNotes bool `protobuf:"varint,13,opt,name=subNotes,proto3" json:"subNotes,omitempty"`
 	// Allow deletion of new resources created in this update when update failed
-	CleanupOnFail        bool     `protobuf:"varint,14,opt,name=cleanup_on_fail,json=cleanupOnFail,proto3" json:"cleanup_on_fail,omitempty"`
+	CleanupOnFail bool `protobuf:"varint,14,opt,name=cleanup_on_fail,json=cleanupOnFail,proto3" json:"cleanup_on_fail,omitempty"`
+	// AdoptExisting brings the resources of the chart that already exist in
+	// the cluster, outside of any release, under the release.
+	AdoptExisting        bool     `protobuf:"varint,15,opt,name=adopt_existing,json=adoptExisting,proto3" json:"adopt_existing,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -519,7 +640,7 @@ func (m *UpdateReleaseRequest) Reset()         { *m = UpdateReleaseRequest{} }
 func (m *UpdateReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateReleaseRequest) ProtoMessage()    {}
 func (*UpdateReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{7}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{7}
 }
 func (m *UpdateReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateReleaseRequest.Unmarshal(m, b)
@@ -637,19 +758,29 @@ func (m *UpdateReleaseRequest) GetCleanupOnFail() bool {
 	return false
 }
 
+func (m *UpdateReleaseRequest) GetAdoptExisting() bool {
+	if m != nil {
+		return m.AdoptExisting
+	}
+	return false
+}
+
 // UpdateReleaseResponse is the response to an update request.
 type UpdateReleaseResponse struct {
-	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// Adopted are the existing resources adopted by the release, or to be
+	// adopted on dry run.
+	Adopted              []*AdoptedResource `protobuf:"bytes,2,rep,name=adopted,proto3" json:"adopted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *UpdateReleaseResponse) Reset()         { *m = UpdateReleaseResponse{} }
 func (m *UpdateReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*UpdateReleaseResponse) ProtoMessage()    {}
 func (*UpdateReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{8}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{8}
 }
 func (m *UpdateReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateReleaseResponse.Unmarshal(m, b)
@@ -676,6 +807,13 @@ func (m *UpdateReleaseResponse) GetRelease() *release.Release {
 	return nil
 }
 
+func (m *UpdateReleaseResponse) GetAdopted() []*AdoptedResource {
+	if m != nil {
+		return m.Adopted
+	}
+	return nil
+}
+
 type RollbackReleaseRequest struct {
 	// The name of the release
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -707,7 +845,7 @@ func (m *RollbackReleaseRequest) Reset()         { *m = RollbackReleaseRequest{}
 func (m *RollbackReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*RollbackReleaseRequest) ProtoMessage()    {}
 func (*RollbackReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{9}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{9}
 }
 func (m *RollbackReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RollbackReleaseRequest.Unmarshal(m, b)
@@ -809,7 +947,7 @@ func (m *RollbackReleaseResponse) Reset()         { *m = RollbackReleaseResponse
 func (m *RollbackReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*RollbackReleaseResponse) ProtoMessage()    {}
 func (*RollbackReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{10}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{10}
 }
 func (m *RollbackReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RollbackReleaseResponse.Unmarshal(m, b)
@@ -863,8 +1001,12 @@ type InstallReleaseRequest struct {
 	Wait           bool `protobuf:"varint,9,opt,name=wait,proto3" json:"wait,omitempty"`
 	DisableCrdHook bool `protobuf:"varint,10,opt,name=disable_crd_hook,json=disableCrdHook,proto3" json:"disable_crd_hook,omitempty"`
 	// Description, if set, will set the description for the installed release
-	Description          string   `protobuf:"bytes,11,opt,name=description,proto3" json:"description,omitempty"`
-	SubNotes             bool     `protobuf:"varint,12,opt,name=subNotes,proto3" json:"subNotes,omitempty"`
+	Description string `protobuf:"bytes,11,opt,name=description,proto3" json:"description,omitempty"`
+	SubNotes    bool   `protobuf:"varint,12,opt,name=subNotes,proto3" json:"subNotes,omitempty"`
+	// AdoptExisting brings the resources of the chart that already exist in
+	// the cluster, outside of any release, under the release instead of
+	// failing the install.
+	AdoptExisting        bool     `protobuf:"varint,13,opt,name=adopt_existing,json=adoptExisting,proto3" json:"adopt_existing,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -874,7 +1016,7 @@ func (m *InstallReleaseRequest) Reset()         { *m = InstallReleaseRequest{} }
 func (m *InstallReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*InstallReleaseRequest) ProtoMessage()    {}
 func (*InstallReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{11}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{11}
 }
 func (m *InstallReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallReleaseRequest.Unmarshal(m, b)
@@ -978,19 +1120,29 @@ func (m *InstallReleaseRequest) GetSubNotes() bool {
 	return false
 }
 
+func (m *InstallReleaseRequest) GetAdoptExisting() bool {
+	if m != nil {
+		return m.AdoptExisting
+	}
+	return false
+}
+
 // InstallReleaseResponse is the response from a release installation.
 type InstallReleaseResponse struct {
-	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// Adopted are the existing resources adopted by the release, or to be
+	// adopted on dry run.
+	Adopted              []*AdoptedResource `protobuf:"bytes,2,rep,name=adopted,proto3" json:"adopted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *InstallReleaseResponse) Reset()         { *m = InstallReleaseResponse{} }
 func (m *InstallReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*InstallReleaseResponse) ProtoMessage()    {}
 func (*InstallReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{12}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{12}
 }
 func (m *InstallReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallReleaseResponse.Unmarshal(m, b)
@@ -1017,6 +1169,13 @@ func (m *InstallReleaseResponse) GetRelease() *release.Release {
 	return nil
 }
 
+func (m *InstallReleaseResponse) GetAdopted() []*AdoptedResource {
+	if m != nil {
+		return m.Adopted
+	}
+	return nil
+}
+
 // UninstallReleaseRequest represents a request to uninstall a named release.
 type UninstallReleaseRequest struct {
 	// Name is the name of the release to delete.
@@ -1038,7 +1197,7 @@ func (m *UninstallReleaseRequest) Reset()         { *m = UninstallReleaseRequest
 func (m *UninstallReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*UninstallReleaseRequest) ProtoMessage()    {}
 func (*UninstallReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{13}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{13}
 }
 func (m *UninstallReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UninstallReleaseRequest.Unmarshal(m, b)
@@ -1108,7 +1267,7 @@ func (m *UninstallReleaseResponse) Reset()         { *m = UninstallReleaseRespon
 func (m *UninstallReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*UninstallReleaseResponse) ProtoMessage()    {}
 func (*UninstallReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{14}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{14}
 }
 func (m *UninstallReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UninstallReleaseResponse.Unmarshal(m, b)
@@ -1153,7 +1312,7 @@ func (m *GetVersionRequest) Reset()         { *m = GetVersionRequest{} }
 func (m *GetVersionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetVersionRequest) ProtoMessage()    {}
 func (*GetVersionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{15}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{15}
 }
 func (m *GetVersionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetVersionRequest.Unmarshal(m, b)
@@ -1184,7 +1343,7 @@ func (m *GetVersionResponse) Reset()         { *m = GetVersionResponse{} }
 func (m *GetVersionResponse) String() string { return proto.CompactTextString(m) }
 func (*GetVersionResponse) ProtoMessage()    {}
 func (*GetVersionResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{16}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{16}
 }
 func (m *GetVersionResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetVersionResponse.Unmarshal(m, b)
@@ -1226,7 +1385,7 @@ func (m *GetHistoryRequest) Reset()         { *m = GetHistoryRequest{} }
 func (m *GetHistoryRequest) String() string { return proto.CompactTextString(m) }
 func (*GetHistoryRequest) ProtoMessage()    {}
 func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{17}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{17}
 }
 func (m *GetHistoryRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetHistoryRequest.Unmarshal(m, b)
@@ -1272,7 +1431,7 @@ func (m *GetHistoryResponse) Reset()         { *m = GetHistoryResponse{} }
 func (m *GetHistoryResponse) String() string { return proto.CompactTextString(m) }
 func (*GetHistoryResponse) ProtoMessage()    {}
 func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{18}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{18}
 }
 func (m *GetHistoryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetHistoryResponse.Unmarshal(m, b)
@@ -1318,7 +1477,7 @@ func (m *TestReleaseRequest) Reset()         { *m = TestReleaseRequest{} }
 func (m *TestReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*TestReleaseRequest) ProtoMessage()    {}
 func (*TestReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{19}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{19}
 }
 func (m *TestReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TestReleaseRequest.Unmarshal(m, b)
@@ -1379,7 +1538,7 @@ func (m *TestReleaseResponse) Reset()         { *m = TestReleaseResponse{} }
 func (m *TestReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*TestReleaseResponse) ProtoMessage()    {}
 func (*TestReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{20}
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{20}
 }
 func (m *TestReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TestReleaseResponse.Unmarshal(m, b)
@@ -1413,164 +1572,1550 @@ func (m *TestReleaseResponse) GetStatus() release.TestRun_Status {
 	return release.TestRun_UNKNOWN
 }
 
-func init() {
-	proto.RegisterType((*ListReleasesRequest)(nil), "hapi.services.tiller.ListReleasesRequest")
-	proto.RegisterType((*ListSort)(nil), "hapi.services.tiller.ListSort")
-	proto.RegisterType((*ListReleasesResponse)(nil), "hapi.services.tiller.ListReleasesResponse")
-	proto.RegisterType((*GetReleaseStatusRequest)(nil), "hapi.services.tiller.GetReleaseStatusRequest")
-	proto.RegisterType((*GetReleaseStatusResponse)(nil), "hapi.services.tiller.GetReleaseStatusResponse")
-	proto.RegisterType((*GetReleaseContentRequest)(nil), "hapi.services.tiller.GetReleaseContentRequest")
-	proto.RegisterType((*GetReleaseContentResponse)(nil), "hapi.services.tiller.GetReleaseContentResponse")
-	proto.RegisterType((*UpdateReleaseRequest)(nil), "hapi.services.tiller.UpdateReleaseRequest")
-	proto.RegisterType((*UpdateReleaseResponse)(nil), "hapi.services.tiller.UpdateReleaseResponse")
-	proto.RegisterType((*RollbackReleaseRequest)(nil), "hapi.services.tiller.RollbackReleaseRequest")
-	proto.RegisterType((*RollbackReleaseResponse)(nil), "hapi.services.tiller.RollbackReleaseResponse")
-	proto.RegisterType((*InstallReleaseRequest)(nil), "hapi.services.tiller.InstallReleaseRequest")
-	proto.RegisterType((*InstallReleaseResponse)(nil), "hapi.services.tiller.InstallReleaseResponse")
-	proto.RegisterType((*UninstallReleaseRequest)(nil), "hapi.services.tiller.UninstallReleaseRequest")
-	proto.RegisterType((*UninstallReleaseResponse)(nil), "hapi.services.tiller.UninstallReleaseResponse")
-	proto.RegisterType((*GetVersionRequest)(nil), "hapi.services.tiller.GetVersionRequest")
-	proto.RegisterType((*GetVersionResponse)(nil), "hapi.services.tiller.GetVersionResponse")
-	proto.RegisterType((*GetHistoryRequest)(nil), "hapi.services.tiller.GetHistoryRequest")
-	proto.RegisterType((*GetHistoryResponse)(nil), "hapi.services.tiller.GetHistoryResponse")
-	proto.RegisterType((*TestReleaseRequest)(nil), "hapi.services.tiller.TestReleaseRequest")
-	proto.RegisterType((*TestReleaseResponse)(nil), "hapi.services.tiller.TestReleaseResponse")
-	proto.RegisterEnum("hapi.services.tiller.ListSort_SortBy", ListSort_SortBy_name, ListSort_SortBy_value)
-	proto.RegisterEnum("hapi.services.tiller.ListSort_SortOrder", ListSort_SortOrder_name, ListSort_SortOrder_value)
+// DiffReleaseRequest requests the differences between a deployed release and
+// the release an update to a chart and values would create.
+type DiffReleaseRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Chart is the protobuf representation of a chart.
+	Chart *chart.Chart `protobuf:"bytes,2,opt,name=chart,proto3" json:"chart,omitempty"`
+	// Values is a string containing (unparsed) YAML values.
+	Values *chart.Config `protobuf:"bytes,3,opt,name=values,proto3" json:"values,omitempty"`
+	// ResetValues will cause Tiller to ignore stored values, resetting to default values.
+	ResetValues bool `protobuf:"varint,4,opt,name=reset_values,json=resetValues,proto3" json:"reset_values,omitempty"`
+	// ReuseValues will cause Tiller to reuse the values from the last release.
+	// This is ignored if reset_values is set.
+	ReuseValues bool `protobuf:"varint,5,opt,name=reuse_values,json=reuseValues,proto3" json:"reuse_values,omitempty"`
+	// Live, if true, also compares the proposed resources with their state in the cluster.
+	Live bool `protobuf:"varint,6,opt,name=live,proto3" json:"live,omitempty"`
+	// Context is the number of unchanged lines shown around each change of a diff.
+	Context              int32    `protobuf:"varint,7,opt,name=context,proto3" json:"context,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *DiffReleaseRequest) Reset()         { *m = DiffReleaseRequest{} }
+func (m *DiffReleaseRequest) String() string { return proto.CompactTextString(m) }
+func (*DiffReleaseRequest) ProtoMessage()    {}
+func (*DiffReleaseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{21}
+}
+func (m *DiffReleaseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffReleaseRequest.Unmarshal(m, b)
+}
+func (m *DiffReleaseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffReleaseRequest.Marshal(b, m, deterministic)
+}
+func (dst *DiffReleaseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffReleaseRequest.Merge(dst, src)
+}
+func (m *DiffReleaseRequest) XXX_Size() int {
+	return xxx_messageInfo_DiffReleaseRequest.Size(m)
+}
+func (m *DiffReleaseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffReleaseRequest.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_DiffReleaseRequest proto.InternalMessageInfo
 
-// ReleaseServiceClient is the client API for ReleaseService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ReleaseServiceClient interface {
-	// ListReleases retrieves release history.
-	// TODO: Allow filtering the set of releases by
-	// release status. By default, ListAllReleases returns the releases who
-	// current status is "Active".
-	ListReleases(ctx context.Context, in *ListReleasesRequest, opts ...grpc.CallOption) (ReleaseService_ListReleasesClient, error)
-	// GetReleasesStatus retrieves status information for the specified release.
-	GetReleaseStatus(ctx context.Context, in *GetReleaseStatusRequest, opts ...grpc.CallOption) (*GetReleaseStatusResponse, error)
-	// GetReleaseContent retrieves the release content (chart + value) for the specified release.
-	GetReleaseContent(ctx context.Context, in *GetReleaseContentRequest, opts ...grpc.CallOption) (*GetReleaseContentResponse, error)
-	// UpdateRelease updates release content.
-	UpdateRelease(ctx context.Context, in *UpdateReleaseRequest, opts ...grpc.CallOption) (*UpdateReleaseResponse, error)
-	// InstallRelease requests installation of a chart as a new release.
-	InstallRelease(ctx context.Context, in *InstallReleaseRequest, opts ...grpc.CallOption) (*InstallReleaseResponse, error)
-	// UninstallRelease requests deletion of a named release.
-	UninstallRelease(ctx context.Context, in *UninstallReleaseRequest, opts ...grpc.CallOption) (*UninstallReleaseResponse, error)
-	// GetVersion returns the current version of the server.
-	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error)
-	// RollbackRelease rolls back a release to a previous version.
-	RollbackRelease(ctx context.Context, in *RollbackReleaseRequest, opts ...grpc.CallOption) (*RollbackReleaseResponse, error)
-	// ReleaseHistory retrieves a release's history.
-	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
-	// RunReleaseTest executes the tests defined of a named release
-	RunReleaseTest(ctx context.Context, in *TestReleaseRequest, opts ...grpc.CallOption) (ReleaseService_RunReleaseTestClient, error)
+func (m *DiffReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
 }
 
-type releaseServiceClient struct {
-	cc *grpc.ClientConn
+func (m *DiffReleaseRequest) GetChart() *chart.Chart {
+	if m != nil {
+		return m.Chart
+	}
+	return nil
 }
 
-func NewReleaseServiceClient(cc *grpc.ClientConn) ReleaseServiceClient {
-	return &releaseServiceClient{cc}
+func (m *DiffReleaseRequest) GetValues() *chart.Config {
+	if m != nil {
+		return m.Values
+	}
+	return nil
 }
 
-func (c *releaseServiceClient) ListReleases(ctx context.Context, in *ListReleasesRequest, opts ...grpc.CallOption) (ReleaseService_ListReleasesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_ReleaseService_serviceDesc.Streams[0], "/hapi.services.tiller.ReleaseService/ListReleases", opts...)
-	if err != nil {
-		return nil, err
+func (m *DiffReleaseRequest) GetResetValues() bool {
+	if m != nil {
+		return m.ResetValues
 	}
-	x := &releaseServiceListReleasesClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	return false
+}
+
+func (m *DiffReleaseRequest) GetReuseValues() bool {
+	if m != nil {
+		return m.ReuseValues
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	return false
+}
+
+func (m *DiffReleaseRequest) GetLive() bool {
+	if m != nil {
+		return m.Live
 	}
-	return x, nil
+	return false
 }
 
-type ReleaseService_ListReleasesClient interface {
-	Recv() (*ListReleasesResponse, error)
-	grpc.ClientStream
+func (m *DiffReleaseRequest) GetContext() int32 {
+	if m != nil {
+		return m.Context
+	}
+	return 0
 }
 
-type releaseServiceListReleasesClient struct {
-	grpc.ClientStream
+// ResourceDiff describes how an update would change a resource of a release.
+type ResourceDiff struct {
+	// Kind is the kind of the resource, e.g. Deployment.
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Name is the name of the resource.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Namespace is the namespace of the resource.
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Change is the change of the resource from the deployed manifest.
+	Change ResourceDiff_Change `protobuf:"varint,4,opt,name=change,proto3,enum=hapi.services.tiller.ResourceDiff_Change" json:"change,omitempty"`
+	// Diff is the unified diff of the resource from the deployed manifest.
+	Diff string `protobuf:"bytes,5,opt,name=diff,proto3" json:"diff,omitempty"`
+	// LiveChange is the change of the resource from its state in the cluster.
+	LiveChange ResourceDiff_Change `protobuf:"varint,6,opt,name=live_change,json=liveChange,proto3,enum=hapi.services.tiller.ResourceDiff_Change" json:"live_change,omitempty"`
+	// LiveDiff is the unified diff of the resource from its state in the cluster,
+	// restricted to the fields set by the proposed manifest.
+	LiveDiff             string   `protobuf:"bytes,7,opt,name=live_diff,json=liveDiff,proto3" json:"live_diff,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (x *releaseServiceListReleasesClient) Recv() (*ListReleasesResponse, error) {
-	m := new(ListReleasesResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *ResourceDiff) Reset()         { *m = ResourceDiff{} }
+func (m *ResourceDiff) String() string { return proto.CompactTextString(m) }
+func (*ResourceDiff) ProtoMessage()    {}
+func (*ResourceDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{22}
+}
+func (m *ResourceDiff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResourceDiff.Unmarshal(m, b)
+}
+func (m *ResourceDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResourceDiff.Marshal(b, m, deterministic)
+}
+func (dst *ResourceDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResourceDiff.Merge(dst, src)
+}
+func (m *ResourceDiff) XXX_Size() int {
+	return xxx_messageInfo_ResourceDiff.Size(m)
+}
+func (m *ResourceDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResourceDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResourceDiff proto.InternalMessageInfo
+
+func (m *ResourceDiff) GetKind() string {
+	if m != nil {
+		return m.Kind
 	}
-	return m, nil
+	return ""
 }
 
-func (c *releaseServiceClient) GetReleaseStatus(ctx context.Context, in *GetReleaseStatusRequest, opts ...grpc.CallOption) (*GetReleaseStatusResponse, error) {
-	out := new(GetReleaseStatusResponse)
-	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseStatus", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ResourceDiff) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return out, nil
+	return ""
 }
 
-func (c *releaseServiceClient) GetReleaseContent(ctx context.Context, in *GetReleaseContentRequest, opts ...grpc.CallOption) (*GetReleaseContentResponse, error) {
-	out := new(GetReleaseContentResponse)
-	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseContent", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ResourceDiff) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
 	}
-	return out, nil
+	return ""
 }
 
-func (c *releaseServiceClient) UpdateRelease(ctx context.Context, in *UpdateReleaseRequest, opts ...grpc.CallOption) (*UpdateReleaseResponse, error) {
-	out := new(UpdateReleaseResponse)
-	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/UpdateRelease", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ResourceDiff) GetChange() ResourceDiff_Change {
+	if m != nil {
+		return m.Change
 	}
-	return out, nil
+	return ResourceDiff_UNCHANGED
 }
 
-func (c *releaseServiceClient) InstallRelease(ctx context.Context, in *InstallReleaseRequest, opts ...grpc.CallOption) (*InstallReleaseResponse, error) {
-	out := new(InstallReleaseResponse)
-	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/InstallRelease", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ResourceDiff) GetDiff() string {
+	if m != nil {
+		return m.Diff
 	}
-	return out, nil
+	return ""
 }
 
-func (c *releaseServiceClient) UninstallRelease(ctx context.Context, in *UninstallReleaseRequest, opts ...grpc.CallOption) (*UninstallReleaseResponse, error) {
-	out := new(UninstallReleaseResponse)
-	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/UninstallRelease", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ResourceDiff) GetLiveChange() ResourceDiff_Change {
+	if m != nil {
+		return m.LiveChange
 	}
-	return out, nil
+	return ResourceDiff_UNCHANGED
 }
 
-func (c *releaseServiceClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error) {
-	out := new(GetVersionResponse)
-	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetVersion", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ResourceDiff) GetLiveDiff() string {
+	if m != nil {
+		return m.LiveDiff
 	}
-	return out, nil
+	return ""
 }
 
-func (c *releaseServiceClient) RollbackRelease(ctx context.Context, in *RollbackReleaseRequest, opts ...grpc.CallOption) (*RollbackReleaseResponse, error) {
+// DiffReleaseResponse is the response to a diff request.
+type DiffReleaseResponse struct {
+	// Release is the release the update would create.
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// Resources are the differences of the resources of the release, in
+	// manifest order followed by the removed resources.
+	Resources            []*ResourceDiff `protobuf:"bytes,2,rep,name=resources,proto3" json:"resources,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *DiffReleaseResponse) Reset()         { *m = DiffReleaseResponse{} }
+func (m *DiffReleaseResponse) String() string { return proto.CompactTextString(m) }
+func (*DiffReleaseResponse) ProtoMessage()    {}
+func (*DiffReleaseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{23}
+}
+func (m *DiffReleaseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffReleaseResponse.Unmarshal(m, b)
+}
+func (m *DiffReleaseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffReleaseResponse.Marshal(b, m, deterministic)
+}
+func (dst *DiffReleaseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffReleaseResponse.Merge(dst, src)
+}
+func (m *DiffReleaseResponse) XXX_Size() int {
+	return xxx_messageInfo_DiffReleaseResponse.Size(m)
+}
+func (m *DiffReleaseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffReleaseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffReleaseResponse proto.InternalMessageInfo
+
+func (m *DiffReleaseResponse) GetRelease() *release.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func (m *DiffReleaseResponse) GetResources() []*ResourceDiff {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
+}
+
+// WatchReleasesRequest requests the events of the releases matching some filters.
+type WatchReleasesRequest struct {
+	// Name restricts the events to the ones of the named release.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Namespace restricts the events to the ones of the releases in the namespace.
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchReleasesRequest) Reset()         { *m = WatchReleasesRequest{} }
+func (m *WatchReleasesRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchReleasesRequest) ProtoMessage()    {}
+func (*WatchReleasesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{24}
+}
+func (m *WatchReleasesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchReleasesRequest.Unmarshal(m, b)
+}
+func (m *WatchReleasesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchReleasesRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchReleasesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchReleasesRequest.Merge(dst, src)
+}
+func (m *WatchReleasesRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchReleasesRequest.Size(m)
+}
+func (m *WatchReleasesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchReleasesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchReleasesRequest proto.InternalMessageInfo
+
+func (m *WatchReleasesRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *WatchReleasesRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+// ReleaseEvent describes a change made to a release.
+type ReleaseEvent struct {
+	Type ReleaseEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=hapi.services.tiller.ReleaseEvent_Type" json:"type,omitempty"`
+	// Name is the name of the release.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Namespace is the namespace of the release.
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Version is the revision of the release. It is not set for hook events.
+	Version int32 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	// Status is the status of the revision. It is not set for hook events.
+	Status release.Status_Code `protobuf:"varint,5,opt,name=status,proto3,enum=hapi.release.Status_Code" json:"status,omitempty"`
+	// Description is the description of the revision, or the outcome of a hook.
+	Description string `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	// Hook is the name of the hook that ran.
+	Hook string `protobuf:"bytes,7,opt,name=hook,proto3" json:"hook,omitempty"`
+	// HookEvent is the event the hook ran for.
+	HookEvent release.Hook_Event `protobuf:"varint,8,opt,name=hook_event,json=hookEvent,proto3,enum=hapi.release.Hook_Event" json:"hook_event,omitempty"`
+	// Time is the time of the change.
+	Time                 *timestamp.Timestamp `protobuf:"bytes,9,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ReleaseEvent) Reset()         { *m = ReleaseEvent{} }
+func (m *ReleaseEvent) String() string { return proto.CompactTextString(m) }
+func (*ReleaseEvent) ProtoMessage()    {}
+func (*ReleaseEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{25}
+}
+func (m *ReleaseEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseEvent.Unmarshal(m, b)
+}
+func (m *ReleaseEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseEvent.Marshal(b, m, deterministic)
+}
+func (dst *ReleaseEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseEvent.Merge(dst, src)
+}
+func (m *ReleaseEvent) XXX_Size() int {
+	return xxx_messageInfo_ReleaseEvent.Size(m)
+}
+func (m *ReleaseEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseEvent proto.InternalMessageInfo
+
+func (m *ReleaseEvent) GetType() ReleaseEvent_Type {
+	if m != nil {
+		return m.Type
+	}
+	return ReleaseEvent_UNKNOWN
+}
+
+func (m *ReleaseEvent) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ReleaseEvent) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ReleaseEvent) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ReleaseEvent) GetStatus() release.Status_Code {
+	if m != nil {
+		return m.Status
+	}
+	return release.Status_UNKNOWN
+}
+
+func (m *ReleaseEvent) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *ReleaseEvent) GetHook() string {
+	if m != nil {
+		return m.Hook
+	}
+	return ""
+}
+
+func (m *ReleaseEvent) GetHookEvent() release.Hook_Event {
+	if m != nil {
+		return m.HookEvent
+	}
+	return release.Hook_UNKNOWN
+}
+
+func (m *ReleaseEvent) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+// WatchReleasesResponse is received for each change made to the watched releases.
+type WatchReleasesResponse struct {
+	Event                *ReleaseEvent `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *WatchReleasesResponse) Reset()         { *m = WatchReleasesResponse{} }
+func (m *WatchReleasesResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchReleasesResponse) ProtoMessage()    {}
+func (*WatchReleasesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{26}
+}
+func (m *WatchReleasesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchReleasesResponse.Unmarshal(m, b)
+}
+func (m *WatchReleasesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchReleasesResponse.Marshal(b, m, deterministic)
+}
+func (dst *WatchReleasesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchReleasesResponse.Merge(dst, src)
+}
+func (m *WatchReleasesResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchReleasesResponse.Size(m)
+}
+func (m *WatchReleasesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchReleasesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchReleasesResponse proto.InternalMessageInfo
+
+func (m *WatchReleasesResponse) GetEvent() *ReleaseEvent {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+// CancelOperationRequest requests the operation in progress on a release to be cancelled.
+type CancelOperationRequest struct {
+	// Name is the name of the release.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Delete the new resources created by the cancelled operation, as cleanup_on_fail does.
+	Cleanup              bool     `protobuf:"varint,2,opt,name=cleanup,proto3" json:"cleanup,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelOperationRequest) Reset()         { *m = CancelOperationRequest{} }
+func (m *CancelOperationRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelOperationRequest) ProtoMessage()    {}
+func (*CancelOperationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{27}
+}
+func (m *CancelOperationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelOperationRequest.Unmarshal(m, b)
+}
+func (m *CancelOperationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelOperationRequest.Marshal(b, m, deterministic)
+}
+func (dst *CancelOperationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelOperationRequest.Merge(dst, src)
+}
+func (m *CancelOperationRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelOperationRequest.Size(m)
+}
+func (m *CancelOperationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelOperationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelOperationRequest proto.InternalMessageInfo
+
+func (m *CancelOperationRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CancelOperationRequest) GetCleanup() bool {
+	if m != nil {
+		return m.Cleanup
+	}
+	return false
+}
+
+// CancelOperationResponse is the response to a cancel request.
+type CancelOperationResponse struct {
+	// Release is the revision the cancelled operation created.
+	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *CancelOperationResponse) Reset()         { *m = CancelOperationResponse{} }
+func (m *CancelOperationResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelOperationResponse) ProtoMessage()    {}
+func (*CancelOperationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{28}
+}
+func (m *CancelOperationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelOperationResponse.Unmarshal(m, b)
+}
+func (m *CancelOperationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelOperationResponse.Marshal(b, m, deterministic)
+}
+func (dst *CancelOperationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelOperationResponse.Merge(dst, src)
+}
+func (m *CancelOperationResponse) XXX_Size() int {
+	return xxx_messageInfo_CancelOperationResponse.Size(m)
+}
+func (m *CancelOperationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelOperationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelOperationResponse proto.InternalMessageInfo
+
+func (m *CancelOperationResponse) GetRelease() *release.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+// RepairReleaseRequest requests the pending revision of a release to be repaired.
+type RepairReleaseRequest struct {
+	// Name is the name of the release.
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RepairReleaseRequest) Reset()         { *m = RepairReleaseRequest{} }
+func (m *RepairReleaseRequest) String() string { return proto.CompactTextString(m) }
+func (*RepairReleaseRequest) ProtoMessage()    {}
+func (*RepairReleaseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{29}
+}
+func (m *RepairReleaseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RepairReleaseRequest.Unmarshal(m, b)
+}
+func (m *RepairReleaseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RepairReleaseRequest.Marshal(b, m, deterministic)
+}
+func (dst *RepairReleaseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepairReleaseRequest.Merge(dst, src)
+}
+func (m *RepairReleaseRequest) XXX_Size() int {
+	return xxx_messageInfo_RepairReleaseRequest.Size(m)
+}
+func (m *RepairReleaseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepairReleaseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepairReleaseRequest proto.InternalMessageInfo
+
+func (m *RepairReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// RepairReleaseResponse is the response to a repair request.
+type RepairReleaseResponse struct {
+	// Release is the pending revision, marked as failed.
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// Restored is the revision deployed again, if no other revision was deployed.
+	Restored             *release.Release `protobuf:"bytes,2,opt,name=restored,proto3" json:"restored,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RepairReleaseResponse) Reset()         { *m = RepairReleaseResponse{} }
+func (m *RepairReleaseResponse) String() string { return proto.CompactTextString(m) }
+func (*RepairReleaseResponse) ProtoMessage()    {}
+func (*RepairReleaseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{30}
+}
+func (m *RepairReleaseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RepairReleaseResponse.Unmarshal(m, b)
+}
+func (m *RepairReleaseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RepairReleaseResponse.Marshal(b, m, deterministic)
+}
+func (dst *RepairReleaseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepairReleaseResponse.Merge(dst, src)
+}
+func (m *RepairReleaseResponse) XXX_Size() int {
+	return xxx_messageInfo_RepairReleaseResponse.Size(m)
+}
+func (m *RepairReleaseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepairReleaseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepairReleaseResponse proto.InternalMessageInfo
+
+func (m *RepairReleaseResponse) GetRelease() *release.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func (m *RepairReleaseResponse) GetRestored() *release.Release {
+	if m != nil {
+		return m.Restored
+	}
+	return nil
+}
+
+// CheckDriftRequest requests the differences between the deployed manifest of a
+// release and the state of its resources in the cluster.
+type CheckDriftRequest struct {
+	// Name is the name of the release.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Reconcile re-applies the deployed manifest to the resources that drifted.
+	Reconcile            bool     `protobuf:"varint,2,opt,name=reconcile,proto3" json:"reconcile,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckDriftRequest) Reset()         { *m = CheckDriftRequest{} }
+func (m *CheckDriftRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckDriftRequest) ProtoMessage()    {}
+func (*CheckDriftRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{31}
+}
+func (m *CheckDriftRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckDriftRequest.Unmarshal(m, b)
+}
+func (m *CheckDriftRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckDriftRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckDriftRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckDriftRequest.Merge(dst, src)
+}
+func (m *CheckDriftRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckDriftRequest.Size(m)
+}
+func (m *CheckDriftRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckDriftRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckDriftRequest proto.InternalMessageInfo
+
+func (m *CheckDriftRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CheckDriftRequest) GetReconcile() bool {
+	if m != nil {
+		return m.Reconcile
+	}
+	return false
+}
+
+// FieldDrift describes a field of a resource whose live value differs from the
+// deployed manifest.
+type FieldDrift struct {
+	// Path is the path of the field in the resource, e.g. spec.replicas.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Expected is the JSON encoded value of the field in the deployed manifest,
+	// or empty if the field is not set by the manifest.
+	Expected string `protobuf:"bytes,2,opt,name=expected,proto3" json:"expected,omitempty"`
+	// Live is the JSON encoded value of the field in the cluster, or empty if
+	// the field is not set in the cluster.
+	Live                 string   `protobuf:"bytes,3,opt,name=live,proto3" json:"live,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FieldDrift) Reset()         { *m = FieldDrift{} }
+func (m *FieldDrift) String() string { return proto.CompactTextString(m) }
+func (*FieldDrift) ProtoMessage()    {}
+func (*FieldDrift) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{32}
+}
+func (m *FieldDrift) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FieldDrift.Unmarshal(m, b)
+}
+func (m *FieldDrift) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FieldDrift.Marshal(b, m, deterministic)
+}
+func (dst *FieldDrift) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FieldDrift.Merge(dst, src)
+}
+func (m *FieldDrift) XXX_Size() int {
+	return xxx_messageInfo_FieldDrift.Size(m)
+}
+func (m *FieldDrift) XXX_DiscardUnknown() {
+	xxx_messageInfo_FieldDrift.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FieldDrift proto.InternalMessageInfo
+
+func (m *FieldDrift) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *FieldDrift) GetExpected() string {
+	if m != nil {
+		return m.Expected
+	}
+	return ""
+}
+
+func (m *FieldDrift) GetLive() string {
+	if m != nil {
+		return m.Live
+	}
+	return ""
+}
+
+// ResourceDrift describes a resource of a release whose live state differs
+// from the deployed manifest.
+type ResourceDrift struct {
+	Kind      string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Missing is set if the resource does not exist in the cluster.
+	Missing bool `protobuf:"varint,4,opt,name=missing,proto3" json:"missing,omitempty"`
+	// Fields are the fields that differ, if the resource exists.
+	Fields               []*FieldDrift `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ResourceDrift) Reset()         { *m = ResourceDrift{} }
+func (m *ResourceDrift) String() string { return proto.CompactTextString(m) }
+func (*ResourceDrift) ProtoMessage()    {}
+func (*ResourceDrift) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{33}
+}
+func (m *ResourceDrift) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResourceDrift.Unmarshal(m, b)
+}
+func (m *ResourceDrift) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResourceDrift.Marshal(b, m, deterministic)
+}
+func (dst *ResourceDrift) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResourceDrift.Merge(dst, src)
+}
+func (m *ResourceDrift) XXX_Size() int {
+	return xxx_messageInfo_ResourceDrift.Size(m)
+}
+func (m *ResourceDrift) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResourceDrift.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResourceDrift proto.InternalMessageInfo
+
+func (m *ResourceDrift) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *ResourceDrift) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ResourceDrift) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ResourceDrift) GetMissing() bool {
+	if m != nil {
+		return m.Missing
+	}
+	return false
+}
+
+func (m *ResourceDrift) GetFields() []*FieldDrift {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+// CheckDriftResponse is the response to a drift check.
+type CheckDriftResponse struct {
+	// Release is the deployed release checked.
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// Resources are the resources that drifted, in the order of the manifest.
+	Resources []*ResourceDrift `protobuf:"bytes,2,rep,name=resources,proto3" json:"resources,omitempty"`
+	// Checked is the number of resources checked.
+	Checked int32 `protobuf:"varint,3,opt,name=checked,proto3" json:"checked,omitempty"`
+	// Reconciled is set if the resources that drifted were reconciled.
+	Reconciled           bool     `protobuf:"varint,4,opt,name=reconciled,proto3" json:"reconciled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckDriftResponse) Reset()         { *m = CheckDriftResponse{} }
+func (m *CheckDriftResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckDriftResponse) ProtoMessage()    {}
+func (*CheckDriftResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{34}
+}
+func (m *CheckDriftResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckDriftResponse.Unmarshal(m, b)
+}
+func (m *CheckDriftResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckDriftResponse.Marshal(b, m, deterministic)
+}
+func (dst *CheckDriftResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckDriftResponse.Merge(dst, src)
+}
+func (m *CheckDriftResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckDriftResponse.Size(m)
+}
+func (m *CheckDriftResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckDriftResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckDriftResponse proto.InternalMessageInfo
+
+func (m *CheckDriftResponse) GetRelease() *release.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func (m *CheckDriftResponse) GetResources() []*ResourceDrift {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
+}
+
+func (m *CheckDriftResponse) GetChecked() int32 {
+	if m != nil {
+		return m.Checked
+	}
+	return 0
+}
+
+func (m *CheckDriftResponse) GetReconciled() bool {
+	if m != nil {
+		return m.Reconciled
+	}
+	return false
+}
+
+// AdoptedResource is an existing resource brought under a release.
+type AdoptedResource struct {
+	Kind                 string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Namespace            string   `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdoptedResource) Reset()         { *m = AdoptedResource{} }
+func (m *AdoptedResource) String() string { return proto.CompactTextString(m) }
+func (*AdoptedResource) ProtoMessage()    {}
+func (*AdoptedResource) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{35}
+}
+func (m *AdoptedResource) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdoptedResource.Unmarshal(m, b)
+}
+func (m *AdoptedResource) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdoptedResource.Marshal(b, m, deterministic)
+}
+func (dst *AdoptedResource) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdoptedResource.Merge(dst, src)
+}
+func (m *AdoptedResource) XXX_Size() int {
+	return xxx_messageInfo_AdoptedResource.Size(m)
+}
+func (m *AdoptedResource) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdoptedResource.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdoptedResource proto.InternalMessageInfo
+
+func (m *AdoptedResource) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *AdoptedResource) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AdoptedResource) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+// RenameReleaseRequest renames a release, or moves it to another namespace.
+type RenameReleaseRequest struct {
+	// Name is the name of the release to rename.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// NewName is the new name of the release. The release keeps its name if
+	// empty.
+	NewName string `protobuf:"bytes,2,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
+	// Namespace is the namespace to move the release to. The release stays in
+	// its namespace if empty.
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// DryRun, if true, renders the renamed release without changing it.
+	DryRun bool `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// DisableHooks causes the server to skip running the upgrade hooks.
+	DisableHooks bool `protobuf:"varint,5,opt,name=disable_hooks,json=disableHooks,proto3" json:"disable_hooks,omitempty"`
+	// timeout specifies the max amount of time any kubernetes client command can run.
+	Timeout int64 `protobuf:"varint,6,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// wait, if true, will wait until all Pods, PVCs, and Services are in a ready state
+	// before marking the release as successful. It will wait for as long as timeout
+	Wait                 bool     `protobuf:"varint,7,opt,name=wait,proto3" json:"wait,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenameReleaseRequest) Reset()         { *m = RenameReleaseRequest{} }
+func (m *RenameReleaseRequest) String() string { return proto.CompactTextString(m) }
+func (*RenameReleaseRequest) ProtoMessage()    {}
+func (*RenameReleaseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{36}
+}
+func (m *RenameReleaseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenameReleaseRequest.Unmarshal(m, b)
+}
+func (m *RenameReleaseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenameReleaseRequest.Marshal(b, m, deterministic)
+}
+func (dst *RenameReleaseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameReleaseRequest.Merge(dst, src)
+}
+func (m *RenameReleaseRequest) XXX_Size() int {
+	return xxx_messageInfo_RenameReleaseRequest.Size(m)
+}
+func (m *RenameReleaseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameReleaseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameReleaseRequest proto.InternalMessageInfo
+
+func (m *RenameReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RenameReleaseRequest) GetNewName() string {
+	if m != nil {
+		return m.NewName
+	}
+	return ""
+}
+
+func (m *RenameReleaseRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *RenameReleaseRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *RenameReleaseRequest) GetDisableHooks() bool {
+	if m != nil {
+		return m.DisableHooks
+	}
+	return false
+}
+
+func (m *RenameReleaseRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *RenameReleaseRequest) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
+}
+
+// RenameReleaseResponse is the response to a rename request.
+type RenameReleaseResponse struct {
+	// Release is the revision of the release under its new name and
+	// namespace.
+	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RenameReleaseResponse) Reset()         { *m = RenameReleaseResponse{} }
+func (m *RenameReleaseResponse) String() string { return proto.CompactTextString(m) }
+func (*RenameReleaseResponse) ProtoMessage()    {}
+func (*RenameReleaseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{37}
+}
+func (m *RenameReleaseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenameReleaseResponse.Unmarshal(m, b)
+}
+func (m *RenameReleaseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenameReleaseResponse.Marshal(b, m, deterministic)
+}
+func (dst *RenameReleaseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameReleaseResponse.Merge(dst, src)
+}
+func (m *RenameReleaseResponse) XXX_Size() int {
+	return xxx_messageInfo_RenameReleaseResponse.Size(m)
+}
+func (m *RenameReleaseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameReleaseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameReleaseResponse proto.InternalMessageInfo
+
+func (m *RenameReleaseResponse) GetRelease() *release.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+// ReleaseSetMember is a release of a release set.
+type ReleaseSetMember struct {
+	// Name is the name of the release.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Namespace is the namespace the release is installed in.
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Chart is the chart the release is installed or upgraded with.
+	Chart *chart.Chart `protobuf:"bytes,3,opt,name=chart,proto3" json:"chart,omitempty"`
+	// Values is a string containing (unparsed) YAML values.
+	Values *chart.Config `protobuf:"bytes,4,opt,name=values,proto3" json:"values,omitempty"`
+	// DependsOn are the names of the releases of the set which must be applied
+	// before this one.
+	DependsOn            []string `protobuf:"bytes,5,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReleaseSetMember) Reset()         { *m = ReleaseSetMember{} }
+func (m *ReleaseSetMember) String() string { return proto.CompactTextString(m) }
+func (*ReleaseSetMember) ProtoMessage()    {}
+func (*ReleaseSetMember) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{38}
+}
+func (m *ReleaseSetMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseSetMember.Unmarshal(m, b)
+}
+func (m *ReleaseSetMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseSetMember.Marshal(b, m, deterministic)
+}
+func (dst *ReleaseSetMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseSetMember.Merge(dst, src)
+}
+func (m *ReleaseSetMember) XXX_Size() int {
+	return xxx_messageInfo_ReleaseSetMember.Size(m)
+}
+func (m *ReleaseSetMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseSetMember.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseSetMember proto.InternalMessageInfo
+
+func (m *ReleaseSetMember) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ReleaseSetMember) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ReleaseSetMember) GetChart() *chart.Chart {
+	if m != nil {
+		return m.Chart
+	}
+	return nil
+}
+
+func (m *ReleaseSetMember) GetValues() *chart.Config {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *ReleaseSetMember) GetDependsOn() []string {
+	if m != nil {
+		return m.DependsOn
+	}
+	return nil
+}
+
+// ApplyReleaseSetRequest installs the releases of a set which do not exist,
+// and upgrades the others. A release is applied once the releases it depends
+// on are, the releases not depending on each other in parallel.
+type ApplyReleaseSetRequest struct {
+	// Releases are the releases of the set.
+	Releases []*ReleaseSetMember `protobuf:"bytes,1,rep,name=releases,proto3" json:"releases,omitempty"`
+	// DryRun, if true, renders the releases without applying them.
+	DryRun bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// timeout specifies the max amount of time any kubernetes client command can run.
+	Timeout int64 `protobuf:"varint,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// wait, if true, will wait until all Pods, PVCs, and Services are in a ready state
+	// before marking a release as successful. It will wait for as long as timeout
+	Wait bool `protobuf:"varint,4,opt,name=wait,proto3" json:"wait,omitempty"`
+	// Atomic, if true, rolls back the releases of the set applied, or failed,
+	// when a release of the set fails.
+	Atomic bool `protobuf:"varint,5,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	// Parallelism is the maximum number of releases applied at once. There is
+	// no limit if zero.
+	Parallelism          int32    `protobuf:"varint,6,opt,name=parallelism,proto3" json:"parallelism,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApplyReleaseSetRequest) Reset()         { *m = ApplyReleaseSetRequest{} }
+func (m *ApplyReleaseSetRequest) String() string { return proto.CompactTextString(m) }
+func (*ApplyReleaseSetRequest) ProtoMessage()    {}
+func (*ApplyReleaseSetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{39}
+}
+func (m *ApplyReleaseSetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApplyReleaseSetRequest.Unmarshal(m, b)
+}
+func (m *ApplyReleaseSetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApplyReleaseSetRequest.Marshal(b, m, deterministic)
+}
+func (dst *ApplyReleaseSetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApplyReleaseSetRequest.Merge(dst, src)
+}
+func (m *ApplyReleaseSetRequest) XXX_Size() int {
+	return xxx_messageInfo_ApplyReleaseSetRequest.Size(m)
+}
+func (m *ApplyReleaseSetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApplyReleaseSetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApplyReleaseSetRequest proto.InternalMessageInfo
+
+func (m *ApplyReleaseSetRequest) GetReleases() []*ReleaseSetMember {
+	if m != nil {
+		return m.Releases
+	}
+	return nil
+}
+
+func (m *ApplyReleaseSetRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *ApplyReleaseSetRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *ApplyReleaseSetRequest) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
+}
+
+func (m *ApplyReleaseSetRequest) GetAtomic() bool {
+	if m != nil {
+		return m.Atomic
+	}
+	return false
+}
+
+func (m *ApplyReleaseSetRequest) GetParallelism() int32 {
+	if m != nil {
+		return m.Parallelism
+	}
+	return 0
+}
+
+// ReleaseSetResult is the result of applying a release of a set.
+type ReleaseSetResult struct {
+	// Name is the name of the release.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Status is the outcome of applying the release.
+	Status ReleaseSetResult_Status `protobuf:"varint,2,opt,name=status,proto3,enum=hapi.services.tiller.ReleaseSetResult_Status" json:"status,omitempty"`
+	// Installed is true if the release was installed rather than upgraded.
+	Installed bool `protobuf:"varint,3,opt,name=installed,proto3" json:"installed,omitempty"`
+	// Release is the revision of the release applied, if any.
+	Release *release.Release `protobuf:"bytes,4,opt,name=release,proto3" json:"release,omitempty"`
+	// Error is the reason the release failed, was skipped or could not be
+	// rolled back.
+	Error                string   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReleaseSetResult) Reset()         { *m = ReleaseSetResult{} }
+func (m *ReleaseSetResult) String() string { return proto.CompactTextString(m) }
+func (*ReleaseSetResult) ProtoMessage()    {}
+func (*ReleaseSetResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{40}
+}
+func (m *ReleaseSetResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseSetResult.Unmarshal(m, b)
+}
+func (m *ReleaseSetResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseSetResult.Marshal(b, m, deterministic)
+}
+func (dst *ReleaseSetResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseSetResult.Merge(dst, src)
+}
+func (m *ReleaseSetResult) XXX_Size() int {
+	return xxx_messageInfo_ReleaseSetResult.Size(m)
+}
+func (m *ReleaseSetResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseSetResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseSetResult proto.InternalMessageInfo
+
+func (m *ReleaseSetResult) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ReleaseSetResult) GetStatus() ReleaseSetResult_Status {
+	if m != nil {
+		return m.Status
+	}
+	return ReleaseSetResult_UNKNOWN
+}
+
+func (m *ReleaseSetResult) GetInstalled() bool {
+	if m != nil {
+		return m.Installed
+	}
+	return false
+}
+
+func (m *ReleaseSetResult) GetRelease() *release.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func (m *ReleaseSetResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// ApplyReleaseSetResponse is the response to applying a release set.
+type ApplyReleaseSetResponse struct {
+	// Results are the results of the releases of the set, in the order of the
+	// request.
+	Results              []*ReleaseSetResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ApplyReleaseSetResponse) Reset()         { *m = ApplyReleaseSetResponse{} }
+func (m *ApplyReleaseSetResponse) String() string { return proto.CompactTextString(m) }
+func (*ApplyReleaseSetResponse) ProtoMessage()    {}
+func (*ApplyReleaseSetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_2602b9adba2a7693, []int{41}
+}
+func (m *ApplyReleaseSetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApplyReleaseSetResponse.Unmarshal(m, b)
+}
+func (m *ApplyReleaseSetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApplyReleaseSetResponse.Marshal(b, m, deterministic)
+}
+func (dst *ApplyReleaseSetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApplyReleaseSetResponse.Merge(dst, src)
+}
+func (m *ApplyReleaseSetResponse) XXX_Size() int {
+	return xxx_messageInfo_ApplyReleaseSetResponse.Size(m)
+}
+func (m *ApplyReleaseSetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApplyReleaseSetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApplyReleaseSetResponse proto.InternalMessageInfo
+
+func (m *ApplyReleaseSetResponse) GetResults() []*ReleaseSetResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ListReleasesRequest)(nil), "hapi.services.tiller.ListReleasesRequest")
+	proto.RegisterType((*ListSort)(nil), "hapi.services.tiller.ListSort")
+	proto.RegisterType((*ListReleasesResponse)(nil), "hapi.services.tiller.ListReleasesResponse")
+	proto.RegisterType((*GetReleaseStatusRequest)(nil), "hapi.services.tiller.GetReleaseStatusRequest")
+	proto.RegisterType((*GetReleaseStatusResponse)(nil), "hapi.services.tiller.GetReleaseStatusResponse")
+	proto.RegisterType((*GetReleaseContentRequest)(nil), "hapi.services.tiller.GetReleaseContentRequest")
+	proto.RegisterType((*GetReleaseContentResponse)(nil), "hapi.services.tiller.GetReleaseContentResponse")
+	proto.RegisterType((*UpdateReleaseRequest)(nil), "hapi.services.tiller.UpdateReleaseRequest")
+	proto.RegisterType((*UpdateReleaseResponse)(nil), "hapi.services.tiller.UpdateReleaseResponse")
+	proto.RegisterType((*RollbackReleaseRequest)(nil), "hapi.services.tiller.RollbackReleaseRequest")
+	proto.RegisterType((*RollbackReleaseResponse)(nil), "hapi.services.tiller.RollbackReleaseResponse")
+	proto.RegisterType((*InstallReleaseRequest)(nil), "hapi.services.tiller.InstallReleaseRequest")
+	proto.RegisterType((*InstallReleaseResponse)(nil), "hapi.services.tiller.InstallReleaseResponse")
+	proto.RegisterType((*UninstallReleaseRequest)(nil), "hapi.services.tiller.UninstallReleaseRequest")
+	proto.RegisterType((*UninstallReleaseResponse)(nil), "hapi.services.tiller.UninstallReleaseResponse")
+	proto.RegisterType((*GetVersionRequest)(nil), "hapi.services.tiller.GetVersionRequest")
+	proto.RegisterType((*GetVersionResponse)(nil), "hapi.services.tiller.GetVersionResponse")
+	proto.RegisterType((*GetHistoryRequest)(nil), "hapi.services.tiller.GetHistoryRequest")
+	proto.RegisterType((*GetHistoryResponse)(nil), "hapi.services.tiller.GetHistoryResponse")
+	proto.RegisterType((*TestReleaseRequest)(nil), "hapi.services.tiller.TestReleaseRequest")
+	proto.RegisterType((*TestReleaseResponse)(nil), "hapi.services.tiller.TestReleaseResponse")
+	proto.RegisterType((*DiffReleaseRequest)(nil), "hapi.services.tiller.DiffReleaseRequest")
+	proto.RegisterType((*ResourceDiff)(nil), "hapi.services.tiller.ResourceDiff")
+	proto.RegisterType((*DiffReleaseResponse)(nil), "hapi.services.tiller.DiffReleaseResponse")
+	proto.RegisterType((*WatchReleasesRequest)(nil), "hapi.services.tiller.WatchReleasesRequest")
+	proto.RegisterType((*ReleaseEvent)(nil), "hapi.services.tiller.ReleaseEvent")
+	proto.RegisterType((*WatchReleasesResponse)(nil), "hapi.services.tiller.WatchReleasesResponse")
+	proto.RegisterType((*CancelOperationRequest)(nil), "hapi.services.tiller.CancelOperationRequest")
+	proto.RegisterType((*CancelOperationResponse)(nil), "hapi.services.tiller.CancelOperationResponse")
+	proto.RegisterType((*RepairReleaseRequest)(nil), "hapi.services.tiller.RepairReleaseRequest")
+	proto.RegisterType((*RepairReleaseResponse)(nil), "hapi.services.tiller.RepairReleaseResponse")
+	proto.RegisterType((*CheckDriftRequest)(nil), "hapi.services.tiller.CheckDriftRequest")
+	proto.RegisterType((*FieldDrift)(nil), "hapi.services.tiller.FieldDrift")
+	proto.RegisterType((*ResourceDrift)(nil), "hapi.services.tiller.ResourceDrift")
+	proto.RegisterType((*CheckDriftResponse)(nil), "hapi.services.tiller.CheckDriftResponse")
+	proto.RegisterType((*AdoptedResource)(nil), "hapi.services.tiller.AdoptedResource")
+	proto.RegisterType((*RenameReleaseRequest)(nil), "hapi.services.tiller.RenameReleaseRequest")
+	proto.RegisterType((*RenameReleaseResponse)(nil), "hapi.services.tiller.RenameReleaseResponse")
+	proto.RegisterType((*ReleaseSetMember)(nil), "hapi.services.tiller.ReleaseSetMember")
+	proto.RegisterType((*ApplyReleaseSetRequest)(nil), "hapi.services.tiller.ApplyReleaseSetRequest")
+	proto.RegisterType((*ReleaseSetResult)(nil), "hapi.services.tiller.ReleaseSetResult")
+	proto.RegisterType((*ApplyReleaseSetResponse)(nil), "hapi.services.tiller.ApplyReleaseSetResponse")
+	proto.RegisterEnum("hapi.services.tiller.ListSort_SortBy", ListSort_SortBy_name, ListSort_SortBy_value)
+	proto.RegisterEnum("hapi.services.tiller.ListSort_SortOrder", ListSort_SortOrder_name, ListSort_SortOrder_value)
+	proto.RegisterEnum("hapi.services.tiller.ResourceDiff_Change", ResourceDiff_Change_name, ResourceDiff_Change_value)
+	proto.RegisterEnum("hapi.services.tiller.ReleaseEvent_Type", ReleaseEvent_Type_name, ReleaseEvent_Type_value)
+	proto.RegisterEnum("hapi.services.tiller.ReleaseSetResult_Status", ReleaseSetResult_Status_name, ReleaseSetResult_Status_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ReleaseServiceClient is the client API for ReleaseService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ReleaseServiceClient interface {
+	// ListReleases retrieves release history.
+	// TODO: Allow filtering the set of releases by
+	// release status. By default, ListAllReleases returns the releases who
+	// current status is "Active".
+	ListReleases(ctx context.Context, in *ListReleasesRequest, opts ...grpc.CallOption) (ReleaseService_ListReleasesClient, error)
+	// GetReleasesStatus retrieves status information for the specified release.
+	GetReleaseStatus(ctx context.Context, in *GetReleaseStatusRequest, opts ...grpc.CallOption) (*GetReleaseStatusResponse, error)
+	// GetReleaseContent retrieves the release content (chart + value) for the specified release.
+	GetReleaseContent(ctx context.Context, in *GetReleaseContentRequest, opts ...grpc.CallOption) (*GetReleaseContentResponse, error)
+	// UpdateRelease updates release content.
+	UpdateRelease(ctx context.Context, in *UpdateReleaseRequest, opts ...grpc.CallOption) (*UpdateReleaseResponse, error)
+	// InstallRelease requests installation of a chart as a new release.
+	InstallRelease(ctx context.Context, in *InstallReleaseRequest, opts ...grpc.CallOption) (*InstallReleaseResponse, error)
+	// UninstallRelease requests deletion of a named release.
+	UninstallRelease(ctx context.Context, in *UninstallReleaseRequest, opts ...grpc.CallOption) (*UninstallReleaseResponse, error)
+	// GetVersion returns the current version of the server.
+	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error)
+	// RollbackRelease rolls back a release to a previous version.
+	RollbackRelease(ctx context.Context, in *RollbackReleaseRequest, opts ...grpc.CallOption) (*RollbackReleaseResponse, error)
+	// ReleaseHistory retrieves a release's history.
+	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
+	// RunReleaseTest executes the tests defined of a named release
+	RunReleaseTest(ctx context.Context, in *TestReleaseRequest, opts ...grpc.CallOption) (ReleaseService_RunReleaseTestClient, error)
+	// DiffRelease compares a release with the release an update would create.
+	DiffRelease(ctx context.Context, in *DiffReleaseRequest, opts ...grpc.CallOption) (*DiffReleaseResponse, error)
+	// WatchReleases streams the changes made to releases as they happen.
+	WatchReleases(ctx context.Context, in *WatchReleasesRequest, opts ...grpc.CallOption) (ReleaseService_WatchReleasesClient, error)
+	// CancelOperation interrupts the install, upgrade or rollback of a release in progress.
+	CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...grpc.CallOption) (*CancelOperationResponse, error)
+	// RepairRelease fails the revision of a release left pending by an interrupted operation.
+	RepairRelease(ctx context.Context, in *RepairReleaseRequest, opts ...grpc.CallOption) (*RepairReleaseResponse, error)
+	// CheckDrift compares the deployed manifest of a release with the live state of its resources.
+	CheckDrift(ctx context.Context, in *CheckDriftRequest, opts ...grpc.CallOption) (*CheckDriftResponse, error)
+	// RenameRelease renames a release, or moves it to another namespace, keeping its history.
+	RenameRelease(ctx context.Context, in *RenameReleaseRequest, opts ...grpc.CallOption) (*RenameReleaseResponse, error)
+	// ApplyReleaseSet installs or upgrades a set of releases in the order of their dependencies.
+	ApplyReleaseSet(ctx context.Context, in *ApplyReleaseSetRequest, opts ...grpc.CallOption) (*ApplyReleaseSetResponse, error)
+}
+
+type releaseServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewReleaseServiceClient(cc *grpc.ClientConn) ReleaseServiceClient {
+	return &releaseServiceClient{cc}
+}
+
+func (c *releaseServiceClient) ListReleases(ctx context.Context, in *ListReleasesRequest, opts ...grpc.CallOption) (ReleaseService_ListReleasesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ReleaseService_serviceDesc.Streams[0], "/hapi.services.tiller.ReleaseService/ListReleases", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &releaseServiceListReleasesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ReleaseService_ListReleasesClient interface {
+	Recv() (*ListReleasesResponse, error)
+	grpc.ClientStream
+}
+
+type releaseServiceListReleasesClient struct {
+	grpc.ClientStream
+}
+
+func (x *releaseServiceListReleasesClient) Recv() (*ListReleasesResponse, error) {
+	m := new(ListReleasesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *releaseServiceClient) GetReleaseStatus(ctx context.Context, in *GetReleaseStatusRequest, opts ...grpc.CallOption) (*GetReleaseStatusResponse, error) {
+	out := new(GetReleaseStatusResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetReleaseContent(ctx context.Context, in *GetReleaseContentRequest, opts ...grpc.CallOption) (*GetReleaseContentResponse, error) {
+	out := new(GetReleaseContentResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseContent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) UpdateRelease(ctx context.Context, in *UpdateReleaseRequest, opts ...grpc.CallOption) (*UpdateReleaseResponse, error) {
+	out := new(UpdateReleaseResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/UpdateRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) InstallRelease(ctx context.Context, in *InstallReleaseRequest, opts ...grpc.CallOption) (*InstallReleaseResponse, error) {
+	out := new(InstallReleaseResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/InstallRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) UninstallRelease(ctx context.Context, in *UninstallReleaseRequest, opts ...grpc.CallOption) (*UninstallReleaseResponse, error) {
+	out := new(UninstallReleaseResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/UninstallRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error) {
+	out := new(GetVersionResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) RollbackRelease(ctx context.Context, in *RollbackReleaseRequest, opts ...grpc.CallOption) (*RollbackReleaseResponse, error) {
 	out := new(RollbackReleaseResponse)
 	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/RollbackRelease", in, out, opts...)
 	if err != nil {
@@ -1620,6 +3165,92 @@ func (x *releaseServiceRunReleaseTestClient) Recv() (*TestReleaseResponse, error
 	return m, nil
 }
 
+func (c *releaseServiceClient) DiffRelease(ctx context.Context, in *DiffReleaseRequest, opts ...grpc.CallOption) (*DiffReleaseResponse, error) {
+	out := new(DiffReleaseResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/DiffRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) WatchReleases(ctx context.Context, in *WatchReleasesRequest, opts ...grpc.CallOption) (ReleaseService_WatchReleasesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ReleaseService_serviceDesc.Streams[2], "/hapi.services.tiller.ReleaseService/WatchReleases", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &releaseServiceWatchReleasesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ReleaseService_WatchReleasesClient interface {
+	Recv() (*WatchReleasesResponse, error)
+	grpc.ClientStream
+}
+
+type releaseServiceWatchReleasesClient struct {
+	grpc.ClientStream
+}
+
+func (x *releaseServiceWatchReleasesClient) Recv() (*WatchReleasesResponse, error) {
+	m := new(WatchReleasesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *releaseServiceClient) CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...grpc.CallOption) (*CancelOperationResponse, error) {
+	out := new(CancelOperationResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/CancelOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) RepairRelease(ctx context.Context, in *RepairReleaseRequest, opts ...grpc.CallOption) (*RepairReleaseResponse, error) {
+	out := new(RepairReleaseResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/RepairRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) CheckDrift(ctx context.Context, in *CheckDriftRequest, opts ...grpc.CallOption) (*CheckDriftResponse, error) {
+	out := new(CheckDriftResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/CheckDrift", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) RenameRelease(ctx context.Context, in *RenameReleaseRequest, opts ...grpc.CallOption) (*RenameReleaseResponse, error) {
+	out := new(RenameReleaseResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/RenameRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) ApplyReleaseSet(ctx context.Context, in *ApplyReleaseSetRequest, opts ...grpc.CallOption) (*ApplyReleaseSetResponse, error) {
+	out := new(ApplyReleaseSetResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/ApplyReleaseSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ReleaseServiceServer is the server API for ReleaseService service.
 type ReleaseServiceServer interface {
 	// ListReleases retrieves release history.
@@ -1645,6 +3276,20 @@ type ReleaseServiceServer interface {
 	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
 	// RunReleaseTest executes the tests defined of a named release
 	RunReleaseTest(*TestReleaseRequest, ReleaseService_RunReleaseTestServer) error
+	// DiffRelease compares a release with the release an update would create.
+	DiffRelease(context.Context, *DiffReleaseRequest) (*DiffReleaseResponse, error)
+	// WatchReleases streams the changes made to releases as they happen.
+	WatchReleases(*WatchReleasesRequest, ReleaseService_WatchReleasesServer) error
+	// CancelOperation interrupts the install, upgrade or rollback of a release in progress.
+	CancelOperation(context.Context, *CancelOperationRequest) (*CancelOperationResponse, error)
+	// RepairRelease fails the revision of a release left pending by an interrupted operation.
+	RepairRelease(context.Context, *RepairReleaseRequest) (*RepairReleaseResponse, error)
+	// CheckDrift compares the deployed manifest of a release with the live state of its resources.
+	CheckDrift(context.Context, *CheckDriftRequest) (*CheckDriftResponse, error)
+	// RenameRelease renames a release, or moves it to another namespace, keeping its history.
+	RenameRelease(context.Context, *RenameReleaseRequest) (*RenameReleaseResponse, error)
+	// ApplyReleaseSet installs or upgrades a set of releases in the order of their dependencies.
+	ApplyReleaseSet(context.Context, *ApplyReleaseSetRequest) (*ApplyReleaseSetResponse, error)
 }
 
 func RegisterReleaseServiceServer(s *grpc.Server, srv ReleaseServiceServer) {
@@ -1837,6 +3482,135 @@ func (x *releaseServiceRunReleaseTestServer) Send(m *TestReleaseResponse) error
 	return x.ServerStream.SendMsg(m)
 }
 
+func _ReleaseService_DiffRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).DiffRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/DiffRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).DiffRelease(ctx, req.(*DiffReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_WatchReleases_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchReleasesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReleaseServiceServer).WatchReleases(m, &releaseServiceWatchReleasesServer{stream})
+}
+
+type ReleaseService_WatchReleasesServer interface {
+	Send(*WatchReleasesResponse) error
+	grpc.ServerStream
+}
+
+type releaseServiceWatchReleasesServer struct {
+	grpc.ServerStream
+}
+
+func (x *releaseServiceWatchReleasesServer) Send(m *WatchReleasesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ReleaseService_CancelOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).CancelOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/CancelOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).CancelOperation(ctx, req.(*CancelOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_RepairRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RepairReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).RepairRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/RepairRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).RepairRelease(ctx, req.(*RepairReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_CheckDrift_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckDriftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).CheckDrift(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/CheckDrift",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).CheckDrift(ctx, req.(*CheckDriftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_RenameRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).RenameRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/RenameRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).RenameRelease(ctx, req.(*RenameReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_ApplyReleaseSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyReleaseSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).ApplyReleaseSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/ApplyReleaseSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).ApplyReleaseSet(ctx, req.(*ApplyReleaseSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hapi.services.tiller.ReleaseService",
 	HandlerType: (*ReleaseServiceServer)(nil),
@@ -1873,6 +3647,30 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetHistory",
 			Handler:    _ReleaseService_GetHistory_Handler,
 		},
+		{
+			MethodName: "DiffRelease",
+			Handler:    _ReleaseService_DiffRelease_Handler,
+		},
+		{
+			MethodName: "CancelOperation",
+			Handler:    _ReleaseService_CancelOperation_Handler,
+		},
+		{
+			MethodName: "RepairRelease",
+			Handler:    _ReleaseService_RepairRelease_Handler,
+		},
+		{
+			MethodName: "CheckDrift",
+			Handler:    _ReleaseService_CheckDrift_Handler,
+		},
+		{
+			MethodName: "RenameRelease",
+			Handler:    _ReleaseService_RenameRelease_Handler,
+		},
+		{
+			MethodName: "ApplyReleaseSet",
+			Handler:    _ReleaseService_ApplyReleaseSet_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1885,96 +3683,170 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _ReleaseService_RunReleaseTest_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchReleases",
+			Handler:       _ReleaseService_WatchReleases_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "hapi/services/tiller.proto",
 }
 
-func init() { proto.RegisterFile("hapi/services/tiller.proto", fileDescriptor_tiller_bb72ee4a42494734) }
-
-var fileDescriptor_tiller_bb72ee4a42494734 = []byte{
-	// 1337 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x58, 0xdd, 0x72, 0xdb, 0x44,
-	0x14, 0x8e, 0x2d, 0xff, 0x1e, 0x27, 0xae, 0xbb, 0x4d, 0x13, 0xd5, 0x14, 0x26, 0x88, 0xa1, 0x75,
-	0x0b, 0x75, 0x20, 0x70, 0xc3, 0x0c, 0xc3, 0x4c, 0xea, 0x86, 0xa4, 0x10, 0xd2, 0x19, 0xb9, 0x2d,
-	0x33, 0xcc, 0x30, 0x1e, 0xc5, 0x5e, 0xb7, 0xa2, 0xb2, 0xd6, 0x68, 0x57, 0xa1, 0x79, 0x04, 0x2e,
-	0x79, 0x07, 0xae, 0x79, 0x06, 0x6e, 0x79, 0x06, 0x5e, 0x86, 0xd9, 0x3f, 0x45, 0x2b, 0x4b, 0xae,
-	0xc8, 0x4d, 0xac, 0xdd, 0x73, 0xf6, 0xfc, 0x7c, 0xdf, 0x9e, 0xb3, 0x67, 0x02, 0xfd, 0xd7, 0xde,
-	0xd2, 0xdf, 0xa7, 0x38, 0xba, 0xf0, 0xa7, 0x98, 0xee, 0x33, 0x3f, 0x08, 0x70, 0x34, 0x5c, 0x46,
-	0x84, 0x11, 0xb4, 0xcd, 0x65, 0x43, 0x2d, 0x1b, 0x4a, 0x59, 0x7f, 0x47, 0x9c, 0x98, 0xbe, 0xf6,
-	0x22, 0x26, 0xff, 0x4a, 0xed, 0xfe, 0x6e, 0x7a, 0x9f, 0x84, 0x73, 0xff, 0x95, 0x12, 0x48, 0x17,
-	0x11, 0x0e, 0xb0, 0x47, 0xb1, 0xfe, 0x35, 0x0e, 0x69, 0x99, 0x1f, 0xce, 0x89, 0x12, 0xbc, 0x67,
-	0x08, 0x18, 0xa6, 0x6c, 0x12, 0xc5, 0xa1, 0x12, 0xde, 0x31, 0x84, 0x94, 0x79, 0x2c, 0xa6, 0x86,
-	0xb3, 0x0b, 0x1c, 0x51, 0x9f, 0x84, 0xfa, 0x57, 0xca, 0x9c, 0xbf, 0xab, 0x70, 0xeb, 0xd4, 0xa7,
-	0xcc, 0x95, 0x07, 0xa9, 0x8b, 0x7f, 0x8d, 0x31, 0x65, 0x68, 0x1b, 0xea, 0x81, 0xbf, 0xf0, 0x99,
-	0x5d, 0xd9, 0xab, 0x0c, 0x2c, 0x57, 0x2e, 0xd0, 0x0e, 0x34, 0xc8, 0x7c, 0x4e, 0x31, 0xb3, 0xab,
-	0x7b, 0x95, 0x41, 0xdb, 0x55, 0x2b, 0xf4, 0x0d, 0x34, 0x29, 0x89, 0xd8, 0xe4, 0xfc, 0xd2, 0xb6,
-	0xf6, 0x2a, 0x83, 0xee, 0xc1, 0xc7, 0xc3, 0x3c, 0x9c, 0x86, 0xdc, 0xd3, 0x98, 0x44, 0x6c, 0xc8,
-	0xff, 0x3c, 0xbe, 0x74, 0x1b, 0x54, 0xfc, 0x72, 0xbb, 0x73, 0x3f, 0x60, 0x38, 0xb2, 0x6b, 0xd2,
-	0xae, 0x5c, 0xa1, 0x63, 0x00, 0x61, 0x97, 0x44, 0x33, 0x1c, 0xd9, 0x75, 0x61, 0x7a, 0x50, 0xc2,
-	0xf4, 0x33, 0xae, 0xef, 0xb6, 0xa9, 0xfe, 0x44, 0x5f, 0xc3, 0xa6, 0x84, 0x64, 0x32, 0x25, 0x33,
-	0x4c, 0xed, 0xc6, 0x9e, 0x35, 0xe8, 0x1e, 0xdc, 0x91, 0xa6, 0x34, 0xfc, 0x63, 0x09, 0xda, 0x88,
-	0xcc, 0xb0, 0xdb, 0x91, 0xea, 0xfc, 0x9b, 0xa2, 0xbb, 0xd0, 0x0e, 0xbd, 0x05, 0xa6, 0x4b, 0x6f,
-	0x8a, 0xed, 0xa6, 0x88, 0xf0, 0x6a, 0xc3, 0x09, 0xa1, 0xa5, 0x9d, 0x3b, 0x8f, 0xa1, 0x21, 0x53,
-	0x43, 0x1d, 0x68, 0xbe, 0x38, 0xfb, 0xfe, 0xec, 0xd9, 0x8f, 0x67, 0xbd, 0x0d, 0xd4, 0x82, 0xda,
-	0xd9, 0xe1, 0x0f, 0x47, 0xbd, 0x0a, 0xba, 0x09, 0x5b, 0xa7, 0x87, 0xe3, 0xe7, 0x13, 0xf7, 0xe8,
-	0xf4, 0xe8, 0x70, 0x7c, 0xf4, 0xa4, 0x57, 0x45, 0x5d, 0x80, 0xd1, 0xc9, 0xa1, 0xfb, 0x7c, 0x22,
-	0x54, 0x2c, 0xe7, 0x03, 0x68, 0x27, 0x39, 0xa0, 0x26, 0x58, 0x87, 0xe3, 0x91, 0x34, 0xf1, 0xe4,
-	0x68, 0x3c, 0xea, 0x55, 0x9c, 0xdf, 0x2b, 0xb0, 0x6d, 0x52, 0x46, 0x97, 0x24, 0xa4, 0x98, 0x73,
-	0x36, 0x25, 0x71, 0x98, 0x70, 0x26, 0x16, 0x08, 0x41, 0x2d, 0xc4, 0x6f, 0x35, 0x63, 0xe2, 0x9b,
-	0x6b, 0x32, 0xc2, 0xbc, 0x40, 0xb0, 0x65, 0xb9, 0x72, 0x81, 0x3e, 0x87, 0x96, 0x82, 0x82, 0xda,
-	0xb5, 0x3d, 0x6b, 0xd0, 0x39, 0xb8, 0x6d, 0x02, 0xa4, 0x3c, 0xba, 0x89, 0x9a, 0x73, 0x0c, 0xbb,
-	0xc7, 0x58, 0x47, 0x22, 0xf1, 0xd3, 0x37, 0x88, 0xfb, 0xf5, 0x16, 0x58, 0x04, 0xc3, 0xfd, 0x7a,
-	0x0b, 0x8c, 0x6c, 0x68, 0xaa, 0xeb, 0x27, 0xc2, 0xa9, 0xbb, 0x7a, 0xe9, 0x30, 0xb0, 0x57, 0x0d,
-	0xa9, 0xbc, 0xf2, 0x2c, 0xdd, 0x83, 0x1a, 0xaf, 0x0c, 0x61, 0xa6, 0x73, 0x80, 0xcc, 0x38, 0x9f,
-	0x86, 0x73, 0xe2, 0x0a, 0xb9, 0x49, 0x9d, 0x95, 0xa5, 0xee, 0x24, 0xed, 0x75, 0x44, 0x42, 0x86,
-	0x43, 0x76, 0xbd, 0xf8, 0x4f, 0xe1, 0x4e, 0x8e, 0x25, 0x95, 0xc0, 0x3e, 0x34, 0x55, 0x68, 0xc2,
-	0x5a, 0x21, 0xae, 0x5a, 0xcb, 0xf9, 0xc7, 0x82, 0xed, 0x17, 0xcb, 0x99, 0xc7, 0xb0, 0x16, 0xad,
-	0x09, 0xea, 0x3e, 0xd4, 0x45, 0x87, 0x51, 0x58, 0xdc, 0x94, 0xb6, 0x65, 0x1b, 0x1a, 0xf1, 0xbf,
-	0xae, 0x94, 0xa3, 0x87, 0xd0, 0xb8, 0xf0, 0x82, 0x18, 0x53, 0x01, 0x44, 0x82, 0x9a, 0xd2, 0x14,
-	0xed, 0xc9, 0x55, 0x1a, 0x68, 0x17, 0x9a, 0xb3, 0xe8, 0x92, 0xf7, 0x17, 0x51, 0x92, 0x2d, 0xb7,
-	0x31, 0x8b, 0x2e, 0xdd, 0x38, 0x44, 0x1f, 0xc1, 0xd6, 0xcc, 0xa7, 0xde, 0x79, 0x80, 0x27, 0xaf,
-	0x09, 0x79, 0x43, 0x45, 0x55, 0xb6, 0xdc, 0x4d, 0xb5, 0x79, 0xc2, 0xf7, 0x50, 0x9f, 0xdf, 0xa4,
-	0x69, 0x84, 0x3d, 0x86, 0xed, 0x86, 0x90, 0x27, 0x6b, 0x8e, 0x21, 0xf3, 0x17, 0x98, 0xc4, 0x4c,
-	0x94, 0x92, 0xe5, 0xea, 0x25, 0xfa, 0x10, 0x36, 0x23, 0x4c, 0x31, 0x9b, 0xa8, 0x28, 0x5b, 0xe2,
-	0x64, 0x47, 0xec, 0xbd, 0x94, 0x61, 0x21, 0xa8, 0xfd, 0xe6, 0xf9, 0xcc, 0x6e, 0x0b, 0x91, 0xf8,
-	0x96, 0xc7, 0x62, 0x8a, 0xf5, 0x31, 0xd0, 0xc7, 0x62, 0x8a, 0xd5, 0xb1, 0x6d, 0xa8, 0xcf, 0x49,
-	0x34, 0xc5, 0x76, 0x47, 0xc8, 0xe4, 0x02, 0xed, 0x41, 0x67, 0x86, 0xe9, 0x34, 0xf2, 0x97, 0x8c,
-	0x33, 0xba, 0x29, 0x30, 0x4d, 0x6f, 0xf1, 0x3c, 0x68, 0x7c, 0x7e, 0x46, 0x18, 0xa6, 0xf6, 0x96,
-	0xcc, 0x43, 0xaf, 0xd1, 0x3d, 0xb8, 0x31, 0x0d, 0xb0, 0x17, 0xc6, 0xcb, 0x09, 0x09, 0x27, 0x73,
-	0xcf, 0x0f, 0xec, 0xae, 0x50, 0xd9, 0x52, 0xdb, 0xcf, 0xc2, 0x6f, 0x3d, 0x3f, 0x70, 0x4e, 0xe0,
-	0x76, 0x86, 0xca, 0xeb, 0xde, 0x8a, 0xbf, 0xaa, 0xb0, 0xe3, 0x92, 0x20, 0x38, 0xf7, 0xa6, 0x6f,
-	0x4a, 0xdc, 0x8b, 0x14, 0x85, 0xd5, 0xf5, 0x14, 0x5a, 0x39, 0x14, 0xa6, 0xae, 0x7a, 0xcd, 0xb8,
-	0xea, 0x06, 0xb9, 0xf5, 0x62, 0x72, 0x1b, 0x26, 0xb9, 0x9a, 0xb9, 0x66, 0x8a, 0xb9, 0x84, 0x96,
-	0xd6, 0x1a, 0x5a, 0xda, 0xab, 0xb4, 0xe4, 0x40, 0x0f, 0x79, 0xd0, 0x7f, 0x07, 0xbb, 0x2b, 0x78,
-	0x5d, 0x17, 0xfc, 0x3f, 0x2c, 0xb8, 0xfd, 0x34, 0xa4, 0xcc, 0x0b, 0x82, 0x0c, 0xf6, 0x49, 0xfd,
-	0x55, 0x4a, 0xd7, 0x5f, 0xf5, 0xff, 0xd4, 0x9f, 0x65, 0x90, 0xa7, 0x99, 0xae, 0xa5, 0x98, 0x2e,
-	0x55, 0x93, 0x46, 0x27, 0x6c, 0x64, 0x3a, 0x21, 0x7a, 0x1f, 0x40, 0x16, 0x91, 0x30, 0x2e, 0x49,
-	0x6a, 0x8b, 0x9d, 0x33, 0xd5, 0xf8, 0x34, 0xaf, 0xad, 0x7c, 0x5e, 0xd3, 0x15, 0x39, 0x80, 0x9e,
-	0x8e, 0x67, 0x1a, 0xcd, 0x44, 0x4c, 0x8a, 0xa0, 0xae, 0xda, 0x1f, 0x45, 0x33, 0x1e, 0x55, 0x96,
-	0xeb, 0xce, 0xfa, 0x12, 0xdc, 0x34, 0x4b, 0xd0, 0x79, 0x0a, 0x3b, 0x59, 0x4a, 0xae, 0x4b, 0xef,
-	0x9f, 0x15, 0xd8, 0x7d, 0x11, 0xfa, 0xb9, 0x04, 0xe7, 0x15, 0xd7, 0x0a, 0xe4, 0xd5, 0x1c, 0xc8,
-	0xb7, 0xa1, 0xbe, 0x8c, 0xa3, 0x57, 0x58, 0x51, 0x28, 0x17, 0x69, 0x2c, 0x6b, 0x26, 0x96, 0x19,
-	0x34, 0xea, 0x2b, 0x68, 0x38, 0x13, 0xb0, 0x57, 0xa3, 0xbc, 0x66, 0xce, 0x3c, 0xaf, 0xe4, 0x0d,
-	0x6d, 0xcb, 0xf7, 0xd2, 0xb9, 0x05, 0x37, 0x8f, 0x31, 0x7b, 0x29, 0x4b, 0x5d, 0x01, 0xe0, 0x1c,
-	0x01, 0x4a, 0x6f, 0x5e, 0xf9, 0x53, 0x5b, 0xa6, 0x3f, 0x3d, 0x60, 0x6a, 0x7d, 0xad, 0xe5, 0x7c,
-	0x25, 0x6c, 0x9f, 0xf8, 0x94, 0x91, 0xe8, 0x72, 0x1d, 0xb8, 0x3d, 0xb0, 0x16, 0xde, 0x5b, 0xf5,
-	0xc4, 0xf2, 0x4f, 0xe7, 0x58, 0x44, 0x90, 0x1c, 0x55, 0x11, 0xa4, 0x07, 0x96, 0x4a, 0xb9, 0x81,
-	0xe5, 0x2d, 0xa0, 0xe7, 0x38, 0x99, 0x9d, 0xde, 0xf1, 0xd6, 0x6b, 0x9a, 0xaa, 0x26, 0x4d, 0x36,
-	0x34, 0x55, 0x9f, 0x51, 0xc4, 0xea, 0x25, 0xbf, 0xac, 0x4b, 0x2f, 0xf2, 0x82, 0x00, 0x07, 0xea,
-	0xd9, 0x4c, 0xd6, 0xce, 0xcf, 0x70, 0xcb, 0xf0, 0xac, 0x72, 0xe0, 0xb9, 0xd2, 0x57, 0xca, 0x33,
-	0xff, 0x44, 0x5f, 0x42, 0x43, 0x0e, 0x9f, 0xc2, 0x6f, 0xf7, 0xe0, 0xae, 0x99, 0x93, 0x30, 0x12,
-	0x87, 0x6a, 0x5a, 0x75, 0x95, 0xee, 0xc1, 0xbf, 0x2d, 0xe8, 0xea, 0xf1, 0x49, 0x8e, 0xc6, 0xc8,
-	0x87, 0xcd, 0xf4, 0x9c, 0x88, 0x1e, 0x14, 0x4f, 0xce, 0x99, 0xf1, 0xbf, 0xff, 0xb0, 0x8c, 0xaa,
-	0xcc, 0xc0, 0xd9, 0xf8, 0xac, 0x82, 0x28, 0xf4, 0xb2, 0xe3, 0x1b, 0x7a, 0x94, 0x6f, 0xa3, 0x60,
-	0x5e, 0xec, 0x0f, 0xcb, 0xaa, 0x6b, 0xb7, 0xe8, 0x42, 0xdc, 0x27, 0x73, 0xe6, 0x42, 0xef, 0x34,
-	0x63, 0x8e, 0x79, 0xfd, 0xfd, 0xd2, 0xfa, 0x89, 0xdf, 0x5f, 0x60, 0xcb, 0x78, 0xd1, 0x51, 0x01,
-	0x5a, 0x79, 0x13, 0x5c, 0xff, 0x93, 0x52, 0xba, 0x89, 0xaf, 0x05, 0x74, 0xcd, 0x16, 0x87, 0x0a,
-	0x0c, 0xe4, 0xbe, 0x4d, 0xfd, 0x4f, 0xcb, 0x29, 0x27, 0xee, 0x28, 0xf4, 0xb2, 0xfd, 0xa5, 0x88,
-	0xc7, 0x82, 0x6e, 0x59, 0xc4, 0x63, 0x51, 0xdb, 0x72, 0x36, 0x90, 0x07, 0x70, 0xd5, 0x5e, 0xd0,
-	0xfd, 0x42, 0x42, 0xcc, 0xae, 0xd4, 0x1f, 0xbc, 0x5b, 0x31, 0x71, 0xb1, 0x84, 0x1b, 0x99, 0x49,
-	0x00, 0x15, 0x40, 0x93, 0x3f, 0x60, 0xf5, 0x1f, 0x95, 0xd4, 0xce, 0x24, 0xa5, 0x3a, 0xd6, 0x9a,
-	0xa4, 0xcc, 0x76, 0xb8, 0x26, 0xa9, 0x4c, 0xf3, 0x73, 0x36, 0x90, 0x0f, 0x5d, 0x37, 0x0e, 0x95,
-	0x6b, 0xde, 0x16, 0x50, 0xc1, 0xe9, 0xd5, 0x8e, 0xd7, 0x7f, 0x50, 0x42, 0xf3, 0xaa, 0xbe, 0x1f,
-	0xc3, 0x4f, 0x2d, 0xad, 0x7a, 0xde, 0x10, 0xff, 0x39, 0xf8, 0xe2, 0xbf, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x20, 0xcd, 0x9e, 0x3a, 0x27, 0x11, 0x00, 0x00,
+func init() { proto.RegisterFile("hapi/services/tiller.proto", fileDescriptor_tiller_2602b9adba2a7693) }
+
+var fileDescriptor_tiller_2602b9adba2a7693 = []byte{
+	// 2436 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x39, 0xdb, 0x6e, 0xdb, 0xc8,
+	0xd9, 0x2b, 0x51, 0xc7, 0x4f, 0xb6, 0xac, 0x4c, 0x1c, 0x9b, 0xe1, 0x1e, 0x7e, 0xff, 0x5c, 0x6c,
+	0xe2, 0x24, 0x8d, 0xdc, 0x75, 0x0b, 0x74, 0x8b, 0x9e, 0x56, 0x91, 0x14, 0xdb, 0x1b, 0xc7, 0x76,
+	0x29, 0x3b, 0x01, 0x5a, 0x14, 0x02, 0x2d, 0x8d, 0x6c, 0x36, 0x14, 0xc9, 0x92, 0x23, 0x27, 0x46,
+	0x5f, 0xa0, 0xed, 0x53, 0xf4, 0xa2, 0x37, 0xbd, 0xe9, 0x75, 0x2f, 0x8b, 0x3e, 0x40, 0x7b, 0x51,
+	0xa0, 0x77, 0x7d, 0x87, 0xf6, 0x0d, 0x8a, 0x39, 0x51, 0x24, 0x45, 0x5a, 0x8c, 0xbb, 0xc0, 0xde,
+	0x58, 0x9c, 0x99, 0xef, 0x30, 0xdf, 0x69, 0xbe, 0x83, 0x41, 0xbb, 0x34, 0x3d, 0x6b, 0x27, 0xc0,
+	0xfe, 0x95, 0x35, 0xc2, 0xc1, 0x0e, 0xb1, 0x6c, 0x1b, 0xfb, 0x6d, 0xcf, 0x77, 0x89, 0x8b, 0xd6,
+	0xe9, 0x59, 0x5b, 0x9e, 0xb5, 0xf9, 0x99, 0xb6, 0xc1, 0x30, 0x46, 0x97, 0xa6, 0x4f, 0xf8, 0x5f,
+	0x0e, 0xad, 0x6d, 0x46, 0xf7, 0x5d, 0x67, 0x62, 0x5d, 0x88, 0x03, 0xce, 0xc2, 0xc7, 0x36, 0x36,
+	0x03, 0x2c, 0x7f, 0x63, 0x48, 0xf2, 0xcc, 0x72, 0x26, 0xae, 0x38, 0xf8, 0x30, 0x76, 0x40, 0x70,
+	0x40, 0x86, 0xfe, 0xcc, 0x11, 0x87, 0xf7, 0x63, 0x87, 0x01, 0x31, 0xc9, 0x2c, 0x88, 0x31, 0xbb,
+	0xc2, 0x7e, 0x60, 0xb9, 0x8e, 0xfc, 0x4d, 0x65, 0x76, 0xe9, 0xba, 0x6f, 0xc4, 0xc1, 0xff, 0x5d,
+	0xb8, 0xee, 0x85, 0x8d, 0x77, 0xd8, 0xea, 0x7c, 0x36, 0xd9, 0x21, 0xd6, 0x14, 0x07, 0xc4, 0x9c,
+	0x7a, 0x1c, 0x40, 0xff, 0x4b, 0x11, 0xee, 0x1e, 0x5a, 0x01, 0x31, 0x38, 0x6e, 0x60, 0xe0, 0x5f,
+	0xcd, 0x70, 0x40, 0xd0, 0x3a, 0x94, 0x6d, 0x6b, 0x6a, 0x11, 0xb5, 0xb0, 0x55, 0xd8, 0x56, 0x0c,
+	0xbe, 0x40, 0x1b, 0x50, 0x71, 0x27, 0x93, 0x00, 0x13, 0xb5, 0xb8, 0x55, 0xd8, 0xae, 0x1b, 0x62,
+	0x85, 0x7e, 0x0c, 0xd5, 0xc0, 0xf5, 0xc9, 0xf0, 0xfc, 0x5a, 0x55, 0xb6, 0x0a, 0xdb, 0xcd, 0xdd,
+	0xcf, 0xda, 0x69, 0x1a, 0x6e, 0x53, 0x4e, 0x03, 0xd7, 0x27, 0x6d, 0xfa, 0xe7, 0xd9, 0xb5, 0x51,
+	0x09, 0xd8, 0x2f, 0xa5, 0x3b, 0xb1, 0x6c, 0x82, 0x7d, 0xb5, 0xc4, 0xe9, 0xf2, 0x15, 0xda, 0x03,
+	0x60, 0x74, 0x5d, 0x7f, 0x8c, 0x7d, 0xb5, 0xcc, 0x48, 0x6f, 0xe7, 0x20, 0x7d, 0x4c, 0xe1, 0x8d,
+	0x7a, 0x20, 0x3f, 0xd1, 0x0f, 0x61, 0x85, 0x2b, 0x73, 0x38, 0x72, 0xc7, 0x38, 0x50, 0x2b, 0x5b,
+	0xca, 0x76, 0x73, 0xf7, 0x3e, 0x27, 0x25, 0x0d, 0x37, 0xe0, 0xea, 0xee, 0xba, 0x63, 0x6c, 0x34,
+	0x38, 0x38, 0xfd, 0x0e, 0xd0, 0x47, 0x50, 0x77, 0xcc, 0x29, 0x0e, 0x3c, 0x73, 0x84, 0xd5, 0x2a,
+	0xbb, 0xe1, 0x7c, 0x43, 0x77, 0xa0, 0x26, 0x99, 0xeb, 0xcf, 0xa0, 0xc2, 0x45, 0x43, 0x0d, 0xa8,
+	0x9e, 0x1d, 0xbd, 0x38, 0x3a, 0x7e, 0x7d, 0xd4, 0xfa, 0x00, 0xd5, 0xa0, 0x74, 0xd4, 0x79, 0xd9,
+	0x6f, 0x15, 0xd0, 0x1d, 0x58, 0x3d, 0xec, 0x0c, 0x4e, 0x87, 0x46, 0xff, 0xb0, 0xdf, 0x19, 0xf4,
+	0x7b, 0xad, 0x22, 0x6a, 0x02, 0x74, 0xf7, 0x3b, 0xc6, 0xe9, 0x90, 0x81, 0x28, 0xfa, 0x27, 0x50,
+	0x0f, 0x65, 0x40, 0x55, 0x50, 0x3a, 0x83, 0x2e, 0x27, 0xd1, 0xeb, 0x0f, 0xba, 0xad, 0x82, 0xfe,
+	0xdb, 0x02, 0xac, 0xc7, 0x4d, 0x16, 0x78, 0xae, 0x13, 0x60, 0x6a, 0xb3, 0x91, 0x3b, 0x73, 0x42,
+	0x9b, 0xb1, 0x05, 0x42, 0x50, 0x72, 0xf0, 0x3b, 0x69, 0x31, 0xf6, 0x4d, 0x21, 0x89, 0x4b, 0x4c,
+	0x9b, 0x59, 0x4b, 0x31, 0xf8, 0x02, 0x7d, 0x0e, 0x35, 0xa1, 0x8a, 0x40, 0x2d, 0x6d, 0x29, 0xdb,
+	0x8d, 0xdd, 0x7b, 0x71, 0x05, 0x09, 0x8e, 0x46, 0x08, 0xa6, 0xef, 0xc1, 0xe6, 0x1e, 0x96, 0x37,
+	0xe1, 0xfa, 0x93, 0x1e, 0x44, 0xf9, 0x9a, 0x53, 0xac, 0x16, 0x04, 0x5f, 0x73, 0x8a, 0x91, 0x0a,
+	0x55, 0xe1, 0xb8, 0xec, 0x3a, 0x65, 0x43, 0x2e, 0x75, 0x02, 0xea, 0x22, 0x21, 0x21, 0x57, 0x1a,
+	0xa5, 0x07, 0x50, 0xa2, 0x31, 0xc5, 0xc8, 0x34, 0x76, 0x51, 0xfc, 0x9e, 0x07, 0xce, 0xc4, 0x35,
+	0xd8, 0x79, 0xdc, 0x74, 0x4a, 0xd2, 0x74, 0xfb, 0x51, 0xae, 0x5d, 0xd7, 0x21, 0xd8, 0x21, 0xb7,
+	0xbb, 0xff, 0x21, 0xdc, 0x4f, 0xa1, 0x24, 0x04, 0xd8, 0x81, 0xaa, 0xb8, 0x1a, 0xa3, 0x96, 0xa9,
+	0x57, 0x09, 0xa5, 0xff, 0x5b, 0x81, 0xf5, 0x33, 0x6f, 0x6c, 0x12, 0x2c, 0x8f, 0x6e, 0xb8, 0xd4,
+	0x43, 0x28, 0xb3, 0xb7, 0x49, 0xe8, 0xe2, 0x0e, 0xa7, 0xcd, 0xb6, 0xda, 0x5d, 0xfa, 0xd7, 0xe0,
+	0xe7, 0xe8, 0x31, 0x54, 0xae, 0x4c, 0x7b, 0x86, 0x03, 0x55, 0x89, 0x6a, 0x4d, 0x40, 0xb2, 0x87,
+	0xcd, 0x10, 0x10, 0x68, 0x13, 0xaa, 0x63, 0xff, 0x9a, 0xbe, 0x4c, 0x2c, 0x24, 0x6b, 0x46, 0x65,
+	0xec, 0x5f, 0x1b, 0x33, 0x07, 0x7d, 0x0a, 0xab, 0x63, 0x2b, 0x30, 0xcf, 0x6d, 0x3c, 0xa4, 0xef,
+	0x4c, 0xc0, 0xa2, 0xb2, 0x66, 0xac, 0x88, 0xcd, 0x7d, 0xba, 0x87, 0x34, 0xea, 0x49, 0x23, 0x1f,
+	0x9b, 0x04, 0xab, 0x15, 0x76, 0x1e, 0xae, 0xa9, 0x0e, 0xe9, 0x23, 0xe4, 0xce, 0x08, 0x0b, 0x25,
+	0xc5, 0x90, 0x4b, 0xf4, 0xff, 0xb0, 0xe2, 0xe3, 0x00, 0x93, 0xa1, 0xb8, 0x65, 0x8d, 0x61, 0x36,
+	0xd8, 0xde, 0x2b, 0x7e, 0x2d, 0x04, 0xa5, 0xb7, 0xa6, 0x45, 0xd4, 0x3a, 0x3b, 0x62, 0xdf, 0x1c,
+	0x6d, 0x16, 0x60, 0x89, 0x06, 0x12, 0x6d, 0x16, 0x60, 0x81, 0xb6, 0x0e, 0xe5, 0x89, 0xeb, 0x8f,
+	0xb0, 0xda, 0x60, 0x67, 0x7c, 0x81, 0xb6, 0xa0, 0x31, 0xc6, 0xc1, 0xc8, 0xb7, 0x3c, 0x42, 0x2d,
+	0xba, 0xc2, 0x74, 0x1a, 0xdd, 0xa2, 0x72, 0x04, 0xb3, 0xf3, 0x23, 0x97, 0xe0, 0x40, 0x5d, 0xe5,
+	0x72, 0xc8, 0x35, 0x7a, 0x00, 0x6b, 0x23, 0x1b, 0x9b, 0xce, 0xcc, 0x1b, 0xba, 0xce, 0x70, 0x62,
+	0x5a, 0xb6, 0xda, 0x64, 0x20, 0xab, 0x62, 0xfb, 0xd8, 0x79, 0x6e, 0x5a, 0x36, 0xfa, 0x0c, 0x9a,
+	0xe6, 0xd8, 0xf5, 0xc8, 0x10, 0xbf, 0xb3, 0x02, 0x62, 0x39, 0x17, 0xea, 0x1a, 0x07, 0x63, 0xbb,
+	0x7d, 0xb1, 0x49, 0xa3, 0xfa, 0x5e, 0xc2, 0xe4, 0xb7, 0xf4, 0x1e, 0xf4, 0x13, 0xa8, 0x32, 0xda,
+	0x78, 0xac, 0x16, 0x59, 0x18, 0x67, 0xbc, 0xc6, 0x1d, 0x0e, 0x64, 0xe0, 0xc0, 0x9d, 0xf9, 0x23,
+	0x6c, 0x48, 0x2c, 0xfd, 0x4f, 0x45, 0xd8, 0x30, 0x5c, 0xdb, 0x3e, 0x37, 0x47, 0x6f, 0x72, 0x38,
+	0x60, 0xc4, 0x57, 0x8a, 0x37, 0xfb, 0x8a, 0x92, 0xe2, 0x2b, 0x91, 0x98, 0x2a, 0xc5, 0x62, 0x2a,
+	0xe6, 0x45, 0xe5, 0x6c, 0x2f, 0xaa, 0xc4, 0xbd, 0x48, 0xba, 0x48, 0x35, 0xe2, 0x22, 0xa1, 0xfd,
+	0x6b, 0x37, 0xd8, 0xbf, 0xbe, 0x68, 0xff, 0x14, 0x1b, 0x43, 0x8a, 0x8d, 0xf5, 0xaf, 0x60, 0x73,
+	0x41, 0x5f, 0xb7, 0x8d, 0xfd, 0x3f, 0x2b, 0x70, 0xef, 0xc0, 0x09, 0x88, 0x69, 0xdb, 0x09, 0xdd,
+	0x87, 0x81, 0x5e, 0xc8, 0x1d, 0xe8, 0xc5, 0xf7, 0x09, 0x74, 0x25, 0x66, 0x3c, 0x69, 0xe9, 0x52,
+	0xc4, 0xd2, 0xb9, 0x82, 0x3f, 0xf6, 0xe4, 0x56, 0x12, 0x4f, 0x2e, 0xfa, 0x18, 0x80, 0x47, 0x2b,
+	0x23, 0xce, 0x8d, 0x54, 0x67, 0x3b, 0x47, 0xe2, 0x85, 0x95, 0x76, 0xad, 0xa5, 0xdb, 0x35, 0x1a,
+	0xfa, 0xdb, 0xd0, 0x92, 0xf7, 0x19, 0xf9, 0x63, 0x76, 0x27, 0x61, 0xa0, 0xa6, 0xd8, 0xef, 0xfa,
+	0x63, 0x7a, 0xab, 0xa4, 0xad, 0x1b, 0x37, 0xc7, 0xfa, 0x4a, 0x22, 0xd6, 0x17, 0x63, 0x78, 0x35,
+	0x2d, 0x86, 0x7f, 0x57, 0x80, 0x8d, 0xa4, 0xe9, 0xbe, 0xb1, 0x20, 0xfe, 0x43, 0x01, 0x36, 0xcf,
+	0x1c, 0x2b, 0xd5, 0x93, 0xd2, 0xa2, 0x78, 0xc1, 0xb6, 0xc5, 0x14, 0xdb, 0xae, 0x43, 0xd9, 0x9b,
+	0xf9, 0x17, 0x58, 0xf8, 0x0a, 0x5f, 0x44, 0x8d, 0x56, 0x8a, 0x1b, 0x2d, 0xa1, 0xf6, 0xf2, 0x82,
+	0xda, 0xf5, 0x21, 0xa8, 0x8b, 0xb7, 0xbc, 0xad, 0xd2, 0x50, 0xa4, 0x2a, 0xa8, 0xf3, 0x0a, 0x40,
+	0xbf, 0x0b, 0x77, 0xf6, 0x30, 0x79, 0xc5, 0xdf, 0x14, 0xa1, 0x00, 0xbd, 0x0f, 0x28, 0xba, 0x39,
+	0xe7, 0x27, 0xb6, 0xe2, 0xfc, 0x64, 0xb1, 0x2d, 0xe1, 0x25, 0x94, 0xfe, 0x7d, 0x46, 0x7b, 0xdf,
+	0x0a, 0x88, 0xeb, 0x5f, 0xdf, 0xa4, 0xdc, 0x16, 0x28, 0x53, 0xf3, 0x9d, 0x28, 0x1a, 0xe8, 0xa7,
+	0xbe, 0x07, 0x28, 0x8a, 0x2a, 0x6e, 0x10, 0x2d, 0xc1, 0x0a, 0xf9, 0x4a, 0xb0, 0x77, 0x80, 0x4e,
+	0x71, 0x58, 0x0d, 0x2e, 0xa9, 0x5e, 0xa4, 0x99, 0x8a, 0x71, 0x33, 0xa9, 0x50, 0x15, 0x0f, 0x9a,
+	0x30, 0xac, 0x5c, 0xd2, 0xa8, 0xf0, 0x4c, 0xdf, 0xb4, 0x6d, 0x6c, 0x8b, 0x42, 0x20, 0x5c, 0xeb,
+	0xbf, 0x80, 0xbb, 0x31, 0xce, 0x42, 0x06, 0x2a, 0x6b, 0x70, 0x21, 0x38, 0xd3, 0x4f, 0xf4, 0x5d,
+	0xa8, 0xf0, 0x72, 0x9a, 0xf1, 0x6d, 0xee, 0x7e, 0x14, 0x97, 0x89, 0x11, 0x99, 0x39, 0xa2, 0xfe,
+	0x36, 0x04, 0xac, 0xfe, 0x9f, 0x02, 0xa0, 0x9e, 0x35, 0x99, 0x7c, 0x53, 0x25, 0x50, 0xb2, 0x1c,
+	0x29, 0x2d, 0x96, 0x23, 0xc9, 0xd2, 0xa3, 0xbc, 0x58, 0x7a, 0x20, 0x28, 0xd9, 0xd6, 0x95, 0x2c,
+	0x83, 0xd8, 0x37, 0x53, 0xb7, 0xeb, 0x10, 0x5a, 0x95, 0x57, 0x79, 0xca, 0x13, 0x4b, 0xfd, 0x1f,
+	0x45, 0x58, 0x91, 0xa1, 0x4c, 0x65, 0xa7, 0xe8, 0x6f, 0x2c, 0x67, 0x2c, 0xa5, 0xa5, 0xdf, 0xa1,
+	0x06, 0x8a, 0x11, 0x0d, 0xdc, 0x58, 0xe7, 0xa2, 0x0e, 0x54, 0x46, 0x97, 0xa6, 0x73, 0xc1, 0x5f,
+	0xf3, 0xe6, 0xee, 0xa3, 0xf4, 0xb7, 0x24, 0xca, 0x99, 0xea, 0xcd, 0xb9, 0xc0, 0x86, 0x40, 0xa4,
+	0x4c, 0xc7, 0xd6, 0x64, 0x22, 0x42, 0x98, 0x7d, 0xa3, 0xaf, 0xa0, 0x41, 0xe5, 0x19, 0x0a, 0xda,
+	0x95, 0xf7, 0xa5, 0x0d, 0x14, 0x9b, 0x7f, 0xa3, 0x0f, 0xa1, 0xce, 0x68, 0x31, 0x26, 0xbc, 0xc7,
+	0xaa, 0xd1, 0x0d, 0x0a, 0xaf, 0xff, 0x08, 0x2a, 0x02, 0x6c, 0x15, 0xea, 0x67, 0x47, 0xdd, 0xfd,
+	0xce, 0xd1, 0x5e, 0xbf, 0xd7, 0xfa, 0x00, 0xd5, 0xa1, 0xdc, 0xe9, 0xf5, 0xfa, 0xbd, 0x56, 0x81,
+	0x36, 0x5c, 0x46, 0xff, 0xe5, 0xf1, 0x2b, 0xd6, 0x53, 0xad, 0x40, 0xed, 0xe5, 0x71, 0xef, 0xe0,
+	0xf9, 0x41, 0xbf, 0xd7, 0x52, 0xf4, 0xdf, 0x14, 0xe0, 0x6e, 0xcc, 0x93, 0x6e, 0xfb, 0xbe, 0x7c,
+	0x09, 0x75, 0x5f, 0xc8, 0x11, 0x88, 0x67, 0x59, 0x5f, 0x2e, 0xae, 0x31, 0x47, 0xd2, 0xf7, 0x61,
+	0xfd, 0xb5, 0x49, 0x46, 0x97, 0xc9, 0x7e, 0x3b, 0xcd, 0xab, 0x63, 0x36, 0x2d, 0x26, 0x7b, 0x97,
+	0x7f, 0x2a, 0xb0, 0x22, 0xa8, 0xf4, 0xaf, 0xb0, 0x43, 0xd0, 0x0f, 0xa0, 0x44, 0xae, 0x3d, 0x4e,
+	0xa2, 0xb9, 0xfb, 0x30, 0xeb, 0x5e, 0x73, 0x8c, 0xf6, 0xe9, 0xb5, 0x87, 0x0d, 0x86, 0x74, 0x0b,
+	0x9f, 0xca, 0xae, 0xdb, 0x3e, 0x0f, 0xc3, 0x9d, 0x77, 0xec, 0x37, 0xb4, 0xd9, 0x02, 0x30, 0x99,
+	0x27, 0x2a, 0x8b, 0xe9, 0x19, 0x41, 0x89, 0xa5, 0x77, 0xee, 0x1a, 0xec, 0x1b, 0x7d, 0x0f, 0x80,
+	0xfe, 0x0e, 0x31, 0x95, 0x86, 0xd5, 0x0b, 0xcd, 0x5d, 0x35, 0xce, 0x8c, 0xa6, 0xad, 0x36, 0x93,
+	0xd6, 0xa8, 0x53, 0x58, 0xf6, 0x89, 0xda, 0x50, 0xa2, 0x4f, 0x1f, 0xab, 0x25, 0x1a, 0xbb, 0x5a,
+	0x9b, 0x4f, 0x49, 0xda, 0x72, 0x4a, 0xd2, 0x3e, 0x95, 0x53, 0x12, 0x83, 0xc1, 0xe9, 0x6f, 0xa0,
+	0x44, 0x75, 0x15, 0x6f, 0xea, 0x1b, 0x50, 0xed, 0x1a, 0xfd, 0xce, 0x29, 0xf3, 0x3e, 0x04, 0xcd,
+	0xc1, 0x69, 0xe7, 0xf4, 0x6c, 0x30, 0x94, 0xce, 0xc9, 0x1a, 0xfb, 0xc1, 0xd9, 0x49, 0xdf, 0x18,
+	0xf4, 0xa9, 0x87, 0x2a, 0x14, 0xa1, 0xd7, 0x3f, 0xec, 0x53, 0x84, 0x12, 0x02, 0xa8, 0x9c, 0x9c,
+	0x19, 0x14, 0xb0, 0x4c, 0xbd, 0x75, 0xff, 0xf8, 0xf8, 0xc5, 0xd0, 0xe8, 0x1c, 0xb5, 0x2a, 0xfa,
+	0x4f, 0xe1, 0x5e, 0xc2, 0x45, 0x84, 0xbb, 0x7e, 0x01, 0x65, 0x2e, 0x29, 0x77, 0x56, 0x7d, 0xb9,
+	0x85, 0x0d, 0x8e, 0xa0, 0x3f, 0x87, 0x8d, 0xae, 0xe9, 0x8c, 0xb0, 0x7d, 0xec, 0x61, 0xdf, 0x24,
+	0xf3, 0x44, 0x98, 0x95, 0x27, 0x64, 0x36, 0x28, 0xc6, 0xb2, 0x01, 0xad, 0x73, 0x17, 0xe8, 0xdc,
+	0xb6, 0xce, 0x7d, 0x0c, 0xeb, 0x06, 0xf6, 0x4c, 0xcb, 0x5f, 0xfe, 0xbe, 0xeb, 0xbf, 0x86, 0x7b,
+	0x09, 0xd8, 0xdb, 0x46, 0x30, 0x4b, 0xb0, 0x34, 0xe7, 0xb2, 0xba, 0xaa, 0x70, 0x63, 0x82, 0xe5,
+	0x60, 0x7a, 0x1f, 0xee, 0x74, 0x2f, 0xf1, 0xe8, 0x4d, 0xcf, 0xb7, 0x26, 0x64, 0x49, 0xbc, 0xfa,
+	0x78, 0xe4, 0x3a, 0x23, 0xcb, 0xc6, 0x42, 0x73, 0xf3, 0x0d, 0xfd, 0x04, 0xe0, 0xb9, 0x85, 0xed,
+	0x31, 0x23, 0x43, 0xf1, 0x3d, 0x93, 0x5c, 0x4a, 0x7c, 0xfa, 0x4d, 0x73, 0x2d, 0x7e, 0xe7, 0xe1,
+	0x11, 0x11, 0x77, 0xab, 0x1b, 0xe1, 0x3a, 0x4c, 0x23, 0x3c, 0x0c, 0xd9, 0xb7, 0xfe, 0xc7, 0x02,
+	0xac, 0x86, 0xef, 0x8c, 0xa4, 0xfa, 0x35, 0x64, 0x0b, 0x15, 0xaa, 0x53, 0x2b, 0x08, 0x68, 0x99,
+	0xcb, 0x73, 0x9e, 0x5c, 0xa2, 0x2f, 0xe8, 0x9c, 0x0e, 0xdb, 0x63, 0x1a, 0xd9, 0xf4, 0xf1, 0xdb,
+	0x4a, 0x77, 0xc1, 0xb9, 0x9c, 0x86, 0x80, 0xd7, 0xff, 0x5a, 0x00, 0x14, 0xd5, 0xe2, 0x6d, 0xed,
+	0xd7, 0x59, 0x7c, 0x81, 0x3f, 0x5d, 0xf2, 0x02, 0x33, 0x86, 0x73, 0x2c, 0xe6, 0xde, 0xf4, 0x26,
+	0x78, 0xac, 0x2a, 0x22, 0xfb, 0xf2, 0x25, 0xfa, 0x04, 0x20, 0xb4, 0xd7, 0x58, 0xc8, 0x1e, 0xd9,
+	0xd1, 0x5f, 0xc3, 0x5a, 0xa2, 0xdc, 0xfe, 0x7a, 0x34, 0xae, 0xff, 0xbd, 0x40, 0x83, 0x81, 0xae,
+	0x73, 0x14, 0x3b, 0xf7, 0xa1, 0xe6, 0xe0, 0xb7, 0xc3, 0x08, 0x8b, 0xaa, 0x83, 0xdf, 0x1e, 0x2d,
+	0xb7, 0xeb, 0xff, 0x36, 0xd3, 0x79, 0xaf, 0x8e, 0x5b, 0xdf, 0xa7, 0x11, 0x1b, 0x13, 0xe8, 0xd6,
+	0xfd, 0x70, 0x01, 0x5a, 0x62, 0x73, 0x80, 0xc9, 0x4b, 0x3c, 0x3d, 0xc7, 0xfe, 0xfb, 0xa7, 0xcb,
+	0x79, 0x89, 0xa8, 0xe4, 0x2e, 0x11, 0x4b, 0x4b, 0x4b, 0xc4, 0x8f, 0x01, 0xc6, 0xd8, 0xc3, 0xce,
+	0x38, 0x18, 0xb2, 0xee, 0x46, 0xa1, 0x3c, 0xc5, 0xce, 0xb1, 0xa3, 0xff, 0xab, 0x00, 0x1b, 0x1d,
+	0xcf, 0xb3, 0xaf, 0xe7, 0xf7, 0x97, 0x86, 0x7d, 0xb6, 0x50, 0xe8, 0x3f, 0xb8, 0xf1, 0x39, 0x0f,
+	0x45, 0x9f, 0x57, 0xfe, 0xd9, 0x73, 0x97, 0x88, 0xa9, 0x94, 0x74, 0x53, 0x95, 0x22, 0x4d, 0xf4,
+	0x06, 0x54, 0x4c, 0xe2, 0x4e, 0xad, 0x91, 0x30, 0xbb, 0x58, 0xd1, 0x9c, 0x2c, 0x4b, 0x7d, 0x2b,
+	0x98, 0x32, 0xa3, 0x97, 0x8d, 0xe8, 0x96, 0xfe, 0xfb, 0x62, 0xd4, 0x34, 0x06, 0x0e, 0x66, 0x76,
+	0xba, 0xcb, 0xf6, 0x13, 0x0d, 0xc0, 0xd3, 0x65, 0xb2, 0x72, 0x5a, 0x89, 0x8e, 0x80, 0x5a, 0x58,
+	0x74, 0x8a, 0x22, 0x76, 0x6b, 0xc6, 0x7c, 0x23, 0xea, 0x59, 0xa5, 0x5c, 0x6f, 0xc9, 0x3a, 0x94,
+	0xb1, 0xef, 0xbb, 0xbe, 0xa8, 0x69, 0xf9, 0x42, 0x3f, 0x84, 0x0a, 0x67, 0xbb, 0x90, 0xed, 0x3b,
+	0x27, 0x27, 0x87, 0x07, 0x2c, 0xdb, 0x03, 0x54, 0x9e, 0x77, 0x0e, 0x0e, 0x59, 0x96, 0x6f, 0x40,
+	0x75, 0xf0, 0xe2, 0xe0, 0xe4, 0x84, 0xa5, 0xf8, 0x35, 0x68, 0x18, 0xc7, 0x87, 0x87, 0xfd, 0xde,
+	0xf0, 0x59, 0xa7, 0xfb, 0xa2, 0x55, 0xd2, 0x7f, 0x0e, 0x9b, 0x0b, 0x1e, 0x20, 0x22, 0xe1, 0x4b,
+	0x7a, 0x5f, 0x2a, 0x66, 0x6e, 0x0f, 0xe0, 0x5a, 0x31, 0x24, 0xda, 0xee, 0xdf, 0x9a, 0xd0, 0x0c,
+	0x4f, 0x19, 0x12, 0xb2, 0x60, 0x25, 0xfa, 0xbf, 0x01, 0xf4, 0x28, 0xfb, 0xbf, 0x25, 0x89, 0x12,
+	0x54, 0x7b, 0x9c, 0x07, 0x94, 0xdf, 0x5d, 0xff, 0xe0, 0xdb, 0x05, 0x14, 0x40, 0x2b, 0x39, 0xb2,
+	0x47, 0x19, 0x86, 0xcd, 0xf8, 0x1f, 0x81, 0xd6, 0xce, 0x0b, 0x2e, 0xd9, 0xa2, 0x2b, 0xb8, 0x33,
+	0x3f, 0x15, 0x73, 0x76, 0xb4, 0x94, 0x4c, 0x7c, 0xb4, 0xaf, 0xed, 0xe4, 0x86, 0x0f, 0xf9, 0xfe,
+	0x12, 0x56, 0x63, 0xd3, 0x59, 0x94, 0xa1, 0xad, 0xb4, 0xa9, 0xbd, 0xf6, 0x24, 0x17, 0x6c, 0xc8,
+	0x6b, 0x0a, 0xcd, 0xf8, 0x14, 0x09, 0x65, 0x10, 0x48, 0x1d, 0x13, 0x6a, 0xdf, 0xca, 0x07, 0x1c,
+	0xb2, 0x0b, 0xa0, 0x95, 0x9c, 0xc0, 0x64, 0xd9, 0x31, 0x63, 0x9e, 0xa4, 0xb5, 0xf3, 0x82, 0x87,
+	0x4c, 0x4d, 0x80, 0xf9, 0x00, 0x06, 0x3d, 0xcc, 0x34, 0x48, 0x7c, 0x6e, 0xa3, 0x6d, 0x2f, 0x07,
+	0x0c, 0x59, 0x78, 0xb0, 0x96, 0x18, 0xca, 0xa2, 0x0c, 0xd5, 0xa4, 0xcf, 0xba, 0xb5, 0xa7, 0x39,
+	0xa1, 0x13, 0x42, 0x89, 0x99, 0xce, 0x0d, 0x42, 0xc5, 0x07, 0x46, 0xda, 0xf6, 0x72, 0xc0, 0x90,
+	0x85, 0x05, 0x4d, 0x63, 0xe6, 0x08, 0xd6, 0x74, 0x70, 0x82, 0x32, 0xb0, 0x17, 0x67, 0x42, 0xda,
+	0xa3, 0x1c, 0x90, 0x91, 0xf8, 0x1e, 0x43, 0x23, 0xd2, 0x34, 0x67, 0xf1, 0x59, 0x9c, 0xd0, 0x68,
+	0x8f, 0x72, 0x40, 0x86, 0x02, 0xd9, 0xb0, 0x1a, 0xeb, 0x76, 0xb2, 0x02, 0x2b, 0xad, 0x6b, 0xd6,
+	0x9e, 0xe4, 0x82, 0x8d, 0xc8, 0xe4, 0xc1, 0x5a, 0xa2, 0x81, 0xc9, 0xf2, 0x89, 0xf4, 0x7e, 0x49,
+	0x7b, 0x9a, 0x13, 0x3a, 0xfa, 0x70, 0xc4, 0x5a, 0x97, 0x2c, 0xf9, 0xd2, 0x7a, 0x21, 0xed, 0x49,
+	0x2e, 0xd8, 0xa8, 0xff, 0xcd, 0x6b, 0xec, 0x2c, 0xff, 0x5b, 0xe8, 0x65, 0xb4, 0xed, 0xe5, 0x80,
+	0x71, 0x71, 0x22, 0x75, 0x5d, 0xb6, 0x38, 0x8b, 0xd5, 0xac, 0xf6, 0x24, 0x17, 0x6c, 0x34, 0x80,
+	0x13, 0xb9, 0x33, 0xcb, 0x58, 0xe9, 0x45, 0x96, 0xf6, 0x34, 0x27, 0xb4, 0xe4, 0xf8, 0x0c, 0x7e,
+	0x56, 0x93, 0xc0, 0xe7, 0x15, 0x36, 0x0d, 0xf8, 0xce, 0x7f, 0x07, 0x00, 0x08, 0xc9, 0x43, 0x6c,
+	0x48, 0x22, 0x00, 0x00,
 }
@@ -0,0 +1,261 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// ArchiveFormatVersion is the version of the release archive format written
+// by Export. Import rejects archives written in a newer format.
+const ArchiveFormatVersion = 1
+
+const (
+	archiveManifestName = "manifest.json"
+	archiveReleasesDir  = "releases"
+)
+
+// ArchiveManifest describes the content of a release archive. It is stored as
+// JSON in the first entry of the archive, followed by one entry per release
+// revision holding the protobuf encoding of the hapi.release.Release.
+type ArchiveManifest struct {
+	FormatVersion int            `json:"formatVersion"`
+	Created       time.Time      `json:"created"`
+	Releases      []ArchiveEntry `json:"releases"`
+}
+
+// ArchiveEntry describes a release revision stored in a release archive.
+type ArchiveEntry struct {
+	Name      string `json:"name"`
+	Version   int32  `json:"version"`
+	Namespace string `json:"namespace"`
+	Status    string `json:"status"`
+	Chart     string `json:"chart,omitempty"`
+	File      string `json:"file"`
+	SHA256    string `json:"sha256"`
+}
+
+// Export writes every revision of the named releases to w as a gzipped tar
+// archive. If no name is given, every release in storage is exported. The
+// exported releases are returned sorted by name and version.
+func (s *Storage) Export(w io.Writer, names ...string) ([]*rspb.Release, error) {
+	var rels []*rspb.Release
+	if len(names) == 0 {
+		s.Log("exporting all releases")
+		ls, err := s.ListReleases()
+		if err != nil {
+			return nil, err
+		}
+		rels = ls
+	} else {
+		for _, name := range names {
+			s.Log("exporting release history of %q", name)
+			h, err := s.History(name)
+			if err != nil {
+				return nil, fmt.Errorf("release %q: %s", name, err)
+			}
+			rels = append(rels, h...)
+		}
+	}
+	sort.Slice(rels, func(i, j int) bool {
+		if rels[i].Name != rels[j].Name {
+			return rels[i].Name < rels[j].Name
+		}
+		return rels[i].Version < rels[j].Version
+	})
+
+	manifest := ArchiveManifest{
+		FormatVersion: ArchiveFormatVersion,
+		Created:       time.Now().UTC(),
+	}
+	payloads := make([][]byte, len(rels))
+	for i, rls := range rels {
+		b, err := proto.Marshal(rls)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode release %q: %s", makeKey(rls.Name, rls.Version), err)
+		}
+		payloads[i] = b
+		manifest.Releases = append(manifest.Releases, newArchiveEntry(rls, b))
+	}
+	mb, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+	if err := writeArchiveFile(tw, archiveManifestName, mb, manifest.Created); err != nil {
+		return nil, err
+	}
+	for i, entry := range manifest.Releases {
+		if err := writeArchiveFile(tw, entry.File, payloads[i], manifest.Created); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return rels, nil
+}
+
+// Import restores every release revision stored in the archive read from r.
+// The whole archive is read and verified before any release is stored.
+// Revisions already in storage are skipped if they are identical to the
+// archived ones, and stop the import otherwise. The restored releases are
+// returned in the order of the archive manifest.
+func (s *Storage) Import(r io.Reader) ([]*rspb.Release, error) {
+	manifest, rels, err := readArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	s.Log("importing %d release(s) archived on %s", len(rels), manifest.Created.Format(time.RFC3339))
+
+	var restored []*rspb.Release
+	for _, rls := range rels {
+		key := makeKey(rls.Name, rls.Version)
+		existing, err := s.Driver.Get(key)
+		if err == nil {
+			if !proto.Equal(existing, rls) {
+				return restored, fmt.Errorf("release %q already exists and differs from the archived one", key)
+			}
+			s.Log("release %q already exists, skipping", key)
+			continue
+		}
+		// Create on the driver directly so that restoring a long
+		// history is not pruned by MaxHistory.
+		s.Log("importing release %q", key)
+		if err := s.Driver.Create(key, rls); err != nil {
+			return restored, err
+		}
+		restored = append(restored, rls)
+	}
+	return restored, nil
+}
+
+func newArchiveEntry(rls *rspb.Release, payload []byte) ArchiveEntry {
+	sum := sha256.Sum256(payload)
+	entry := ArchiveEntry{
+		Name:      rls.Name,
+		Version:   rls.Version,
+		Namespace: rls.Namespace,
+		Status:    rls.GetInfo().GetStatus().GetCode().String(),
+		File:      path.Join(archiveReleasesDir, makeKey(rls.Name, rls.Version)+".pb"),
+		SHA256:    hex.EncodeToString(sum[:]),
+	}
+	if md := rls.GetChart().GetMetadata(); md != nil {
+		entry.Chart = md.Name + "-" + md.Version
+	}
+	return entry
+}
+
+func writeArchiveFile(tw *tar.Writer, name string, b []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(b)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(b)
+	return err
+}
+
+// readArchive reads and verifies a release archive, returning its manifest and
+// the archived releases in the order of the manifest.
+func readArchive(r io.Reader) (*ArchiveManifest, []*rspb.Release, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid release archive: %s", err)
+	}
+	defer zr.Close()
+
+	var manifest *ArchiveManifest
+	files := map[string][]byte{}
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid release archive: %s", err)
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid release archive: %s", err)
+		}
+		if hdr.Name == archiveManifestName {
+			manifest = &ArchiveManifest{}
+			if err := json.Unmarshal(b, manifest); err != nil {
+				return nil, nil, fmt.Errorf("invalid release archive manifest: %s", err)
+			}
+			continue
+		}
+		files[hdr.Name] = b
+	}
+
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("invalid release archive: no %s", archiveManifestName)
+	}
+	if manifest.FormatVersion < 1 || manifest.FormatVersion > ArchiveFormatVersion {
+		return nil, nil, fmt.Errorf("unsupported release archive format version %d", manifest.FormatVersion)
+	}
+
+	rels := make([]*rspb.Release, 0, len(manifest.Releases))
+	for _, entry := range manifest.Releases {
+		b, ok := files[entry.File]
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid release archive: missing %s", entry.File)
+		}
+		sum := sha256.Sum256(b)
+		if hex.EncodeToString(sum[:]) != entry.SHA256 {
+			return nil, nil, fmt.Errorf("invalid release archive: checksum mismatch for %s", entry.File)
+		}
+		rls := &rspb.Release{}
+		if err := proto.Unmarshal(b, rls); err != nil {
+			return nil, nil, fmt.Errorf("invalid release archive: could not decode %s: %s", entry.File, err)
+		}
+		if rls.Name != entry.Name || rls.Version != entry.Version {
+			return nil, nil, fmt.Errorf("invalid release archive: %s holds release %q, expected %q",
+				entry.File, makeKey(rls.Name, rls.Version), makeKey(entry.Name, entry.Version))
+		}
+		rels = append(rels, rls)
+	}
+	return manifest, rels, nil
+}
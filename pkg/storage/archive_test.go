@@ -0,0 +1,154 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func setupArchiveStorage(t *testing.T) *Storage {
+	storage := Init(driver.NewMemory())
+	for _, rls := range []*rspb.Release{
+		ReleaseTestData{Name: "happy-cats", Version: 1, Status: rspb.Status_SUPERSEDED}.ToRelease(),
+		ReleaseTestData{Name: "happy-cats", Version: 2, Status: rspb.Status_DEPLOYED}.ToRelease(),
+		ReleaseTestData{Name: "angry-bird", Version: 1, Status: rspb.Status_DEPLOYED}.ToRelease(),
+	} {
+		assertErrNil(t.Fatal, storage.Create(rls), "StoreRelease")
+	}
+	return storage
+}
+
+func TestStorageExportImport(t *testing.T) {
+	src := setupArchiveStorage(t)
+
+	var buf bytes.Buffer
+	exported, err := src.Export(&buf, "happy-cats")
+	assertErrNil(t.Fatal, err, "Export")
+	if len(exported) != 2 {
+		t.Fatalf("Expected 2 exported releases, got %d", len(exported))
+	}
+
+	dst := Init(driver.NewMemory())
+	dst.MaxHistory = 1
+	imported, err := dst.Import(bytes.NewReader(buf.Bytes()))
+	assertErrNil(t.Fatal, err, "Import")
+	if len(imported) != 2 {
+		t.Fatalf("Expected 2 imported releases, got %d", len(imported))
+	}
+
+	for _, want := range exported {
+		got, err := dst.Get(want.Name, want.Version)
+		assertErrNil(t.Fatal, err, "QueryRelease")
+		if !proto.Equal(want, got) {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+	if _, err := dst.Get("angry-bird", 1); err == nil {
+		t.Errorf("Expected angry-bird not to be imported")
+	}
+
+	// importing the same archive again is a no-op
+	imported, err = dst.Import(bytes.NewReader(buf.Bytes()))
+	assertErrNil(t.Fatal, err, "Import")
+	if len(imported) != 0 {
+		t.Errorf("Expected no release to be imported again, got %d", len(imported))
+	}
+}
+
+func TestStorageExportAll(t *testing.T) {
+	src := setupArchiveStorage(t)
+
+	var buf bytes.Buffer
+	exported, err := src.Export(&buf)
+	assertErrNil(t.Fatal, err, "Export")
+
+	var keys []string
+	for _, rls := range exported {
+		keys = append(keys, makeKey(rls.Name, rls.Version))
+	}
+	expect := []string{"angry-bird.v1", "happy-cats.v1", "happy-cats.v2"}
+	if !reflect.DeepEqual(keys, expect) {
+		t.Errorf("Expected %v, got %v", expect, keys)
+	}
+}
+
+func TestStorageImportConflict(t *testing.T) {
+	src := setupArchiveStorage(t)
+
+	var buf bytes.Buffer
+	_, err := src.Export(&buf, "angry-bird")
+	assertErrNil(t.Fatal, err, "Export")
+
+	dst := Init(driver.NewMemory())
+	rls := ReleaseTestData{Name: "angry-bird", Version: 1, Status: rspb.Status_DELETED}.ToRelease()
+	assertErrNil(t.Fatal, dst.Create(rls), "StoreRelease")
+
+	if _, err := dst.Import(&buf); err == nil || !strings.Contains(err.Error(), "differs") {
+		t.Errorf("Expected a conflict error, got %v", err)
+	}
+}
+
+func TestStorageImportInvalid(t *testing.T) {
+	src := setupArchiveStorage(t)
+
+	var buf bytes.Buffer
+	_, err := src.Export(&buf, "angry-bird")
+	assertErrNil(t.Fatal, err, "Export")
+
+	// rewrite the archive with a tampered release payload
+	var tampered bytes.Buffer
+	zr, err := gzip.NewReader(&buf)
+	assertErrNil(t.Fatal, err, "gzip")
+	tr := tar.NewReader(zr)
+	zw := gzip.NewWriter(&tampered)
+	tw := tar.NewWriter(zw)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		var b bytes.Buffer
+		b.ReadFrom(tr)
+		data := b.Bytes()
+		if hdr.Name != archiveManifestName {
+			data = append(data, 0)
+		}
+		hdr.Size = int64(len(data))
+		assertErrNil(t.Fatal, tw.WriteHeader(hdr), "tar")
+		tw.Write(data)
+	}
+	tw.Close()
+	zw.Close()
+
+	dst := Init(driver.NewMemory())
+	if _, err := dst.Import(&tampered); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a checksum error, got %v", err)
+	}
+	if rls, _ := dst.ListReleases(); len(rls) != 0 {
+		t.Errorf("Expected no release to be imported, got %d", len(rls))
+	}
+}
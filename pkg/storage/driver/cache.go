@@ -0,0 +1,438 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"k8s.io/apimachinery/pkg/watch"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
+)
+
+var _ Driver = (*Cache)(nil)
+var _ Locker = (*Cache)(nil)
+
+// DefaultCachePollInterval is the interval at which a Cache reloads the
+// releases of a driver that is not a Watcher.
+const DefaultCachePollInterval = 30 * time.Second
+
+// cacheRewatchDelay is the time a Cache waits before watching the storage
+// again once a watch ended.
+const cacheRewatchDelay = time.Second
+
+// ReleaseEvent describes a change to the release stored under Key. Release is
+// nil if the release was deleted.
+type ReleaseEvent struct {
+	Key     string
+	Release *rspb.Release
+}
+
+// Watcher is the interface implemented by drivers able to notify of the
+// changes made to the releases they store.
+//
+// Watch returns a channel receiving an event for each release created,
+// updated or deleted in storage. The channel is closed when stop is closed or
+// the watch ends, after which changes may be missed until Watch is called
+// again.
+type Watcher interface {
+	Watch(stop <-chan struct{}) (<-chan ReleaseEvent, error)
+}
+
+// CacheStats holds the counters of a Cache.
+type CacheStats struct {
+	// Hits is the number of reads served from memory.
+	Hits uint64
+	// Misses is the number of reads that fetched releases from the
+	// underlying driver.
+	Misses uint64
+	// Releases is the number of releases held in memory.
+	Releases int
+}
+
+// Cache is a driver keeping the releases stored by another driver decoded in
+// memory, so that listing and querying releases does not fetch and decode
+// every release in storage.
+//
+// Writes go through to the underlying driver and update the cache. Changes
+// made to the storage by others, e.g. another Tiller, are picked up once
+// Start is called: by watching the storage if the underlying driver is a
+// Watcher, and by reloading every release at PollInterval otherwise.
+type Cache struct {
+	// hits and misses are accessed atomically, keep them 64-bit aligned.
+	hits   uint64
+	misses uint64
+
+	driver Driver
+	Log    func(string, ...interface{})
+
+	// PollInterval is the interval at which every release is reloaded from
+	// an underlying driver that is not a Watcher. Values of 0 or less
+	// disable polling.
+	PollInterval time.Duration
+
+	mu     sync.RWMutex
+	recs   map[string]*record
+	loaded bool
+	// gen is incremented on every change to the cache, so that releases
+	// fetched from the underlying driver while the cache changed are not
+	// cached.
+	gen uint64
+}
+
+// NewCache initializes a new Cache of the releases stored by d.
+func NewCache(d Driver) *Cache {
+	return &Cache{
+		driver:       d,
+		Log:          func(_ string, _ ...interface{}) {},
+		PollInterval: DefaultCachePollInterval,
+		recs:         map[string]*record{},
+	}
+}
+
+// Name returns the name of the underlying driver.
+func (c *Cache) Name() string {
+	return c.driver.Name()
+}
+
+// Stats returns the counters of the cache.
+func (c *Cache) Stats() CacheStats {
+	c.mu.RLock()
+	n := len(c.recs)
+	c.mu.RUnlock()
+	return CacheStats{
+		Hits:     atomic.LoadUint64(&c.hits),
+		Misses:   atomic.LoadUint64(&c.misses),
+		Releases: n,
+	}
+}
+
+// Start keeps the cache up to date with the changes made to the storage by
+// others in the background, until stop is closed.
+func (c *Cache) Start(stop <-chan struct{}) {
+	if w, ok := c.driver.(Watcher); ok {
+		go c.watch(w, stop)
+		return
+	}
+	if c.PollInterval > 0 {
+		go c.poll(stop)
+	}
+}
+
+// Get returns the release named by key, fetching it from the underlying
+// driver if it is not cached.
+func (c *Cache) Get(key string) (*rspb.Release, error) {
+	c.mu.RLock()
+	rec, ok := c.recs[key]
+	loaded, gen := c.loaded, c.gen
+	c.mu.RUnlock()
+
+	if ok || loaded {
+		atomic.AddUint64(&c.hits, 1)
+		if !ok {
+			return nil, storageerrors.ErrReleaseNotFound(key)
+		}
+		return cloneRelease(rec.rls), nil
+	}
+
+	atomic.AddUint64(&c.misses, 1)
+	rls, err := c.driver.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.gen == gen {
+		c.recs[key] = newRecord(key, rls)
+	}
+	c.mu.Unlock()
+	return rls, nil
+}
+
+// List returns the list of all releases such that filter(release) == true.
+func (c *Cache) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	recs, err := c.records()
+	if err != nil {
+		return nil, err
+	}
+
+	var ls []*rspb.Release
+	for _, rec := range recs {
+		if filter(rec.rls) {
+			ls = append(ls, cloneRelease(rec.rls))
+		}
+	}
+	return ls, nil
+}
+
+// Query returns the set of releases that match the provided set of labels, or
+// ErrReleaseNotFound if none does.
+func (c *Cache) Query(keyvals map[string]string) ([]*rspb.Release, error) {
+	recs, err := c.records()
+	if err != nil {
+		return nil, err
+	}
+
+	var lbs labels
+
+	lbs.init()
+	lbs.fromMap(keyvals)
+
+	var ls []*rspb.Release
+	for _, rec := range recs {
+		if rec.lbs.match(lbs) {
+			ls = append(ls, cloneRelease(rec.rls))
+		}
+	}
+	if len(ls) == 0 {
+		return nil, storageerrors.ErrReleaseNotFound(keyvals["NAME"])
+	}
+	return ls, nil
+}
+
+// Create stores the release with the underlying driver and caches it.
+func (c *Cache) Create(key string, rls *rspb.Release) error {
+	if err := c.driver.Create(key, rls); err != nil {
+		// the cache may have missed the release
+		c.invalidate()
+		return err
+	}
+	c.store(key, rls)
+	return nil
+}
+
+// Update updates the release with the underlying driver and caches it.
+func (c *Cache) Update(key string, rls *rspb.Release) error {
+	if err := c.driver.Update(key, rls); err != nil {
+		// the cache may hold a stale release
+		c.invalidate()
+		return err
+	}
+	c.store(key, rls)
+	return nil
+}
+
+// Delete deletes the release with the underlying driver and drops it from the
+// cache.
+func (c *Cache) Delete(key string) (*rspb.Release, error) {
+	rls, err := c.driver.Delete(key)
+	if err != nil {
+		c.invalidate()
+		return rls, err
+	}
+	c.remove(key)
+	return rls, nil
+}
+
+// Lock locks the named release with the underlying driver, if it is a Locker.
+func (c *Cache) Lock(name, holder string, ttl time.Duration) error {
+	if locker, ok := c.driver.(Locker); ok {
+		return locker.Lock(name, holder, ttl)
+	}
+	return nil
+}
+
+// Unlock unlocks the named release with the underlying driver, if it is a
+// Locker.
+func (c *Cache) Unlock(name, holder string) error {
+	if locker, ok := c.driver.(Locker); ok {
+		return locker.Unlock(name, holder)
+	}
+	return nil
+}
+
+// records returns the records of every release, loading them from the
+// underlying driver if they are not all cached.
+func (c *Cache) records() ([]*record, error) {
+	c.mu.RLock()
+	if c.loaded {
+		recs := make([]*record, 0, len(c.recs))
+		for _, rec := range c.recs {
+			recs = append(recs, rec)
+		}
+		c.mu.RUnlock()
+		atomic.AddUint64(&c.hits, 1)
+		return recs, nil
+	}
+	c.mu.RUnlock()
+
+	atomic.AddUint64(&c.misses, 1)
+	return c.load()
+}
+
+// load fetches every release from the underlying driver and replaces the
+// content of the cache with them, unless the cache changed in the meantime.
+func (c *Cache) load() ([]*record, error) {
+	c.mu.RLock()
+	gen := c.gen
+	c.mu.RUnlock()
+
+	rels, err := c.driver.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		c.Log("cache: failed to load releases: %s", err)
+		return nil, err
+	}
+	recs := make(map[string]*record, len(rels))
+	ls := make([]*record, 0, len(rels))
+	for _, rls := range rels {
+		key := releaseKey(rls.Name, rls.Version)
+		rec := newRecord(key, rls)
+		recs[key] = rec
+		ls = append(ls, rec)
+	}
+
+	c.mu.Lock()
+	if c.gen == gen {
+		c.recs = recs
+		c.loaded = true
+		c.gen++
+	}
+	c.mu.Unlock()
+	return ls, nil
+}
+
+// store caches the release stored under key.
+func (c *Cache) store(key string, rls *rspb.Release) {
+	rec := newRecord(key, rls)
+
+	c.mu.Lock()
+	c.recs[key] = rec
+	c.gen++
+	c.mu.Unlock()
+}
+
+// remove drops the release stored under key from the cache.
+func (c *Cache) remove(key string) {
+	c.mu.Lock()
+	delete(c.recs, key)
+	c.gen++
+	c.mu.Unlock()
+}
+
+// invalidate drops every release from the cache, so that they are loaded
+// again from the underlying driver.
+func (c *Cache) invalidate() {
+	c.mu.Lock()
+	c.recs = map[string]*record{}
+	c.loaded = false
+	c.gen++
+	c.mu.Unlock()
+}
+
+// apply updates the cache with a change made to the storage.
+func (c *Cache) apply(ev ReleaseEvent) {
+	if ev.Release == nil {
+		c.remove(ev.Key)
+		return
+	}
+	c.store(ev.Key, ev.Release)
+}
+
+// watch applies the changes reported by w until stop is closed, watching the
+// storage again whenever a watch ends.
+func (c *Cache) watch(w Watcher, stop <-chan struct{}) {
+	for {
+		events, err := w.Watch(stop)
+		if err != nil {
+			c.Log("cache: failed to watch releases: %s", err)
+		} else {
+			// changes made since the previous watch ended were missed
+			c.invalidate()
+			for ev := range events {
+				c.apply(ev)
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(cacheRewatchDelay):
+		}
+	}
+}
+
+// poll reloads every release at PollInterval until stop is closed.
+func (c *Cache) poll(stop <-chan struct{}) {
+	ticker := time.NewTicker(c.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.load()
+		}
+	}
+}
+
+// watchReleases forwards the events of w as ReleaseEvents, decoding the
+// objects they hold with decode, until stop is closed or w ends.
+func watchReleases(w watch.Interface, stop <-chan struct{}, log func(string, ...interface{}), decode func(watch.Event) (ReleaseEvent, error)) <-chan ReleaseEvent {
+	events := make(chan ReleaseEvent)
+	go func() {
+		defer close(events)
+		defer w.Stop()
+		for {
+			var ev watch.Event
+			select {
+			case <-stop:
+				return
+			case e, ok := <-w.ResultChan():
+				if !ok {
+					return
+				}
+				ev = e
+			}
+
+			switch ev.Type {
+			case watch.Added, watch.Modified, watch.Deleted:
+			case watch.Error:
+				log("watch: watch failed: %v", ev.Object)
+				return
+			default:
+				continue
+			}
+			rev, err := decode(ev)
+			if err != nil {
+				log("watch: failed to decode release: %s", err)
+				continue
+			}
+
+			select {
+			case <-stop:
+				return
+			case events <- rev:
+			}
+		}
+	}()
+	return events
+}
+
+// releaseKey returns the key a release is stored under.
+func releaseKey(name string, version int32) string {
+	return fmt.Sprintf("%s.v%d", name, version)
+}
+
+// cloneRelease returns a deep copy of rls, so that callers cannot modify the
+// cached release.
+func cloneRelease(rls *rspb.Release) *rspb.Release {
+	return proto.Clone(rls).(*rspb.Release)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func all(*rspb.Release) bool { return true }
+
+// eventually polls cond until it holds or a second elapsed.
+func eventually(t *testing.T, desc string, cond func() bool) {
+	for deadline := time.Now().Add(time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", desc)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCacheName(t *testing.T) {
+	if c := NewCache(NewMemory()); c.Name() != MemoryDriverName {
+		t.Errorf("Expected name to be %q, got %q", MemoryDriverName, c.Name())
+	}
+}
+
+func TestCacheGet(t *testing.T) {
+	c := NewCache(tsFixtureMemory(t))
+
+	for i := 0; i < 2; i++ {
+		rls, err := c.Get("rls-a.v1")
+		if err != nil {
+			t.Fatalf("Failed to get release: %s", err)
+		}
+		if rls.Name != "rls-a" || rls.Version != 1 {
+			t.Errorf("Expected release rls-a.v1, got %s.v%d", rls.Name, rls.Version)
+		}
+		// modifying the returned release must not modify the cache
+		rls.Name = "modified"
+	}
+	if stats := c.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %+v", stats)
+	}
+
+	if _, err := c.Get("rls-a.v5"); err == nil {
+		t.Errorf("Expected an error getting a missing release")
+	}
+}
+
+func TestCacheList(t *testing.T) {
+	c := NewCache(tsFixtureMemory(t))
+
+	for i := 0; i < 2; i++ {
+		rels, err := c.List(all)
+		if err != nil {
+			t.Fatalf("Failed to list releases: %s", err)
+		}
+		if len(rels) != 8 {
+			t.Errorf("Expected 8 releases, got %d", len(rels))
+		}
+	}
+	if stats := c.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Releases != 8 {
+		t.Errorf("Expected 1 hit, 1 miss and 8 releases, got %+v", stats)
+	}
+
+	// every release is loaded, so getting one is a hit, even if missing
+	c.Get("rls-b.v2")
+	c.Get("rls-b.v5")
+	if stats := c.Stats(); stats.Hits != 3 {
+		t.Errorf("Expected 3 hits, got %+v", stats)
+	}
+}
+
+func TestCacheQuery(t *testing.T) {
+	c := NewCache(tsFixtureMemory(t))
+
+	rels, err := c.Query(map[string]string{"NAME": "rls-a", "STATUS": "DEPLOYED"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	if len(rels) != 1 || rels[0].Version != 4 {
+		t.Errorf("Expected rls-a.v4, got %v", rels)
+	}
+
+	if _, err := c.Query(map[string]string{"NAME": "rls-c"}); err == nil {
+		t.Errorf("Expected an error querying a missing release")
+	}
+}
+
+func TestCacheWrites(t *testing.T) {
+	mem := tsFixtureMemory(t)
+	c := NewCache(mem)
+	if _, err := c.List(all); err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+
+	rls := releaseStub("rls-c", 1, "default", rspb.Status_DEPLOYED)
+	if err := c.Create(testKey(rls.Name, rls.Version), rls); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	rls.Info.Status.Code = rspb.Status_SUPERSEDED
+	if err := c.Update(testKey(rls.Name, rls.Version), rls); err != nil {
+		t.Fatalf("Failed to update release: %s", err)
+	}
+	if _, err := c.Delete("rls-a.v1"); err != nil {
+		t.Fatalf("Failed to delete release: %s", err)
+	}
+
+	got, err := c.Get("rls-c.v1")
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if got.Info.Status.Code != rspb.Status_SUPERSEDED {
+		t.Errorf("Expected cached release to be updated, got %s", got.Info.Status.Code)
+	}
+	if _, err := c.Get("rls-a.v1"); err == nil {
+		t.Errorf("Expected deleted release to be dropped from the cache")
+	}
+	if _, err := mem.Get("rls-c.v1"); err != nil {
+		t.Errorf("Expected release to be written to the underlying driver: %s", err)
+	}
+	if stats := c.Stats(); stats.Misses != 1 {
+		t.Errorf("Expected writes not to cause misses, got %+v", stats)
+	}
+
+	// a failed write invalidates the cache
+	if err := c.Create(testKey(rls.Name, rls.Version), rls); err == nil {
+		t.Fatalf("Expected an error creating an existing release")
+	}
+	c.List(all)
+	if stats := c.Stats(); stats.Misses != 2 {
+		t.Errorf("Expected a miss after a failed write, got %+v", stats)
+	}
+}
+
+func TestCacheWatch(t *testing.T) {
+	var mock MockConfigMapsInterface
+	mock.Init(t, releaseStub("rls-a", 1, "default", rspb.Status_DEPLOYED))
+	cfgmaps := NewConfigMaps(&mock)
+
+	c := NewCache(cfgmaps)
+	stop := make(chan struct{})
+	defer close(stop)
+	c.Start(stop)
+	eventually(t, "the watch", mock.watching)
+
+	// changes made behind the back of the cache are picked up
+	rls := releaseStub("rls-b", 1, "default", rspb.Status_DEPLOYED)
+	if err := cfgmaps.Create(testKey(rls.Name, rls.Version), rls); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	eventually(t, "the created release", func() bool { return c.Stats().Releases == 1 })
+	if _, err := c.Get("rls-b.v1"); err != nil {
+		t.Errorf("Failed to get release: %s", err)
+	}
+	if stats := c.Stats(); stats.Hits != 1 {
+		t.Errorf("Expected 1 hit, got %+v", stats)
+	}
+
+	if _, err := cfgmaps.Delete("rls-b.v1"); err != nil {
+		t.Fatalf("Failed to delete release: %s", err)
+	}
+	eventually(t, "the deleted release", func() bool { return c.Stats().Releases == 0 })
+}
+
+func TestCachePoll(t *testing.T) {
+	mem := tsFixtureMemory(t)
+	c := NewCache(mem)
+	c.PollInterval = 10 * time.Millisecond
+	stop := make(chan struct{})
+	defer close(stop)
+	c.Start(stop)
+
+	if _, err := c.List(all); err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+	rls := releaseStub("rls-c", 1, "default", rspb.Status_DEPLOYED)
+	if err := mem.Create(testKey(rls.Name, rls.Version), rls); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	eventually(t, "the created release", func() bool {
+		rels, _ := c.List(all)
+		return len(rels) == 9
+	})
+}
@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kblabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
@@ -34,6 +35,8 @@ import (
 )
 
 var _ Driver = (*ConfigMaps)(nil)
+var _ Locker = (*ConfigMaps)(nil)
+var _ Watcher = (*ConfigMaps)(nil)
 
 // ConfigMapsDriverName is the string name of the driver.
 const ConfigMapsDriverName = "ConfigMap"
@@ -43,14 +46,20 @@ const ConfigMapsDriverName = "ConfigMap"
 type ConfigMaps struct {
 	impl corev1.ConfigMapInterface
 	Log  func(string, ...interface{})
+
+	// ChunkSize is the maximum size of the encoded release stored in a
+	// single ConfigMap. Larger releases are split across several ConfigMaps.
+	// Values of 0 or less disable chunking.
+	ChunkSize int
 }
 
 // NewConfigMaps initializes a new ConfigMaps wrapping an implementation of
 // the kubernetes ConfigMapsInterface.
 func NewConfigMaps(impl corev1.ConfigMapInterface) *ConfigMaps {
 	return &ConfigMaps{
-		impl: impl,
-		Log:  func(_ string, _ ...interface{}) {},
+		impl:      impl,
+		Log:       func(_ string, _ ...interface{}) {},
+		ChunkSize: DefaultChunkSize,
 	}
 }
 
@@ -73,7 +82,7 @@ func (cfgmaps *ConfigMaps) Get(key string) (*rspb.Release, error) {
 		return nil, err
 	}
 	// found the configmap, decode the base64 data string
-	r, err := decodeRelease(obj.Data["release"])
+	r, err := cfgmaps.decodeObject(obj)
 	if err != nil {
 		cfgmaps.Log("get: failed to decode data %q: %s", key, err)
 		return nil, err
@@ -100,7 +109,7 @@ func (cfgmaps *ConfigMaps) List(filter func(*rspb.Release) bool) ([]*rspb.Releas
 	// iterate over the configmaps object list
 	// and decode each release
 	for _, item := range list.Items {
-		rls, err := decodeRelease(item.Data["release"])
+		rls, err := cfgmaps.decodeObject(&item)
 		if err != nil {
 			cfgmaps.Log("list: failed to decode release: %v: %s", item, err)
 			continue
@@ -137,7 +146,7 @@ func (cfgmaps *ConfigMaps) Query(labels map[string]string) ([]*rspb.Release, err
 
 	var results []*rspb.Release
 	for _, item := range list.Items {
-		rls, err := decodeRelease(item.Data["release"])
+		rls, err := cfgmaps.decodeObject(&item)
 		if err != nil {
 			cfgmaps.Log("query: failed to decode release: %s", err)
 			continue
@@ -162,8 +171,15 @@ func (cfgmaps *ConfigMaps) Create(key string, rls *rspb.Release) error {
 		cfgmaps.Log("create: failed to encode release %q: %s", rls.Name, err)
 		return err
 	}
+	// store the chunks of a large release before the configmap referencing them
+	chunks := cfgmaps.splitObject(obj)
+	if err := cfgmaps.createChunks(chunks); err != nil {
+		cfgmaps.Log("create: failed to create chunks of %q: %s", key, err)
+		return err
+	}
 	// push the configmap object out into the kubiverse
 	if _, err := cfgmaps.impl.Create(obj); err != nil {
+		cfgmaps.deleteChunks(chunks)
 		if apierrors.IsAlreadyExists(err) {
 			return storageerrors.ErrReleaseExists(key)
 		}
@@ -189,33 +205,242 @@ func (cfgmaps *ConfigMaps) Update(key string, rls *rspb.Release) error {
 		cfgmaps.Log("update: failed to encode release %q: %s", rls.Name, err)
 		return err
 	}
+	// remember the chunks of the previous revision of the configmap so they
+	// can be removed once the new one is in place, and the resourceVersion
+	// it was read at so that a concurrent update is detected
+	var stale []*v1.ConfigMap
+	if old, err := cfgmaps.impl.Get(key, metav1.GetOptions{}); err == nil {
+		stale = cfgmaps.chunkObjects(old)
+		obj.ResourceVersion = old.ResourceVersion
+	}
+	// store the new chunks before the configmap referencing them
+	chunks := cfgmaps.splitObject(obj)
+	if err := cfgmaps.createChunks(chunks); err != nil {
+		cfgmaps.Log("update: failed to create chunks of %q: %s", key, err)
+		return err
+	}
 	// push the configmap object out into the kubiverse
 	_, err = cfgmaps.impl.Update(obj)
 	if err != nil {
+		cfgmaps.deleteChunks(chunks)
+		if apierrors.IsConflict(err) {
+			return storageerrors.ErrReleaseConflict(key)
+		}
+
 		cfgmaps.Log("update: failed to update: %s", err)
 		return err
 	}
+	cfgmaps.deleteChunks(stale)
 	return nil
 }
 
 // Delete deletes the ConfigMap holding the release named by key.
 func (cfgmaps *ConfigMaps) Delete(key string) (rls *rspb.Release, err error) {
 	// fetch the release to check existence
-	if rls, err = cfgmaps.Get(key); err != nil {
+	obj, err := cfgmaps.impl.Get(key, metav1.GetOptions{})
+	if err != nil {
 		if apierrors.IsNotFound(err) {
-			return nil, storageerrors.ErrReleaseExists(rls.Name)
+			return nil, storageerrors.ErrReleaseNotFound(key)
 		}
 
 		cfgmaps.Log("delete: failed to get release %q: %s", key, err)
 		return nil, err
 	}
-	// delete the release
+	if rls, err = cfgmaps.decodeObject(obj); err != nil {
+		cfgmaps.Log("delete: failed to decode release %q: %s", key, err)
+		return nil, err
+	}
+	// delete the release, then the chunks it no longer references
 	if err = cfgmaps.impl.Delete(key, &metav1.DeleteOptions{}); err != nil {
 		return rls, err
 	}
+	cfgmaps.deleteChunks(cfgmaps.chunkObjects(obj))
 	return rls, nil
 }
 
+// Lock acquires or renews the lock of the named release on behalf of holder.
+// The lock is held in a ConfigMap, taken over once expired by updating it at the
+// resourceVersion it was read at.
+func (cfgmaps *ConfigMaps) Lock(name, holder string, ttl time.Duration) error {
+	key := lockName(name)
+	now := time.Now()
+
+	obj, err := cfgmaps.impl.Get(key, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			cfgmaps.Log("lock: failed to get lock of %q: %s", name, err)
+			return err
+		}
+		obj = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   key,
+				Labels: newLockLabels(name).toMap(),
+			},
+			Data: newLockData(holder, now.Add(ttl)),
+		}
+		if _, err := cfgmaps.impl.Create(obj); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return storageerrors.ErrReleaseLocked(name)
+			}
+
+			cfgmaps.Log("lock: failed to create lock of %q: %s", name, err)
+			return err
+		}
+		return nil
+	}
+
+	if lockHeld(obj.Data, holder, now) {
+		return storageerrors.ErrReleaseLocked(name)
+	}
+	obj.Data = newLockData(holder, now.Add(ttl))
+	if _, err := cfgmaps.impl.Update(obj); err != nil {
+		if apierrors.IsConflict(err) {
+			return storageerrors.ErrReleaseLocked(name)
+		}
+
+		cfgmaps.Log("lock: failed to update lock of %q: %s", name, err)
+		return err
+	}
+	return nil
+}
+
+// Unlock releases the lock of the named release if it is held by holder.
+func (cfgmaps *ConfigMaps) Unlock(name, holder string) error {
+	key := lockName(name)
+
+	obj, err := cfgmaps.impl.Get(key, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if obj.Data[lockHolderKey] != holder {
+		// the lock expired and was taken over
+		return nil
+	}
+	err = cfgmaps.impl.Delete(key, &metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &obj.ResourceVersion},
+	})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
+
+// Watch sends an event for every release created, updated or deleted in the
+// ConfigMaps watched by the driver, until stop is closed or the watch ends.
+func (cfgmaps *ConfigMaps) Watch(stop <-chan struct{}) (<-chan ReleaseEvent, error) {
+	lsel := kblabels.Set{"OWNER": "TILLER"}.AsSelector()
+	opts := metav1.ListOptions{LabelSelector: lsel.String()}
+
+	w, err := cfgmaps.impl.Watch(opts)
+	if err != nil {
+		cfgmaps.Log("watch: failed to watch: %s", err)
+		return nil, err
+	}
+	return watchReleases(w, stop, cfgmaps.Log, func(ev watch.Event) (ReleaseEvent, error) {
+		obj, ok := ev.Object.(*v1.ConfigMap)
+		if !ok {
+			return ReleaseEvent{}, fmt.Errorf("unexpected object %T", ev.Object)
+		}
+		if ev.Type == watch.Deleted {
+			return ReleaseEvent{Key: obj.Name}, nil
+		}
+		rls, err := cfgmaps.decodeObject(obj)
+		if err != nil {
+			return ReleaseEvent{}, fmt.Errorf("%q: %s", obj.Name, err)
+		}
+		return ReleaseEvent{Key: obj.Name, Release: rls}, nil
+	}), nil
+}
+
+// decodeObject reassembles and decodes the release held by a ConfigMap,
+// fetching the chunks holding the rest of its body if it has been split.
+func (cfgmaps *ConfigMaps) decodeObject(obj *v1.ConfigMap) (*rspb.Release, error) {
+	n, set, err := chunkInfo(obj.Labels)
+	if err != nil {
+		return nil, err
+	}
+	if n == 1 {
+		return decodeRelease(obj.Data["release"])
+	}
+
+	var data strings.Builder
+	data.WriteString(obj.Data["release"])
+	for i := 1; i < n; i++ {
+		chunk, err := cfgmaps.impl.Get(chunkName(obj.Name, set, i), metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chunk %d/%d of %q: %s", i+1, n, obj.Name, err)
+		}
+		data.WriteString(chunk.Data["release"])
+	}
+	return decodeRelease(data.String())
+}
+
+// splitObject moves the part of the release held by obj that exceeds the
+// chunk size into new chunk ConfigMaps, and labels obj so that the chunks can
+// be found again. The chunk ConfigMaps are returned without being created.
+func (cfgmaps *ConfigMaps) splitObject(obj *v1.ConfigMap) []*v1.ConfigMap {
+	pieces := splitChunks(obj.Data["release"], cfgmaps.ChunkSize)
+	if len(pieces) == 1 {
+		return nil
+	}
+
+	set := newChunkSet()
+	obj.Data["release"] = pieces[0]
+	obj.Labels[chunksLabel] = strconv.Itoa(len(pieces))
+	obj.Labels[chunkSetLabel] = set
+
+	var chunks []*v1.ConfigMap
+	for i := 1; i < len(pieces); i++ {
+		chunks = append(chunks, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   chunkName(obj.Name, set, i),
+				Labels: newChunkLabels(obj.Name, set, i).toMap(),
+			},
+			Data: map[string]string{"release": pieces[i]},
+		})
+	}
+	return chunks
+}
+
+// chunkObjects returns the chunk ConfigMaps referenced by obj, holding only
+// their names.
+func (cfgmaps *ConfigMaps) chunkObjects(obj *v1.ConfigMap) []*v1.ConfigMap {
+	n, set, err := chunkInfo(obj.Labels)
+	if err != nil {
+		return nil
+	}
+	var chunks []*v1.ConfigMap
+	for i := 1; i < n; i++ {
+		chunks = append(chunks, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: chunkName(obj.Name, set, i)}})
+	}
+	return chunks
+}
+
+// createChunks creates the chunk ConfigMaps. If any of them fails, the ones
+// already created are deleted.
+func (cfgmaps *ConfigMaps) createChunks(chunks []*v1.ConfigMap) error {
+	for i, chunk := range chunks {
+		if _, err := cfgmaps.impl.Create(chunk); err != nil {
+			cfgmaps.deleteChunks(chunks[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteChunks deletes the chunk ConfigMaps. Failures are only logged: an
+// orphaned chunk is never referenced, and so never read, again.
+func (cfgmaps *ConfigMaps) deleteChunks(chunks []*v1.ConfigMap) {
+	for _, chunk := range chunks {
+		if err := cfgmaps.impl.Delete(chunk.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			cfgmaps.Log("failed to delete chunk %q: %s", chunk.Name, err)
+		}
+	}
+}
+
 // newConfigMapsObject constructs a kubernetes ConfigMap object
 // to store a release. Each configmap data entry is the base64
 // encoded string of a release's binary protobuf encoding.
@@ -229,6 +454,9 @@ func (cfgmaps *ConfigMaps) Delete(key string) (rls *rspb.Release, err error) {
 //    "OWNER"          - owner of the configmap, currently "TILLER".
 //    "NAME"           - name of the release.
 //
+// Releases too large for a single configmap are split by Create and Update,
+// which add the chunk labels described in chunks.go.
+//
 func newConfigMapsObject(key string, rls *rspb.Release, lbs labels) (*v1.ConfigMap, error) {
 	const owner = "TILLER"
 
@@ -15,12 +15,14 @@ package driver
 
 import (
 	"encoding/base64"
+	"reflect"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
 	"k8s.io/api/core/v1"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
 
 func TestConfigMapName(t *testing.T) {
@@ -183,3 +185,110 @@ func TestConfigMapUpdate(t *testing.T) {
 		t.Errorf("Expected status %s, got status %s", rel.Info.Status.Code, got.Info.Status.Code)
 	}
 }
+
+func TestConfigMapChunked(t *testing.T) {
+	vers := int32(1)
+	name := "smug-pigeon"
+	namespace := "default"
+	key := testKey(name, vers)
+	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
+	rel.Manifest = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: smug-pigeon\n"
+
+	cfgmaps := newTestFixtureCfgMaps(t)
+	cfgmaps.ChunkSize = 16
+	mock := cfgmaps.impl.(*MockConfigMapsInterface)
+
+	// store the release across several configmaps
+	if err := cfgmaps.Create(key, rel); err != nil {
+		t.Fatalf("Failed to create release with key %q: %s", key, err)
+	}
+	if len(mock.objects) < 2 {
+		t.Fatalf("Expected release to be split across several configmaps, got %d", len(mock.objects))
+	}
+
+	// get, list and query reassemble the release
+	got, err := cfgmaps.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release with key %q: %s", key, err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+	ls, err := cfgmaps.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+	if len(ls) != 1 || !shallowReleaseEqual(rel, ls[0]) {
+		t.Errorf("Expected [{%q}], got %v", rel, ls)
+	}
+	ls, err = cfgmaps.Query(map[string]string{"NAME": name, "OWNER": "TILLER"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	if len(ls) != 1 || !shallowReleaseEqual(rel, ls[0]) {
+		t.Errorf("Expected [{%q}], got %v", rel, ls)
+	}
+
+	// a release missing one of its chunks is never returned
+	head := mock.objects[key]
+	_, set, _ := chunkInfo(head.Labels)
+	chunk := mock.objects[chunkName(key, set, 1)]
+	delete(mock.objects, chunk.Name)
+	if _, err := cfgmaps.Get(key); err == nil {
+		t.Errorf("Expected an error when getting a release with a missing chunk")
+	}
+	mock.objects[chunk.Name] = chunk
+
+	// updating replaces the chunks
+	rel.Manifest += "data:\n  key: value\n"
+	if err := cfgmaps.Update(key, rel); err != nil {
+		t.Fatalf("Failed to update release: %s", err)
+	}
+	if _, ok := mock.objects[chunk.Name]; ok {
+		t.Errorf("Expected stale chunk %q to be deleted", chunk.Name)
+	}
+	got, err = cfgmaps.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release with key %q: %s", key, err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+
+	// deleting removes every chunk
+	if _, err := cfgmaps.Delete(key); err != nil {
+		t.Fatalf("Failed to delete release with key %q: %s", key, err)
+	}
+	if len(mock.objects) != 0 {
+		t.Errorf("Expected no configmaps left, got %d", len(mock.objects))
+	}
+}
+
+// racingConfigMapsInterface simulates another Tiller updating a ConfigMap between the
+// read and the write of an update.
+type racingConfigMapsInterface struct {
+	*MockConfigMapsInterface
+}
+
+func (mock racingConfigMapsInterface) Update(cfgmap *v1.ConfigMap) (*v1.ConfigMap, error) {
+	if _, err := mock.MockConfigMapsInterface.Update(mock.objects[cfgmap.Name]); err != nil {
+		return nil, err
+	}
+	return mock.MockConfigMapsInterface.Update(cfgmap)
+}
+
+func TestConfigMapUpdateConflict(t *testing.T) {
+	name := "smug-pigeon"
+	vers := int32(1)
+	key := testKey(name, vers)
+	rel := releaseStub(name, vers, "default", rspb.Status_DEPLOYED)
+
+	cfgmaps := newTestFixtureCfgMaps(t, []*rspb.Release{rel}...)
+	cfgmaps.impl = racingConfigMapsInterface{cfgmaps.impl.(*MockConfigMapsInterface)}
+
+	rel.Info.Status.Code = rspb.Status_SUPERSEDED
+	err := cfgmaps.Update(key, rel)
+	if expected := storageerrors.ErrReleaseConflict(key); !reflect.DeepEqual(err, expected) {
+		t.Errorf("Expected %q, got %v", expected, err)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultChunkSize is the maximum number of bytes of an encoded release stored
+// in a single ConfigMap or Secret. Kubernetes rejects objects larger than 1MiB,
+// so larger releases are split across several objects, leaving headroom for
+// the object metadata.
+const DefaultChunkSize = 768 * 1024
+
+// A release whose encoded body exceeds the chunk size is stored as a head
+// object, named by the release key, and a set of numbered chunk objects.
+//
+// The head object carries the usual release labels plus:
+//
+//	"CHUNKS"         - total number of chunks, including the one held by the head.
+//	"CHUNK_SET"      - identifier of the set of chunk objects holding the rest of the body.
+//
+// Each chunk object is named "<key>.<set>.<index>" and labelled with:
+//
+//	"CHUNK_OF"       - key of the release the chunk belongs to.
+//	"CHUNK_SET"      - identifier of the set the chunk belongs to.
+//	"CHUNK_INDEX"    - position of the chunk in the release body, starting at 1.
+//
+// Chunk objects carry neither the "OWNER" nor the "NAME" label so they never
+// match the selectors used to list or query releases. They are always written
+// before, and deleted after, the head object that references them, so a
+// release is never visible until every chunk of its body has been stored.
+const (
+	chunksLabel     = "CHUNKS"
+	chunkSetLabel   = "CHUNK_SET"
+	chunkOfLabel    = "CHUNK_OF"
+	chunkIndexLabel = "CHUNK_INDEX"
+)
+
+// splitChunks splits the encoded release s into pieces of at most size bytes.
+// A size of 0 or less disables chunking.
+func splitChunks(s string, size int) []string {
+	if size <= 0 || len(s) <= size {
+		return []string{s}
+	}
+	var chunks []string
+	for len(s) > size {
+		chunks = append(chunks, s[:size])
+		s = s[size:]
+	}
+	return append(chunks, s)
+}
+
+// newChunkSet returns a new identifier for a set of chunk objects. A fresh set
+// is used on every write so that the chunks referenced by the current head
+// object are never modified in place.
+func newChunkSet() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// chunkName returns the name of the object holding the chunk at index of the
+// release named by key.
+func chunkName(key, set string, index int) string {
+	return fmt.Sprintf("%s.%s.%d", key, set, index)
+}
+
+// newChunkLabels returns the labels of the chunk object at index of the
+// release named by key.
+func newChunkLabels(key, set string, index int) labels {
+	var lbs labels
+
+	lbs.init()
+	lbs.set(chunkOfLabel, key)
+	lbs.set(chunkSetLabel, set)
+	lbs.set(chunkIndexLabel, strconv.Itoa(index))
+	return lbs
+}
+
+// chunkInfo returns the number of chunks and the chunk set recorded in the
+// labels of a head object. Releases stored in a single object report one chunk.
+func chunkInfo(lbs map[string]string) (int, string, error) {
+	v, ok := lbs[chunksLabel]
+	if !ok {
+		return 1, "", nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, "", fmt.Errorf("invalid %s label %q", chunksLabel, v)
+	}
+	return n, lbs[chunkSetLabel], nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitChunks(t *testing.T) {
+	var tests = []struct {
+		desc   string
+		data   string
+		size   int
+		expect []string
+	}{
+		{"chunking disabled", "abcdef", 0, []string{"abcdef"}},
+		{"smaller than chunk size", "abc", 4, []string{"abc"}},
+		{"exactly chunk size", "abcd", 4, []string{"abcd"}},
+		{"uneven split", "abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"even split", "abcdefgh", 4, []string{"abcd", "efgh"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitChunks(tt.data, tt.size); !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("%s: expected %q, got %q", tt.desc, tt.expect, got)
+		}
+	}
+}
+
+func TestChunkInfo(t *testing.T) {
+	n, set, err := chunkInfo(map[string]string{"NAME": "smug-pigeon"})
+	if err != nil || n != 1 || set != "" {
+		t.Errorf("Expected a single chunk, got %d %q %v", n, set, err)
+	}
+
+	n, set, err = chunkInfo(map[string]string{chunksLabel: "3", chunkSetLabel: "abc"})
+	if err != nil || n != 3 || set != "abc" {
+		t.Errorf("Expected 3 chunks in set %q, got %d %q %v", "abc", n, set, err)
+	}
+
+	if _, _, err := chunkInfo(map[string]string{chunksLabel: "zero"}); err == nil {
+		t.Errorf("Expected an error for an invalid %s label", chunksLabel)
+	}
+}
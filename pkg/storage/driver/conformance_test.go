@@ -0,0 +1,98 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/storage/driver/drivertest"
+)
+
+func TestMemoryConformance(t *testing.T) {
+	drivertest.Run(t, func(t *testing.T) (driver.Driver, func()) {
+		return driver.NewMemory(), func() {}
+	})
+}
+
+func TestConfigMapsConformance(t *testing.T) {
+	drivertest.Run(t, func(t *testing.T) (driver.Driver, func()) {
+		client := fake.NewSimpleClientset()
+		return driver.NewConfigMaps(client.CoreV1().ConfigMaps(v1.NamespaceDefault)), func() {}
+	})
+}
+
+func TestSecretsConformance(t *testing.T) {
+	drivertest.Run(t, func(t *testing.T) (driver.Driver, func()) {
+		client := fake.NewSimpleClientset()
+		return driver.NewSecrets(client.CoreV1().Secrets(v1.NamespaceDefault)), func() {}
+	})
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	if !driver.SQLiteSupported {
+		t.Skip("the sqlite3 dialect requires cgo")
+	}
+	drivertest.Run(t, func(t *testing.T) (driver.Driver, func()) {
+		dir := tempDir(t)
+		d, err := driver.NewSQL("sqlite3", filepath.Join(dir, "releases.db"), t.Logf)
+		if err != nil {
+			os.RemoveAll(dir)
+			t.Fatalf("Failed to open sqlite database: %s", err)
+		}
+		return d, func() { os.RemoveAll(dir) }
+	})
+}
+
+func TestFileConformance(t *testing.T) {
+	drivertest.Run(t, func(t *testing.T) (driver.Driver, func()) {
+		dir := tempDir(t)
+		d, err := driver.NewFile(dir)
+		if err != nil {
+			os.RemoveAll(dir)
+			t.Fatalf("Failed to create file driver: %s", err)
+		}
+		return d, func() { os.RemoveAll(dir) }
+	})
+}
+
+func TestCacheConformance(t *testing.T) {
+	drivertest.Run(t, func(t *testing.T) (driver.Driver, func()) {
+		return driver.NewCache(driver.NewMemory()), func() {}
+	})
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "helm-driver-")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %s", err)
+	}
+	return dir
+}
+
+func TestObservedConformance(t *testing.T) {
+	drivertest.Run(t, func(t *testing.T) (driver.Driver, func()) {
+		return driver.NewObserved(driver.NewMemory(), func(_, _ string, _ time.Duration) {}), func() {}
+	})
+}
@@ -0,0 +1,29 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*Package drivertest provides a conformance test suite for storage drivers.
+
+Run checks that a driver.Driver stores, updates, deletes and queries releases
+the way Tiller expects, so that drivers maintained outside of Helm can prove
+they are compatible with the ones in pkg/storage/driver:
+
+	func TestConformance(t *testing.T) {
+		drivertest.Run(t, func(t *testing.T) (driver.Driver, func()) {
+			return NewMyDriver(), func() {}
+		})
+	}
+*/
+package drivertest // import "k8s.io/helm/pkg/storage/driver/drivertest"
@@ -0,0 +1,367 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivertest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+// concurrency is the number of goroutines accessing a driver at once in the
+// concurrent access tests.
+const concurrency = 10
+
+// Factory returns a new driver holding no release, and a function releasing
+// the resources of the driver once the test is done.
+type Factory func(t *testing.T) (driver.Driver, func())
+
+// Run runs the conformance tests as subtests of t, each of them against a new
+// driver returned by newDriver.
+func Run(t *testing.T, newDriver Factory) {
+	var tests = []struct {
+		name string
+		test func(*testing.T, driver.Driver)
+	}{
+		{"Create", testCreate},
+		{"Get", testGet},
+		{"Update", testUpdate},
+		{"Delete", testDelete},
+		{"List", testList},
+		{"Query", testQuery},
+		{"ConcurrentCreate", testConcurrentCreate},
+		{"ConcurrentAccess", testConcurrentAccess},
+	}
+
+	for _, tt := range tests {
+		test := tt.test
+		t.Run(tt.name, func(t *testing.T) {
+			d, cleanup := newDriver(t)
+			defer cleanup()
+			test(t, d)
+		})
+	}
+}
+
+// fixture holds the releases most tests start with.
+func fixture() []*rspb.Release {
+	return []*rspb.Release{
+		releaseStub("rls-a", 1, "default", rspb.Status_SUPERSEDED),
+		releaseStub("rls-a", 2, "default", rspb.Status_DEPLOYED),
+		releaseStub("rls-b", 1, "other", rspb.Status_DEPLOYED),
+		releaseStub("rls-c", 1, "default", rspb.Status_DELETED),
+	}
+}
+
+func testCreate(t *testing.T, d driver.Driver) {
+	rls := releaseStub("rls-a", 1, "default", rspb.Status_DEPLOYED)
+	if err := d.Create(key(rls), rls); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	got, err := d.Get(key(rls))
+	if err != nil {
+		t.Fatalf("Failed to get created release: %s", err)
+	}
+	if !proto.Equal(got, rls) {
+		t.Errorf("Expected %v, got %v", rls, got)
+	}
+
+	if err := d.Create(key(rls), rls); !isExists(err) {
+		t.Errorf("Expected an already exists error creating a release twice, got %v", err)
+	}
+
+	next := releaseStub("rls-a", 2, "default", rspb.Status_DEPLOYED)
+	if err := d.Create(key(next), next); err != nil {
+		t.Errorf("Failed to create a second revision of a release: %s", err)
+	}
+}
+
+func testGet(t *testing.T, d driver.Driver) {
+	create(t, d, fixture()...)
+
+	for _, rls := range fixture() {
+		got, err := d.Get(key(rls))
+		if err != nil {
+			t.Errorf("Failed to get %s: %s", key(rls), err)
+			continue
+		}
+		if !proto.Equal(got, rls) {
+			t.Errorf("Expected %v, got %v", rls, got)
+		}
+	}
+
+	for _, k := range []string{"rls-a.v3", "missing.v1"} {
+		if _, err := d.Get(k); !isNotFound(err) {
+			t.Errorf("Expected a not found error getting %s, got %v", k, err)
+		}
+	}
+}
+
+func testUpdate(t *testing.T, d driver.Driver) {
+	create(t, d, fixture()...)
+
+	rls := releaseStub("rls-a", 2, "default", rspb.Status_FAILED)
+	rls.Manifest = "updated"
+	if err := d.Update(key(rls), rls); err != nil {
+		t.Fatalf("Failed to update release: %s", err)
+	}
+	got, err := d.Get(key(rls))
+	if err != nil {
+		t.Fatalf("Failed to get updated release: %s", err)
+	}
+	if !proto.Equal(got, rls) {
+		t.Errorf("Expected %v, got %v", rls, got)
+	}
+
+	rels, err := d.Query(map[string]string{"NAME": "rls-a", "OWNER": "TILLER", "STATUS": "FAILED"})
+	if err != nil {
+		t.Fatalf("Failed to query updated release: %s", err)
+	}
+	if got, want := keys(rels), []string{"rls-a.v2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the labels of the release to be updated: expected %v, got %v", want, got)
+	}
+
+	missing := releaseStub("rls-a", 3, "default", rspb.Status_DEPLOYED)
+	if err := d.Update(key(missing), missing); !isNotFound(err) {
+		t.Errorf("Expected a not found error updating a missing release, got %v", err)
+	}
+}
+
+func testDelete(t *testing.T, d driver.Driver) {
+	create(t, d, fixture()...)
+
+	want := fixture()[0]
+	got, err := d.Delete(key(want))
+	if err != nil {
+		t.Fatalf("Failed to delete release: %s", err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("Expected the deleted release %v, got %v", want, got)
+	}
+
+	if _, err := d.Get(key(want)); !isNotFound(err) {
+		t.Errorf("Expected a not found error getting a deleted release, got %v", err)
+	}
+	if _, err := d.Delete(key(want)); !isNotFound(err) {
+		t.Errorf("Expected a not found error deleting a release twice, got %v", err)
+	}
+
+	rels, err := d.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+	if got, want := keys(rels), []string{"rls-a.v2", "rls-b.v1", "rls-c.v1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v to be left, got %v", want, got)
+	}
+}
+
+func testList(t *testing.T, d driver.Driver) {
+	create(t, d, fixture()...)
+
+	var tests = []struct {
+		desc   string
+		filter func(*rspb.Release) bool
+		keys   []string
+	}{
+		{
+			"every release",
+			func(*rspb.Release) bool { return true },
+			[]string{"rls-a.v1", "rls-a.v2", "rls-b.v1", "rls-c.v1"},
+		},
+		{
+			"deployed releases",
+			func(rls *rspb.Release) bool { return rls.Info.Status.Code == rspb.Status_DEPLOYED },
+			[]string{"rls-a.v2", "rls-b.v1"},
+		},
+		{
+			"releases of a namespace",
+			func(rls *rspb.Release) bool { return rls.Namespace == "other" },
+			[]string{"rls-b.v1"},
+		},
+		{
+			"no release",
+			func(*rspb.Release) bool { return false },
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		rels, err := d.List(tt.filter)
+		if err != nil {
+			t.Errorf("%s: failed to list releases: %s", tt.desc, err)
+			continue
+		}
+		if got := keys(rels); !reflect.DeepEqual(got, tt.keys) {
+			t.Errorf("%s: expected %v, got %v", tt.desc, tt.keys, got)
+		}
+	}
+}
+
+func testQuery(t *testing.T, d driver.Driver) {
+	create(t, d, fixture()...)
+
+	var tests = []struct {
+		desc   string
+		labels map[string]string
+		keys   []string
+	}{
+		{
+			"history of a release",
+			map[string]string{"NAME": "rls-a", "OWNER": "TILLER"},
+			[]string{"rls-a.v1", "rls-a.v2"},
+		},
+		{
+			"deployed revision of a release",
+			map[string]string{"NAME": "rls-a", "OWNER": "TILLER", "STATUS": "DEPLOYED"},
+			[]string{"rls-a.v2"},
+		},
+		{
+			"deployed releases",
+			map[string]string{"OWNER": "TILLER", "STATUS": "DEPLOYED"},
+			[]string{"rls-a.v2", "rls-b.v1"},
+		},
+		{
+			"missing release",
+			map[string]string{"NAME": "missing", "OWNER": "TILLER"},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		rels, err := d.Query(tt.labels)
+		// drivers may either return no release or a not found error when
+		// no release matches
+		if err != nil && !(tt.keys == nil && isNotFound(err)) {
+			t.Errorf("%s: failed to query releases: %s", tt.desc, err)
+			continue
+		}
+		if got := keys(rels); !reflect.DeepEqual(got, tt.keys) {
+			t.Errorf("%s: expected %v, got %v", tt.desc, tt.keys, got)
+		}
+	}
+}
+
+func testConcurrentCreate(t *testing.T, d driver.Driver) {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created int
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rls := releaseStub("rls-a", 1, "default", rspb.Status_DEPLOYED)
+			if err := d.Create(key(rls), rls); err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Errorf("Expected a release created concurrently to be created once, got %d", created)
+	}
+}
+
+func testConcurrentAccess(t *testing.T, d driver.Driver) {
+	var wg sync.WaitGroup
+	for i := 1; i <= concurrency; i++ {
+		wg.Add(2)
+		go func(version int32) {
+			defer wg.Done()
+			rls := releaseStub("rls-a", version, "default", rspb.Status_PENDING_INSTALL)
+			if err := d.Create(key(rls), rls); err != nil {
+				t.Errorf("Failed to create %s: %s", key(rls), err)
+				return
+			}
+			rls = releaseStub("rls-a", version, "default", rspb.Status_DEPLOYED)
+			if err := d.Update(key(rls), rls); err != nil {
+				t.Errorf("Failed to update %s: %s", key(rls), err)
+			}
+		}(int32(i))
+		go func() {
+			defer wg.Done()
+			if _, err := d.List(func(*rspb.Release) bool { return true }); err != nil {
+				t.Errorf("Failed to list releases: %s", err)
+			}
+			if _, err := d.Query(map[string]string{"NAME": "rls-a", "OWNER": "TILLER"}); err != nil && !isNotFound(err) {
+				t.Errorf("Failed to query releases: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	rels, err := d.Query(map[string]string{"NAME": "rls-a", "OWNER": "TILLER", "STATUS": "DEPLOYED"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	if len(rels) != concurrency {
+		t.Errorf("Expected %d deployed releases, got %v", concurrency, keys(rels))
+	}
+}
+
+// create stores rels in d, failing the test on error.
+func create(t *testing.T, d driver.Driver, rels ...*rspb.Release) {
+	for _, rls := range rels {
+		if err := d.Create(key(rls), rls); err != nil {
+			t.Fatalf("Test setup failed to create %s: %s", key(rls), err)
+		}
+	}
+}
+
+func releaseStub(name string, version int32, namespace string, code rspb.Status_Code) *rspb.Release {
+	return &rspb.Release{
+		Name:      name,
+		Version:   version,
+		Namespace: namespace,
+		Info:      &rspb.Info{Status: &rspb.Status{Code: code}},
+	}
+}
+
+// key returns the storage key of rls.
+func key(rls *rspb.Release) string {
+	return fmt.Sprintf("%s.v%d", rls.Name, rls.Version)
+}
+
+// keys returns the sorted storage keys of rels.
+func keys(rels []*rspb.Release) []string {
+	var ks []string
+	for _, rls := range rels {
+		ks = append(ks, key(rls))
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+func isNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "not found")
+}
+
+func isExists(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "already exists")
+}
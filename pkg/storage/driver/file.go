@@ -0,0 +1,459 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
+)
+
+var _ Driver = (*File)(nil)
+var _ Locker = (*File)(nil)
+
+// FileDriverName is the string name of this driver.
+const FileDriverName = "File"
+
+// A File driver stores releases under a directory laid out as follows:
+//
+//	releases/<key>   - the encoded release stored under key, one file per revision.
+//	index.json       - the labels of every release, and the locks of releases.
+//	.lock            - the file locked by every operation.
+//
+// Every operation locks the .lock file, shared for reads and exclusively for
+// writes, so that several processes can use the same directory. Files are
+// written to a temporary file first and renamed into place, so that a crash
+// never leaves a partially written release or index behind. The index is
+// rebuilt from the release files if it is missing.
+const (
+	fileReleasesDir = "releases"
+	fileIndexName   = "index.json"
+	fileLockName    = ".lock"
+)
+
+// fileIndex is the index of the releases stored by a File driver.
+type fileIndex struct {
+	// Releases maps the key of each release to its labels.
+	Releases map[string]labels `json:"releases"`
+	// Locks maps the name of each locked release to the data of its lock.
+	Locks map[string]map[string]string `json:"locks,omitempty"`
+}
+
+// File is the file system storage driver implementation.
+type File struct {
+	dir string
+	Log func(string, ...interface{})
+}
+
+// NewFile initializes a new File driver storing releases under dir, which is
+// created if it does not exist.
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(filepath.Join(dir, fileReleasesDir), 0700); err != nil {
+		return nil, err
+	}
+	return &File{
+		dir: dir,
+		Log: func(_ string, _ ...interface{}) {},
+	}, nil
+}
+
+// Name returns the name of the driver.
+func (file *File) Name() string {
+	return FileDriverName
+}
+
+// Get returns the release named by key or returns ErrReleaseNotFound.
+func (file *File) Get(key string) (*rspb.Release, error) {
+	if !validFileKey(key) {
+		return nil, storageerrors.ErrInvalidKey(key)
+	}
+	defer unlock(file.rlock())
+
+	rls, err := file.readRelease(key)
+	if os.IsNotExist(err) {
+		return nil, storageerrors.ErrReleaseNotFound(key)
+	}
+	if err != nil {
+		file.Log("get: failed to read release %q: %s", key, err)
+		return nil, err
+	}
+	return rls, nil
+}
+
+// List returns the list of all releases such that filter(release) == true
+func (file *File) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	defer unlock(file.rlock())
+
+	idx, err := file.readIndex()
+	if err != nil {
+		file.Log("list: failed to read index: %s", err)
+		return nil, err
+	}
+
+	var ls []*rspb.Release
+	for key := range idx.Releases {
+		rls, err := file.readRelease(key)
+		if err != nil {
+			file.Log("list: failed to read release %q: %s", key, err)
+			continue
+		}
+		if filter(rls) {
+			ls = append(ls, rls)
+		}
+	}
+	return ls, nil
+}
+
+// Query returns the set of releases that match the provided set of labels,
+// or ErrReleaseNotFound if none does.
+func (file *File) Query(keyvals map[string]string) ([]*rspb.Release, error) {
+	defer unlock(file.rlock())
+
+	idx, err := file.readIndex()
+	if err != nil {
+		file.Log("query: failed to read index: %s", err)
+		return nil, err
+	}
+
+	var lbs labels
+
+	lbs.init()
+	lbs.fromMap(keyvals)
+
+	var ls []*rspb.Release
+	for key, recLbs := range idx.Releases {
+		if !recLbs.match(lbs) {
+			continue
+		}
+		rls, err := file.readRelease(key)
+		if err != nil {
+			file.Log("query: failed to read release %q: %s", key, err)
+			continue
+		}
+		ls = append(ls, rls)
+	}
+	if len(ls) == 0 {
+		return nil, storageerrors.ErrReleaseNotFound(keyvals["NAME"])
+	}
+	return ls, nil
+}
+
+// Create creates a new release or returns ErrReleaseExists.
+func (file *File) Create(key string, rls *rspb.Release) error {
+	if !validFileKey(key) {
+		return storageerrors.ErrInvalidKey(key)
+	}
+	release, err := file.wlock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	idx, err := file.readIndex()
+	if err != nil {
+		return err
+	}
+	if _, ok := idx.Releases[key]; ok {
+		return storageerrors.ErrReleaseExists(key)
+	}
+
+	lbs := newFileLabels(rls)
+	lbs.set("CREATED_AT", strconv.Itoa(int(time.Now().Unix())))
+	if err := file.writeRelease(key, rls); err != nil {
+		file.Log("create: failed to write release %q: %s", key, err)
+		return err
+	}
+	idx.Releases[key] = lbs
+	return file.writeIndex(idx)
+}
+
+// Update updates a release or returns ErrReleaseNotFound.
+func (file *File) Update(key string, rls *rspb.Release) error {
+	if !validFileKey(key) {
+		return storageerrors.ErrInvalidKey(key)
+	}
+	release, err := file.wlock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	idx, err := file.readIndex()
+	if err != nil {
+		return err
+	}
+	old, ok := idx.Releases[key]
+	if !ok {
+		return storageerrors.ErrReleaseNotFound(key)
+	}
+
+	lbs := newFileLabels(rls)
+	lbs.set("CREATED_AT", old.get("CREATED_AT"))
+	lbs.set("MODIFIED_AT", strconv.Itoa(int(time.Now().Unix())))
+	if err := file.writeRelease(key, rls); err != nil {
+		file.Log("update: failed to write release %q: %s", key, err)
+		return err
+	}
+	idx.Releases[key] = lbs
+	return file.writeIndex(idx)
+}
+
+// Delete deletes a release or returns ErrReleaseNotFound.
+func (file *File) Delete(key string) (*rspb.Release, error) {
+	if !validFileKey(key) {
+		return nil, storageerrors.ErrInvalidKey(key)
+	}
+	release, err := file.wlock()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	idx, err := file.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := idx.Releases[key]; !ok {
+		return nil, storageerrors.ErrReleaseNotFound(key)
+	}
+	rls, err := file.readRelease(key)
+	if err != nil {
+		file.Log("delete: failed to read release %q: %s", key, err)
+		return nil, err
+	}
+
+	// drop the release from the index first, so that a crash leaves an
+	// orphaned file rather than a dangling index entry
+	delete(idx.Releases, key)
+	if err := file.writeIndex(idx); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(file.releasePath(key)); err != nil && !os.IsNotExist(err) {
+		file.Log("delete: failed to remove release %q: %s", key, err)
+	}
+	return rls, nil
+}
+
+// Lock acquires or renews the lock of the named release on behalf of holder.
+// Locks are recorded in the index.
+func (file *File) Lock(name, holder string, ttl time.Duration) error {
+	release, err := file.wlock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	idx, err := file.readIndex()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if data, ok := idx.Locks[name]; ok && lockHeld(data, holder, now) {
+		return storageerrors.ErrReleaseLocked(name)
+	}
+	if idx.Locks == nil {
+		idx.Locks = map[string]map[string]string{}
+	}
+	idx.Locks[name] = newLockData(holder, now.Add(ttl))
+	return file.writeIndex(idx)
+}
+
+// Unlock releases the lock of the named release if it is held by holder.
+func (file *File) Unlock(name, holder string) error {
+	release, err := file.wlock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	idx, err := file.readIndex()
+	if err != nil {
+		return err
+	}
+	if data, ok := idx.Locks[name]; !ok || data[lockHolderKey] != holder {
+		// the lock expired and was taken over
+		return nil
+	}
+	delete(idx.Locks, name)
+	return file.writeIndex(idx)
+}
+
+// rlock locks the directory for reading. Locking failures are logged and
+// otherwise ignored, e.g. on file systems not supporting locks: a read racing
+// a write fails at worst.
+func (file *File) rlock() func() {
+	fn, err := file.lockDir(false)
+	if err != nil {
+		file.Log("failed to lock %s for reading: %s", file.dir, err)
+	}
+	return fn
+}
+
+// wlock locks the directory for writing. Writes must not go on without the
+// lock, which keeps the index consistent with the release files.
+func (file *File) wlock() (func(), error) {
+	fn, err := file.lockDir(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %s", file.dir, err)
+	}
+	return fn, nil
+}
+
+// lockDir locks the lock file of the directory, exclusively or shared. Each
+// call locks a new file descriptor, so that the lock also excludes the other
+// goroutines of the process.
+func (file *File) lockDir(exclusive bool) (func(), error) {
+	fl := flock.New(filepath.Join(file.dir, fileLockName))
+	var err error
+	if exclusive {
+		err = fl.Lock()
+	} else {
+		err = fl.RLock()
+	}
+	return func() { fl.Unlock() }, err
+}
+
+// readIndex reads the index of the directory, rebuilding it from the release
+// files if it is missing.
+func (file *File) readIndex() (*fileIndex, error) {
+	b, err := ioutil.ReadFile(filepath.Join(file.dir, fileIndexName))
+	if os.IsNotExist(err) {
+		return file.rebuildIndex()
+	}
+	if err != nil {
+		return nil, err
+	}
+	idx := &fileIndex{}
+	if err := json.Unmarshal(b, idx); err != nil {
+		return nil, err
+	}
+	if idx.Releases == nil {
+		idx.Releases = map[string]labels{}
+	}
+	return idx, nil
+}
+
+// rebuildIndex returns the index of the release files of the directory.
+func (file *File) rebuildIndex() (*fileIndex, error) {
+	idx := &fileIndex{Releases: map[string]labels{}}
+	infos, err := ioutil.ReadDir(filepath.Join(file.dir, fileReleasesDir))
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		key := info.Name()
+		if info.IsDir() || !validFileKey(key) {
+			continue
+		}
+		rls, err := file.readRelease(key)
+		if err != nil {
+			file.Log("failed to index release %q: %s", key, err)
+			continue
+		}
+		lbs := newFileLabels(rls)
+		lbs.set("CREATED_AT", strconv.Itoa(int(info.ModTime().Unix())))
+		idx.Releases[key] = lbs
+	}
+	if len(idx.Releases) > 0 {
+		file.Log("rebuilt the index of %d release(s)", len(idx.Releases))
+	}
+	return idx, nil
+}
+
+func (file *File) writeIndex(idx *fileIndex) error {
+	b, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(file.dir, fileIndexName), b)
+}
+
+func (file *File) releasePath(key string) string {
+	return filepath.Join(file.dir, fileReleasesDir, key)
+}
+
+func (file *File) readRelease(key string) (*rspb.Release, error) {
+	b, err := ioutil.ReadFile(file.releasePath(key))
+	if err != nil {
+		return nil, err
+	}
+	return decodeRelease(string(b))
+}
+
+func (file *File) writeRelease(key string, rls *rspb.Release) error {
+	s, err := encodeRelease(rls)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(file.releasePath(key), []byte(s))
+}
+
+// writeFileAtomic writes b to a temporary file renamed to path, so that path
+// holds either its previous or its new content.
+func writeFileAtomic(path string, b []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// newFileLabels returns the index labels of rls.
+func newFileLabels(rls *rspb.Release) labels {
+	var lbs labels
+
+	lbs.init()
+	lbs.set("NAME", rls.Name)
+	lbs.set("OWNER", "TILLER")
+	lbs.set("STATUS", rspb.Status_Code_name[int32(rls.GetInfo().GetStatus().GetCode())])
+	lbs.set("VERSION", strconv.Itoa(int(rls.Version)))
+	return lbs
+}
+
+// validFileKey reports whether key can name a release file, i.e. is neither
+// empty, hidden nor a path.
+func validFileKey(key string) bool {
+	return key != "" && !strings.HasPrefix(key, ".") && !strings.ContainsAny(key, `/\`)
+}
@@ -0,0 +1,222 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
+)
+
+func tsFixtureFile(t *testing.T) (*File, func()) {
+	return newTestFixtureFile(t,
+		releaseStub("rls-a", 1, "default", rspb.Status_SUPERSEDED),
+		releaseStub("rls-a", 2, "default", rspb.Status_DEPLOYED),
+		releaseStub("rls-b", 1, "default", rspb.Status_SUPERSEDED),
+		releaseStub("rls-b", 2, "default", rspb.Status_DEPLOYED),
+	)
+}
+
+func TestFileName(t *testing.T) {
+	file, cleanup := newTestFixtureFile(t)
+	defer cleanup()
+
+	if file.Name() != FileDriverName {
+		t.Errorf("Expected name to be %q, got %q", FileDriverName, file.Name())
+	}
+}
+
+func TestFileCreateGet(t *testing.T) {
+	file, cleanup := tsFixtureFile(t)
+	defer cleanup()
+
+	rls := releaseStub("rls-c", 1, "default", rspb.Status_DEPLOYED)
+	if err := file.Create("rls-c.v1", rls); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	got, err := file.Get("rls-c.v1")
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if !proto.Equal(rls, got) {
+		t.Errorf("Expected %v, got %v", rls, got)
+	}
+
+	if err := file.Create("rls-c.v1", rls); !reflect.DeepEqual(err, storageerrors.ErrReleaseExists("rls-c.v1")) {
+		t.Errorf("Expected ErrReleaseExists, got %v", err)
+	}
+	if _, err := file.Get("rls-c.v2"); !reflect.DeepEqual(err, storageerrors.ErrReleaseNotFound("rls-c.v2")) {
+		t.Errorf("Expected ErrReleaseNotFound, got %v", err)
+	}
+	for _, key := range []string{"", "../rls-c.v1", ".index.json"} {
+		if _, err := file.Get(key); !reflect.DeepEqual(err, storageerrors.ErrInvalidKey(key)) {
+			t.Errorf("Expected ErrInvalidKey for %q, got %v", key, err)
+		}
+	}
+}
+
+func TestFileListQuery(t *testing.T) {
+	file, cleanup := tsFixtureFile(t)
+	defer cleanup()
+
+	ls, err := file.List(func(rls *rspb.Release) bool { return rls.Name == "rls-a" })
+	if err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+	if len(ls) != 2 {
+		t.Errorf("Expected 2 releases, got %d", len(ls))
+	}
+
+	ls, err = file.Query(map[string]string{"STATUS": "DEPLOYED", "OWNER": "TILLER"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	if len(ls) != 2 {
+		t.Errorf("Expected 2 releases, got %d", len(ls))
+	}
+
+	if _, err := file.Query(map[string]string{"NAME": "rls-z"}); err == nil {
+		t.Errorf("Expected an error querying a missing release")
+	}
+}
+
+func TestFileUpdate(t *testing.T) {
+	file, cleanup := tsFixtureFile(t)
+	defer cleanup()
+
+	rls := releaseStub("rls-a", 2, "default", rspb.Status_SUPERSEDED)
+	if err := file.Update("rls-a.v2", rls); err != nil {
+		t.Fatalf("Failed to update release: %s", err)
+	}
+	ls, err := file.Query(map[string]string{"NAME": "rls-a", "STATUS": "SUPERSEDED"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	if len(ls) != 2 {
+		t.Errorf("Expected 2 superseded releases, got %d", len(ls))
+	}
+
+	if err := file.Update("rls-z.v1", releaseStub("rls-z", 1, "default", rspb.Status_DELETED)); err == nil {
+		t.Errorf("Expected an error updating a missing release")
+	}
+}
+
+func TestFileDelete(t *testing.T) {
+	file, cleanup := tsFixtureFile(t)
+	defer cleanup()
+
+	rls, err := file.Delete("rls-a.v1")
+	if err != nil {
+		t.Fatalf("Failed to delete release: %s", err)
+	}
+	if rls.Name != "rls-a" || rls.Version != 1 {
+		t.Errorf("Expected to delete rls-a.v1, deleted %s.v%d", rls.Name, rls.Version)
+	}
+	if _, err := file.Get("rls-a.v1"); err == nil {
+		t.Errorf("Expected an error getting a deleted release")
+	}
+	if _, err := os.Stat(file.releasePath("rls-a.v1")); !os.IsNotExist(err) {
+		t.Errorf("Expected the release file to be removed, got %v", err)
+	}
+	if _, err := file.Delete("rls-a.v1"); err == nil {
+		t.Errorf("Expected an error deleting a missing release")
+	}
+}
+
+func TestFilePersistence(t *testing.T) {
+	file, cleanup := tsFixtureFile(t)
+	defer cleanup()
+
+	// releases survive a restart, even without the index
+	if err := os.Remove(filepath.Join(file.dir, fileIndexName)); err != nil {
+		t.Fatalf("Failed to remove index: %s", err)
+	}
+	reopened, err := NewFile(file.dir)
+	if err != nil {
+		t.Fatalf("Failed to reopen directory: %s", err)
+	}
+	ls, err := reopened.Query(map[string]string{"NAME": "rls-b"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	if len(ls) != 2 {
+		t.Errorf("Expected 2 releases, got %d", len(ls))
+	}
+}
+
+func TestFileLockFailure(t *testing.T) {
+	file, cleanup := tsFixtureFile(t)
+	defer cleanup()
+
+	// a directory in place of the lock file cannot be locked
+	lockPath := filepath.Join(file.dir, fileLockName)
+	if err := os.RemoveAll(lockPath); err != nil {
+		t.Fatalf("Failed to remove lock file: %s", err)
+	}
+	if err := os.Mkdir(lockPath, 0700); err != nil {
+		t.Fatalf("Failed to create lock directory: %s", err)
+	}
+
+	if _, err := file.Get("rls-a.v1"); err != nil {
+		t.Errorf("Expected reads to go on without the lock, got %s", err)
+	}
+	if err := file.Create("rls-c.v1", releaseStub("rls-c", 1, "default", rspb.Status_DEPLOYED)); err == nil {
+		t.Errorf("Expected an error creating a release without the lock")
+	}
+	if err := file.Update("rls-a.v1", releaseStub("rls-a", 1, "default", rspb.Status_DELETED)); err == nil {
+		t.Errorf("Expected an error updating a release without the lock")
+	}
+	if _, err := file.Delete("rls-a.v1"); err == nil {
+		t.Errorf("Expected an error deleting a release without the lock")
+	}
+	if _, err := file.Get("rls-c.v1"); err == nil {
+		t.Errorf("Expected the release not to be created")
+	}
+}
+
+func TestFileConcurrentCreate(t *testing.T) {
+	file, cleanup := newTestFixtureFile(t)
+	defer cleanup()
+
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 1; i <= n; i++ {
+		wg.Add(1)
+		go func(v int32) {
+			defer wg.Done()
+			if err := file.Create(testKey("rls-a", v), releaseStub("rls-a", v, "default", rspb.Status_SUPERSEDED)); err != nil {
+				t.Errorf("Failed to create release: %s", err)
+			}
+		}(int32(i))
+	}
+	wg.Wait()
+
+	ls, err := file.Query(map[string]string{"NAME": "rls-a"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	if len(ls) != n {
+		t.Errorf("Expected %d releases, got %d", n, len(ls))
+	}
+}
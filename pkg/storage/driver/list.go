@@ -0,0 +1,172 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"fmt"
+	"regexp"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+// ListSortBy is the key releases are sorted by when listed.
+type ListSortBy int
+
+const (
+	// ListUnsorted leaves releases in the order they are stored in.
+	ListUnsorted ListSortBy = iota
+	// ListSortByName sorts releases by name.
+	ListSortByName
+	// ListSortByLastReleased sorts releases by the time they were last
+	// deployed.
+	ListSortByLastReleased
+	// ListSortByChartName sorts releases by the name of their chart.
+	ListSortByChartName
+)
+
+// ListOptions selects, sorts and paginates the releases listed.
+type ListOptions struct {
+	// StatusCodes restricts the releases listed to the ones with one of the
+	// status codes. Every release is listed if empty.
+	StatusCodes []rspb.Status_Code
+	// Namespace restricts the releases listed to the ones in the namespace.
+	Namespace string
+	// Filter is a regular expression the names of the releases listed must
+	// match.
+	Filter string
+
+	SortBy   ListSortBy
+	SortDesc bool
+
+	// Offset is the name of the first release of the page, e.g. the Next
+	// release of the previous page. The page starts at the first release if
+	// empty.
+	Offset string
+	// Limit is the maximum number of releases of the page. Values of 0 or
+	// less mean no limit.
+	Limit int
+}
+
+// ListPage is a page of the releases matching some ListOptions.
+type ListPage struct {
+	Releases []*rspb.Release
+	// Total is the number of releases matching the options, before the page
+	// is cut at the offset and limit.
+	Total int
+	// Next is the name of the release following the page, if any.
+	Next string
+}
+
+// Lister is the interface implemented by drivers able to select, sort and
+// paginate the releases they store without decoding all of them.
+//
+// ListPage returns the page of the releases matching opts.
+type Lister interface {
+	ListPage(opts ListOptions) (*ListPage, error)
+}
+
+// FilterPage selects, sorts and paginates rels in memory according to opts.
+func FilterPage(rels []*rspb.Release, opts ListOptions) (*ListPage, error) {
+	var preg *regexp.Regexp
+	if opts.Filter != "" {
+		var err error
+		if preg, err = regexp.Compile(opts.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []*rspb.Release
+	for _, rls := range rels {
+		if !hasStatus(rls, opts.StatusCodes) {
+			continue
+		}
+		if opts.Namespace != "" && rls.Namespace != opts.Namespace {
+			continue
+		}
+		if preg != nil && !preg.MatchString(rls.Name) {
+			continue
+		}
+		matches = append(matches, rls)
+	}
+
+	switch opts.SortBy {
+	case ListSortByName:
+		relutil.SortByName(matches)
+	case ListSortByLastReleased:
+		relutil.SortByDate(matches)
+	case ListSortByChartName:
+		relutil.SortByChartName(matches)
+	}
+	if opts.SortDesc {
+		for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+			matches[i], matches[j] = matches[j], matches[i]
+		}
+	}
+
+	start, end, err := pageBounds(len(matches), func(i int) string { return matches[i].Name }, opts.Offset, opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+	page := &ListPage{
+		Releases: matches[start:end],
+		Total:    len(matches),
+	}
+	if end < len(matches) {
+		page.Next = matches[end].Name
+	}
+	return page, nil
+}
+
+// pageBounds returns the bounds of the page of n sorted releases starting at
+// the last release named offset, or at the first release if offset is empty,
+// and holding at most limit releases. name returns the name of the release at
+// an index.
+func pageBounds(n int, name func(int) string, offset string, limit int) (int, int, error) {
+	start := 0
+	if offset != "" {
+		start = -1
+		for i := 0; i < n; i++ {
+			if name(i) == offset {
+				start = i
+			}
+		}
+		if start == -1 {
+			return 0, 0, fmt.Errorf("offset %q not found", offset)
+		}
+	}
+
+	end := n
+	if limit > 0 && start+limit < n {
+		end = start + limit
+	}
+	return start, end, nil
+}
+
+// hasStatus reports whether the status of rls is one of codes, or codes is
+// empty.
+func hasStatus(rls *rspb.Release, codes []rspb.Status_Code) bool {
+	if len(codes) == 0 {
+		return true
+	}
+	for _, code := range codes {
+		if rls.GetInfo().GetStatus().GetCode() == code {
+			return true
+		}
+	}
+	return false
+}
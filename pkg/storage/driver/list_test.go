@@ -0,0 +1,153 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func listStub(name, namespace, chartName string, deployed int64, code rspb.Status_Code) *rspb.Release {
+	rls := releaseStub(name, 1, namespace, code)
+	rls.Chart = &chart.Chart{Metadata: &chart.Metadata{Name: chartName, Version: "0.1.0", AppVersion: "1.0"}}
+	rls.Info.LastDeployed = &timestamp.Timestamp{Seconds: deployed}
+	return rls
+}
+
+var listFixture = []*rspb.Release{
+	listStub("a-one", "default", "zeta", 30, rspb.Status_DEPLOYED),
+	listStub("b-two", "default", "alpha", 10, rspb.Status_DEPLOYED),
+	listStub("c-three", "other", "mid", 20, rspb.Status_DEPLOYED),
+	listStub("d-four", "default", "beta", 40, rspb.Status_DELETED),
+	listStub("e-five", "default", "gamma", 50, rspb.Status_FAILED),
+}
+
+var listTests = []struct {
+	desc  string
+	opts  ListOptions
+	names []string
+	total int
+	next  string
+}{
+	{
+		"deployed releases by name",
+		ListOptions{StatusCodes: []rspb.Status_Code{rspb.Status_DEPLOYED}, SortBy: ListSortByName},
+		[]string{"a-one", "b-two", "c-three"}, 3, "",
+	},
+	{
+		"deployed releases of a namespace",
+		ListOptions{StatusCodes: []rspb.Status_Code{rspb.Status_DEPLOYED}, Namespace: "default", SortBy: ListSortByName},
+		[]string{"a-one", "b-two"}, 2, "",
+	},
+	{
+		"releases matching a filter",
+		ListOptions{Filter: "^[a-c]-t", SortBy: ListSortByName},
+		[]string{"b-two", "c-three"}, 2, "",
+	},
+	{
+		"releases matching a filter with a word boundary",
+		ListOptions{Filter: `^c\b`, SortBy: ListSortByName},
+		[]string{"c-three"}, 1, "",
+	},
+	{
+		"releases matching a filter ignoring case",
+		ListOptions{Filter: `(?i)^D-F`, SortBy: ListSortByName},
+		[]string{"d-four"}, 1, "",
+	},
+	{
+		"releases by last released, descending",
+		ListOptions{SortBy: ListSortByLastReleased, SortDesc: true},
+		[]string{"e-five", "d-four", "a-one", "c-three", "b-two"}, 5, "",
+	},
+	{
+		"releases by chart name",
+		ListOptions{SortBy: ListSortByChartName},
+		[]string{"b-two", "d-four", "e-five", "c-three", "a-one"}, 5, "",
+	},
+	{
+		"first page",
+		ListOptions{SortBy: ListSortByName, Limit: 2},
+		[]string{"a-one", "b-two"}, 5, "c-three",
+	},
+	{
+		"page at an offset",
+		ListOptions{SortBy: ListSortByName, Offset: "c-three", Limit: 2},
+		[]string{"c-three", "d-four"}, 5, "e-five",
+	},
+	{
+		"page at an offset by last released, descending",
+		ListOptions{SortBy: ListSortByLastReleased, SortDesc: true, Offset: "a-one", Limit: 1},
+		[]string{"a-one"}, 5, "c-three",
+	},
+	{
+		"page at an offset by chart name",
+		ListOptions{SortBy: ListSortByChartName, Offset: "e-five", Limit: 2},
+		[]string{"e-five", "c-three"}, 5, "a-one",
+	},
+	{
+		"page of the releases matching a prefix",
+		ListOptions{Filter: "^d-", StatusCodes: []rspb.Status_Code{rspb.Status_DELETED}, Limit: 1},
+		[]string{"d-four"}, 1, "",
+	},
+}
+
+func testListPage(t *testing.T, list func(ListOptions) (*ListPage, error)) {
+	for _, tt := range listTests {
+		page, err := list(tt.opts)
+		if err != nil {
+			t.Errorf("%s: failed to list: %s", tt.desc, err)
+			continue
+		}
+		var names []string
+		for _, rls := range page.Releases {
+			names = append(names, rls.Name)
+		}
+		if !reflect.DeepEqual(names, tt.names) {
+			t.Errorf("%s: expected %v, got %v", tt.desc, tt.names, names)
+		}
+		if page.Total != tt.total || page.Next != tt.next {
+			t.Errorf("%s: expected total %d and next %q, got %d and %q", tt.desc, tt.total, tt.next, page.Total, page.Next)
+		}
+	}
+
+	if _, err := list(ListOptions{Offset: "missing"}); err == nil {
+		t.Errorf("Expected an error listing at a missing offset")
+	}
+	if _, err := list(ListOptions{Filter: "("}); err == nil {
+		t.Errorf("Expected an error listing with an invalid filter")
+	}
+}
+
+func TestFilterPage(t *testing.T) {
+	testListPage(t, func(opts ListOptions) (*ListPage, error) {
+		rels := make([]*rspb.Release, len(listFixture))
+		copy(rels, listFixture)
+		return FilterPage(rels, opts)
+	})
+}
+
+func TestSQLiteListPage(t *testing.T) {
+	sqlDriver, cleanup := newTestFixtureSQLite(t, listFixture...)
+	defer cleanup()
+
+	testListPage(t, sqlDriver.ListPage)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"strconv"
+	"time"
+)
+
+// Locker is the interface implemented by drivers able to lock a release
+// against concurrent operations from every Tiller sharing the storage.
+//
+// Lock acquires, or renews, the lock of the named release on behalf of
+// holder until ttl elapses. It returns ErrReleaseLocked if the lock is
+// held by another holder and has not expired.
+//
+// Unlock releases the lock of the named release if it is held by holder.
+type Locker interface {
+	Lock(name, holder string, ttl time.Duration) error
+	Unlock(name, holder string) error
+}
+
+// The lock of a release stored in ConfigMaps or Secrets is an object named
+// "<name>.lock", labelled with "LOCK_OF" set to the release name. Its data
+// records the holder of the lock and the time at which the lock expires, in
+// seconds since the epoch. Like chunk objects, lock objects carry neither the
+// "OWNER" nor the "NAME" label so they never match release selectors.
+//
+// Lock objects are only ever updated at the resourceVersion they were read
+// at, so two Tillers racing for an expired lock cannot both acquire it.
+const (
+	lockOfLabel   = "LOCK_OF"
+	lockHolderKey = "holder"
+	lockExpiryKey = "expires"
+)
+
+// lockName returns the name of the object holding the lock of the named
+// release.
+func lockName(name string) string {
+	return name + ".lock"
+}
+
+// newLockLabels returns the labels of the object holding the lock of the
+// named release.
+func newLockLabels(name string) labels {
+	var lbs labels
+
+	lbs.init()
+	lbs.set(lockOfLabel, name)
+	return lbs
+}
+
+// newLockData returns the data of a lock held by holder until expiry.
+func newLockData(holder string, expiry time.Time) map[string]string {
+	return map[string]string{
+		lockHolderKey: holder,
+		lockExpiryKey: strconv.FormatInt(expiry.Unix(), 10),
+	}
+}
+
+// lockHeld reports whether the lock described by data is held by a holder
+// other than holder at time now.
+func lockHeld(data map[string]string, holder string, now time.Time) bool {
+	if data[lockHolderKey] == holder {
+		return false
+	}
+	expiry, err := strconv.ParseInt(data[lockExpiryKey], 10, 64)
+	if err != nil {
+		// a lock with an unreadable expiry would never expire, so it is
+		// taken over rather than blocking the release forever
+		return false
+	}
+	return now.Unix() < expiry
+}
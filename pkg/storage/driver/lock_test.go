@@ -0,0 +1,117 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
+)
+
+func testLocker(t *testing.T, l Locker) {
+	const name = "smug-pigeon"
+	locked := storageerrors.ErrReleaseLocked(name)
+
+	if err := l.Lock(name, "tiller-a", time.Minute); err != nil {
+		t.Fatalf("Failed to lock %s: %s", name, err)
+	}
+	// the holder can renew its lock
+	if err := l.Lock(name, "tiller-a", time.Minute); err != nil {
+		t.Fatalf("Failed to renew lock of %s: %s", name, err)
+	}
+	// another holder cannot take it
+	if err := l.Lock(name, "tiller-b", time.Minute); !reflect.DeepEqual(err, locked) {
+		t.Fatalf("Expected %q, got %v", locked, err)
+	}
+	// releasing a lock held by another holder is a no-op
+	if err := l.Unlock(name, "tiller-b"); err != nil {
+		t.Fatalf("Failed to unlock %s: %s", name, err)
+	}
+	if err := l.Lock(name, "tiller-b", time.Minute); !reflect.DeepEqual(err, locked) {
+		t.Fatalf("Expected %q, got %v", locked, err)
+	}
+	// once released, the lock can be taken by another holder
+	if err := l.Unlock(name, "tiller-a"); err != nil {
+		t.Fatalf("Failed to unlock %s: %s", name, err)
+	}
+	if err := l.Lock(name, "tiller-b", -time.Minute); err != nil {
+		t.Fatalf("Failed to lock %s: %s", name, err)
+	}
+	// and expired locks can be taken over
+	if err := l.Lock(name, "tiller-a", time.Minute); err != nil {
+		t.Fatalf("Failed to take over expired lock of %s: %s", name, err)
+	}
+	if err := l.Lock(name, "tiller-b", time.Minute); !reflect.DeepEqual(err, locked) {
+		t.Fatalf("Expected %q, got %v", locked, err)
+	}
+}
+
+func TestConfigMapLock(t *testing.T) {
+	cfgmaps := newTestFixtureCfgMaps(t)
+	testLocker(t, cfgmaps)
+
+	// lock objects are not mistaken for releases
+	rels, err := cfgmaps.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+	if len(rels) != 0 {
+		t.Errorf("Expected no release, got %d", len(rels))
+	}
+}
+
+func TestSecretLock(t *testing.T) {
+	testLocker(t, newTestFixtureSecrets(t))
+}
+
+func TestSQLiteLock(t *testing.T) {
+	sqlDriver, cleanup := newTestFixtureSQLite(t)
+	defer cleanup()
+
+	testLocker(t, sqlDriver)
+}
+
+func TestFileLock(t *testing.T) {
+	file, cleanup := newTestFixtureFile(t)
+	defer cleanup()
+
+	testLocker(t, file)
+}
+
+func TestLockHeld(t *testing.T) {
+	now := time.Unix(1000, 0)
+	for _, tt := range []struct {
+		name   string
+		data   map[string]string
+		holder string
+		held   bool
+	}{
+		{"by another holder", newLockData("tiller-a", now.Add(time.Minute)), "tiller-b", true},
+		{"by the holder", newLockData("tiller-a", now.Add(time.Minute)), "tiller-a", false},
+		{"expired", newLockData("tiller-a", now.Add(-time.Minute)), "tiller-b", false},
+		// a corrupt expiry would never expire, so the lock is taken over
+		{"corrupt expiry", map[string]string{lockHolderKey: "tiller-a", lockExpiryKey: "soon"}, "tiller-b", false},
+		{"missing expiry", map[string]string{lockHolderKey: "tiller-a"}, "tiller-b", false},
+	} {
+		if held := lockHeld(tt.data, tt.holder, now); held != tt.held {
+			t.Errorf("%s: expected held to be %t, got %t", tt.name, tt.held, held)
+		}
+	}
+}
@@ -17,15 +17,24 @@ limitations under the License.
 package driver // import "k8s.io/helm/pkg/storage/driver"
 
 import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
-	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	"k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kblabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
@@ -92,6 +101,11 @@ type MockConfigMapsInterface struct {
 	corev1.ConfigMapInterface
 
 	objects map[string]*v1.ConfigMap
+	version int
+	// mu guards the watch, which is set up by the goroutine watching.
+	mu       sync.Mutex
+	watcher  *watch.FakeWatcher
+	selector kblabels.Selector
 }
 
 // Init initializes the MockConfigMapsInterface with the set of releases.
@@ -105,6 +119,8 @@ func (mock *MockConfigMapsInterface) Init(t *testing.T, releases ...*rspb.Releas
 		if err != nil {
 			t.Fatalf("Failed to create configmap: %s", err)
 		}
+		mock.version++
+		cfgmap.ResourceVersion = fmt.Sprint(mock.version)
 		mock.objects[objkey] = cfgmap
 	}
 }
@@ -115,14 +131,21 @@ func (mock *MockConfigMapsInterface) Get(name string, options metav1.GetOptions)
 	if !ok {
 		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "tests"}, name)
 	}
-	return object, nil
+	copied := *object
+	return &copied, nil
 }
 
-// List returns the a of ConfigMaps.
+// List returns the a of ConfigMaps matching the label selector.
 func (mock *MockConfigMapsInterface) List(opts metav1.ListOptions) (*v1.ConfigMapList, error) {
+	sel, err := kblabels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
 	var list v1.ConfigMapList
 	for _, cfgmap := range mock.objects {
-		list.Items = append(list.Items, *cfgmap)
+		if sel.Matches(kblabels.Set(cfgmap.Labels)) {
+			list.Items = append(list.Items, *cfgmap)
+		}
 	}
 	return &list, nil
 }
@@ -133,29 +156,76 @@ func (mock *MockConfigMapsInterface) Create(cfgmap *v1.ConfigMap) (*v1.ConfigMap
 	if object, ok := mock.objects[name]; ok {
 		return object, apierrors.NewAlreadyExists(schema.GroupResource{Resource: "tests"}, name)
 	}
+	mock.version++
+	cfgmap.ResourceVersion = fmt.Sprint(mock.version)
 	mock.objects[name] = cfgmap
+	mock.notify(watch.Added, cfgmap)
 	return cfgmap, nil
 }
 
-// Update updates a ConfigMap.
+// Update updates a ConfigMap. Updates made at a stale resourceVersion are rejected.
 func (mock *MockConfigMapsInterface) Update(cfgmap *v1.ConfigMap) (*v1.ConfigMap, error) {
 	name := cfgmap.ObjectMeta.Name
-	if _, ok := mock.objects[name]; !ok {
+	object, ok := mock.objects[name]
+	if !ok {
 		return nil, apierrors.NewNotFound(v1.Resource("tests"), name)
 	}
+	if cfgmap.ResourceVersion != "" && cfgmap.ResourceVersion != object.ResourceVersion {
+		return nil, apierrors.NewConflict(schema.GroupResource{Resource: "tests"}, name, fmt.Errorf("stale resourceVersion"))
+	}
+	mock.version++
+	cfgmap.ResourceVersion = fmt.Sprint(mock.version)
 	mock.objects[name] = cfgmap
+	mock.notify(watch.Modified, cfgmap)
 	return cfgmap, nil
 }
 
 // Delete deletes a ConfigMap by name.
 func (mock *MockConfigMapsInterface) Delete(name string, opts *metav1.DeleteOptions) error {
-	if _, ok := mock.objects[name]; !ok {
+	object, ok := mock.objects[name]
+	if !ok {
 		return apierrors.NewNotFound(v1.Resource("tests"), name)
 	}
+	if p := opts.Preconditions; p != nil && p.ResourceVersion != nil && *p.ResourceVersion != object.ResourceVersion {
+		return apierrors.NewConflict(schema.GroupResource{Resource: "tests"}, name, fmt.Errorf("stale resourceVersion"))
+	}
 	delete(mock.objects, name)
+	mock.notify(watch.Deleted, object)
 	return nil
 }
 
+// Watch returns a watch receiving an event for every ConfigMap matching the label
+// selector created, updated or deleted from now on.
+func (mock *MockConfigMapsInterface) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	sel, err := kblabels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.watcher = watch.NewFakeWithChanSize(100, false)
+	mock.selector = sel
+	return mock.watcher, nil
+}
+
+// notify sends a copy of cfgmap to the watch, if any.
+func (mock *MockConfigMapsInterface) notify(t watch.EventType, cfgmap *v1.ConfigMap) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if mock.watcher == nil || !mock.selector.Matches(kblabels.Set(cfgmap.Labels)) {
+		return
+	}
+	copied := *cfgmap
+	mock.watcher.Action(t, &copied)
+}
+
+// watching reports whether a watch has been set up.
+func (mock *MockConfigMapsInterface) watching() bool {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return mock.watcher != nil
+}
+
 // newTestFixture initializes a MockSecretsInterface.
 // Secrets are created for each release provided.
 func newTestFixtureSecrets(t *testing.T, releases ...*rspb.Release) *Secrets {
@@ -170,6 +240,11 @@ type MockSecretsInterface struct {
 	corev1.SecretInterface
 
 	objects map[string]*v1.Secret
+	version int
+	// mu guards the watch, which is set up by the goroutine watching.
+	mu       sync.Mutex
+	watcher  *watch.FakeWatcher
+	selector kblabels.Selector
 }
 
 // Init initializes the MockSecretsInterface with the set of releases.
@@ -183,6 +258,8 @@ func (mock *MockSecretsInterface) Init(t *testing.T, releases ...*rspb.Release)
 		if err != nil {
 			t.Fatalf("Failed to create secret: %s", err)
 		}
+		mock.version++
+		secret.ResourceVersion = fmt.Sprint(mock.version)
 		mock.objects[objkey] = secret
 	}
 }
@@ -193,14 +270,21 @@ func (mock *MockSecretsInterface) Get(name string, options metav1.GetOptions) (*
 	if !ok {
 		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "tests"}, name)
 	}
-	return object, nil
+	copied := *object
+	return &copied, nil
 }
 
-// List returns the a of Secret.
+// List returns the a of Secret matching the label selector.
 func (mock *MockSecretsInterface) List(opts metav1.ListOptions) (*v1.SecretList, error) {
+	sel, err := kblabels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
 	var list v1.SecretList
 	for _, secret := range mock.objects {
-		list.Items = append(list.Items, *secret)
+		if sel.Matches(kblabels.Set(secret.Labels)) {
+			list.Items = append(list.Items, *secret)
+		}
 	}
 	return &list, nil
 }
@@ -211,39 +295,207 @@ func (mock *MockSecretsInterface) Create(secret *v1.Secret) (*v1.Secret, error)
 	if object, ok := mock.objects[name]; ok {
 		return object, apierrors.NewAlreadyExists(schema.GroupResource{Resource: "tests"}, name)
 	}
+	mock.version++
+	secret.ResourceVersion = fmt.Sprint(mock.version)
 	mock.objects[name] = secret
+	mock.notify(watch.Added, secret)
 	return secret, nil
 }
 
-// Update updates a Secret.
+// Update updates a Secret. Updates made at a stale resourceVersion are rejected.
 func (mock *MockSecretsInterface) Update(secret *v1.Secret) (*v1.Secret, error) {
 	name := secret.ObjectMeta.Name
-	if _, ok := mock.objects[name]; !ok {
+	object, ok := mock.objects[name]
+	if !ok {
 		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "tests"}, name)
 	}
+	if secret.ResourceVersion != "" && secret.ResourceVersion != object.ResourceVersion {
+		return nil, apierrors.NewConflict(schema.GroupResource{Resource: "tests"}, name, fmt.Errorf("stale resourceVersion"))
+	}
+	mock.version++
+	secret.ResourceVersion = fmt.Sprint(mock.version)
 	mock.objects[name] = secret
+	mock.notify(watch.Modified, secret)
 	return secret, nil
 }
 
 // Delete deletes a Secret by name.
 func (mock *MockSecretsInterface) Delete(name string, opts *metav1.DeleteOptions) error {
-	if _, ok := mock.objects[name]; !ok {
+	object, ok := mock.objects[name]
+	if !ok {
 		return apierrors.NewNotFound(schema.GroupResource{Resource: "tests"}, name)
 	}
+	if p := opts.Preconditions; p != nil && p.ResourceVersion != nil && *p.ResourceVersion != object.ResourceVersion {
+		return apierrors.NewConflict(schema.GroupResource{Resource: "tests"}, name, fmt.Errorf("stale resourceVersion"))
+	}
 	delete(mock.objects, name)
+	mock.notify(watch.Deleted, object)
 	return nil
 }
 
-// newTestFixtureSQL mocks the SQL database (for testing purposes)
-func newTestFixtureSQL(t *testing.T, releases ...*rspb.Release) (*SQL, sqlmock.Sqlmock) {
-	sqlDB, mock, err := sqlmock.New()
+// Watch returns a watch receiving an event for every Secret matching the label
+// selector created, updated or deleted from now on.
+func (mock *MockSecretsInterface) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	sel, err := kblabels.Parse(opts.LabelSelector)
 	if err != nil {
-		t.Fatalf("error when opening stub database connection: %v", err)
+		return nil, err
+	}
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.watcher = watch.NewFakeWithChanSize(100, false)
+	mock.selector = sel
+	return mock.watcher, nil
+}
+
+// notify sends a copy of secret to the watch, if any.
+func (mock *MockSecretsInterface) notify(t watch.EventType, secret *v1.Secret) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if mock.watcher == nil || !mock.selector.Matches(kblabels.Set(secret.Labels)) {
+		return
 	}
+	copied := *secret
+	mock.watcher.Action(t, &copied)
+}
 
-	sqlxDB := sqlx.NewDb(sqlDB, "sqlmock")
+// newTestFixtureSQLRecorder initializes a SQL driver of the given dialect
+// whose database records the statements it receives instead of running them,
+// so that the queries of the dialects without an embedded database can be
+// checked.
+func newTestFixtureSQLRecorder(dialect string) (*SQL, *sqlRecorder) {
+	recorder := &sqlRecorder{}
 	return &SQL{
-		db:  sqlxDB,
-		Log: func(_ string, _ ...interface{}) {},
-	}, mock
+		db:      sqlx.NewDb(sql.OpenDB(recorder), dialect),
+		dialect: dialect,
+		Log:     func(_ string, _ ...interface{}) {},
+	}, recorder
+}
+
+// sqlRecorder is a database/sql connector recording the statements run on its
+// connections. Queries return the rows answered by answer, if set, or no rows,
+// and other statements affect one row.
+type sqlRecorder struct {
+	mu         sync.Mutex
+	statements []string
+	answer     func(query string) (columns []string, rows [][]sqldriver.Value)
+}
+
+func (r *sqlRecorder) Connect(context.Context) (sqldriver.Conn, error) { return r, nil }
+func (r *sqlRecorder) Driver() sqldriver.Driver                        { return r }
+func (r *sqlRecorder) Open(string) (sqldriver.Conn, error)             { return r, nil }
+func (r *sqlRecorder) Close() error                                    { return nil }
+func (r *sqlRecorder) Begin() (sqldriver.Tx, error)                    { return r, nil }
+func (r *sqlRecorder) Commit() error                                   { return nil }
+func (r *sqlRecorder) Rollback() error                                 { return nil }
+
+func (r *sqlRecorder) Prepare(query string) (sqldriver.Stmt, error) {
+	return &sqlRecorderStmt{recorder: r, query: query}, nil
+}
+
+func (r *sqlRecorder) record(query string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, query)
+}
+
+// Statements returns the statements recorded so far and forgets them.
+func (r *sqlRecorder) Statements() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	statements := r.statements
+	r.statements = nil
+	return statements
+}
+
+type sqlRecorderStmt struct {
+	recorder *sqlRecorder
+	query    string
+}
+
+func (s *sqlRecorderStmt) Close() error  { return nil }
+func (s *sqlRecorderStmt) NumInput() int { return -1 }
+
+func (s *sqlRecorderStmt) Exec([]sqldriver.Value) (sqldriver.Result, error) {
+	s.recorder.record(s.query)
+	return sqldriver.RowsAffected(1), nil
+}
+
+func (s *sqlRecorderStmt) Query([]sqldriver.Value) (sqldriver.Rows, error) {
+	s.recorder.record(s.query)
+	rows := &sqlRecorderRows{}
+	if s.recorder.answer != nil {
+		rows.columns, rows.values = s.recorder.answer(s.query)
+	}
+	return rows, nil
+}
+
+type sqlRecorderRows struct {
+	columns []string
+	values  [][]sqldriver.Value
+}
+
+func (r *sqlRecorderRows) Columns() []string { return r.columns }
+func (r *sqlRecorderRows) Close() error      { return nil }
+
+func (r *sqlRecorderRows) Next(dest []sqldriver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// newTestFixtureSQLite initializes a SQL driver backed by an SQLite database
+// file in a temporary directory. The returned func closes the database and
+// removes the directory. The test is skipped in builds without cgo.
+func newTestFixtureSQLite(t *testing.T, releases ...*rspb.Release) (*SQL, func()) {
+	if !sqliteSupported {
+		t.Skip("the sqlite3 dialect requires cgo")
+	}
+	dir, err := ioutil.TempDir("", "helm-sql-")
+	if err != nil {
+		t.Fatalf("error when creating temporary directory: %v", err)
+	}
+
+	sqlDriver, err := NewSQL(sqliteDialect, filepath.Join(dir, "releases.db"), t.Logf)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("error when opening sqlite database: %v", err)
+	}
+	cleanup := func() {
+		sqlDriver.db.Close()
+		os.RemoveAll(dir)
+	}
+
+	for _, rls := range releases {
+		if err := sqlDriver.Create(testKey(rls.Name, rls.Version), rls); err != nil {
+			cleanup()
+			t.Fatalf("Test setup failed to create: %s\n", err)
+		}
+	}
+	return sqlDriver, cleanup
+}
+
+// newTestFixtureFile initializes a File driver storing releases in a
+// temporary directory. The returned func removes the directory.
+func newTestFixtureFile(t *testing.T, releases ...*rspb.Release) (*File, func()) {
+	dir, err := ioutil.TempDir("", "helm-file-")
+	if err != nil {
+		t.Fatalf("error when creating temporary directory: %v", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	file, err := NewFile(dir)
+	if err != nil {
+		cleanup()
+		t.Fatalf("error when initializing file driver: %v", err)
+	}
+	for _, rls := range releases {
+		if err := file.Create(testKey(rls.Name, rls.Version), rls); err != nil {
+			cleanup()
+			t.Fatalf("Test setup failed to create: %s\n", err)
+		}
+	}
+	return file, cleanup
 }
@@ -0,0 +1,140 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"time"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+var _ Driver = (*Observed)(nil)
+var _ Locker = (*Observed)(nil)
+var _ Lister = (*Observed)(nil)
+
+// ObserveFunc is called with the name, key and duration of the calls made to
+// an Observed driver. The key is the key of the release for get, create,
+// update and delete, the name of the release for lock, unlock and queries by
+// name, and empty otherwise.
+type ObserveFunc func(op, key string, elapsed time.Duration)
+
+// Observed is a driver reporting the duration of every call made to the
+// driver it wraps, e.g. to export storage latencies as metrics.
+type Observed struct {
+	driver  Driver
+	observe ObserveFunc
+}
+
+// observedWatcher is an Observed driver wrapping a Watcher.
+type observedWatcher struct {
+	*Observed
+	watcher Watcher
+}
+
+// NewObserved returns a driver calling observe with every call made to d. The
+// returned driver is a Watcher if d is.
+func NewObserved(d Driver, observe ObserveFunc) Driver {
+	o := &Observed{driver: d, observe: observe}
+	if w, ok := d.(Watcher); ok {
+		return &observedWatcher{Observed: o, watcher: w}
+	}
+	return o
+}
+
+func (o *Observed) since(op, key string, start time.Time) {
+	o.observe(op, key, time.Since(start))
+}
+
+// Name returns the name of the wrapped driver.
+func (o *Observed) Name() string {
+	return o.driver.Name()
+}
+
+// Get returns the release named by key.
+func (o *Observed) Get(key string) (*rspb.Release, error) {
+	defer o.since("get", key, time.Now())
+	return o.driver.Get(key)
+}
+
+// List returns the releases matching filter.
+func (o *Observed) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	defer o.since("list", "", time.Now())
+	return o.driver.List(filter)
+}
+
+// Query returns the releases matching keyvals.
+func (o *Observed) Query(keyvals map[string]string) ([]*rspb.Release, error) {
+	defer o.since("query", keyvals["NAME"], time.Now())
+	return o.driver.Query(keyvals)
+}
+
+// Create stores rls under key.
+func (o *Observed) Create(key string, rls *rspb.Release) error {
+	defer o.since("create", key, time.Now())
+	return o.driver.Create(key, rls)
+}
+
+// Update updates the release stored under key.
+func (o *Observed) Update(key string, rls *rspb.Release) error {
+	defer o.since("update", key, time.Now())
+	return o.driver.Update(key, rls)
+}
+
+// Delete deletes the release stored under key.
+func (o *Observed) Delete(key string) (*rspb.Release, error) {
+	defer o.since("delete", key, time.Now())
+	return o.driver.Delete(key)
+}
+
+// ListPage returns the page of the releases matching opts, listing every
+// release and filtering them in memory if the wrapped driver is not a Lister.
+func (o *Observed) ListPage(opts ListOptions) (*ListPage, error) {
+	defer o.since("list_page", "", time.Now())
+	if lister, ok := o.driver.(Lister); ok {
+		return lister.ListPage(opts)
+	}
+	rels, err := o.driver.List(func(_ *rspb.Release) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	return FilterPage(rels, opts)
+}
+
+// Lock locks the named release with the wrapped driver, if it is a Locker.
+func (o *Observed) Lock(name, holder string, ttl time.Duration) error {
+	if locker, ok := o.driver.(Locker); ok {
+		defer o.since("lock", name, time.Now())
+		return locker.Lock(name, holder, ttl)
+	}
+	return nil
+}
+
+// Unlock unlocks the named release with the wrapped driver, if it is a
+// Locker.
+func (o *Observed) Unlock(name, holder string) error {
+	if locker, ok := o.driver.(Locker); ok {
+		defer o.since("unlock", name, time.Now())
+		return locker.Unlock(name, holder)
+	}
+	return nil
+}
+
+// Watch watches the wrapped driver. Watches are not observed, as they last
+// until stopped.
+func (o *observedWatcher) Watch(stop <-chan struct{}) (<-chan ReleaseEvent, error) {
+	return o.watcher.Watch(stop)
+}
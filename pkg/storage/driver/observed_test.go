@@ -0,0 +1,78 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestObserved(t *testing.T) {
+	var ops []string
+	observe := func(op, key string, elapsed time.Duration) {
+		if elapsed < 0 {
+			t.Errorf("Unexpected duration %s of %s", elapsed, op)
+		}
+		ops = append(ops, op+" "+key)
+	}
+
+	o := NewObserved(NewMemory(), observe)
+	if _, ok := o.(Watcher); ok {
+		t.Error("Expected a driver wrapping memory not to be a Watcher")
+	}
+	if o.Name() != MemoryDriverName {
+		t.Errorf("Expected the name of the wrapped driver, got %q", o.Name())
+	}
+
+	rls := releaseStub("angry-bird", 1, "default", rspb.Status_DEPLOYED)
+	key := testKey(rls.Name, rls.Version)
+	o.Create(key, rls)
+	o.Get(key)
+	o.Update(key, rls)
+	o.List(func(_ *rspb.Release) bool { return true })
+	o.Query(map[string]string{"NAME": rls.Name})
+	o.(Lister).ListPage(ListOptions{})
+	o.Delete(key)
+
+	expected := []string{
+		"create " + key,
+		"get " + key,
+		"update " + key,
+		"list ",
+		"query angry-bird",
+		"list_page ",
+		"delete " + key,
+	}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("Expected calls %v to be observed, got %v", expected, ops)
+	}
+
+	cfgmaps := newTestFixtureCfgMaps(t)
+	if _, ok := NewObserved(cfgmaps, observe).(Watcher); !ok {
+		t.Error("Expected a driver wrapping configmaps to be a Watcher")
+	}
+	ops = nil
+	locker := NewObserved(cfgmaps, observe).(Locker)
+	locker.Lock("angry-bird", "tiller-0", time.Minute)
+	locker.Unlock("angry-bird", "tiller-0")
+	if !reflect.DeepEqual(ops, []string{"lock angry-bird", "unlock angry-bird"}) {
+		t.Errorf("Expected the lock calls to be observed, got %v", ops)
+	}
+}
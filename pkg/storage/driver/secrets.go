@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kblabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
@@ -34,6 +35,8 @@ import (
 )
 
 var _ Driver = (*Secrets)(nil)
+var _ Locker = (*Secrets)(nil)
+var _ Watcher = (*Secrets)(nil)
 
 // SecretsDriverName is the string name of the driver.
 const SecretsDriverName = "Secret"
@@ -43,14 +46,20 @@ const SecretsDriverName = "Secret"
 type Secrets struct {
 	impl corev1.SecretInterface
 	Log  func(string, ...interface{})
+
+	// ChunkSize is the maximum size of the encoded release stored in a
+	// single Secret. Larger releases are split across several Secrets.
+	// Values of 0 or less disable chunking.
+	ChunkSize int
 }
 
 // NewSecrets initializes a new Secrets wrapping an implementation of
 // the kubernetes SecretsInterface.
 func NewSecrets(impl corev1.SecretInterface) *Secrets {
 	return &Secrets{
-		impl: impl,
-		Log:  func(_ string, _ ...interface{}) {},
+		impl:      impl,
+		Log:       func(_ string, _ ...interface{}) {},
+		ChunkSize: DefaultChunkSize,
 	}
 }
 
@@ -73,7 +82,7 @@ func (secrets *Secrets) Get(key string) (*rspb.Release, error) {
 		return nil, err
 	}
 	// found the secret, decode the base64 data string
-	r, err := decodeRelease(string(obj.Data["release"]))
+	r, err := secrets.decodeObject(obj)
 	if err != nil {
 		secrets.Log("get: failed to decode data %q: %s", key, err)
 		return nil, err
@@ -100,7 +109,7 @@ func (secrets *Secrets) List(filter func(*rspb.Release) bool) ([]*rspb.Release,
 	// iterate over the secrets object list
 	// and decode each release
 	for _, item := range list.Items {
-		rls, err := decodeRelease(string(item.Data["release"]))
+		rls, err := secrets.decodeObject(&item)
 		if err != nil {
 			secrets.Log("list: failed to decode release: %v: %s", item, err)
 			continue
@@ -137,7 +146,7 @@ func (secrets *Secrets) Query(labels map[string]string) ([]*rspb.Release, error)
 
 	var results []*rspb.Release
 	for _, item := range list.Items {
-		rls, err := decodeRelease(string(item.Data["release"]))
+		rls, err := secrets.decodeObject(&item)
 		if err != nil {
 			secrets.Log("query: failed to decode release: %s", err)
 			continue
@@ -162,8 +171,15 @@ func (secrets *Secrets) Create(key string, rls *rspb.Release) error {
 		secrets.Log("create: failed to encode release %q: %s", rls.Name, err)
 		return err
 	}
+	// store the chunks of a large release before the secret referencing them
+	chunks := secrets.splitObject(obj)
+	if err := secrets.createChunks(chunks); err != nil {
+		secrets.Log("create: failed to create chunks of %q: %s", key, err)
+		return err
+	}
 	// push the secret object out into the kubiverse
 	if _, err := secrets.impl.Create(obj); err != nil {
+		secrets.deleteChunks(chunks)
 		if apierrors.IsAlreadyExists(err) {
 			return storageerrors.ErrReleaseExists(rls.Name)
 		}
@@ -189,33 +205,242 @@ func (secrets *Secrets) Update(key string, rls *rspb.Release) error {
 		secrets.Log("update: failed to encode release %q: %s", rls.Name, err)
 		return err
 	}
+	// remember the chunks of the previous revision of the secret so they
+	// can be removed once the new one is in place, and the resourceVersion
+	// it was read at so that a concurrent update is detected
+	var stale []*v1.Secret
+	if old, err := secrets.impl.Get(key, metav1.GetOptions{}); err == nil {
+		stale = secrets.chunkObjects(old)
+		obj.ResourceVersion = old.ResourceVersion
+	}
+	// store the new chunks before the secret referencing them
+	chunks := secrets.splitObject(obj)
+	if err := secrets.createChunks(chunks); err != nil {
+		secrets.Log("update: failed to create chunks of %q: %s", key, err)
+		return err
+	}
 	// push the secret object out into the kubiverse
 	_, err = secrets.impl.Update(obj)
 	if err != nil {
+		secrets.deleteChunks(chunks)
+		if apierrors.IsConflict(err) {
+			return storageerrors.ErrReleaseConflict(key)
+		}
+
 		secrets.Log("update: failed to update: %s", err)
 		return err
 	}
+	secrets.deleteChunks(stale)
 	return nil
 }
 
 // Delete deletes the Secret holding the release named by key.
 func (secrets *Secrets) Delete(key string) (rls *rspb.Release, err error) {
 	// fetch the release to check existence
-	if rls, err = secrets.Get(key); err != nil {
+	obj, err := secrets.impl.Get(key, metav1.GetOptions{})
+	if err != nil {
 		if apierrors.IsNotFound(err) {
-			return nil, storageerrors.ErrReleaseExists(rls.Name)
+			return nil, storageerrors.ErrReleaseNotFound(key)
 		}
 
 		secrets.Log("delete: failed to get release %q: %s", key, err)
 		return nil, err
 	}
-	// delete the release
+	if rls, err = secrets.decodeObject(obj); err != nil {
+		secrets.Log("delete: failed to decode release %q: %s", key, err)
+		return nil, err
+	}
+	// delete the release, then the chunks it no longer references
 	if err = secrets.impl.Delete(key, &metav1.DeleteOptions{}); err != nil {
 		return rls, err
 	}
+	secrets.deleteChunks(secrets.chunkObjects(obj))
 	return rls, nil
 }
 
+// Lock acquires or renews the lock of the named release on behalf of holder.
+// The lock is held in a Secret, taken over once expired by updating it at the
+// resourceVersion it was read at.
+func (secrets *Secrets) Lock(name, holder string, ttl time.Duration) error {
+	key := lockName(name)
+	now := time.Now()
+
+	obj, err := secrets.impl.Get(key, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			secrets.Log("lock: failed to get lock of %q: %s", name, err)
+			return err
+		}
+		obj = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   key,
+				Labels: newLockLabels(name).toMap(),
+			},
+			Data: newSecretLockData(holder, now.Add(ttl)),
+		}
+		if _, err := secrets.impl.Create(obj); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return storageerrors.ErrReleaseLocked(name)
+			}
+
+			secrets.Log("lock: failed to create lock of %q: %s", name, err)
+			return err
+		}
+		return nil
+	}
+
+	if lockHeld(secretLockData(obj.Data), holder, now) {
+		return storageerrors.ErrReleaseLocked(name)
+	}
+	obj.Data = newSecretLockData(holder, now.Add(ttl))
+	if _, err := secrets.impl.Update(obj); err != nil {
+		if apierrors.IsConflict(err) {
+			return storageerrors.ErrReleaseLocked(name)
+		}
+
+		secrets.Log("lock: failed to update lock of %q: %s", name, err)
+		return err
+	}
+	return nil
+}
+
+// Unlock releases the lock of the named release if it is held by holder.
+func (secrets *Secrets) Unlock(name, holder string) error {
+	key := lockName(name)
+
+	obj, err := secrets.impl.Get(key, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if secretLockData(obj.Data)[lockHolderKey] != holder {
+		// the lock expired and was taken over
+		return nil
+	}
+	err = secrets.impl.Delete(key, &metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &obj.ResourceVersion},
+	})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
+
+// Watch sends an event for every release created, updated or deleted in the
+// Secrets watched by the driver, until stop is closed or the watch ends.
+func (secrets *Secrets) Watch(stop <-chan struct{}) (<-chan ReleaseEvent, error) {
+	lsel := kblabels.Set{"OWNER": "TILLER"}.AsSelector()
+	opts := metav1.ListOptions{LabelSelector: lsel.String()}
+
+	w, err := secrets.impl.Watch(opts)
+	if err != nil {
+		secrets.Log("watch: failed to watch: %s", err)
+		return nil, err
+	}
+	return watchReleases(w, stop, secrets.Log, func(ev watch.Event) (ReleaseEvent, error) {
+		obj, ok := ev.Object.(*v1.Secret)
+		if !ok {
+			return ReleaseEvent{}, fmt.Errorf("unexpected object %T", ev.Object)
+		}
+		if ev.Type == watch.Deleted {
+			return ReleaseEvent{Key: obj.Name}, nil
+		}
+		rls, err := secrets.decodeObject(obj)
+		if err != nil {
+			return ReleaseEvent{}, fmt.Errorf("%q: %s", obj.Name, err)
+		}
+		return ReleaseEvent{Key: obj.Name, Release: rls}, nil
+	}), nil
+}
+
+// decodeObject reassembles and decodes the release held by a Secret,
+// fetching the chunks holding the rest of its body if it has been split.
+func (secrets *Secrets) decodeObject(obj *v1.Secret) (*rspb.Release, error) {
+	n, set, err := chunkInfo(obj.Labels)
+	if err != nil {
+		return nil, err
+	}
+	if n == 1 {
+		return decodeRelease(string(obj.Data["release"]))
+	}
+
+	var data strings.Builder
+	data.Write(obj.Data["release"])
+	for i := 1; i < n; i++ {
+		chunk, err := secrets.impl.Get(chunkName(obj.Name, set, i), metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chunk %d/%d of %q: %s", i+1, n, obj.Name, err)
+		}
+		data.Write(chunk.Data["release"])
+	}
+	return decodeRelease(data.String())
+}
+
+// splitObject moves the part of the release held by obj that exceeds the
+// chunk size into new chunk Secrets, and labels obj so that the chunks can
+// be found again. The chunk Secrets are returned without being created.
+func (secrets *Secrets) splitObject(obj *v1.Secret) []*v1.Secret {
+	pieces := splitChunks(string(obj.Data["release"]), secrets.ChunkSize)
+	if len(pieces) == 1 {
+		return nil
+	}
+
+	set := newChunkSet()
+	obj.Data["release"] = []byte(pieces[0])
+	obj.Labels[chunksLabel] = strconv.Itoa(len(pieces))
+	obj.Labels[chunkSetLabel] = set
+
+	var chunks []*v1.Secret
+	for i := 1; i < len(pieces); i++ {
+		chunks = append(chunks, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   chunkName(obj.Name, set, i),
+				Labels: newChunkLabels(obj.Name, set, i).toMap(),
+			},
+			Data: map[string][]byte{"release": []byte(pieces[i])},
+		})
+	}
+	return chunks
+}
+
+// chunkObjects returns the chunk Secrets referenced by obj, holding only
+// their names.
+func (secrets *Secrets) chunkObjects(obj *v1.Secret) []*v1.Secret {
+	n, set, err := chunkInfo(obj.Labels)
+	if err != nil {
+		return nil
+	}
+	var chunks []*v1.Secret
+	for i := 1; i < n; i++ {
+		chunks = append(chunks, &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: chunkName(obj.Name, set, i)}})
+	}
+	return chunks
+}
+
+// createChunks creates the chunk Secrets. If any of them fails, the ones
+// already created are deleted.
+func (secrets *Secrets) createChunks(chunks []*v1.Secret) error {
+	for i, chunk := range chunks {
+		if _, err := secrets.impl.Create(chunk); err != nil {
+			secrets.deleteChunks(chunks[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteChunks deletes the chunk Secrets. Failures are only logged: an
+// orphaned chunk is never referenced, and so never read, again.
+func (secrets *Secrets) deleteChunks(chunks []*v1.Secret) {
+	for _, chunk := range chunks {
+		if err := secrets.impl.Delete(chunk.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			secrets.Log("failed to delete chunk %q: %s", chunk.Name, err)
+		}
+	}
+}
+
 // newSecretsObject constructs a kubernetes Secret object
 // to store a release. Each secret data entry is the base64
 // encoded string of a release's binary protobuf encoding.
@@ -229,6 +454,9 @@ func (secrets *Secrets) Delete(key string) (rls *rspb.Release, err error) {
 //    "OWNER"          - owner of the secret, currently "TILLER".
 //    "NAME"           - name of the release.
 //
+// Releases too large for a single secret are split by Create and Update,
+// which add the chunk labels described in chunks.go.
+//
 func newSecretsObject(key string, rls *rspb.Release, lbs labels) (*v1.Secret, error) {
 	const owner = "TILLER"
 
@@ -257,3 +485,22 @@ func newSecretsObject(key string, rls *rspb.Release, lbs labels) (*v1.Secret, er
 		Data: map[string][]byte{"release": []byte(s)},
 	}, nil
 }
+
+// newSecretLockData returns the data of a lock Secret held by holder until
+// expiry.
+func newSecretLockData(holder string, expiry time.Time) map[string][]byte {
+	data := map[string][]byte{}
+	for k, v := range newLockData(holder, expiry) {
+		data[k] = []byte(v)
+	}
+	return data
+}
+
+// secretLockData returns the data of a lock Secret as strings.
+func secretLockData(data map[string][]byte) map[string]string {
+	m := map[string]string{}
+	for k, v := range data {
+		m[k] = string(v)
+	}
+	return m
+}
@@ -15,12 +15,14 @@ package driver
 
 import (
 	"encoding/base64"
+	"reflect"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
 	"k8s.io/api/core/v1"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
 
 func TestSecretName(t *testing.T) {
@@ -183,3 +185,110 @@ func TestSecretUpdate(t *testing.T) {
 		t.Errorf("Expected status %s, got status %s", rel.Info.Status.Code, got.Info.Status.Code)
 	}
 }
+
+func TestSecretChunked(t *testing.T) {
+	vers := int32(1)
+	name := "smug-pigeon"
+	namespace := "default"
+	key := testKey(name, vers)
+	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
+	rel.Manifest = "apiVersion: v1\nkind: Secret\nmetadata:\n  name: smug-pigeon\n"
+
+	secrets := newTestFixtureSecrets(t)
+	secrets.ChunkSize = 16
+	mock := secrets.impl.(*MockSecretsInterface)
+
+	// store the release across several secrets
+	if err := secrets.Create(key, rel); err != nil {
+		t.Fatalf("Failed to create release with key %q: %s", key, err)
+	}
+	if len(mock.objects) < 2 {
+		t.Fatalf("Expected release to be split across several secrets, got %d", len(mock.objects))
+	}
+
+	// get, list and query reassemble the release
+	got, err := secrets.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release with key %q: %s", key, err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+	ls, err := secrets.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+	if len(ls) != 1 || !shallowReleaseEqual(rel, ls[0]) {
+		t.Errorf("Expected [{%q}], got %v", rel, ls)
+	}
+	ls, err = secrets.Query(map[string]string{"NAME": name, "OWNER": "TILLER"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	if len(ls) != 1 || !shallowReleaseEqual(rel, ls[0]) {
+		t.Errorf("Expected [{%q}], got %v", rel, ls)
+	}
+
+	// a release missing one of its chunks is never returned
+	head := mock.objects[key]
+	_, set, _ := chunkInfo(head.Labels)
+	chunk := mock.objects[chunkName(key, set, 1)]
+	delete(mock.objects, chunk.Name)
+	if _, err := secrets.Get(key); err == nil {
+		t.Errorf("Expected an error when getting a release with a missing chunk")
+	}
+	mock.objects[chunk.Name] = chunk
+
+	// updating replaces the chunks
+	rel.Manifest += "data:\n  key: value\n"
+	if err := secrets.Update(key, rel); err != nil {
+		t.Fatalf("Failed to update release: %s", err)
+	}
+	if _, ok := mock.objects[chunk.Name]; ok {
+		t.Errorf("Expected stale chunk %q to be deleted", chunk.Name)
+	}
+	got, err = secrets.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release with key %q: %s", key, err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+
+	// deleting removes every chunk
+	if _, err := secrets.Delete(key); err != nil {
+		t.Fatalf("Failed to delete release with key %q: %s", key, err)
+	}
+	if len(mock.objects) != 0 {
+		t.Errorf("Expected no secrets left, got %d", len(mock.objects))
+	}
+}
+
+// racingSecretsInterface simulates another Tiller updating a Secret between the
+// read and the write of an update.
+type racingSecretsInterface struct {
+	*MockSecretsInterface
+}
+
+func (mock racingSecretsInterface) Update(secret *v1.Secret) (*v1.Secret, error) {
+	if _, err := mock.MockSecretsInterface.Update(mock.objects[secret.Name]); err != nil {
+		return nil, err
+	}
+	return mock.MockSecretsInterface.Update(secret)
+}
+
+func TestSecretUpdateConflict(t *testing.T) {
+	name := "smug-pigeon"
+	vers := int32(1)
+	key := testKey(name, vers)
+	rel := releaseStub(name, vers, "default", rspb.Status_DEPLOYED)
+
+	secrets := newTestFixtureSecrets(t, []*rspb.Release{rel}...)
+	secrets.impl = racingSecretsInterface{secrets.impl.(*MockSecretsInterface)}
+
+	rel.Info.Status.Code = rspb.Status_SUPERSEDED
+	err := secrets.Update(key, rel)
+	if expected := storageerrors.ErrReleaseConflict(key); !reflect.DeepEqual(err, expected) {
+		t.Errorf("Expected %q, got %v", expected, err)
+	}
+}
@@ -17,14 +17,20 @@ limitations under the License.
 package driver
 
 import (
+	"database/sql"
 	"fmt"
+	"regexp"
+	"regexp/syntax"
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/jmoiron/sqlx"
 	migrate "github.com/rubenv/sql-migrate"
 
+	// Import mysql for mysql dialect
+	_ "github.com/go-sql-driver/mysql"
 	// Import pq for postgres dialect
 	_ "github.com/lib/pq"
 
@@ -33,18 +39,32 @@ import (
 )
 
 var _ Driver = (*SQL)(nil)
+var _ Locker = (*SQL)(nil)
+var _ Lister = (*SQL)(nil)
 
 var labelMap = map[string]string{
-	"MODIFIED_AT": "modified_at",
-	"CREATED_AT":  "created_at",
-	"VERSION":     "version",
-	"STATUS":      "status",
-	"OWNER":       "owner",
-	"NAME":        "name",
+	"MODIFIED_AT":   "modified_at",
+	"CREATED_AT":    "created_at",
+	"VERSION":       "version",
+	"STATUS":        "status",
+	"OWNER":         "owner",
+	"NAME":          "name",
+	"NAMESPACE":     "namespace",
+	"CHART_NAME":    "chart_name",
+	"CHART_VERSION": "chart_version",
+	"APP_VERSION":   "app_version",
 }
 
+const (
+	postgresDialect = "postgres"
+	mysqlDialect    = "mysql"
+	sqliteDialect   = "sqlite3"
+)
+
 var supportedSQLDialects = map[string]struct{}{
-	"postgres": {},
+	postgresDialect: {},
+	mysqlDialect:    {},
+	sqliteDialect:   {},
 }
 
 // SQLDriverName is the string name of this driver.
@@ -52,8 +72,9 @@ const SQLDriverName = "SQL"
 
 // SQL is the sql storage driver implementation.
 type SQL struct {
-	db  *sqlx.DB
-	Log func(string, ...interface{})
+	db      *sqlx.DB
+	dialect string
+	Log     func(string, ...interface{})
 }
 
 // Name returns the name of the driver.
@@ -64,7 +85,90 @@ func (s *SQL) Name() string {
 func (s *SQL) ensureDBSetup() error {
 	// Populate the database with the relations we need if they don't exist yet
 	migrations := &migrate.MemoryMigrationSource{
-		Migrations: []*migrate.Migration{
+		Migrations: sqlMigrations(s.dialect),
+	}
+
+	if _, err := migrate.Exec(s.db.DB, s.dialect, migrations, migrate.Up); err != nil {
+		return err
+	}
+	return s.backfillColumns()
+}
+
+// sqlMigrations returns the migrations setting up the relations of the given
+// dialect. Migration ids are shared across dialects so that a migration is
+// applied at most once whatever the database.
+func sqlMigrations(dialect string) []*migrate.Migration {
+	return append(sqlReleasesMigrations(dialect), sqlLocksMigration(dialect), sqlColumnsMigration(dialect))
+}
+
+// sqlReleasesMigrations returns the migrations setting up the releases
+// relation for the given dialect.
+func sqlReleasesMigrations(dialect string) []*migrate.Migration {
+	switch dialect {
+	case mysqlDialect:
+		return []*migrate.Migration{
+			{
+				Id: "init",
+				Up: []string{
+					`
+						CREATE TABLE releases (
+							` + "`key`" + ` VARCHAR(67) PRIMARY KEY,
+							body LONGTEXT NOT NULL,
+
+							name VARCHAR(64) NOT NULL,
+							version INTEGER NOT NULL,
+							status VARCHAR(64) NOT NULL,
+							owner VARCHAR(64) NOT NULL,
+							created_at INTEGER NOT NULL,
+							modified_at INTEGER NOT NULL DEFAULT 0
+						) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+					`,
+					`CREATE INDEX releases_version_idx ON releases (version);`,
+					`CREATE INDEX releases_status_idx ON releases (status);`,
+					`CREATE INDEX releases_owner_idx ON releases (owner);`,
+					`CREATE INDEX releases_created_at_idx ON releases (created_at);`,
+					`CREATE INDEX releases_modified_at_idx ON releases (modified_at);`,
+				},
+				Down: []string{
+					`
+						 DROP TABLE releases;
+					`,
+				},
+			},
+		}
+	case sqliteDialect:
+		return []*migrate.Migration{
+			{
+				Id: "init",
+				Up: []string{
+					`
+						CREATE TABLE releases (
+							key VARCHAR(67) PRIMARY KEY,
+							body TEXT NOT NULL,
+
+							name VARCHAR(64) NOT NULL,
+							version INTEGER NOT NULL,
+							status TEXT NOT NULL,
+							owner TEXT NOT NULL,
+							created_at INTEGER NOT NULL,
+							modified_at INTEGER NOT NULL DEFAULT 0
+						);
+					`,
+					`CREATE INDEX releases_version_idx ON releases (version);`,
+					`CREATE INDEX releases_status_idx ON releases (status);`,
+					`CREATE INDEX releases_owner_idx ON releases (owner);`,
+					`CREATE INDEX releases_created_at_idx ON releases (created_at);`,
+					`CREATE INDEX releases_modified_at_idx ON releases (modified_at);`,
+				},
+				Down: []string{
+					`
+						 DROP TABLE releases;
+					`,
+				},
+			},
+		}
+	default:
+		return []*migrate.Migration{
 			{
 				Id: "init",
 				Up: []string{
@@ -95,11 +199,117 @@ func (s *SQL) ensureDBSetup() error {
 					`,
 				},
 			},
+		}
+	}
+}
+
+// sqlLocksMigration returns the migration setting up the release_locks
+// relation for the given dialect. Each row holds the lock of a release.
+func sqlLocksMigration(dialect string) *migrate.Migration {
+	table := `
+		CREATE TABLE release_locks (
+			name VARCHAR(64) PRIMARY KEY,
+			holder VARCHAR(255) NOT NULL,
+			expires_at INTEGER NOT NULL
+		)`
+	if dialect == mysqlDialect {
+		table += " ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+	}
+	return &migrate.Migration{
+		Id: "locks",
+		Up: []string{table + ";"},
+		Down: []string{
+			`
+				 DROP TABLE release_locks;
+			`,
 		},
 	}
+}
 
-	_, err := migrate.Exec(s.db.DB, "postgres", migrations, migrate.Up)
-	return err
+// sqlColumnsMigration returns the migration adding to the releases relation
+// the columns used to select and sort releases when listing them. The columns
+// of the releases stored before the migration are filled by backfillColumns.
+func sqlColumnsMigration(dialect string) *migrate.Migration {
+	text, indexedText := "TEXT", "TEXT"
+	if dialect == mysqlDialect {
+		// MySQL cannot index TEXT columns, and limits the length of index
+		// keys to 767 bytes, i.e. 191 utf8mb4 characters, in older versions.
+		text, indexedText = "VARCHAR(255)", "VARCHAR(191)"
+	}
+	return &migrate.Migration{
+		Id: "release_columns",
+		Up: []string{
+			`ALTER TABLE releases ADD COLUMN namespace ` + indexedText + ` NOT NULL DEFAULT '';`,
+			`ALTER TABLE releases ADD COLUMN chart_name ` + indexedText + ` NOT NULL DEFAULT '';`,
+			`ALTER TABLE releases ADD COLUMN chart_version ` + text + ` NOT NULL DEFAULT '';`,
+			`ALTER TABLE releases ADD COLUMN app_version ` + text + ` NOT NULL DEFAULT '';`,
+			`ALTER TABLE releases ADD COLUMN last_deployed INTEGER NOT NULL DEFAULT 0;`,
+			`CREATE INDEX releases_name_idx ON releases (name);`,
+			`CREATE INDEX releases_namespace_idx ON releases (namespace);`,
+			`CREATE INDEX releases_chart_name_idx ON releases (chart_name);`,
+			`CREATE INDEX releases_last_deployed_idx ON releases (last_deployed);`,
+		},
+		Down: []string{
+			`DROP INDEX releases_name_idx` + onReleases(dialect) + `;`,
+			`DROP INDEX releases_namespace_idx` + onReleases(dialect) + `;`,
+			`DROP INDEX releases_chart_name_idx` + onReleases(dialect) + `;`,
+			`DROP INDEX releases_last_deployed_idx` + onReleases(dialect) + `;`,
+			`ALTER TABLE releases DROP COLUMN namespace;`,
+			`ALTER TABLE releases DROP COLUMN chart_name;`,
+			`ALTER TABLE releases DROP COLUMN chart_version;`,
+			`ALTER TABLE releases DROP COLUMN app_version;`,
+			`ALTER TABLE releases DROP COLUMN last_deployed;`,
+		},
+	}
+}
+
+// onReleases returns the clause naming the table of an index to drop, which
+// MySQL requires.
+func onReleases(dialect string) string {
+	if dialect == mysqlDialect {
+		return " ON releases"
+	}
+	return ""
+}
+
+// backfillColumns fills the columns added by sqlColumnsMigration for the
+// releases stored before it was applied.
+func (s *SQL) backfillColumns() error {
+	var records []SQLReleaseWrapper
+	if err := s.db.Select(&records, s.rebind("SELECT %s, body FROM releases WHERE namespace = '' AND owner = 'TILLER'")); err != nil {
+		return err
+	}
+	for _, record := range records {
+		rls, err := decodeRelease(record.Body)
+		if err != nil {
+			s.Log("backfill: failed to decode release %s: %v", record.Key, err)
+			continue
+		}
+		if _, err := s.db.NamedExec(fmt.Sprintf("UPDATE releases SET namespace=:namespace, chart_name=:chart_name, chart_version=:chart_version, app_version=:app_version, last_deployed=:last_deployed WHERE %s=:key", s.keyColumn()),
+			newSQLReleaseWrapper(record.Key, record.Body, rls),
+		); err != nil {
+			return fmt.Errorf("failed to backfill release %s: %v", record.Key, err)
+		}
+	}
+	if len(records) > 0 {
+		s.Log("backfilled the columns of %d release(s)", len(records))
+	}
+	return nil
+}
+
+// keyColumn returns the name of the primary key column, quoted where the
+// dialect reserves the word "key".
+func (s *SQL) keyColumn() string {
+	if s.dialect == mysqlDialect {
+		return "`key`"
+	}
+	return "key"
+}
+
+// rebind substitutes the key column into query and rewrites its '?'
+// placeholders into the bindvar syntax of the dialect, e.g. $1 for postgres.
+func (s *SQL) rebind(query string) string {
+	return s.db.Rebind(fmt.Sprintf(query, s.keyColumn()))
 }
 
 // SQLReleaseWrapper describes how Helm releases are stored in an SQL database
@@ -119,12 +329,51 @@ type SQLReleaseWrapper struct {
 	Owner      string `db:"owner"`
 	CreatedAt  int    `db:"created_at"`
 	ModifiedAt int    `db:"modified_at"`
+
+	// Columns used to select and sort releases when listing them, added by
+	// the "release_columns" migration.
+	Namespace    string `db:"namespace"`
+	ChartName    string `db:"chart_name"`
+	ChartVersion string `db:"chart_version"`
+	AppVersion   string `db:"app_version"`
+	LastDeployed int64  `db:"last_deployed"`
+}
+
+// newSQLReleaseWrapper returns the row storing the release rls, encoded as
+// body, under key.
+func newSQLReleaseWrapper(key, body string, rls *rspb.Release) *SQLReleaseWrapper {
+	md := rls.GetChart().GetMetadata()
+	return &SQLReleaseWrapper{
+		Key:  key,
+		Body: body,
+
+		Name:         rls.Name,
+		Version:      int(rls.Version),
+		Status:       rspb.Status_Code_name[int32(rls.GetInfo().GetStatus().GetCode())],
+		Owner:        "TILLER",
+		Namespace:    rls.Namespace,
+		ChartName:    md.GetName(),
+		ChartVersion: md.GetVersion(),
+		AppVersion:   md.GetAppVersion(),
+		LastDeployed: rls.GetInfo().GetLastDeployed().GetSeconds(),
+	}
+}
+
+// sqlLockWrapper describes how the lock of a release is stored in an SQL
+// database.
+type sqlLockWrapper struct {
+	Name      string `db:"name"`
+	Holder    string `db:"holder"`
+	ExpiresAt int64  `db:"expires_at"`
 }
 
-// NewSQL initializes a new memory driver.
+// NewSQL initializes a new sql driver.
 func NewSQL(dialect, connectionString string, logger func(string, ...interface{})) (*SQL, error) {
 	if _, ok := supportedSQLDialects[dialect]; !ok {
-		return nil, fmt.Errorf("%s dialect isn't supported, only \"postgres\" is available for now", dialect)
+		return nil, fmt.Errorf("%s dialect isn't supported, use one of %s", dialect, strings.Join(sqlDialectNames(), ", "))
+	}
+	if dialect == sqliteDialect && !sqliteSupported {
+		return nil, fmt.Errorf("%s dialect requires a build with cgo enabled (CGO_ENABLED=1)", dialect)
 	}
 
 	db, err := sqlx.Connect(dialect, connectionString)
@@ -133,8 +382,9 @@ func NewSQL(dialect, connectionString string, logger func(string, ...interface{}
 	}
 
 	driver := &SQL{
-		db:  db,
-		Log: logger,
+		db:      db,
+		dialect: dialect,
+		Log:     logger,
 	}
 
 	if err := driver.ensureDBSetup(); err != nil {
@@ -144,11 +394,21 @@ func NewSQL(dialect, connectionString string, logger func(string, ...interface{}
 	return driver, nil
 }
 
+// sqlDialectNames returns the sorted names of the supported SQL dialects.
+func sqlDialectNames() []string {
+	var names []string
+	for name := range supportedSQLDialects {
+		names = append(names, fmt.Sprintf("%q", name))
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Get returns the release named by key.
 func (s *SQL) Get(key string) (*rspb.Release, error) {
 	var record SQLReleaseWrapper
 	// Get will return an error if the result is empty
-	err := s.db.Get(&record, "SELECT body FROM releases WHERE key = $1", key)
+	err := s.db.Get(&record, s.rebind("SELECT body FROM releases WHERE %s = ?"), key)
 	if err != nil {
 		s.Log("got SQL error when getting release %s: %v", key, err)
 		return nil, storageerrors.ErrReleaseNotFound(key)
@@ -240,6 +500,292 @@ func (s *SQL) Query(labels map[string]string) ([]*rspb.Release, error) {
 	return releases, nil
 }
 
+// sqlListBatchSize is the maximum number of releases fetched by a single
+// query of ListPage, bounding the number of bind parameters of the query.
+const sqlListBatchSize = 500
+
+// ListPage returns the page of the releases matching opts. The releases are
+// selected and sorted by the database. When opts.Filter is empty or only
+// matches the names starting with a literal prefix, e.g. "^web-", the database
+// also cuts the page, and only the releases of the page are read. Other
+// filters are Go regular expressions, which differ from the ones of MySQL and
+// PostgreSQL: the names of the releases starting with the literal prefix the
+// filter is anchored to, if any, are read and matched by Tiller, and only the
+// releases of the page are then fetched and decoded.
+func (s *SQL) ListPage(opts ListOptions) (*ListPage, error) {
+	var preg *regexp.Regexp
+	if opts.Filter != "" {
+		var err error
+		if preg, err = regexp.Compile(opts.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	where := []string{"owner = 'TILLER'"}
+	var args []interface{}
+	if len(opts.StatusCodes) > 0 {
+		marks := make([]string, len(opts.StatusCodes))
+		for i, code := range opts.StatusCodes {
+			marks[i] = "?"
+			args = append(args, rspb.Status_Code_name[int32(code)])
+		}
+		where = append(where, "status IN ("+strings.Join(marks, ", ")+")")
+	}
+	if opts.Namespace != "" {
+		where = append(where, "namespace = ?")
+		args = append(args, opts.Namespace)
+	}
+	if prefix := filterPrefix(opts.Filter); prefix != "" {
+		cond, prefixArgs := s.namePrefix(prefix)
+		where = append(where, cond)
+		args = append(args, prefixArgs...)
+	}
+	if preg == nil || filterIsPrefix(opts.Filter) {
+		return s.cutPage(opts, strings.Join(where, " AND "), args)
+	}
+
+	query := fmt.Sprintf("SELECT %s, name FROM releases WHERE %s ORDER BY %s",
+		s.keyColumn(), strings.Join(where, " AND "), s.listOrder(opts))
+	var rows []SQLReleaseWrapper
+	if err := s.db.Select(&rows, s.db.Rebind(query), args...); err != nil {
+		s.Log("list: failed to list: %v", err)
+		return nil, err
+	}
+	matches := rows[:0]
+	for _, row := range rows {
+		if preg.MatchString(row.Name) {
+			matches = append(matches, row)
+		}
+	}
+	rows = matches
+
+	start, end, err := pageBounds(len(rows), func(i int) string { return rows[i].Name }, opts.Offset, opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+	page := &ListPage{Total: len(rows)}
+	if end < len(rows) {
+		page.Next = rows[end].Name
+	}
+	if page.Releases, err = s.getPage(rows[start:end]); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// cutPage returns the page of the releases matching the condition cond,
+// counted, sorted and cut by the database.
+func (s *SQL) cutPage(opts ListOptions, cond string, args []interface{}) (*ListPage, error) {
+	page := &ListPage{}
+	if err := s.db.Get(&page.Total, s.db.Rebind("SELECT COUNT(*) FROM releases WHERE "+cond), args...); err != nil {
+		s.Log("list: failed to count releases: %v", err)
+		return nil, err
+	}
+
+	pageArgs := append([]interface{}{}, args...)
+	if opts.Offset != "" {
+		from, fromArgs, err := s.listFrom(opts, cond, args)
+		if err != nil {
+			return nil, err
+		}
+		cond += " AND " + from
+		pageArgs = append(pageArgs, fromArgs...)
+	}
+	query := fmt.Sprintf("SELECT %s, name FROM releases WHERE %s ORDER BY %s", s.keyColumn(), cond, s.listOrder(opts))
+	if opts.Limit > 0 {
+		// the release following the page is read as well, to name the
+		// next page
+		query += " LIMIT ?"
+		pageArgs = append(pageArgs, opts.Limit+1)
+	}
+	var rows []SQLReleaseWrapper
+	if err := s.db.Select(&rows, s.db.Rebind(query), pageArgs...); err != nil {
+		s.Log("list: failed to list: %v", err)
+		return nil, err
+	}
+	if opts.Limit > 0 && len(rows) > opts.Limit {
+		page.Next = rows[opts.Limit].Name
+		rows = rows[:opts.Limit]
+	}
+
+	var err error
+	if page.Releases, err = s.getPage(rows); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// listFrom returns the condition selecting the releases sorted at or after the
+// page offset, and its arguments. As with pageBounds, the page starts at the
+// last of the releases matching cond named opts.Offset.
+func (s *SQL) listFrom(opts ListOptions, cond string, args []interface{}) (string, []interface{}, error) {
+	reversed := opts
+	reversed.SortDesc = !opts.SortDesc
+	query := fmt.Sprintf("SELECT %s, name, chart_name, last_deployed FROM releases WHERE %s AND name = ? ORDER BY %s",
+		s.keyColumn(), cond, s.listOrder(reversed))
+	var rows []SQLReleaseWrapper
+	if err := s.db.Select(&rows, s.db.Rebind(query), append(append([]interface{}{}, args...), opts.Offset)...); err != nil {
+		s.Log("list: failed to get offset %s: %v", opts.Offset, err)
+		return "", nil, err
+	}
+
+	for _, row := range rows {
+		// MySQL compares names ignoring case
+		if row.Name != opts.Offset {
+			continue
+		}
+		cmp := ">"
+		if opts.SortDesc {
+			cmp = "<"
+		}
+		from := fmt.Sprintf("%s %s= ?", s.keyColumn(), cmp)
+		column := listSortColumn(opts.SortBy)
+		if column == "" {
+			return from, []interface{}{row.Key}, nil
+		}
+		value := map[string]interface{}{
+			"name":          row.Name,
+			"last_deployed": row.LastDeployed,
+			"chart_name":    row.ChartName,
+		}[column]
+		return fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND %[3]s))", column, cmp, from), []interface{}{value, value, row.Key}, nil
+	}
+	return "", nil, fmt.Errorf("offset %q not found", opts.Offset)
+}
+
+// namePrefix returns the condition selecting the releases whose name starts
+// with prefix, and its arguments. LIKE ignores case in MySQL and SQLite, so
+// names are also compared as bytes in these dialects.
+func (s *SQL) namePrefix(prefix string) (string, []interface{}) {
+	pattern := likeEscaper.Replace(prefix) + "%"
+	switch s.dialect {
+	case mysqlDialect:
+		return "name LIKE ? ESCAPE '!' AND CAST(name AS BINARY) LIKE ? ESCAPE '!'", []interface{}{pattern, pattern}
+	case sqliteDialect:
+		return "substr(name, 1, ?) = ?", []interface{}{utf8.RuneCountInString(prefix), prefix}
+	}
+	return "name LIKE ? ESCAPE '!'", []interface{}{pattern}
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, with '!' as the escape
+// character.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// filterPrefix returns the literal, case-sensitive prefix of the names matched
+// by the regular expression filter, or an empty string if the filter is not
+// anchored to the start of the names by such a prefix.
+func filterPrefix(filter string) string {
+	re := parseFilter(filter)
+	if re == nil || re.Op != syntax.OpConcat || len(re.Sub) < 2 || re.Sub[0].Op != syntax.OpBeginText {
+		return ""
+	}
+	lit := re.Sub[1]
+	if lit.Op != syntax.OpLiteral || lit.Flags&syntax.FoldCase != 0 {
+		return ""
+	}
+	return string(lit.Rune)
+}
+
+// filterIsPrefix reports whether the regular expression filter matches
+// exactly the names starting with its literal prefix.
+func filterIsPrefix(filter string) bool {
+	re := parseFilter(filter)
+	return re != nil && re.Op == syntax.OpConcat && len(re.Sub) == 2 && filterPrefix(filter) != ""
+}
+
+// parseFilter returns the simplified syntax tree of the regular expression
+// filter, or nil if it is invalid.
+func parseFilter(filter string) *syntax.Regexp {
+	re, err := syntax.Parse(filter, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	return re.Simplify()
+}
+
+// listOrder returns the ORDER BY clause sorting releases as requested by
+// opts. Releases are also sorted by key, so that pages are stable.
+func (s *SQL) listOrder(opts ListOptions) string {
+	dir := "ASC"
+	if opts.SortDesc {
+		dir = "DESC"
+	}
+	order := s.keyColumn() + " " + dir
+	if column := listSortColumn(opts.SortBy); column != "" {
+		order = column + " " + dir + ", " + order
+	}
+	return order
+}
+
+// listSortColumn returns the column releases are sorted by before their key,
+// or an empty string if they are only sorted by key.
+func listSortColumn(sortBy ListSortBy) string {
+	switch sortBy {
+	case ListSortByName:
+		return "name"
+	case ListSortByLastReleased:
+		return "last_deployed"
+	case ListSortByChartName:
+		return "chart_name"
+	}
+	return ""
+}
+
+// getPage fetches and decodes the releases stored in rows, in the order of
+// rows, in batches of sqlListBatchSize.
+func (s *SQL) getPage(rows []SQLReleaseWrapper) ([]*rspb.Release, error) {
+	var releases []*rspb.Release
+	for i := 0; i < len(rows); i += sqlListBatchSize {
+		j := i + sqlListBatchSize
+		if j > len(rows) {
+			j = len(rows)
+		}
+		rels, err := s.getBatch(rows[i:j])
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, rels...)
+	}
+	return releases, nil
+}
+
+// getBatch fetches and decodes the releases stored in rows, in the order of
+// rows. Releases deleted since rows were read are skipped.
+func (s *SQL) getBatch(rows []SQLReleaseWrapper) ([]*rspb.Release, error) {
+	keys := make([]string, len(rows))
+	for i, row := range rows {
+		keys[i] = row.Key
+	}
+	query, args, err := sqlx.In(fmt.Sprintf("SELECT %[1]s, body FROM releases WHERE %[1]s IN (?)", s.keyColumn()), keys)
+	if err != nil {
+		return nil, err
+	}
+	var records []SQLReleaseWrapper
+	if err := s.db.Select(&records, s.db.Rebind(query), args...); err != nil {
+		s.Log("list: failed to get releases: %v", err)
+		return nil, err
+	}
+	bodies := make(map[string]string, len(records))
+	for _, record := range records {
+		bodies[record.Key] = record.Body
+	}
+
+	var releases []*rspb.Release
+	for _, key := range keys {
+		body, ok := bodies[key]
+		if !ok {
+			continue
+		}
+		release, err := decodeRelease(body)
+		if err != nil {
+			s.Log("list: failed to decode release %s: %v", key, err)
+			continue
+		}
+		releases = append(releases, release)
+	}
+	return releases, nil
+}
+
 // Create creates a new release.
 func (s *SQL) Create(key string, rls *rspb.Release) error {
 	body, err := encodeRelease(rls)
@@ -254,21 +800,14 @@ func (s *SQL) Create(key string, rls *rspb.Release) error {
 		return fmt.Errorf("error beginning transaction: %v", err)
 	}
 
-	if _, err := transaction.NamedExec("INSERT INTO releases (key, body, name, version, status, owner, created_at) VALUES (:key, :body, :name, :version, :status, :owner, :created_at)",
-		&SQLReleaseWrapper{
-			Key:  key,
-			Body: body,
-
-			Name:      rls.Name,
-			Version:   int(rls.Version),
-			Status:    rspb.Status_Code_name[int32(rls.Info.Status.Code)],
-			Owner:     "TILLER",
-			CreatedAt: int(time.Now().Unix()),
-		},
+	row := newSQLReleaseWrapper(key, body, rls)
+	row.CreatedAt = int(time.Now().Unix())
+	if _, err := transaction.NamedExec(fmt.Sprintf("INSERT INTO releases (%s, body, name, version, status, owner, created_at, namespace, chart_name, chart_version, app_version, last_deployed) VALUES (:key, :body, :name, :version, :status, :owner, :created_at, :namespace, :chart_name, :chart_version, :app_version, :last_deployed)", s.keyColumn()),
+		row,
 	); err != nil {
 		defer transaction.Rollback()
 		var record SQLReleaseWrapper
-		if err := transaction.Get(&record, "SELECT key FROM releases WHERE key = ?", key); err == nil {
+		if err := transaction.Get(&record, s.rebind("SELECT %[1]s FROM releases WHERE %[1]s = ?"), key); err == nil {
 			s.Log("release %s already exists", key)
 			return storageerrors.ErrReleaseExists(key)
 		}
@@ -289,22 +828,30 @@ func (s *SQL) Update(key string, rls *rspb.Release) error {
 		return err
 	}
 
-	if _, err := s.db.NamedExec("UPDATE releases SET body=:body, name=:name, version=:version, status=:status, owner=:owner, modified_at=:modified_at WHERE key=:key",
-		&SQLReleaseWrapper{
-			Key:  key,
-			Body: body,
-
-			Name:       rls.Name,
-			Version:    int(rls.Version),
-			Status:     rspb.Status_Code_name[int32(rls.Info.Status.Code)],
-			Owner:      "TILLER",
-			ModifiedAt: int(time.Now().Unix()),
-		},
-	); err != nil {
+	row := newSQLReleaseWrapper(key, body, rls)
+	row.ModifiedAt = int(time.Now().Unix())
+	result, err := s.db.NamedExec(fmt.Sprintf("UPDATE releases SET body=:body, name=:name, version=:version, status=:status, owner=:owner, modified_at=:modified_at, namespace=:namespace, chart_name=:chart_name, chart_version=:chart_version, app_version=:app_version, last_deployed=:last_deployed WHERE %s=:key", s.keyColumn()),
+		row,
+	)
+	if err != nil {
 		s.Log("failed to update release %s in SQL database: %v", key, err)
 		return err
 	}
 
+	// MySQL does not count the rows left unchanged by an update, so an
+	// update matching no row is only reported once the row is known to be
+	// missing.
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		var count int
+		if err := s.db.Get(&count, s.rebind("SELECT COUNT(*) FROM releases WHERE %s = ?"), key); err != nil {
+			s.Log("failed to check the existence of release %s: %v", key, err)
+			return err
+		}
+		if count == 0 {
+			return storageerrors.ErrReleaseNotFound(key)
+		}
+	}
+
 	return nil
 }
 
@@ -317,9 +864,10 @@ func (s *SQL) Delete(key string) (*rspb.Release, error) {
 	}
 
 	var record SQLReleaseWrapper
-	err = transaction.Get(&record, "SELECT body FROM releases WHERE key = $1", key)
+	err = transaction.Get(&record, s.rebind("SELECT body FROM releases WHERE %s = ?"), key)
 	if err != nil {
 		s.Log("release %s not found: %v", key, err)
+		transaction.Rollback()
 		return nil, storageerrors.ErrReleaseNotFound(key)
 	}
 
@@ -331,6 +879,69 @@ func (s *SQL) Delete(key string) (*rspb.Release, error) {
 	}
 	defer transaction.Commit()
 
-	_, err = transaction.Exec("DELETE FROM releases WHERE key = $1", key)
+	_, err = transaction.Exec(s.rebind("DELETE FROM releases WHERE %s = ?"), key)
 	return release, err
 }
+
+// Lock acquires or renews the lock of the named release on behalf of holder.
+// The row holding the lock is locked for the duration of the transaction
+// checking and updating it, so that two Tillers cannot both acquire it.
+func (s *SQL) Lock(name, holder string, ttl time.Duration) error {
+	now := time.Now()
+
+	transaction, err := s.db.Beginx()
+	if err != nil {
+		s.Log("failed to start SQL transaction: %v", err)
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	var lock sqlLockWrapper
+	err = transaction.Get(&lock, s.db.Rebind("SELECT name, holder, expires_at FROM release_locks WHERE name = ?"+s.forUpdate()), name)
+	switch {
+	case err == sql.ErrNoRows:
+		_, err = transaction.Exec(s.db.Rebind("INSERT INTO release_locks (name, holder, expires_at) VALUES (?, ?, ?)"),
+			name, holder, now.Add(ttl).Unix())
+		if err != nil {
+			// another Tiller inserted the lock since we looked for it
+			s.Log("failed to insert lock of %s: %v", name, err)
+			transaction.Rollback()
+			return storageerrors.ErrReleaseLocked(name)
+		}
+	case err != nil:
+		s.Log("failed to get lock of %s: %v", name, err)
+		transaction.Rollback()
+		return err
+	case lock.Holder != holder && now.Unix() < lock.ExpiresAt:
+		transaction.Rollback()
+		return storageerrors.ErrReleaseLocked(name)
+	default:
+		_, err = transaction.Exec(s.db.Rebind("UPDATE release_locks SET holder = ?, expires_at = ? WHERE name = ?"),
+			holder, now.Add(ttl).Unix(), name)
+		if err != nil {
+			s.Log("failed to update lock of %s: %v", name, err)
+			transaction.Rollback()
+			return err
+		}
+	}
+
+	return transaction.Commit()
+}
+
+// Unlock releases the lock of the named release if it is held by holder.
+func (s *SQL) Unlock(name, holder string) error {
+	_, err := s.db.Exec(s.db.Rebind("DELETE FROM release_locks WHERE name = ? AND holder = ?"), name, holder)
+	if err != nil {
+		s.Log("failed to delete lock of %s: %v", name, err)
+	}
+	return err
+}
+
+// forUpdate returns the clause locking the rows selected in a transaction.
+// SQLite has no row locks, but only lets a single transaction write to the
+// database at a time.
+func (s *SQL) forUpdate() string {
+	if s.dialect == sqliteDialect {
+		return ""
+	}
+	return " FOR UPDATE"
+}
@@ -0,0 +1,26 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +build cgo
+
+package driver
+
+// Import go-sqlite3 for sqlite3 dialect. The driver wraps the SQLite C
+// library and is only available in builds with cgo enabled.
+import _ "github.com/mattn/go-sqlite3"
+
+// sqliteSupported reports whether this build can open sqlite3 databases.
+const sqliteSupported = true
@@ -17,17 +17,24 @@ limitations under the License.
 package driver
 
 import (
-	"fmt"
-	"regexp"
+	sqldriver "database/sql/driver"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
-	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	migrate "github.com/rubenv/sql-migrate"
+
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
 
 func TestSQLName(t *testing.T) {
-	sqlDriver, _ := newTestFixtureSQL(t)
+	sqlDriver, cleanup := newTestFixtureSQLite(t)
+	defer cleanup()
+
 	if sqlDriver.Name() != SQLDriverName {
 		t.Errorf("Expected name to be %q, got %q", SQLDriverName, sqlDriver.Name())
 	}
@@ -40,22 +47,8 @@ func TestSQLGet(t *testing.T) {
 	key := testKey(name, vers)
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
-	body, err := encodeRelease(rel)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	sqlDriver, mock := newTestFixtureSQL(t)
-	mock.
-		ExpectQuery("SELECT body FROM releases WHERE key = ?").
-		WithArgs(key).
-		WillReturnRows(
-			mock.NewRows([]string{
-				"body",
-			}).AddRow(
-				body,
-			),
-		).RowsWillBeClosed()
+	sqlDriver, cleanup := newTestFixtureSQLite(t, rel)
+	defer cleanup()
 
 	got, err := sqlDriver.Get(key)
 	if err != nil {
@@ -66,36 +59,21 @@ func TestSQLGet(t *testing.T) {
 		t.Errorf("Expected release {%q}, got {%q}", rel, got)
 	}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("sql expectations weren't met: %v", err)
+	if _, err := sqlDriver.Get(testKey(name, vers+1)); err == nil {
+		t.Errorf("Expected an error when getting a missing release")
 	}
 }
 
 func TestSQLList(t *testing.T) {
-	body1, _ := encodeRelease(releaseStub("key-1", 1, "default", rspb.Status_DELETED))
-	body2, _ := encodeRelease(releaseStub("key-2", 1, "default", rspb.Status_DELETED))
-	body3, _ := encodeRelease(releaseStub("key-3", 1, "default", rspb.Status_DEPLOYED))
-	body4, _ := encodeRelease(releaseStub("key-4", 1, "default", rspb.Status_DEPLOYED))
-	body5, _ := encodeRelease(releaseStub("key-5", 1, "default", rspb.Status_SUPERSEDED))
-	body6, _ := encodeRelease(releaseStub("key-6", 1, "default", rspb.Status_SUPERSEDED))
-
-	sqlDriver, mock := newTestFixtureSQL(t)
-
-	for i := 0; i < 3; i++ {
-		mock.
-			ExpectQuery("SELECT body FROM releases WHERE owner = 'TILLER'").
-			WillReturnRows(
-				mock.NewRows([]string{
-					"body",
-				}).
-					AddRow(body1).
-					AddRow(body2).
-					AddRow(body3).
-					AddRow(body4).
-					AddRow(body5).
-					AddRow(body6),
-			).RowsWillBeClosed()
-	}
+	sqlDriver, cleanup := newTestFixtureSQLite(t,
+		releaseStub("key-1", 1, "default", rspb.Status_DELETED),
+		releaseStub("key-2", 1, "default", rspb.Status_DELETED),
+		releaseStub("key-3", 1, "default", rspb.Status_DEPLOYED),
+		releaseStub("key-4", 1, "default", rspb.Status_DEPLOYED),
+		releaseStub("key-5", 1, "default", rspb.Status_SUPERSEDED),
+		releaseStub("key-6", 1, "default", rspb.Status_SUPERSEDED),
+	)
+	defer cleanup()
 
 	// list all deleted releases
 	del, err := sqlDriver.List(func(rel *rspb.Release) bool {
@@ -132,10 +110,6 @@ func TestSQLList(t *testing.T) {
 	if len(ssd) != 2 {
 		t.Errorf("Expected 2 superseded, got %d:\n%v\n", len(ssd), ssd)
 	}
-
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("sql expectations weren't met: %v", err)
-	}
 }
 
 func TestSqlCreate(t *testing.T) {
@@ -145,22 +119,19 @@ func TestSqlCreate(t *testing.T) {
 	key := testKey(name, vers)
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
-	sqlDriver, mock := newTestFixtureSQL(t)
-	body, _ := encodeRelease(rel)
-
-	mock.ExpectBegin()
-	mock.
-		ExpectExec(regexp.QuoteMeta("INSERT INTO releases (key, body, name, version, status, owner, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)")).
-		WithArgs(key, body, rel.Name, int(rel.Version), rspb.Status_Code_name[int32(rel.Info.Status.Code)], "TILLER", int(time.Now().Unix())).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectCommit()
+	sqlDriver, cleanup := newTestFixtureSQLite(t)
+	defer cleanup()
 
 	if err := sqlDriver.Create(key, rel); err != nil {
 		t.Fatalf("failed to create release with key %q: %v", key, err)
 	}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("sql expectations weren't met: %v", err)
+	got, err := sqlDriver.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release: %v", err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected release {%q}, got {%q}", rel, got)
 	}
 }
 
@@ -171,35 +142,16 @@ func TestSqlCreateAlreadyExists(t *testing.T) {
 	key := testKey(name, vers)
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
-	sqlDriver, mock := newTestFixtureSQL(t)
-	body, _ := encodeRelease(rel)
-
-	// Insert fails (primary key already exists)
-	mock.ExpectBegin()
-	mock.
-		ExpectExec(regexp.QuoteMeta("INSERT INTO releases (key, body, name, version, status, owner, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)")).
-		WithArgs(key, body, rel.Name, int(rel.Version), rspb.Status_Code_name[int32(rel.Info.Status.Code)], "TILLER", int(time.Now().Unix())).
-		WillReturnError(fmt.Errorf("dialect dependent SQL error"))
+	sqlDriver, cleanup := newTestFixtureSQLite(t, rel)
+	defer cleanup()
 
 	// Let's check that we do make sure the error is due to a release already existing
-	mock.
-		ExpectQuery(regexp.QuoteMeta("SELECT key FROM releases WHERE key = ?")).
-		WithArgs(key).
-		WillReturnRows(
-			mock.NewRows([]string{
-				"body",
-			}).AddRow(
-				body,
-			),
-		).RowsWillBeClosed()
-	mock.ExpectRollback()
-
-	if err := sqlDriver.Create(key, rel); err == nil {
-		t.Fatalf("failed to create release with key %q: %v", key, err)
+	err := sqlDriver.Create(key, rel)
+	if err == nil {
+		t.Fatalf("Expected an error when creating release %q twice", key)
 	}
-
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("sql expectations weren't met: %v", err)
+	if err.Error() != storageerrors.ErrReleaseExists(key).Error() {
+		t.Errorf("Expected ErrReleaseExists, got %v", err)
 	}
 }
 
@@ -210,20 +162,20 @@ func TestSqlUpdate(t *testing.T) {
 	key := testKey(name, vers)
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
-	sqlDriver, mock := newTestFixtureSQL(t)
-	body, _ := encodeRelease(rel)
-
-	mock.
-		ExpectExec(regexp.QuoteMeta("UPDATE releases SET body=?, name=?, version=?, status=?, owner=?, modified_at=? WHERE key=?")).
-		WithArgs(body, rel.Name, int(rel.Version), rspb.Status_Code_name[int32(rel.Info.Status.Code)], "TILLER", int(time.Now().Unix()), key).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlDriver, cleanup := newTestFixtureSQLite(t, rel)
+	defer cleanup()
 
+	rel.Info.Status.Code = rspb.Status_SUPERSEDED
 	if err := sqlDriver.Update(key, rel); err != nil {
 		t.Fatalf("failed to update release with key %q: %v", key, err)
 	}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("sql expectations weren't met: %v", err)
+	got, err := sqlDriver.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release: %v", err)
+	}
+	if got.Info.Status.Code != rspb.Status_SUPERSEDED {
+		t.Errorf("Expected status %s, got %s", rspb.Status_SUPERSEDED, got.Info.Status.Code)
 	}
 }
 
@@ -240,42 +192,20 @@ func TestSqlQuery(t *testing.T) {
 	}
 
 	supersededRelease := releaseStub("smug-pigeon", 1, "default", rspb.Status_SUPERSEDED)
-	supersededReleaseBody, _ := encodeRelease(supersededRelease)
 	deployedRelease := releaseStub("smug-pigeon", 2, "default", rspb.Status_DEPLOYED)
-	deployedReleaseBody, _ := encodeRelease(deployedRelease)
 
 	// Let's actually start our test
-	sqlDriver, mock := newTestFixtureSQL(t)
-
-	mock.
-		ExpectQuery(regexp.QuoteMeta("SELECT body FROM releases WHERE name=? AND owner=? AND status=?")).
-		WithArgs("smug-pigeon", "TILLER", "DEPLOYED").
-		WillReturnRows(
-			mock.NewRows([]string{
-				"body",
-			}).AddRow(
-				deployedReleaseBody,
-			),
-		).RowsWillBeClosed()
-
-	mock.
-		ExpectQuery(regexp.QuoteMeta("SELECT body FROM releases WHERE name=? AND owner=?")).
-		WithArgs("smug-pigeon", "TILLER").
-		WillReturnRows(
-			mock.NewRows([]string{
-				"body",
-			}).AddRow(
-				supersededReleaseBody,
-			).AddRow(
-				deployedReleaseBody,
-			),
-		).RowsWillBeClosed()
+	sqlDriver, cleanup := newTestFixtureSQLite(t, supersededRelease, deployedRelease)
+	defer cleanup()
 
 	results, err := sqlDriver.Query(labelSetDeployed)
 	if err != nil {
 		t.Fatalf("failed to query for deployed smug-pigeon release: %v", err)
 	}
 
+	if len(results) != 1 {
+		t.Errorf("expected a resultset of size 1, got %d", len(results))
+	}
 	for _, res := range results {
 		if !shallowReleaseEqual(res, deployedRelease) {
 			t.Errorf("Expected release {%q}, got {%q}", deployedRelease, res)
@@ -297,8 +227,8 @@ func TestSqlQuery(t *testing.T) {
 		}
 	}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("sql expectations weren't met: %v", err)
+	if _, err := sqlDriver.Query(map[string]string{"NAME": "other-pigeon"}); err == nil {
+		t.Errorf("Expected an error when querying a missing release")
 	}
 }
 
@@ -309,27 +239,8 @@ func TestSqlDelete(t *testing.T) {
 	key := testKey(name, vers)
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
-	body, _ := encodeRelease(rel)
-
-	sqlDriver, mock := newTestFixtureSQL(t)
-
-	mock.ExpectBegin()
-	mock.
-		ExpectQuery("SELECT body FROM releases WHERE key = ?").
-		WithArgs(key).
-		WillReturnRows(
-			mock.NewRows([]string{
-				"body",
-			}).AddRow(
-				body,
-			),
-		).RowsWillBeClosed()
-
-	mock.
-		ExpectExec(regexp.QuoteMeta("DELETE FROM releases WHERE key = $1")).
-		WithArgs(key).
-		WillReturnResult(sqlmock.NewResult(0, 1))
-	mock.ExpectCommit()
+	sqlDriver, cleanup := newTestFixtureSQLite(t, rel)
+	defer cleanup()
 
 	deletedRelease, err := sqlDriver.Delete(key)
 	if err != nil {
@@ -340,7 +251,286 @@ func TestSqlDelete(t *testing.T) {
 		t.Errorf("Expected release {%q}, got {%q}", rel, deletedRelease)
 	}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("sql expectations weren't met: %v", err)
+	if _, err := sqlDriver.Get(key); err == nil {
+		t.Errorf("Expected an error when getting a deleted release")
+	}
+	if _, err := sqlDriver.Delete(key); err == nil {
+		t.Errorf("Expected an error when deleting a missing release")
+	}
+}
+
+func TestNewSQLUnsupportedDialect(t *testing.T) {
+	if _, err := NewSQL("oracle", "", func(_ string, _ ...interface{}) {}); err == nil {
+		t.Errorf("Expected an error for an unsupported dialect")
+	}
+}
+
+func TestNewSQLiteWithoutCgo(t *testing.T) {
+	if sqliteSupported {
+		t.Skip("sqlite3 is supported in builds with cgo")
+	}
+	_, err := NewSQL(sqliteDialect, filepath.Join(os.TempDir(), "releases.db"), func(_ string, _ ...interface{}) {})
+	if err == nil || !strings.Contains(err.Error(), "cgo") {
+		t.Errorf("Expected an error mentioning cgo, got %v", err)
+	}
+}
+
+func TestFilterPrefix(t *testing.T) {
+	for filter, prefix := range map[string]string{
+		"^web":       "web",
+		`^web\b`:     "web",
+		"^web-.*db$": "web-",
+		"^a.b":       "a",
+		"web":        "",
+		"^[wW]eb":    "",
+		"(?i)^web":   "",
+		"(?m)^web":   "",
+		"^(web":      "",
+	} {
+		if got := filterPrefix(filter); got != prefix {
+			t.Errorf("Expected prefix %q for %q, got %q", prefix, filter, got)
+		}
+	}
+}
+
+func TestFilterIsPrefix(t *testing.T) {
+	for filter, isPrefix := range map[string]bool{
+		"^web":       true,
+		"^web-":      true,
+		`^web\b`:     false,
+		"^web$":      false,
+		"^web-.*db$": false,
+		"web":        false,
+		"(?i)^web":   false,
+		"^(web":      false,
+	} {
+		if got := filterIsPrefix(filter); got != isPrefix {
+			t.Errorf("Expected %v for %q, got %v", isPrefix, filter, got)
+		}
+	}
+}
+
+func TestSQLiteListPagePrefixCase(t *testing.T) {
+	sqlDriver, cleanup := newTestFixtureSQLite(t,
+		releaseStub("web-a", 1, "default", rspb.Status_DEPLOYED),
+		releaseStub("Web-b", 1, "default", rspb.Status_DEPLOYED),
+		releaseStub("web-c", 1, "default", rspb.Status_DEPLOYED),
+	)
+	defer cleanup()
+
+	page, err := sqlDriver.ListPage(ListOptions{Filter: "^web-", SortBy: ListSortByName, Limit: 1})
+	if err != nil {
+		t.Fatalf("Failed to list: %v", err)
+	}
+	if len(page.Releases) != 1 || page.Releases[0].Name != "web-a" || page.Total != 2 || page.Next != "web-c" {
+		t.Errorf("Expected web-a of 2 releases followed by web-c, got %v of %d followed by %q", page.Releases, page.Total, page.Next)
+	}
+}
+
+func TestSQLListPageQueries(t *testing.T) {
+	for _, tt := range []struct {
+		dialect string
+		want    []string
+	}{
+		{
+			dialect: postgresDialect,
+			want: []string{
+				"SELECT COUNT(*) FROM releases WHERE owner = 'TILLER' AND name LIKE $1 ESCAPE '!'",
+				"SELECT key, name, chart_name, last_deployed FROM releases WHERE owner = 'TILLER' AND name LIKE $1 ESCAPE '!' AND name = $2 ORDER BY name DESC, key DESC",
+				"SELECT key, name FROM releases WHERE owner = 'TILLER' AND name LIKE $1 ESCAPE '!' AND (name > $2 OR (name = $3 AND key >= $4)) ORDER BY name ASC, key ASC LIMIT $5",
+			},
+		},
+		{
+			dialect: mysqlDialect,
+			want: []string{
+				"SELECT COUNT(*) FROM releases WHERE owner = 'TILLER' AND name LIKE ? ESCAPE '!' AND CAST(name AS BINARY) LIKE ? ESCAPE '!'",
+				"SELECT `key`, name, chart_name, last_deployed FROM releases WHERE owner = 'TILLER' AND name LIKE ? ESCAPE '!' AND CAST(name AS BINARY) LIKE ? ESCAPE '!' AND name = ? ORDER BY name DESC, `key` DESC",
+				"SELECT `key`, name FROM releases WHERE owner = 'TILLER' AND name LIKE ? ESCAPE '!' AND CAST(name AS BINARY) LIKE ? ESCAPE '!' AND (name > ? OR (name = ? AND `key` >= ?)) ORDER BY name ASC, `key` ASC LIMIT ?",
+			},
+		},
+	} {
+		sqlDriver, recorder := newTestFixtureSQLRecorder(tt.dialect)
+		recorder.answer = func(query string) ([]string, [][]sqldriver.Value) {
+			switch {
+			case strings.HasPrefix(query, "SELECT COUNT(*)"):
+				return []string{"count"}, [][]sqldriver.Value{{int64(3)}}
+			case strings.Contains(query, "chart_name, last_deployed"):
+				return []string{"key", "name", "chart_name", "last_deployed"}, [][]sqldriver.Value{{"web-b.v1", "web-b", "web", int64(0)}}
+			}
+			return nil, nil
+		}
+
+		if _, err := sqlDriver.ListPage(ListOptions{Filter: "^web-", SortBy: ListSortByName, Offset: "web-b", Limit: 2}); err != nil {
+			t.Errorf("%s: failed to list: %v", tt.dialect, err)
+		}
+		if got := recorder.Statements(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected statements\n%s\ngot\n%s", tt.dialect, strings.Join(tt.want, "\n"), strings.Join(got, "\n"))
+		}
+	}
+}
+
+func TestSQLiteQueryColumns(t *testing.T) {
+	sqlDriver, cleanup := newTestFixtureSQLite(t, listFixture...)
+	defer cleanup()
+
+	for label, value := range map[string]string{
+		"NAMESPACE":     "other",
+		"CHART_NAME":    "alpha",
+		"CHART_VERSION": "0.1.0",
+		"APP_VERSION":   "1.0",
+	} {
+		if _, err := sqlDriver.Query(map[string]string{label: value}); err != nil {
+			t.Errorf("Failed to query by %s: %v", label, err)
+		}
+	}
+
+	results, err := sqlDriver.Query(map[string]string{"NAMESPACE": "other"})
+	if err != nil {
+		t.Fatalf("Failed to query by namespace: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "c-three" {
+		t.Errorf("Expected release c-three, got %v", results)
+	}
+}
+
+func TestSQLiteBackfillColumns(t *testing.T) {
+	sqlDriver, cleanup := newTestFixtureSQLite(t)
+	defer cleanup()
+
+	// store a release the way it was before the columns were added
+	rel := listStub("c-three", "other", "mid", 20, rspb.Status_DEPLOYED)
+	body, _ := encodeRelease(rel)
+	if _, err := sqlDriver.db.Exec("INSERT INTO releases (key, body, name, version, status, owner, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		testKey(rel.Name, rel.Version), body, rel.Name, rel.Version, "DEPLOYED", "TILLER", time.Now().Unix()); err != nil {
+		t.Fatalf("Failed to insert release: %v", err)
+	}
+
+	if err := sqlDriver.backfillColumns(); err != nil {
+		t.Fatalf("Failed to backfill columns: %v", err)
+	}
+	page, err := sqlDriver.ListPage(ListOptions{Namespace: "other", SortBy: ListSortByChartName})
+	if err != nil {
+		t.Fatalf("Failed to list releases: %v", err)
+	}
+	if len(page.Releases) != 1 || page.Releases[0].Name != rel.Name {
+		t.Errorf("Expected release %s, got %v", rel.Name, page.Releases)
+	}
+}
+
+func TestSQLDialectQueries(t *testing.T) {
+	for _, tt := range []struct {
+		dialect string
+		want    []string
+	}{
+		{
+			dialect: postgresDialect,
+			want: []string{
+				"SELECT body FROM releases WHERE key = $1",
+				"INSERT INTO releases (key, body, name, version, status, owner, created_at, namespace, chart_name, chart_version, app_version, last_deployed) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
+				"UPDATE releases SET body=$1, name=$2, version=$3, status=$4, owner=$5, modified_at=$6, namespace=$7, chart_name=$8, chart_version=$9, app_version=$10, last_deployed=$11 WHERE key=$12",
+				"SELECT body FROM releases WHERE key = $1",
+				"SELECT name, holder, expires_at FROM release_locks WHERE name = $1 FOR UPDATE",
+				"INSERT INTO release_locks (name, holder, expires_at) VALUES ($1, $2, $3)",
+				"DELETE FROM release_locks WHERE name = $1 AND holder = $2",
+			},
+		},
+		{
+			dialect: mysqlDialect,
+			want: []string{
+				"SELECT body FROM releases WHERE `key` = ?",
+				"INSERT INTO releases (`key`, body, name, version, status, owner, created_at, namespace, chart_name, chart_version, app_version, last_deployed) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				"UPDATE releases SET body=?, name=?, version=?, status=?, owner=?, modified_at=?, namespace=?, chart_name=?, chart_version=?, app_version=?, last_deployed=? WHERE `key`=?",
+				"SELECT body FROM releases WHERE `key` = ?",
+				"SELECT name, holder, expires_at FROM release_locks WHERE name = ? FOR UPDATE",
+				"INSERT INTO release_locks (name, holder, expires_at) VALUES (?, ?, ?)",
+				"DELETE FROM release_locks WHERE name = ? AND holder = ?",
+			},
+		},
+	} {
+		sqlDriver, recorder := newTestFixtureSQLRecorder(tt.dialect)
+		rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+		key := testKey(rel.Name, rel.Version)
+
+		// the recorded database holds no rows, so the reads find nothing
+		sqlDriver.Get(key)
+		if err := sqlDriver.Create(key, rel); err != nil {
+			t.Errorf("%s: failed to create release: %v", tt.dialect, err)
+		}
+		if err := sqlDriver.Update(key, rel); err != nil {
+			t.Errorf("%s: failed to update release: %v", tt.dialect, err)
+		}
+		sqlDriver.Delete(key)
+		if err := sqlDriver.Lock(rel.Name, "tiller", time.Minute); err != nil {
+			t.Errorf("%s: failed to lock release: %v", tt.dialect, err)
+		}
+		if err := sqlDriver.Unlock(rel.Name, "tiller"); err != nil {
+			t.Errorf("%s: failed to unlock release: %v", tt.dialect, err)
+		}
+
+		got := recorder.Statements()
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected statements\n%s\ngot\n%s", tt.dialect, strings.Join(tt.want, "\n"), strings.Join(got, "\n"))
+		}
+	}
+}
+
+func TestSQLMigrations(t *testing.T) {
+	ids := func(migrations []*migrate.Migration) (ids []string) {
+		for _, m := range migrations {
+			ids = append(ids, m.Id)
+		}
+		return ids
+	}
+	statements := func(migrations []*migrate.Migration) string {
+		var all []string
+		for _, m := range migrations {
+			all = append(all, m.Up...)
+			all = append(all, m.Down...)
+		}
+		return strings.Join(all, "\n")
+	}
+
+	want := []string{"init", "locks", "release_columns"}
+	for _, dialect := range []string{postgresDialect, mysqlDialect, sqliteDialect} {
+		if got := ids(sqlMigrations(dialect)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected migrations %v, got %v", dialect, want, got)
+		}
+	}
+
+	postgres := statements(sqlMigrations(postgresDialect))
+	for _, s := range []string{
+		"key VARCHAR(67) PRIMARY KEY",
+		"CREATE INDEX ON releases (key);",
+		"ADD COLUMN namespace TEXT NOT NULL",
+		"DROP INDEX releases_name_idx;",
+	} {
+		if !strings.Contains(postgres, s) {
+			t.Errorf("postgres: expected the migrations to contain %q", s)
+		}
+	}
+	if strings.Contains(postgres, "`") || strings.Contains(postgres, "ENGINE") {
+		t.Errorf("postgres: expected no MySQL syntax in the migrations:\n%s", postgres)
+	}
+
+	mysql := sqlMigrations(mysqlDialect)
+	for _, m := range mysql {
+		for _, s := range m.Up {
+			if strings.Contains(s, "CREATE TABLE") && !strings.Contains(s, "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4") {
+				t.Errorf("mysql: expected InnoDB and utf8mb4 in %q", s)
+			}
+		}
+	}
+	for _, s := range []string{
+		"`key` VARCHAR(67) PRIMARY KEY",
+		"ADD COLUMN namespace VARCHAR(191) NOT NULL",
+		"ADD COLUMN chart_version VARCHAR(255) NOT NULL",
+		"DROP INDEX releases_name_idx ON releases;",
+	} {
+		if !strings.Contains(statements(mysql), s) {
+			t.Errorf("mysql: expected the migrations to contain %q", s)
+		}
+	}
+	if strings.Contains(statements(mysql), " TEXT ") {
+		t.Errorf("mysql: expected no unindexable TEXT column in the migrations")
 	}
 }
@@ -24,14 +24,28 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/encryption"
 )
 
 var b64 = base64.StdEncoding
 
 var magicGzip = []byte{0x1f, 0x8b, 0x08}
 
+// keyProvider wraps the data keys encrypting the releases encoded by
+// encodeRelease. Releases are stored unencrypted when it is nil.
+var keyProvider encryption.KeyProvider
+
+// SetKeyProvider enables the encryption at rest of the releases stored by the
+// drivers, with data keys wrapped by kp. It must be called before the drivers
+// are used. Releases stored unencrypted remain readable; a nil kp disables the
+// encryption of the releases stored afterwards.
+func SetKeyProvider(kp encryption.KeyProvider) {
+	keyProvider = kp
+}
+
 // encodeRelease encodes a release returning a base64 encoded
 // gzipped binary protobuf encoding representation, or error.
+// The gzipped encoding is encrypted when a key provider is set.
 func encodeRelease(rls *rspb.Release) (string, error) {
 	b, err := proto.Marshal(rls)
 	if err != nil {
@@ -47,7 +61,14 @@ func encodeRelease(rls *rspb.Release) (string, error) {
 	}
 	w.Close()
 
-	return b64.EncodeToString(buf.Bytes()), nil
+	b = buf.Bytes()
+	if keyProvider != nil {
+		if b, err = encryption.Encrypt(keyProvider, b); err != nil {
+			return "", err
+		}
+	}
+
+	return b64.EncodeToString(b), nil
 }
 
 // decodeRelease decodes the bytes in data into a release
@@ -61,10 +82,19 @@ func decodeRelease(data string) (*rspb.Release, error) {
 		return nil, err
 	}
 
+	// Releases stored before encryption was enabled are not
+	// encrypted, so we only decrypt if the encryption magic
+	// header is found
+	if encryption.IsEncrypted(b) {
+		if b, err = encryption.Decrypt(keyProvider, b); err != nil {
+			return nil, err
+		}
+	}
+
 	// For backwards compatibility with releases that were stored before
 	// compression was introduced we skip decompression if the
 	// gzip magic header is not found
-	if bytes.Equal(b[0:3], magicGzip) {
+	if bytes.HasPrefix(b, magicGzip) {
 		r, err := gzip.NewReader(bytes.NewReader(b))
 		if err != nil {
 			return nil, err
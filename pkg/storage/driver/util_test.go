@@ -0,0 +1,79 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"testing"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/encryption"
+)
+
+// xorKeyProvider is a KeyProvider for tests only.
+type xorKeyProvider struct{}
+
+func (xorKeyProvider) WrapKey(key []byte) ([]byte, error)       { return xor(key), nil }
+func (xorKeyProvider) UnwrapKey(wrapped []byte) ([]byte, error) { return xor(wrapped), nil }
+
+func xor(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[i] = b[i] ^ 0x5a
+	}
+	return out
+}
+
+func TestEncodeReleaseEncrypted(t *testing.T) {
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+
+	plain, err := encodeRelease(rel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetKeyProvider(xorKeyProvider{})
+	defer SetKeyProvider(nil)
+
+	enc, err := encodeRelease(rel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := b64.DecodeString(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encryption.IsEncrypted(b) {
+		t.Fatalf("Expected release to be encrypted")
+	}
+
+	// both encrypted and unencrypted releases decode
+	for _, data := range []string{enc, plain} {
+		got, err := decodeRelease(data)
+		if err != nil {
+			t.Fatalf("Failed to decode release: %s", err)
+		}
+		if !shallowReleaseEqual(rel, got) {
+			t.Errorf("Expected {%q}, got {%q}", rel, got)
+		}
+	}
+
+	// encrypted releases can't be decoded without the key provider
+	SetKeyProvider(nil)
+	if _, err := decodeRelease(enc); err == nil {
+		t.Errorf("Expected an error when decoding without a key provider")
+	}
+}
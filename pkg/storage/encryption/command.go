@@ -0,0 +1,72 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption // import "k8s.io/helm/pkg/storage/encryption"
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var _ KeyProvider = (*CommandKeyProvider)(nil)
+
+// CommandKeyProvider delegates wrapping and unwrapping of data keys to an
+// external command, typically a wrapper around a KMS client.
+//
+// The command is run with "wrap" or "unwrap" appended to its arguments. It
+// reads the base64 encoded key on its standard input and must write the base64
+// encoded result on its standard output. Key rotation is left to the command.
+type CommandKeyProvider struct {
+	Command string
+	Args    []string
+}
+
+// NewCommandKeyProvider returns a CommandKeyProvider running command with args.
+func NewCommandKeyProvider(command string, args ...string) *CommandKeyProvider {
+	return &CommandKeyProvider{Command: command, Args: args}
+}
+
+// WrapKey runs the command to encrypt key.
+func (p *CommandKeyProvider) WrapKey(key []byte) ([]byte, error) {
+	return p.run("wrap", key)
+}
+
+// UnwrapKey runs the command to decrypt a key returned by WrapKey.
+func (p *CommandKeyProvider) UnwrapKey(wrapped []byte) ([]byte, error) {
+	return p.run("unwrap", wrapped)
+}
+
+func (p *CommandKeyProvider) run(op string, in []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	args := append(append([]string{}, p.Args...), op)
+	cmd := exec.Command(p.Command, args...)
+	cmd.Stdin = strings.NewReader(base64.StdEncoding.EncodeToString(in))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s %s: %s: %s", p.Command, op, err, strings.TrimSpace(stderr.String()))
+	}
+
+	out, err := base64.StdEncoding.DecodeString(strings.TrimSpace(stdout.String()))
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: invalid output: %s", p.Command, op, err)
+	}
+	return out, nil
+}
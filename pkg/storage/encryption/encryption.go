@@ -0,0 +1,150 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package encryption implements envelope encryption of release records at rest.
+
+Each record is encrypted with its own randomly generated AES-256-GCM data key.
+The data key is in turn wrapped by a KeyProvider, which holds or has access to
+the key encryption keys, and stored alongside the ciphertext. Rotating the key
+encryption keys therefore never requires re-encrypting the stored records.
+*/
+package encryption // import "k8s.io/helm/pkg/storage/encryption"
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// magic prefixes encrypted records. The leading zero byte can start neither a
+// gzip stream nor a protobuf message, so encrypted records can be told apart
+// from the records stored before encryption was enabled. The last byte is the
+// version of the format.
+var magic = []byte{0x00, 'h', 'e', 0x01}
+
+// dataKeySize is the size in bytes of the AES-256 data keys.
+const dataKeySize = 32
+
+// ErrNoKeyProvider is returned when decrypting a record without a KeyProvider.
+var ErrNoKeyProvider = errors.New("release is encrypted but no key provider is configured")
+
+// KeyProvider wraps and unwraps the data keys used to encrypt records.
+type KeyProvider interface {
+	// WrapKey encrypts a data key.
+	WrapKey(key []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key returned by WrapKey, including one
+	// wrapped before the provider's keys were rotated.
+	UnwrapKey(wrapped []byte) ([]byte, error)
+}
+
+// IsEncrypted reports whether data was produced by Encrypt.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// Encrypt encrypts data with a new data key wrapped by kp.
+//
+// The result is laid out as the magic header, the length of the wrapped key
+// as a big endian uint32, the wrapped key, the GCM nonce and the ciphertext.
+func Encrypt(kp KeyProvider, data []byte) ([]byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	wrapped, err := kp.WrapKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %s", err)
+	}
+
+	sealed, err := seal(key, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(magic)
+	binary.Write(&buf, binary.BigEndian, uint32(len(wrapped)))
+	buf.Write(wrapped)
+	buf.Write(sealed)
+	return buf.Bytes(), nil
+}
+
+// Decrypt decrypts data produced by Encrypt, unwrapping its data key with kp.
+func Decrypt(kp KeyProvider, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, errors.New("data is not encrypted")
+	}
+	if kp == nil {
+		return nil, ErrNoKeyProvider
+	}
+
+	data = data[len(magic):]
+	if len(data) < 4 {
+		return nil, errors.New("encrypted data is truncated")
+	}
+	n := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint32(len(data)) < n {
+		return nil, errors.New("encrypted data is truncated")
+	}
+
+	key, err := kp.UnwrapKey(data[:n])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %s", err)
+	}
+	return open(key, data[n:])
+}
+
+// seal encrypts data with AES-GCM under key, returning the nonce followed by
+// the ciphertext.
+func seal(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// open decrypts data returned by seal.
+func open(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("encrypted data is truncated")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func writeKeyFile(t *testing.T, path string, ids ...string) {
+	var buf bytes.Buffer
+	buf.WriteString("# test keys\n")
+	for _, id := range ids {
+		key := bytes.Repeat([]byte(id[:1]), dataKeySize)
+		fmt.Fprintf(&buf, "%s:%s\n", id, base64.StdEncoding.EncodeToString(key))
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func tempKeyFile(t *testing.T, ids ...string) (string, func()) {
+	dir, err := ioutil.TempDir("", "helm-encryption-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "keys")
+	writeKeyFile(t, path, ids...)
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	path, cleanup := tempKeyFile(t, "a")
+	defer cleanup()
+
+	kp, err := NewFileKeyProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("release data")
+	enc, err := Encrypt(kp, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(enc) {
+		t.Errorf("Expected encrypted data to start with the magic header")
+	}
+	if bytes.Contains(enc, data) {
+		t.Errorf("Expected data to be encrypted, got %q", enc)
+	}
+
+	dec, err := Decrypt(kp, enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dec, data) {
+		t.Errorf("Expected %q, got %q", data, dec)
+	}
+
+	// tampering with the ciphertext is detected
+	enc[len(enc)-1] ^= 0xff
+	if _, err := Decrypt(kp, enc); err == nil {
+		t.Errorf("Expected an error when decrypting tampered data")
+	}
+
+	if _, err := Decrypt(nil, enc); err != ErrNoKeyProvider {
+		t.Errorf("Expected ErrNoKeyProvider, got %v", err)
+	}
+	if _, err := Decrypt(kp, data); err == nil {
+		t.Errorf("Expected an error when decrypting unencrypted data")
+	}
+}
+
+func TestFileKeyProviderRotation(t *testing.T) {
+	path, cleanup := tempKeyFile(t, "a")
+	defer cleanup()
+
+	kp, err := NewFileKeyProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := Encrypt(kp, []byte("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rotate: the new key comes first, the old one is kept for decryption
+	writeKeyFile(t, path, "b", "a")
+	os.Chtimes(path, time.Now(), time.Now().Add(time.Minute))
+
+	current, err := Encrypt(kp, []byte("current"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, enc := range [][]byte{old, current} {
+		if _, err := Decrypt(kp, enc); err != nil {
+			t.Errorf("Failed to decrypt after rotation: %s", err)
+		}
+	}
+
+	// once the old key is removed, records encrypted with it can't be read
+	writeKeyFile(t, path, "b")
+	os.Chtimes(path, time.Now(), time.Now().Add(2*time.Minute))
+	if _, err := Decrypt(kp, old); err == nil {
+		t.Errorf("Expected an error when decrypting with a removed key")
+	}
+	if _, err := Decrypt(kp, current); err != nil {
+		t.Errorf("Failed to decrypt with the current key: %s", err)
+	}
+}
+
+func TestParseKeysErrors(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(make([]byte, dataKeySize))
+	var tests = []struct {
+		desc string
+		data string
+	}{
+		{"empty file", "# no keys\n"},
+		{"missing id", ":" + key},
+		{"invalid base64", "a:not base64"},
+		{"short key", "a:" + base64.StdEncoding.EncodeToString([]byte("short"))},
+		{"duplicate id", "a:" + key + "\na:" + key},
+	}
+
+	for _, tt := range tests {
+		if _, _, err := parseKeys([]byte(tt.data)); err == nil {
+			t.Errorf("%s: expected an error", tt.desc)
+		}
+	}
+}
+
+func TestCommandKeyProvider(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	// an identity "KMS" echoing the key back
+	kp := NewCommandKeyProvider("sh", "-c", "cat", "kms")
+
+	data := []byte("release data")
+	enc, err := Encrypt(kp, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := Decrypt(kp, enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dec, data) {
+		t.Errorf("Expected %q, got %q", data, dec)
+	}
+
+	failing := NewCommandKeyProvider("sh", "-c", "echo denied >&2; exit 1", "kms")
+	if _, err := Encrypt(failing, data); err == nil {
+		t.Errorf("Expected an error when the command fails")
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption // import "k8s.io/helm/pkg/storage/encryption"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ KeyProvider = (*FileKeyProvider)(nil)
+
+// FileKeyProvider wraps data keys with AES-256-GCM keys read from a local file.
+//
+// Each non-empty line of the file holds a key as "<id>:<base64 key>"; lines
+// starting with '#' are ignored. Data keys are wrapped with the first key of
+// the file and unwrapped with the key whose id they were wrapped with, so keys
+// are rotated by adding a new key at the top of the file and removing old keys
+// once no record uses them anymore. The file is read again whenever it changes.
+type FileKeyProvider struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	ids     []string
+	keys    map[string][]byte
+}
+
+// NewFileKeyProvider returns a FileKeyProvider using the keys in the file at path.
+func NewFileKeyProvider(path string) (*FileKeyProvider, error) {
+	p := &FileKeyProvider{path: path}
+	if _, _, err := p.load(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// WrapKey encrypts key with the first key of the file. The result starts with
+// the length of the key id and the key id.
+func (p *FileKeyProvider) WrapKey(key []byte) ([]byte, error) {
+	ids, keys, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	id := ids[0]
+	sealed, err := seal(keys[id], key)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{byte(len(id))}, id...), sealed...), nil
+}
+
+// UnwrapKey decrypts a key returned by WrapKey.
+func (p *FileKeyProvider) UnwrapKey(wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 1 || len(wrapped) < 1+int(wrapped[0]) {
+		return nil, errors.New("wrapped key is truncated")
+	}
+	id := string(wrapped[1 : 1+int(wrapped[0])])
+
+	_, keys, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	kek, ok := keys[id]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found in %s", id, p.path)
+	}
+	return open(kek, wrapped[1+int(wrapped[0]):])
+}
+
+// load returns the key ids, in file order, and the keys of the file, reading
+// it again if it has been modified since it was last read.
+func (p *FileKeyProvider) load() ([]string, map[string][]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fi, err := os.Stat(p.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.keys != nil && fi.ModTime().Equal(p.modTime) {
+		return p.ids, p.keys, nil
+	}
+
+	data, err := ioutil.ReadFile(p.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids, keys, err := parseKeys(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid key file %s: %s", p.path, err)
+	}
+	p.modTime, p.ids, p.keys = fi.ModTime(), ids, keys
+	return ids, keys, nil
+}
+
+func parseKeys(data []byte) ([]string, map[string][]byte, error) {
+	var ids []string
+	keys := map[string][]byte{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || len(parts[0]) > 255 {
+			return nil, nil, fmt.Errorf("line %d: expected <id>:<base64 key>", n)
+		}
+		id := parts[0]
+		key, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %s", n, err)
+		}
+		if len(key) != dataKeySize {
+			return nil, nil, fmt.Errorf("line %d: key %q is %d bytes long, expected %d", n, id, len(key), dataKeySize)
+		}
+		if _, ok := keys[id]; ok {
+			return nil, nil, fmt.Errorf("line %d: duplicate key %q", n, id)
+		}
+		ids = append(ids, id)
+		keys[id] = key
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil, errors.New("no key found")
+	}
+	return ids, keys, nil
+}
@@ -24,4 +24,43 @@ var (
 	ErrReleaseExists = func(release string) error { return fmt.Errorf("release: %q already exists", release) }
 	// ErrInvalidKey indicates that a release key could not be parsed.
 	ErrInvalidKey = func(release string) error { return fmt.Errorf("release: %q invalid key", release) }
+	// ErrReleaseLocked indicates that another operation holds the lock of a release.
+	ErrReleaseLocked = func(release string) error { return &ReleaseLockedError{Release: release} }
+	// ErrNoDeployedReleases indicates that no revision of a release is deployed.
+	ErrNoDeployedReleases = func(release string) error { return &NoDeployedReleasesError{Release: release} }
+	// ErrReleaseConflict indicates that a release was modified concurrently.
+	ErrReleaseConflict = func(release string) error {
+		return fmt.Errorf("release: %q was modified by another operation", release)
+	}
 )
+
+// ReleaseLockedError is the error returned by ErrReleaseLocked.
+type ReleaseLockedError struct {
+	Release string
+}
+
+func (e *ReleaseLockedError) Error() string {
+	return fmt.Sprintf("release: %q is locked, another operation is in progress", e.Release)
+}
+
+// IsReleaseLocked reports whether err indicates that a release is locked.
+func IsReleaseLocked(err error) bool {
+	_, ok := err.(*ReleaseLockedError)
+	return ok
+}
+
+// NoDeployedReleasesError is the error returned by ErrNoDeployedReleases.
+type NoDeployedReleasesError struct {
+	Release string
+}
+
+func (e *NoDeployedReleasesError) Error() string {
+	return fmt.Sprintf("%q has no deployed releases", e.Release)
+}
+
+// IsNoDeployedReleases reports whether err indicates that no revision of a
+// release is deployed.
+func IsNoDeployedReleases(err error) bool {
+	_, ok := err.(*NoDeployedReleasesError)
+	return ok
+}
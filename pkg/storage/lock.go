@@ -0,0 +1,130 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/helm/pkg/storage/driver"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
+)
+
+// DefaultLockTTL is the time after which the lock of a release held by a
+// Tiller that stopped renewing it, e.g. because it crashed, can be taken over.
+const DefaultLockTTL = 2 * time.Minute
+
+// locks tracks the releases locked by a Storage.
+type locks struct {
+	mu     sync.Mutex
+	held   map[string]bool
+	holder string
+}
+
+// LockRelease locks the named release against concurrent operations. The
+// lock is held until the returned function is called.
+//
+// Operations from this Tiller are serialized in memory. If the driver is a
+// driver.Locker, the lock is also taken in storage, so that operations from
+// every Tiller sharing the storage are serialized, and renewed in the
+// background until released. ErrReleaseLocked is returned if the release is
+// already locked.
+func (s *Storage) LockRelease(name string) (func(), error) {
+	s.locks.mu.Lock()
+	if s.locks.held[name] {
+		s.locks.mu.Unlock()
+		return nil, storageerrors.ErrReleaseLocked(name)
+	}
+	if s.locks.held == nil {
+		s.locks.held = map[string]bool{}
+	}
+	if s.locks.holder == "" {
+		s.locks.holder = newLockHolder()
+	}
+	s.locks.held[name] = true
+	holder := s.locks.holder
+	s.locks.mu.Unlock()
+
+	release := func() {
+		s.locks.mu.Lock()
+		delete(s.locks.held, name)
+		s.locks.mu.Unlock()
+	}
+
+	locker, ok := s.Driver.(driver.Locker)
+	if !ok {
+		return release, nil
+	}
+
+	ttl := s.LockTTL
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+	s.Log("locking release %q", name)
+	if err := locker.Lock(name, holder, ttl); err != nil {
+		release()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := locker.Lock(name, holder, ttl); err != nil {
+					s.Log("failed to renew lock of release %q: %s", name, err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			s.Log("unlocking release %q", name)
+			if err := locker.Unlock(name, holder); err != nil {
+				s.Log("failed to unlock release %q: %s", name, err)
+			}
+			release()
+		})
+	}, nil
+}
+
+// isLocked reports whether the named release is locked by this Tiller.
+func (s *Storage) isLocked(name string) bool {
+	s.locks.mu.Lock()
+	defer s.locks.mu.Unlock()
+	return s.locks.held[name]
+}
+
+// newLockHolder returns an identifier of the holder of the locks taken by a
+// Storage, unique across the Tillers sharing the storage.
+func newLockHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "tiller"
+	}
+	return fmt.Sprintf("%s-%x", host, rand.New(rand.NewSource(time.Now().UnixNano())).Int63())
+}
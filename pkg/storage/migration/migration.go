@@ -0,0 +1,198 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package migration copies release records between storage drivers.
+
+Every revision of every release is copied under the same key, so release
+versions and statuses, and the labels the drivers derive from them, are
+preserved. Each copy is read back and compared with the original before the
+next one starts. Migrated keys are recorded in an optional checkpoint file,
+so that an interrupted migration resumes where it stopped.
+*/
+package migration // import "k8s.io/helm/pkg/storage/migration"
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+// Options control a migration.
+type Options struct {
+	// DryRun only reports the releases that would be migrated.
+	DryRun bool
+	// DeleteSource deletes each release from the source driver once its
+	// copy has been verified.
+	DeleteSource bool
+	// Checkpoint is the path of a file recording the keys of the migrated
+	// releases. Releases it lists are skipped. Ignored if empty.
+	Checkpoint string
+	// Log receives a line for every release.
+	Log func(string, ...interface{})
+}
+
+// Result summarizes a migration.
+type Result struct {
+	// Migrated is the number of releases copied, or that would be copied in
+	// a dry run.
+	Migrated int
+	// Skipped is the number of releases already migrated, either listed in
+	// the checkpoint or found identical in the destination.
+	Skipped int
+	// Deleted is the number of releases deleted from the source.
+	Deleted int
+}
+
+// Migrate copies every release revision stored by src into dst.
+//
+// A release already present in dst is left untouched if it is identical to
+// the source, and stops the migration with an error otherwise.
+func Migrate(src, dst driver.Driver, opts Options) (*Result, error) {
+	log := opts.Log
+	if log == nil {
+		log = func(string, ...interface{}) {}
+	}
+
+	done, err := readCheckpoint(opts.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	rels, err := src.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases from %s: %s", src.Name(), err)
+	}
+	sort.Slice(rels, func(i, j int) bool {
+		if rels[i].Name != rels[j].Name {
+			return rels[i].Name < rels[j].Name
+		}
+		return rels[i].Version < rels[j].Version
+	})
+
+	var checkpoint *os.File
+	if opts.Checkpoint != "" && !opts.DryRun {
+		if checkpoint, err = os.OpenFile(opts.Checkpoint, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err != nil {
+			return nil, err
+		}
+		defer checkpoint.Close()
+	}
+
+	res := &Result{}
+	for _, rls := range rels {
+		key := makeKey(rls.Name, rls.Version)
+		if done[key] {
+			log("skipping %s: listed in checkpoint", key)
+			res.Skipped++
+			continue
+		}
+		if opts.DryRun {
+			log("would migrate %s (%s)", key, rls.Info.Status.Code)
+			res.Migrated++
+			continue
+		}
+
+		copied, err := copyRelease(dst, key, rls)
+		if err != nil {
+			return res, err
+		}
+		if copied {
+			log("migrated %s (%s)", key, rls.Info.Status.Code)
+			res.Migrated++
+		} else {
+			log("skipping %s: already in %s", key, dst.Name())
+			res.Skipped++
+		}
+
+		if opts.DeleteSource {
+			if _, err := src.Delete(key); err != nil {
+				return res, fmt.Errorf("failed to delete %s from %s: %s", key, src.Name(), err)
+			}
+			log("deleted %s from %s", key, src.Name())
+			res.Deleted++
+		}
+
+		if checkpoint != nil {
+			if _, err := fmt.Fprintln(checkpoint, key); err != nil {
+				return res, fmt.Errorf("failed to write checkpoint: %s", err)
+			}
+		}
+	}
+	return res, nil
+}
+
+// copyRelease stores rls in dst under key and verifies the copy. It returns
+// false if an identical release was already stored.
+func copyRelease(dst driver.Driver, key string, rls *rspb.Release) (bool, error) {
+	if existing, err := dst.Get(key); err == nil {
+		if !proto.Equal(existing, rls) {
+			return false, fmt.Errorf("%s already exists in %s and differs from the source", key, dst.Name())
+		}
+		return false, nil
+	}
+
+	if err := dst.Create(key, rls); err != nil {
+		return false, fmt.Errorf("failed to copy %s to %s: %s", key, dst.Name(), err)
+	}
+
+	got, err := dst.Get(key)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify %s in %s: %s", key, dst.Name(), err)
+	}
+	if !proto.Equal(got, rls) {
+		return false, fmt.Errorf("failed to verify %s in %s: copy differs from the source", key, dst.Name())
+	}
+	return true, nil
+}
+
+// readCheckpoint returns the keys listed in the checkpoint file at path. A
+// missing file lists no key.
+func readCheckpoint(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+	if path == "" {
+		return done, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if key := strings.TrimSpace(scanner.Text()); key != "" {
+			done[key] = true
+		}
+	}
+	return done, scanner.Err()
+}
+
+// makeKey concatenates a release name and version into the key used by the
+// storage drivers, see storage.makeKey.
+func makeKey(rlsname string, version int32) string {
+	return fmt.Sprintf("%s.v%d", rlsname, version)
+}
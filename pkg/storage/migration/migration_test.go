@@ -0,0 +1,180 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func releaseStub(name string, vers int32, code rspb.Status_Code) *rspb.Release {
+	return &rspb.Release{
+		Name:      name,
+		Version:   vers,
+		Namespace: "default",
+		Info:      &rspb.Info{Status: &rspb.Status{Code: code}},
+	}
+}
+
+func newSource(t *testing.T) *driver.Memory {
+	mem := driver.NewMemory()
+	for _, rls := range []*rspb.Release{
+		releaseStub("angry-beaver", 1, rspb.Status_SUPERSEDED),
+		releaseStub("angry-beaver", 2, rspb.Status_DEPLOYED),
+		releaseStub("smug-pigeon", 1, rspb.Status_DELETED),
+	} {
+		if err := mem.Create(makeKey(rls.Name, rls.Version), rls); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return mem
+}
+
+func count(t *testing.T, d driver.Driver) int {
+	ls, err := d.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(ls)
+}
+
+func TestMigrate(t *testing.T) {
+	src, dst := newSource(t), driver.NewMemory()
+
+	res, err := Migrate(src, dst, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Migrated != 3 || res.Skipped != 0 || res.Deleted != 0 {
+		t.Errorf("Unexpected result %+v", res)
+	}
+
+	rls, err := dst.Get("angry-beaver.v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rls.Info.Status.Code != rspb.Status_DEPLOYED {
+		t.Errorf("Expected status DEPLOYED, got %s", rls.Info.Status.Code)
+	}
+	deployed, err := dst.Query(map[string]string{"NAME": "angry-beaver", "OWNER": "TILLER", "STATUS": "DEPLOYED"})
+	if err != nil || len(deployed) != 1 {
+		t.Errorf("Expected 1 deployed release to be found by labels, got %d (%v)", len(deployed), err)
+	}
+
+	// migrating again skips the identical releases
+	res, err = Migrate(src, dst, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Migrated != 0 || res.Skipped != 3 {
+		t.Errorf("Unexpected result %+v", res)
+	}
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	src, dst := newSource(t), driver.NewMemory()
+
+	res, err := Migrate(src, dst, Options{DryRun: true, DeleteSource: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Migrated != 3 || res.Deleted != 0 {
+		t.Errorf("Unexpected result %+v", res)
+	}
+	if n := count(t, dst); n != 0 {
+		t.Errorf("Expected no release in the destination, got %d", n)
+	}
+	if n := count(t, src); n != 3 {
+		t.Errorf("Expected 3 releases in the source, got %d", n)
+	}
+}
+
+func TestMigrateDeleteSource(t *testing.T) {
+	src, dst := newSource(t), driver.NewMemory()
+
+	res, err := Migrate(src, dst, Options{DeleteSource: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Migrated != 3 || res.Deleted != 3 {
+		t.Errorf("Unexpected result %+v", res)
+	}
+	if n := count(t, src); n != 0 {
+		t.Errorf("Expected no release left in the source, got %d", n)
+	}
+	if n := count(t, dst); n != 3 {
+		t.Errorf("Expected 3 releases in the destination, got %d", n)
+	}
+}
+
+func TestMigrateCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-migration-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	checkpoint := filepath.Join(dir, "checkpoint")
+
+	// pretend a previous run migrated the first revision
+	if err := ioutil.WriteFile(checkpoint, []byte("angry-beaver.v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	src, dst := newSource(t), driver.NewMemory()
+	res, err := Migrate(src, dst, Options{Checkpoint: checkpoint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Migrated != 2 || res.Skipped != 1 {
+		t.Errorf("Unexpected result %+v", res)
+	}
+	if _, err := dst.Get("angry-beaver.v1"); err == nil {
+		t.Errorf("Expected release listed in the checkpoint not to be copied")
+	}
+
+	b, err := ioutil.ReadFile(checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "angry-beaver.v1\nangry-beaver.v2\nsmug-pigeon.v1\n"
+	if string(b) != expect {
+		t.Errorf("Expected checkpoint %q, got %q", expect, b)
+	}
+}
+
+func TestMigrateConflict(t *testing.T) {
+	src, dst := newSource(t), driver.NewMemory()
+
+	other := releaseStub("angry-beaver", 1, rspb.Status_FAILED)
+	if err := dst.Create("angry-beaver.v1", other); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Migrate(src, dst, Options{DeleteSource: true})
+	if err == nil || !strings.Contains(err.Error(), "differs from the source") {
+		t.Fatalf("Expected a conflict error, got %v", err)
+	}
+	if n := count(t, src); n != 3 {
+		t.Errorf("Expected the source to be left untouched, got %d releases", n)
+	}
+}
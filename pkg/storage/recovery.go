@@ -0,0 +1,164 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"fmt"
+	"strings"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
+)
+
+// IsPending reports whether rls is the revision of an install, upgrade or
+// rollback in progress, or interrupted.
+func IsPending(rls *rspb.Release) bool {
+	switch rls.GetInfo().GetStatus().GetCode() {
+	case rspb.Status_PENDING_INSTALL, rspb.Status_PENDING_UPGRADE, rspb.Status_PENDING_ROLLBACK:
+		return true
+	}
+	return false
+}
+
+// ListPending returns the last revision of every release whose last revision
+// is pending.
+func (s *Storage) ListPending() ([]*rspb.Release, error) {
+	pending, err := s.ListFilterAny(
+		relutil.StatusFilter(rspb.Status_PENDING_INSTALL),
+		relutil.StatusFilter(rspb.Status_PENDING_UPGRADE),
+		relutil.StatusFilter(rspb.Status_PENDING_ROLLBACK),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	relutil.SortByName(pending)
+	var rels []*rspb.Release
+	for i, rls := range pending {
+		if i > 0 && pending[i-1].Name == rls.Name {
+			continue
+		}
+		last, err := s.Last(rls.Name)
+		if err != nil {
+			return nil, err
+		}
+		if IsPending(last) {
+			rels = append(rels, last)
+		}
+	}
+	return rels, nil
+}
+
+// RecoverPending looks for the releases left pending by operations that were
+// interrupted, e.g. by a crash of Tiller. A pending release is orphaned if no
+// operation holds its lock. The names of the pending releases still locked by
+// an operation, in progress or whose lock has not expired yet, are returned
+// apart.
+//
+// With repair, the orphaned releases are repaired for reason. Otherwise they
+// are only returned, to be repaired later on request. If names are given, only
+// the named releases are looked at.
+func (s *Storage) RecoverPending(repair bool, reason string, names ...string) (orphaned []*rspb.Release, locked []string, err error) {
+	pending, err := s.ListPending()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	only := map[string]bool{}
+	for _, name := range names {
+		only[name] = true
+	}
+	for _, rls := range pending {
+		if len(only) > 0 && !only[rls.Name] {
+			continue
+		}
+		unlock, err := s.LockRelease(rls.Name)
+		if err != nil {
+			if storageerrors.IsReleaseLocked(err) {
+				locked = append(locked, rls.Name)
+				continue
+			}
+			return orphaned, locked, err
+		}
+
+		// the operation may have ended since the release was listed
+		last, err := s.Last(rls.Name)
+		if err == nil && IsPending(last) {
+			if repair {
+				_, err = s.Repair(last, reason)
+			}
+			orphaned = append(orphaned, last)
+		}
+		unlock()
+		if err != nil {
+			return orphaned, locked, err
+		}
+	}
+	return orphaned, locked, nil
+}
+
+// Repair marks rls, the pending revision of an interrupted operation, as
+// failed for reason. If no revision of the release is deployed any more, the
+// last revision superseded before rls, failed revisions aside, is deployed
+// again and returned. The release must be locked.
+func (s *Storage) Repair(rls *rspb.Release, reason string) (*rspb.Release, error) {
+	if !IsPending(rls) {
+		return nil, fmt.Errorf("release %q revision %d is not pending", rls.Name, rls.Version)
+	}
+	operation := strings.ToLower(strings.TrimPrefix(rls.Info.Status.Code.String(), "PENDING_"))
+
+	s.Log("repairing release %q revision %d left pending by an interrupted %s", rls.Name, rls.Version, operation)
+	rls.Info.Status.Code = rspb.Status_FAILED
+	rls.Info.Description = fmt.Sprintf("%s interrupted: %s", strings.Title(operation), reason)
+	if err := s.Update(rls); err != nil {
+		return nil, err
+	}
+
+	deployed, err := s.DeployedAll(rls.Name)
+	if err != nil && !storageerrors.IsNoDeployedReleases(err) {
+		return nil, err
+	}
+	if len(deployed) > 0 {
+		return nil, nil
+	}
+
+	h, err := s.History(rls.Name)
+	if err != nil {
+		return nil, err
+	}
+	relutil.Reverse(h, relutil.SortByRevision)
+	for _, prev := range h {
+		if prev.Version >= rls.Version {
+			continue
+		}
+		switch prev.GetInfo().GetStatus().GetCode() {
+		case rspb.Status_FAILED:
+			continue
+		case rspb.Status_SUPERSEDED:
+		default:
+			return nil, nil
+		}
+		s.Log("restoring release %q revision %d as deployed", prev.Name, prev.Version)
+		prev.Info.Status.Code = rspb.Status_DEPLOYED
+		if err := s.Update(prev); err != nil {
+			return nil, err
+		}
+		return prev, nil
+	}
+	return nil, nil
+}
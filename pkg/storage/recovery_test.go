@@ -0,0 +1,123 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"reflect"
+	"testing"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func TestStorageRecoverPending(t *testing.T) {
+	storage := Init(driver.NewMemory())
+	storage.Log = t.Logf
+
+	for _, rls := range []*rspb.Release{
+		// an interrupted upgrade, the previous revision still deployed
+		ReleaseTestData{Name: "angry-bird", Version: 1, Status: rspb.Status_DEPLOYED}.ToRelease(),
+		ReleaseTestData{Name: "angry-bird", Version: 2, Status: rspb.Status_PENDING_UPGRADE}.ToRelease(),
+		// an interrupted rollback, after a failed upgrade
+		ReleaseTestData{Name: "happy-cats", Version: 1, Status: rspb.Status_SUPERSEDED}.ToRelease(),
+		ReleaseTestData{Name: "happy-cats", Version: 2, Status: rspb.Status_SUPERSEDED}.ToRelease(),
+		ReleaseTestData{Name: "happy-cats", Version: 3, Status: rspb.Status_FAILED}.ToRelease(),
+		ReleaseTestData{Name: "happy-cats", Version: 4, Status: rspb.Status_PENDING_ROLLBACK}.ToRelease(),
+		// an interrupted install
+		ReleaseTestData{Name: "lazy-ducks", Version: 1, Status: rspb.Status_PENDING_INSTALL}.ToRelease(),
+		// an install in progress
+		ReleaseTestData{Name: "busy-bees", Version: 1, Status: rspb.Status_PENDING_INSTALL}.ToRelease(),
+		// a pending revision followed by a newer one
+		ReleaseTestData{Name: "old-owls", Version: 1, Status: rspb.Status_PENDING_INSTALL}.ToRelease(),
+		ReleaseTestData{Name: "old-owls", Version: 2, Status: rspb.Status_DEPLOYED}.ToRelease(),
+	} {
+		assertErrNil(t.Fatal, storage.Create(rls), "Storing release")
+	}
+
+	unlock, err := storage.LockRelease("busy-bees")
+	assertErrNil(t.Fatal, err, "Locking release")
+	defer unlock()
+
+	// without repair, the orphaned releases are left as is
+	orphaned, locked, err := storage.RecoverPending(false, "")
+	assertErrNil(t.Fatal, err, "Recovering pending releases")
+	if names := releaseNames(orphaned); !reflect.DeepEqual(names, []string{"angry-bird", "happy-cats", "lazy-ducks"}) {
+		t.Errorf("Expected the orphaned releases to be angry-bird, happy-cats and lazy-ducks, got %v", names)
+	}
+	if !reflect.DeepEqual(locked, []string{"busy-bees"}) {
+		t.Errorf("Expected busy-bees to be locked, got %v", locked)
+	}
+	if rls, _ := storage.Last("lazy-ducks"); rls.Info.Status.Code != rspb.Status_PENDING_INSTALL {
+		t.Errorf("Expected lazy-ducks to be left pending, got %s", rls.Info.Status.Code)
+	}
+
+	orphaned, _, err = storage.RecoverPending(true, "Tiller restarted", "angry-bird")
+	assertErrNil(t.Fatal, err, "Repairing a pending release")
+	if names := releaseNames(orphaned); !reflect.DeepEqual(names, []string{"angry-bird"}) {
+		t.Errorf("Expected only angry-bird to be repaired, got %v", names)
+	}
+
+	orphaned, _, err = storage.RecoverPending(true, "Tiller restarted")
+	assertErrNil(t.Fatal, err, "Repairing pending releases")
+	if names := releaseNames(orphaned); !reflect.DeepEqual(names, []string{"happy-cats", "lazy-ducks"}) {
+		t.Errorf("Expected happy-cats and lazy-ducks to be repaired, got %v", names)
+	}
+
+	expect := []struct {
+		name    string
+		version int32
+		status  rspb.Status_Code
+		desc    string
+	}{
+		{"angry-bird", 1, rspb.Status_DEPLOYED, ""},
+		{"angry-bird", 2, rspb.Status_FAILED, "Upgrade interrupted: Tiller restarted"},
+		{"happy-cats", 2, rspb.Status_DEPLOYED, ""},
+		{"happy-cats", 3, rspb.Status_FAILED, ""},
+		{"happy-cats", 4, rspb.Status_FAILED, "Rollback interrupted: Tiller restarted"},
+		{"lazy-ducks", 1, rspb.Status_FAILED, "Install interrupted: Tiller restarted"},
+		{"busy-bees", 1, rspb.Status_PENDING_INSTALL, ""},
+		{"old-owls", 1, rspb.Status_PENDING_INSTALL, ""},
+	}
+	for _, e := range expect {
+		rls, err := storage.Get(e.name, e.version)
+		assertErrNil(t.Fatal, err, "Getting release")
+		if code := rls.Info.Status.Code; code != e.status {
+			t.Errorf("Expected %s revision %d to be %s, got %s", e.name, e.version, e.status, code)
+		}
+		if e.desc != "" && rls.Info.Description != e.desc {
+			t.Errorf("Expected %s revision %d description %q, got %q", e.name, e.version, e.desc, rls.Info.Description)
+		}
+	}
+}
+
+func TestStorageRepairNotPending(t *testing.T) {
+	storage := Init(driver.NewMemory())
+	rls := ReleaseTestData{Name: "angry-bird", Version: 1, Status: rspb.Status_DEPLOYED}.ToRelease()
+	assertErrNil(t.Fatal, storage.Create(rls), "Storing release")
+
+	if _, err := storage.Repair(rls, "repaired"); err == nil {
+		t.Error("Expected an error repairing a deployed release")
+	}
+}
+
+func releaseNames(rels []*rspb.Release) []string {
+	var names []string
+	for _, rls := range rels {
+		names = append(names, rls.Name)
+	}
+	return names
+}
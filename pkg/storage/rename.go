@@ -0,0 +1,53 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import "fmt"
+
+// Rename moves the revisions of the release oldName under newName. The
+// revisions are copied on the driver directly, so that a long history is not
+// pruned by the retention policy, and changes are not notified revision by
+// revision. The revisions of oldName are deleted once all of them are copied,
+// and the copies are deleted again if one revision cannot be copied.
+func (s *Storage) Rename(oldName, newName string) error {
+	history, err := s.History(oldName)
+	if err != nil {
+		return err
+	}
+
+	s.Log("moving %d revision(s) of %q to %q", len(history), oldName, newName)
+	var moved []string
+	for _, rls := range history {
+		m := *rls
+		m.Name = newName
+		key := makeKey(newName, m.Version)
+		if err := s.Driver.Create(key, &m); err != nil {
+			for _, key := range moved {
+				s.Driver.Delete(key)
+			}
+			return fmt.Errorf("cannot move revision %d of %q: %s", rls.Version, oldName, err)
+		}
+		moved = append(moved, key)
+	}
+
+	for _, rls := range history {
+		if _, err := s.Driver.Delete(makeKey(oldName, rls.Version)); err != nil {
+			s.Log("warning: cannot delete revision %d of %q after moving it to %q: %s", rls.Version, oldName, newName, err)
+		}
+	}
+	return nil
+}
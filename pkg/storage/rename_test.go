@@ -0,0 +1,76 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"testing"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func TestStorageRename(t *testing.T) {
+	storage := Init(driver.NewMemory())
+	for v := int32(1); v <= 3; v++ {
+		rls := ReleaseTestData{Name: "angry-bird", Version: v, Status: rspb.Status_SUPERSEDED}.ToRelease()
+		assertErrNil(t.Fatal, storage.Create(rls), "Storing release 'angry-bird'")
+	}
+
+	// the history is moved whole and silently
+	storage.MaxHistory = 2
+	var changes []Change
+	storage.Notify = func(change Change, _ *rspb.Release) { changes = append(changes, change) }
+	assertErrNil(t.Fatal, storage.Rename("angry-bird", "happy-bird"), "Rename")
+
+	h, err := storage.History("happy-bird")
+	assertErrNil(t.Fatal, err, "History")
+	if len(h) != 3 {
+		t.Errorf("Expected 3 revisions of happy-bird, got %d", len(h))
+	}
+	for _, rls := range h {
+		if rls.Name != "happy-bird" {
+			t.Errorf("Expected revision %d to be named happy-bird, got %s", rls.Version, rls.Name)
+		}
+	}
+	if h, _ := storage.History("angry-bird"); len(h) != 0 {
+		t.Errorf("Expected no revision left under angry-bird, got %d", len(h))
+	}
+	if len(changes) != 0 {
+		t.Errorf("Expected no change to be notified, got %v", changes)
+	}
+}
+
+func TestStorageRenameConflict(t *testing.T) {
+	storage := Init(driver.NewMemory())
+	for _, rls := range []*rspb.Release{
+		ReleaseTestData{Name: "angry-bird", Version: 1, Status: rspb.Status_SUPERSEDED}.ToRelease(),
+		ReleaseTestData{Name: "angry-bird", Version: 2, Status: rspb.Status_DEPLOYED}.ToRelease(),
+		ReleaseTestData{Name: "happy-bird", Version: 2, Status: rspb.Status_DEPLOYED}.ToRelease(),
+	} {
+		assertErrNil(t.Fatal, storage.Create(rls), "Storing release")
+	}
+
+	if err := storage.Rename("angry-bird", "happy-bird"); err == nil {
+		t.Fatal("Expected moving a revision over an existing one to fail")
+	}
+	if h, _ := storage.History("angry-bird"); len(h) != 2 {
+		t.Errorf("Expected the history of angry-bird to be kept, got %d revisions", len(h))
+	}
+	if h, _ := storage.History("happy-bird"); len(h) != 1 || h[0].Version != 2 {
+		t.Errorf("Expected the copies to be deleted, got %d revisions of happy-bird", len(h))
+	}
+}
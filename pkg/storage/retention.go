@@ -0,0 +1,236 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// Chart annotations overriding the retention policy of Tiller for the
+// releases of a chart. Durations are Go durations, e.g. "720h", or a number
+// of days, e.g. "30d".
+const (
+	// HistoryMaxAnnotation overrides the maximum number of revisions kept.
+	HistoryMaxAnnotation = "helm.sh/history-max"
+	// HistoryKeepNewerThanAnnotation overrides the age under which revisions
+	// are always kept.
+	HistoryKeepNewerThanAnnotation = "helm.sh/history-keep-newer-than"
+	// PurgeDeletedAfterAnnotation overrides the time after which deleted
+	// releases are purged.
+	PurgeDeletedAfterAnnotation = "helm.sh/purge-deleted-after"
+)
+
+// RetentionPolicy describes which revisions of a release are kept in storage.
+// The last deployed revision of a release is always kept, unless the whole
+// release is purged.
+type RetentionPolicy struct {
+	// MaxHistory is the maximum number of revisions kept. Values of 0 or
+	// less mean no limit.
+	MaxHistory int
+	// KeepNewerThan is the age under which revisions are kept even if the
+	// release has more than MaxHistory revisions. Values of 0 or less
+	// protect no revision.
+	KeepNewerThan time.Duration
+	// PurgeDeletedAfter is the time after which every revision of a deleted
+	// release is removed. Values of 0 or less keep deleted releases.
+	PurgeDeletedAfter time.Duration
+}
+
+// RetentionPolicy returns the retention policy of the release rls belongs to,
+// i.e. the retention policy of the storage overridden by the annotations of
+// the chart of rls.
+func (s *Storage) RetentionPolicy(rls *rspb.Release) RetentionPolicy {
+	policy := RetentionPolicy{
+		MaxHistory:        s.MaxHistory,
+		KeepNewerThan:     s.KeepHistoryNewerThan,
+		PurgeDeletedAfter: s.PurgeDeletedAfter,
+	}
+
+	annotations := rls.GetChart().GetMetadata().GetAnnotations()
+	if v, ok := annotations[HistoryMaxAnnotation]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			policy.MaxHistory = n
+		} else {
+			s.Log("ignoring invalid %s annotation %q of release %q", HistoryMaxAnnotation, v, rls.Name)
+		}
+	}
+	if v, ok := annotations[HistoryKeepNewerThanAnnotation]; ok {
+		if d, err := parseRetentionDuration(v); err == nil {
+			policy.KeepNewerThan = d
+		} else {
+			s.Log("ignoring invalid %s annotation %q of release %q", HistoryKeepNewerThanAnnotation, v, rls.Name)
+		}
+	}
+	if v, ok := annotations[PurgeDeletedAfterAnnotation]; ok {
+		if d, err := parseRetentionDuration(v); err == nil {
+			policy.PurgeDeletedAfter = d
+		} else {
+			s.Log("ignoring invalid %s annotation %q of release %q", PurgeDeletedAfterAnnotation, v, rls.Name)
+		}
+	}
+	return policy
+}
+
+// SweepHistory applies the retention policy of every release in storage and
+// returns the revisions it removed. Releases with an operation in progress,
+// i.e. locked by this Tiller or whose last revision is pending or being
+// deleted, are left for the next sweep. The sweep takes no lock, so that it
+// never makes an operation started meanwhile fail as locked.
+func (s *Storage) SweepHistory() ([]*rspb.Release, error) {
+	s.Log("sweeping release history")
+	rels, err := s.ListReleases()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var names []string
+	for _, rls := range rels {
+		if !seen[rls.Name] {
+			seen[rls.Name] = true
+			names = append(names, rls.Name)
+		}
+	}
+	sort.Strings(names)
+
+	var removed []*rspb.Release
+	var errs []error
+	for _, name := range names {
+		r, err := s.sweepRelease(name)
+		removed = append(removed, r...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	switch c := len(errs); c {
+	case 0:
+		return removed, nil
+	case 1:
+		return removed, errs[0]
+	default:
+		return removed, fmt.Errorf("encountered %d errors sweeping release history. First is: %s", c, errs[0])
+	}
+}
+
+// sweepRelease applies the retention policy of the named release.
+func (s *Storage) sweepRelease(name string) ([]*rspb.Release, error) {
+	if s.isLocked(name) {
+		s.Log("skipping sweep of %q: an operation is in progress", name)
+		return nil, nil
+	}
+
+	h, err := s.History(name)
+	if err != nil || len(h) == 0 {
+		return nil, err
+	}
+	// We want oldest to newest
+	relutil.SortByRevision(h)
+	last := h[len(h)-1]
+	if IsPending(last) || last.GetInfo().GetStatus().GetCode() == rspb.Status_DELETING {
+		s.Log("skipping sweep of %q: its last revision is %s", name, last.GetInfo().GetStatus().GetCode())
+		return nil, nil
+	}
+	policy := s.RetentionPolicy(last)
+	now := time.Now()
+
+	var toDelete []*rspb.Release
+	switch {
+	case policy.PurgeDeletedAfter > 0 && last.GetInfo().GetStatus().GetCode() == rspb.Status_DELETED &&
+		revisionTime(last.GetInfo().GetDeleted()).Before(now.Add(-policy.PurgeDeletedAfter)):
+		s.Log("purging release %q, deleted more than %s ago", name, policy.PurgeDeletedAfter)
+		toDelete = h
+	case policy.MaxHistory > 0:
+		var lastDeployed *rspb.Release
+		for _, rls := range h {
+			if rls.GetInfo().GetStatus().GetCode() == rspb.Status_DEPLOYED {
+				lastDeployed = rls
+			}
+		}
+		toDelete = leastRecent(h, policy.MaxHistory, lastDeployed, policy.KeepNewerThan, now)
+	}
+
+	var removed []*rspb.Release
+	for _, rls := range toDelete {
+		if s.isLocked(name) {
+			s.Log("stopping sweep of %q: an operation started", name)
+			break
+		}
+		if err := s.deleteReleaseVersion(name, rls.GetVersion()); err != nil {
+			return removed, err
+		}
+		removed = append(removed, rls)
+	}
+	if len(removed) > 0 {
+		s.Log("swept %d record(s) from %s", len(removed), name)
+	}
+	return removed, nil
+}
+
+// leastRecent returns the oldest revisions of the history h, sorted from
+// oldest to newest, to delete so that no more than max revisions are left.
+// The last deployed revision, and revisions deployed less than keepNewerThan
+// before now, are kept even if more than max revisions are left as a result.
+func leastRecent(h []*rspb.Release, max int, lastDeployed *rspb.Release, keepNewerThan time.Duration, now time.Time) []*rspb.Release {
+	cutoff := now.Add(-keepNewerThan)
+	var toDelete []*rspb.Release
+	for _, rel := range h {
+		// once we have enough releases to delete to reach the max, stop
+		if len(h)-len(toDelete) <= max {
+			break
+		}
+		if lastDeployed != nil && rel.GetVersion() == lastDeployed.GetVersion() {
+			continue
+		}
+		if keepNewerThan > 0 && revisionTime(rel.GetInfo().GetLastDeployed()).After(cutoff) {
+			continue
+		}
+		toDelete = append(toDelete, rel)
+	}
+	return toDelete
+}
+
+// revisionTime converts a timestamp of a revision, treating missing ones as
+// the epoch.
+func revisionTime(ts *timestamp.Timestamp) time.Time {
+	if ts == nil {
+		return time.Unix(0, 0)
+	}
+	return timeconv.Time(ts)
+}
+
+// parseRetentionDuration parses a Go duration, or a number of days suffixed
+// with "d".
+func parseRetentionDuration(v string) (time.Duration, error) {
+	if days := strings.TrimSuffix(v, "d"); days != v {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(v)
+}
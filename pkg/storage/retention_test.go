@@ -0,0 +1,204 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// agedRelease returns a release deployed age ago.
+func agedRelease(name string, version int32, status rspb.Status_Code, age time.Duration) *rspb.Release {
+	rls := ReleaseTestData{Name: name, Version: version, Status: status}.ToRelease()
+	rls.Info.LastDeployed = timeconv.Timestamp(time.Now().Add(-age))
+	return rls
+}
+
+func versions(rels []*rspb.Release) []int32 {
+	var vs []int32
+	for _, rls := range rels {
+		vs = append(vs, rls.Version)
+	}
+	return vs
+}
+
+func TestStorageRetentionPolicy(t *testing.T) {
+	storage := Init(driver.NewMemory())
+	storage.MaxHistory = 10
+	storage.KeepHistoryNewerThan = time.Hour
+	storage.PurgeDeletedAfter = 24 * time.Hour
+
+	rls := ReleaseTestData{Name: "angry-bird", Version: 1}.ToRelease()
+	expect := RetentionPolicy{MaxHistory: 10, KeepNewerThan: time.Hour, PurgeDeletedAfter: 24 * time.Hour}
+	if policy := storage.RetentionPolicy(rls); policy != expect {
+		t.Errorf("Expected %+v, got %+v", expect, policy)
+	}
+
+	rls.Chart = &chart.Chart{Metadata: &chart.Metadata{Annotations: map[string]string{
+		HistoryMaxAnnotation:           "3",
+		HistoryKeepNewerThanAnnotation: "30d",
+		PurgeDeletedAfterAnnotation:    "not-a-duration",
+	}}}
+	expect = RetentionPolicy{MaxHistory: 3, KeepNewerThan: 30 * 24 * time.Hour, PurgeDeletedAfter: 24 * time.Hour}
+	if policy := storage.RetentionPolicy(rls); policy != expect {
+		t.Errorf("Expected %+v, got %+v", expect, policy)
+	}
+}
+
+func TestStorageSweepHistory(t *testing.T) {
+	storage := Init(driver.NewMemory())
+	storage.Log = t.Logf
+	storage.KeepHistoryNewerThan = 30 * 24 * time.Hour
+	storage.PurgeDeletedAfter = 7 * 24 * time.Hour
+
+	day := 24 * time.Hour
+	for _, rls := range []*rspb.Release{
+		// keep the last 2 revisions, the last deployed one, and any newer than 30 days
+		agedRelease("angry-bird", 1, rspb.Status_SUPERSEDED, 90*day),
+		agedRelease("angry-bird", 2, rspb.Status_DEPLOYED, 80*day),
+		agedRelease("angry-bird", 3, rspb.Status_FAILED, 70*day),
+		agedRelease("angry-bird", 4, rspb.Status_FAILED, 10*day),
+		agedRelease("angry-bird", 5, rspb.Status_FAILED, 5*day),
+		agedRelease("angry-bird", 6, rspb.Status_FAILED, day),
+		// deleted for longer than 7 days
+		agedRelease("happy-cats", 1, rspb.Status_SUPERSEDED, 20*day),
+		agedRelease("happy-cats", 2, rspb.Status_DELETED, 10*day),
+		// deleted recently
+		agedRelease("lazy-dog", 1, rspb.Status_DELETED, day),
+	} {
+		if rls.Info.Status.Code == rspb.Status_DELETED {
+			rls.Info.Deleted = rls.Info.LastDeployed
+		}
+		assertErrNil(t.Fatal, storage.Create(rls), "StoreRelease")
+	}
+
+	// the history of angry-bird is limited by its chart
+	last, err := storage.Get("angry-bird", 6)
+	assertErrNil(t.Fatal, err, "QueryRelease")
+	last.Chart = &chart.Chart{Metadata: &chart.Metadata{Annotations: map[string]string{HistoryMaxAnnotation: "2"}}}
+	assertErrNil(t.Fatal, storage.Update(last), "UpdateRelease")
+
+	removed, err := storage.SweepHistory()
+	assertErrNil(t.Fatal, err, "SweepHistory")
+
+	var keys []string
+	for _, rls := range removed {
+		keys = append(keys, makeKey(rls.Name, rls.Version))
+	}
+	expect := []string{"angry-bird.v1", "angry-bird.v3", "happy-cats.v1", "happy-cats.v2"}
+	if !reflect.DeepEqual(keys, expect) {
+		t.Errorf("Expected %v to be removed, got %v", expect, keys)
+	}
+
+	h, err := storage.History("angry-bird")
+	assertErrNil(t.Fatal, err, "History")
+	if len(h) != 4 {
+		t.Errorf("Expected 4 revisions of angry-bird to be kept, got %v", versions(h))
+	}
+	if _, err := storage.History("lazy-dog"); err != nil {
+		t.Errorf("Expected lazy-dog to be kept: %s", err)
+	}
+
+	// a release locked by an operation in progress is left alone
+	unlock, err := storage.LockRelease("lazy-dog")
+	assertErrNil(t.Fatal, err, "LockRelease")
+	defer unlock()
+	storage.PurgeDeletedAfter = time.Minute
+	removed, err = storage.SweepHistory()
+	assertErrNil(t.Fatal, err, "SweepHistory")
+	if len(removed) != 0 {
+		t.Errorf("Expected nothing to be removed, got %v", versions(removed))
+	}
+}
+
+func TestStorageSweepHistorySkipsPending(t *testing.T) {
+	storage := Init(driver.NewMemory())
+	storage.Log = t.Logf
+	storage.MaxHistory = 1
+
+	for _, rls := range []*rspb.Release{
+		agedRelease("angry-bird", 1, rspb.Status_SUPERSEDED, 3*time.Hour),
+		agedRelease("angry-bird", 2, rspb.Status_DEPLOYED, 2*time.Hour),
+		agedRelease("angry-bird", 3, rspb.Status_PENDING_UPGRADE, time.Hour),
+	} {
+		assertErrNil(t.Fatal, storage.Driver.Create(makeKey(rls.Name, rls.Version), rls), "CreateRelease")
+	}
+
+	removed, err := storage.SweepHistory()
+	assertErrNil(t.Fatal, err, "SweepHistory")
+	if len(removed) != 0 {
+		t.Errorf("Expected nothing to be removed from a pending release, got %v", versions(removed))
+	}
+}
+
+// queryHookMemory is an in-memory driver calling onQuery before every query.
+type queryHookMemory struct {
+	*driver.Memory
+	onQuery func()
+}
+
+func (mem *queryHookMemory) Query(labels map[string]string) ([]*rspb.Release, error) {
+	if mem.onQuery != nil {
+		mem.onQuery()
+	}
+	return mem.Memory.Query(labels)
+}
+
+func TestStorageSweepHistoryLocked(t *testing.T) {
+	mem := &queryHookMemory{Memory: driver.NewMemory()}
+	storage := Init(mem)
+	storage.Log = t.Logf
+	storage.MaxHistory = 1
+
+	for _, rls := range []*rspb.Release{
+		agedRelease("angry-bird", 1, rspb.Status_SUPERSEDED, 2*time.Hour),
+		agedRelease("angry-bird", 2, rspb.Status_DEPLOYED, time.Hour),
+	} {
+		assertErrNil(t.Fatal, storage.Driver.Create(makeKey(rls.Name, rls.Version), rls), "CreateRelease")
+	}
+
+	// an operation started while the history is read is not refused, and
+	// the sweep leaves the release to it
+	var unlock func()
+	mem.onQuery = func() {
+		mem.onQuery = nil
+		var err error
+		unlock, err = storage.LockRelease("angry-bird")
+		assertErrNil(t.Fatal, err, "LockRelease")
+	}
+	removed, err := storage.SweepHistory()
+	assertErrNil(t.Fatal, err, "SweepHistory")
+	if unlock == nil {
+		t.Fatal("Expected the history to be queried")
+	}
+	unlock()
+	if len(removed) != 0 {
+		t.Errorf("Expected nothing to be removed from a release locked during the sweep, got %v", versions(removed))
+	}
+
+	removed, err = storage.SweepHistory()
+	assertErrNil(t.Fatal, err, "SweepHistory")
+	if !reflect.DeepEqual(versions(removed), []int32{1}) {
+		t.Errorf("Expected revision 1 to be removed once unlocked, got %v", versions(removed))
+	}
+}
@@ -19,15 +19,29 @@ package storage // import "k8s.io/helm/pkg/storage"
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	relutil "k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/storage/driver"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
 
 // NoReleasesErr indicates that a given release cannot be found
 const NoReleasesErr = "has no deployed releases"
 
+// Change is a kind of change made to a release in storage.
+type Change int
+
+const (
+	// ReleaseCreated is the change of a release created in storage.
+	ReleaseCreated Change = iota
+	// ReleaseUpdated is the change of a release updated in storage.
+	ReleaseUpdated
+	// ReleaseDeleted is the change of a release deleted from storage.
+	ReleaseDeleted
+)
+
 // Storage represents a storage engine for a Release.
 type Storage struct {
 	driver.Driver
@@ -37,7 +51,27 @@ type Storage struct {
 	// ignored (meaning no limits are imposed).
 	MaxHistory int
 
+	// KeepHistoryNewerThan specifies the age under which historical releases
+	// are retained even if there are more than MaxHistory of them. Values of
+	// 0 or less protect no release.
+	KeepHistoryNewerThan time.Duration
+
+	// PurgeDeletedAfter specifies the time after which the whole history of
+	// a deleted release is removed by SweepHistory. Values of 0 or less
+	// mean deleted releases are retained.
+	PurgeDeletedAfter time.Duration
+
+	// LockTTL specifies the time after which the lock of a release that is no
+	// longer renewed expires. Values of 0 or less mean DefaultLockTTL.
+	LockTTL time.Duration
+
+	// Notify, if set, is called with every release created, updated or
+	// deleted through the storage, once the change is stored.
+	Notify func(change Change, rls *rspb.Release)
+
 	Log func(string, ...interface{})
+
+	locks locks
 }
 
 // Get retrieves the release from storage. An error is returned
@@ -53,11 +87,15 @@ func (s *Storage) Get(name string, version int32) (*rspb.Release, error) {
 // release, or a release with identical key already exists.
 func (s *Storage) Create(rls *rspb.Release) error {
 	s.Log("creating release %q", makeKey(rls.Name, rls.Version))
-	if s.MaxHistory > 0 {
+	if policy := s.RetentionPolicy(rls); policy.MaxHistory > 0 {
 		// Want to make space for one more release.
-		s.removeLeastRecent(rls.Name, s.MaxHistory-1)
+		s.removeLeastRecent(rls.Name, policy.MaxHistory-1, policy.KeepNewerThan)
 	}
-	return s.Driver.Create(makeKey(rls.Name, rls.Version), rls)
+	if err := s.Driver.Create(makeKey(rls.Name, rls.Version), rls); err != nil {
+		return err
+	}
+	s.notify(ReleaseCreated, rls)
+	return nil
 }
 
 // Update update the release in storage. An error is returned if the
@@ -65,7 +103,11 @@ func (s *Storage) Create(rls *rspb.Release) error {
 // does not exist.
 func (s *Storage) Update(rls *rspb.Release) error {
 	s.Log("updating release %q", makeKey(rls.Name, rls.Version))
-	return s.Driver.Update(makeKey(rls.Name, rls.Version), rls)
+	if err := s.Driver.Update(makeKey(rls.Name, rls.Version), rls); err != nil {
+		return err
+	}
+	s.notify(ReleaseUpdated, rls)
+	return nil
 }
 
 // Delete deletes the release from storage. An error is returned if
@@ -73,7 +115,12 @@ func (s *Storage) Update(rls *rspb.Release) error {
 // does not exist.
 func (s *Storage) Delete(name string, version int32) (*rspb.Release, error) {
 	s.Log("deleting release %q", makeKey(name, version))
-	return s.Driver.Delete(makeKey(name, version))
+	rls, err := s.Driver.Delete(makeKey(name, version))
+	if err != nil {
+		return nil, err
+	}
+	s.notify(ReleaseDeleted, rls)
+	return rls, nil
 }
 
 // ListReleases returns all releases from storage. An error is returned if the
@@ -121,19 +168,34 @@ func (s *Storage) ListFilterAny(fns ...relutil.FilterFunc) ([]*rspb.Release, err
 	})
 }
 
+// ListPage returns the page of the releases matching opts. Drivers that are
+// a driver.Lister select, sort and paginate releases in storage; the releases
+// of other drivers are all listed and filtered in memory.
+func (s *Storage) ListPage(opts driver.ListOptions) (*driver.ListPage, error) {
+	s.Log("listing a page of releases")
+	if lister, ok := s.Driver.(driver.Lister); ok {
+		return lister.ListPage(opts)
+	}
+	rels, err := s.Driver.List(func(_ *rspb.Release) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	return driver.FilterPage(rels, opts)
+}
+
 // Deployed returns the last deployed release with the provided release name, or
 // returns ErrReleaseNotFound if not found.
 func (s *Storage) Deployed(name string) (*rspb.Release, error) {
 	ls, err := s.DeployedAll(name)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			return nil, fmt.Errorf("%q %s", name, NoReleasesErr)
+			return nil, storageerrors.ErrNoDeployedReleases(name)
 		}
 		return nil, err
 	}
 
 	if len(ls) == 0 {
-		return nil, fmt.Errorf("%q %s", name, NoReleasesErr)
+		return nil, storageerrors.ErrNoDeployedReleases(name)
 	}
 
 	return ls[0], err
@@ -153,7 +215,7 @@ func (s *Storage) DeployedAll(name string) ([]*rspb.Release, error) {
 		return ls, nil
 	}
 	if strings.Contains(err.Error(), "not found") {
-		return nil, fmt.Errorf("%q %s", name, NoReleasesErr)
+		return nil, storageerrors.ErrNoDeployedReleases(name)
 	}
 	return nil, err
 }
@@ -167,11 +229,11 @@ func (s *Storage) History(name string) ([]*rspb.Release, error) {
 }
 
 // removeLeastRecent removes items from history until the length number of releases
-// does not exceed max.
+// does not exceed max. Releases deployed less than keepNewerThan ago are kept.
 //
 // We allow max to be set explicitly so that calling functions can "make space"
 // for the new records they are going to write.
-func (s *Storage) removeLeastRecent(name string, max int) error {
+func (s *Storage) removeLeastRecent(name string, max int, keepNewerThan time.Duration) error {
 	if max < 0 {
 		return nil
 	}
@@ -191,20 +253,7 @@ func (s *Storage) removeLeastRecent(name string, max int) error {
 		return err
 	}
 
-	var toDelete []*rspb.Release
-	for _, rel := range h {
-		// once we have enough releases to delete to reach the max, stop
-		if len(h)-len(toDelete) == max {
-			break
-		}
-		if lastDeployed != nil {
-			if rel.GetVersion() != lastDeployed.GetVersion() {
-				toDelete = append(toDelete, rel)
-			}
-		} else {
-			toDelete = append(toDelete, rel)
-		}
-	}
+	toDelete := leastRecent(h, max, lastDeployed, keepNewerThan, time.Now())
 
 	// Delete as many as possible. In the case of API throughput limitations,
 	// multiple invocations of this function will eventually delete them all.
@@ -252,6 +301,13 @@ func (s *Storage) Last(name string) (*rspb.Release, error) {
 	return h[0], nil
 }
 
+// notify calls Notify, if set, with a change made to rls.
+func (s *Storage) notify(change Change, rls *rspb.Release) {
+	if s.Notify != nil {
+		s.Notify(change, rls)
+	}
+}
+
 // makeKey concatenates a release name and version into
 // a string with format ```<release_name>#v<version>```.
 // This key is used to uniquely identify storage objects.
@@ -20,9 +20,11 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/storage/driver"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
 
 func TestStorageCreate(t *testing.T) {
@@ -203,6 +205,10 @@ func TestStorageDeployed(t *testing.T) {
 	case rls.Info.Status.Code != rspb.Status_DEPLOYED:
 		t.Fatalf("Expected release status 'DEPLOYED', actual %s\n", rls.Info.Status.Code)
 	}
+
+	if _, err := storage.Deployed("angry-cat"); !storageerrors.IsNoDeployedReleases(err) {
+		t.Fatalf("Expected no deployed releases, got %v", err)
+	}
 }
 
 func TestStorageHistory(t *testing.T) {
@@ -376,6 +382,100 @@ func TestStorageLast(t *testing.T) {
 	}
 }
 
+func TestStorageLockRelease(t *testing.T) {
+	storage := Init(driver.NewMemory())
+
+	unlock, err := storage.LockRelease("angry-beaver")
+	assertErrNil(t.Fatal, err, "LockRelease")
+
+	if _, err := storage.LockRelease("angry-beaver"); !storageerrors.IsReleaseLocked(err) {
+		t.Fatalf("Expected locking a locked release to fail, got %v", err)
+	}
+	// other releases are not affected
+	unlockOther, err := storage.LockRelease("happy-cats")
+	assertErrNil(t.Fatal, err, "LockRelease")
+	unlockOther()
+
+	unlock()
+	unlock, err = storage.LockRelease("angry-beaver")
+	assertErrNil(t.Fatal, err, "LockRelease")
+	unlock()
+}
+
+// lockingMemory is an in-memory driver recording the locks taken in storage.
+type lockingMemory struct {
+	*driver.Memory
+	locks map[string]string
+}
+
+func (mem *lockingMemory) Lock(name, holder string, ttl time.Duration) error {
+	if h, ok := mem.locks[name]; ok && h != holder {
+		return storageerrors.ErrReleaseLocked(name)
+	}
+	mem.locks[name] = holder
+	return nil
+}
+
+func (mem *lockingMemory) Unlock(name, holder string) error {
+	if mem.locks[name] == holder {
+		delete(mem.locks, name)
+	}
+	return nil
+}
+
+func TestStorageLockReleaseDistributed(t *testing.T) {
+	mem := &lockingMemory{Memory: driver.NewMemory(), locks: map[string]string{}}
+	tiller1, tiller2 := Init(mem), Init(mem)
+
+	unlock, err := tiller1.LockRelease("angry-beaver")
+	assertErrNil(t.Fatal, err, "LockRelease")
+	if _, ok := mem.locks["angry-beaver"]; !ok {
+		t.Fatal("Expected the release to be locked in storage")
+	}
+
+	if _, err := tiller2.LockRelease("angry-beaver"); err == nil {
+		t.Fatal("Expected locking a release locked by another Tiller to fail")
+	}
+
+	unlock()
+	if _, ok := mem.locks["angry-beaver"]; ok {
+		t.Fatal("Expected the lock to be released in storage")
+	}
+	unlock, err = tiller2.LockRelease("angry-beaver")
+	assertErrNil(t.Fatal, err, "LockRelease")
+	unlock()
+}
+
+func TestStorageNotify(t *testing.T) {
+	storage := Init(driver.NewMemory())
+
+	var changes []string
+	storage.Notify = func(change Change, rls *rspb.Release) {
+		changes = append(changes, fmt.Sprintf("%d %s.v%d %s", change, rls.Name, rls.Version, rls.Info.Status.Code))
+	}
+
+	rls := ReleaseTestData{Name: "angry-beaver", Version: 1, Status: rspb.Status_PENDING_INSTALL}.ToRelease()
+	assertErrNil(t.Fatal, storage.Create(rls), "StoreRelease")
+	rls.Info.Status.Code = rspb.Status_DEPLOYED
+	assertErrNil(t.Fatal, storage.Update(rls), "UpdateRelease")
+	_, err := storage.Delete(rls.Name, rls.Version)
+	assertErrNil(t.Fatal, err, "DeleteRelease")
+
+	// failed changes are not notified
+	if _, err := storage.Delete(rls.Name, rls.Version); err == nil {
+		t.Fatal("Expected deleting a missing release to fail")
+	}
+
+	expected := []string{
+		"0 angry-beaver.v1 PENDING_INSTALL",
+		"1 angry-beaver.v1 DEPLOYED",
+		"2 angry-beaver.v1 DEPLOYED",
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected changes %v, got %v", expected, changes)
+	}
+}
+
 type ReleaseTestData struct {
 	Name      string
 	Version   int32
@@ -0,0 +1,94 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/tiller/admission"
+)
+
+const sourcePrefix = "# Source: "
+
+// admit checks the resources and hooks of rel against the admission policy,
+// if any. The returned error lists every violation of the policy.
+func (s *ReleaseServer) admit(rel *release.Release) error {
+	if s.AdmissionPolicy == nil {
+		return nil
+	}
+	policy, err := s.AdmissionPolicy.Load()
+	if err != nil {
+		return fmt.Errorf("cannot load admission policy: %s", err)
+	}
+
+	objs, err := admissionObjects(rel)
+	if err != nil {
+		return err
+	}
+	if vs := policy.Check(objs); len(vs) > 0 {
+		s.Log("release %s rejected by admission policy with %d violations", rel.Name, len(vs))
+		return fmt.Errorf("release %s rejected by admission policy:\n%s", rel.Name, vs)
+	}
+	return nil
+}
+
+// admissionObjects returns the resources of rel, followed by its hooks.
+func admissionObjects(rel *release.Release) ([]*admission.Object, error) {
+	manifests := []string{rel.Manifest}
+	for _, h := range rel.Hooks {
+		manifests = append(manifests, sourcePrefix+h.Path+"\n"+h.Manifest)
+	}
+
+	var objs []*admission.Object
+	for _, m := range manifests {
+		resources, err := splitResources(m, rel.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range resources {
+			obj := &admission.Object{
+				Kind:      r.kind,
+				Name:      r.name,
+				Namespace: r.namespace,
+				Source:    manifestSource(r.content),
+			}
+			if err := yaml.Unmarshal([]byte(r.content), &obj.Content); err != nil {
+				return nil, err
+			}
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+// manifestSource returns the template a manifest was rendered from, as
+// written in its leading comments.
+func manifestSource(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, sourcePrefix) {
+			return strings.TrimPrefix(line, sourcePrefix)
+		}
+		if line != "" && line != "---" && !strings.HasPrefix(line, "#") {
+			break
+		}
+	}
+	return ""
+}
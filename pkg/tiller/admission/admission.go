@@ -0,0 +1,212 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*Package admission checks the rendered resources of a release against an
+admission policy before they are applied.
+
+A Policy is a list of rules, each of a type implemented by a Checker. The
+built-in rule types match field paths, forbid kinds and require labels; other
+types can be added with RegisterRuleType.
+*/
+package admission // import "k8s.io/helm/pkg/tiller/admission"
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+)
+
+// Object is a rendered resource of a release.
+type Object struct {
+	Kind      string
+	Name      string
+	Namespace string
+	// Source is the template the resource was rendered from.
+	Source string
+	// Content is the decoded manifest of the resource.
+	Content map[string]interface{}
+}
+
+// Checker checks objects against a rule.
+type Checker interface {
+	// Check returns a message for every violation of the rule by obj.
+	Check(obj *Object) []string
+}
+
+// RuleType returns the Checker of a rule of its type, or an error if the rule
+// is invalid.
+type RuleType func(rule *Rule) (Checker, error)
+
+var (
+	ruleTypesMu sync.RWMutex
+	ruleTypes   = map[string]RuleType{
+		"field":          newFieldChecker,
+		"forbiddenKinds": newForbiddenKindsChecker,
+		"requiredLabels": newRequiredLabelsChecker,
+	}
+)
+
+// RegisterRuleType registers the rule type name, replacing any previous one.
+func RegisterRuleType(name string, t RuleType) {
+	ruleTypesMu.Lock()
+	defer ruleTypesMu.Unlock()
+	ruleTypes[name] = t
+}
+
+// Rule is a rule of an admission policy.
+type Rule struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Kinds are the kinds of the resources the rule applies to, all if empty.
+	Kinds []string `json:"kinds,omitempty"`
+	// Message replaces the description of the violations of the rule.
+	Message string `json:"message,omitempty"`
+
+	// Paths are the field paths checked by rules of type field.
+	Paths []string `json:"paths,omitempty"`
+	// Required requires the fields to be set.
+	Required bool `json:"required,omitempty"`
+	// ForbiddenValues are the values the fields must not be set to.
+	ForbiddenValues []interface{} `json:"forbiddenValues,omitempty"`
+	// ForbiddenPattern is a regular expression the string fields must not
+	// match.
+	ForbiddenPattern string `json:"forbiddenPattern,omitempty"`
+
+	// Labels are the labels required by rules of type requiredLabels.
+	Labels []string `json:"labels,omitempty"`
+
+	// Params holds the settings of rule types registered with
+	// RegisterRuleType.
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	checker Checker
+}
+
+// appliesTo reports whether the rule applies to obj.
+func (r *Rule) appliesTo(obj *Object) bool {
+	if len(r.Kinds) == 0 {
+		return true
+	}
+	for _, k := range r.Kinds {
+		if k == obj.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy is an admission policy. A release is admitted if none of its
+// resources violates any of the rules.
+type Policy struct {
+	Rules []*Rule `json:"rules"`
+}
+
+// Parse parses a YAML admission policy.
+func Parse(data []byte) (*Policy, error) {
+	p := &Policy{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(p.Rules))
+	for i, r := range p.Rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("rule %q is defined twice", r.Name)
+		}
+		names[r.Name] = true
+
+		ruleTypesMu.RLock()
+		t, ok := ruleTypes[r.Type]
+		ruleTypesMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("rule %q: unknown type %q", r.Name, r.Type)
+		}
+		c, err := t(r)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %s", r.Name, err)
+		}
+		r.checker = c
+	}
+	return p, nil
+}
+
+// Check returns every violation of the policy by objs.
+func (p *Policy) Check(objs []*Object) Violations {
+	var vs Violations
+	for _, obj := range objs {
+		for _, r := range p.Rules {
+			if !r.appliesTo(obj) {
+				continue
+			}
+			for _, msg := range r.checker.Check(obj) {
+				vs = append(vs, Violation{
+					Rule:      r.Name,
+					Kind:      obj.Kind,
+					Name:      obj.Name,
+					Namespace: obj.Namespace,
+					Source:    obj.Source,
+					Message:   msg,
+				})
+			}
+		}
+	}
+	return vs
+}
+
+// Violation is the violation of a rule by a resource.
+type Violation struct {
+	Rule      string
+	Kind      string
+	Name      string
+	Namespace string
+	Source    string
+	Message   string
+}
+
+func (v Violation) String() string {
+	s := fmt.Sprintf("[%s] %s %s", v.Rule, v.Kind, v.Name)
+	if v.Source != "" {
+		s += fmt.Sprintf(" (%s)", v.Source)
+	}
+	return s + ": " + v.Message
+}
+
+// Violations are the violations of a policy. They are an error listing every
+// violation on its own line.
+type Violations []Violation
+
+func (vs Violations) Error() string {
+	lines := make([]string, len(vs))
+	for i, v := range vs {
+		lines[i] = v.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,218 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission // import "k8s.io/helm/pkg/tiller/admission"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testPolicy = `
+rules:
+- name: no-privileged
+  type: field
+  kinds: [Pod, Deployment]
+  paths:
+  - spec.containers[*].securityContext.privileged
+  - spec.template.spec.containers[*].securityContext.privileged
+  forbiddenValues: [true]
+- name: no-latest
+  type: field
+  kinds: [Pod, Deployment]
+  paths:
+  - spec.containers[*].image
+  - spec.template.spec.containers[*].image
+  forbiddenPattern: ":latest$"
+  message: images must be pinned
+- name: limits
+  type: field
+  kinds: [Deployment]
+  paths: ["spec.template.spec.containers[*].resources.limits"]
+  required: true
+- name: no-cluster-bindings
+  type: forbiddenKinds
+  kinds: [ClusterRoleBinding]
+- name: team-label
+  type: requiredLabels
+  labels: [team]
+`
+
+func testObject(t *testing.T, manifest, source string) *Object {
+	var content map[string]interface{}
+	if err := yaml.Unmarshal([]byte(manifest), &content); err != nil {
+		t.Fatal(err)
+	}
+	metadata := content["metadata"].(map[string]interface{})
+	return &Object{
+		Kind:    content["kind"].(string),
+		Name:    metadata["name"].(string),
+		Source:  source,
+		Content: content,
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %s", err)
+	}
+
+	objs := []*Object{
+		testObject(t, `
+kind: Deployment
+metadata:
+  name: web
+  labels: {team: a}
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:latest
+        securityContext: {privileged: true}
+      - name: sidecar
+        image: busybox:1.30
+        resources:
+          limits: {cpu: 100m}
+`, "web/templates/deployment.yaml"),
+		testObject(t, `
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - name: debug
+    image: busybox:1.30
+`, "web/templates/pod.yaml"),
+		testObject(t, `
+kind: ClusterRoleBinding
+metadata:
+  name: admin
+  labels: {team: a}
+`, "web/templates/rbac.yaml"),
+		testObject(t, `
+kind: Service
+metadata:
+  name: web
+  labels: {team: a}
+spec:
+  ports: [{port: 80}]
+`, "web/templates/service.yaml"),
+	}
+
+	var got []string
+	for _, v := range p.Check(objs) {
+		got = append(got, v.String())
+	}
+	expected := []string{
+		`[no-privileged] Deployment web (web/templates/deployment.yaml): spec.template.spec.containers[0].securityContext.privileged: must not be true`,
+		`[no-latest] Deployment web (web/templates/deployment.yaml): spec.template.spec.containers[0].image: images must be pinned`,
+		`[limits] Deployment web (web/templates/deployment.yaml): spec.template.spec.containers[0].resources.limits: is required`,
+		`[team-label] Pod debug (web/templates/pod.yaml): label team is required`,
+		`[no-cluster-bindings] ClusterRoleBinding admin (web/templates/rbac.yaml): kind ClusterRoleBinding is forbidden`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected violations\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, policy := range []string{
+		"rules:\n- type: field\n  paths: [spec]\n  required: true\n",
+		"rules:\n- name: a\n  type: unknown\n",
+		"rules:\n- name: a\n  type: field\n  paths: [spec]\n",
+		"rules:\n- name: a\n  type: field\n  paths: [\"spec.containers[x]\"]\n  required: true\n",
+		"rules:\n- name: a\n  type: field\n  paths: [spec]\n  forbiddenPattern: \"(\"\n",
+		"rules:\n- name: a\n  type: forbiddenKinds\n",
+		"rules:\n- name: a\n  type: requiredLabels\n",
+		"rules:\n- name: a\n  type: forbiddenKinds\n  kinds: [Secret]\n- name: a\n  type: forbiddenKinds\n  kinds: [Secret]\n",
+	} {
+		if _, err := Parse([]byte(policy)); err == nil {
+			t.Errorf("Expected an error parsing\n%s", policy)
+		}
+	}
+}
+
+type nameLengthChecker struct {
+	max int
+}
+
+func (c nameLengthChecker) Check(obj *Object) []string {
+	if len(obj.Name) > c.max {
+		return []string{"name is too long"}
+	}
+	return nil
+}
+
+func TestRegisterRuleType(t *testing.T) {
+	RegisterRuleType("nameLength", func(rule *Rule) (Checker, error) {
+		max, _ := rule.Params["max"].(float64)
+		return nameLengthChecker{max: int(max)}, nil
+	})
+	p, err := Parse([]byte("rules:\n- name: short-names\n  type: nameLength\n  params: {max: 5}\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %s", err)
+	}
+	vs := p.Check([]*Object{{Kind: "Secret", Name: "credentials"}, {Kind: "Secret", Name: "token"}})
+	if len(vs) != 1 || vs[0].Name != "credentials" {
+		t.Errorf("Expected credentials to violate the rule, got %v", vs)
+	}
+}
+
+func TestSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-admission-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "policy.yaml")
+	if err := ioutil.WriteFile(filename, []byte(testPolicy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := FileSource(filename).Load()
+	if err != nil {
+		t.Fatalf("Failed to load policy file: %s", err)
+	}
+	if len(p.Rules) != 5 {
+		t.Errorf("Expected 5 rules, got %d", len(p.Rules))
+	}
+
+	clientset := fake.NewSimpleClientset()
+	src := &ConfigMapSource{ConfigMaps: clientset.CoreV1(), Namespace: "kube-system", Name: "tiller-admission"}
+	if _, err := src.Load(); err == nil {
+		t.Error("Expected an error loading a missing configmap")
+	}
+	clientset.CoreV1().ConfigMaps("kube-system").Create(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "tiller-admission"},
+		Data:       map[string]string{ConfigMapKey: testPolicy},
+	})
+	if p, err = src.Load(); err != nil {
+		t.Fatalf("Failed to load policy configmap: %s", err)
+	}
+	if len(p.Rules) != 5 {
+		t.Errorf("Expected 5 rules, got %d", len(p.Rules))
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission // import "k8s.io/helm/pkg/tiller/admission"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// fieldChecker checks the fields of objects at paths.
+//
+// A path is a list of keys separated by dots, such as
+// spec.template.spec.containers[*].image. A key of * or [*] matches every
+// element of a list or value of a map, and a key of [N] the Nth element of a
+// list.
+type fieldChecker struct {
+	rule    *Rule
+	paths   [][]string
+	pattern *regexp.Regexp
+}
+
+func newFieldChecker(rule *Rule) (Checker, error) {
+	if len(rule.Paths) == 0 {
+		return nil, errors.New("no paths")
+	}
+	if !rule.Required && len(rule.ForbiddenValues) == 0 && rule.ForbiddenPattern == "" {
+		return nil, errors.New("one of required, forbiddenValues or forbiddenPattern must be set")
+	}
+	c := &fieldChecker{rule: rule}
+	for _, p := range rule.Paths {
+		keys, err := parsePath(p)
+		if err != nil {
+			return nil, err
+		}
+		c.paths = append(c.paths, keys)
+	}
+	if rule.ForbiddenPattern != "" {
+		pattern, err := regexp.Compile(rule.ForbiddenPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid forbiddenPattern: %s", err)
+		}
+		c.pattern = pattern
+	}
+	return c, nil
+}
+
+// parsePath splits a field path into its keys.
+func parsePath(p string) ([]string, error) {
+	var keys []string
+	for _, part := range strings.Split(p, ".") {
+		// containers[*] is containers followed by [*]
+		for part != "" {
+			i := strings.Index(part, "[")
+			if i < 0 {
+				keys = append(keys, part)
+				break
+			}
+			if i > 0 {
+				keys = append(keys, part[:i])
+			}
+			j := strings.Index(part, "]")
+			if j < i {
+				return nil, fmt.Errorf("invalid path %q", p)
+			}
+			index := part[i+1 : j]
+			if _, err := strconv.Atoi(index); err != nil && index != "*" {
+				return nil, fmt.Errorf("invalid index %q in path %q", index, p)
+			}
+			keys = append(keys, "["+index+"]")
+			part = part[j+1:]
+		}
+	}
+	for _, k := range keys {
+		if k == "" {
+			return nil, fmt.Errorf("invalid path %q", p)
+		}
+	}
+	return keys, nil
+}
+
+// field is a field found at a path, or missing.
+type field struct {
+	path    string
+	value   interface{}
+	missing bool
+}
+
+// resolve returns the fields of v at keys. A missing key is returned as a
+// missing field at the full path, but wildcards over missing fields match
+// nothing.
+func resolve(v interface{}, keys []string, path string, fields []field) []field {
+	if len(keys) == 0 {
+		return append(fields, field{path: path, value: v})
+	}
+	key, rest := keys[0], keys[1:]
+
+	if key == "*" || key == "[*]" {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for _, k := range sortedKeys(t) {
+				fields = resolve(t[k], rest, joinPath(path, k), fields)
+			}
+		case []interface{}:
+			for i, e := range t {
+				fields = resolve(e, rest, fmt.Sprintf("%s[%d]", path, i), fields)
+			}
+		}
+		return fields
+	}
+
+	if strings.HasPrefix(key, "[") {
+		i, _ := strconv.Atoi(key[1 : len(key)-1])
+		if l, ok := v.([]interface{}); ok && i < len(l) {
+			return resolve(l[i], rest, joinPath(path, key), fields)
+		}
+		return append(fields, field{path: joinPath(path, keys...), missing: true})
+	}
+
+	if m, ok := v.(map[string]interface{}); ok {
+		if e, ok := m[key]; ok && e != nil {
+			return resolve(e, rest, joinPath(path, key), fields)
+		}
+	}
+	return append(fields, field{path: joinPath(path, keys...), missing: true})
+}
+
+// joinPath appends keys to path.
+func joinPath(path string, keys ...string) string {
+	for _, k := range keys {
+		if path != "" && !strings.HasPrefix(k, "[") {
+			path += "."
+		}
+		path += k
+	}
+	return path
+}
+
+func (c *fieldChecker) Check(obj *Object) []string {
+	var msgs []string
+	for _, keys := range c.paths {
+		for _, f := range resolve(obj.Content, keys, "", nil) {
+			if msg := c.checkField(f); msg != "" {
+				if c.rule.Message != "" {
+					msg = c.rule.Message
+				}
+				msgs = append(msgs, f.path+": "+msg)
+			}
+		}
+	}
+	return msgs
+}
+
+// checkField returns the description of the violation of the rule by f, if
+// any.
+func (c *fieldChecker) checkField(f field) string {
+	if f.missing {
+		if c.rule.Required {
+			return "is required"
+		}
+		return ""
+	}
+	for _, forbidden := range c.rule.ForbiddenValues {
+		if reflect.DeepEqual(f.value, forbidden) {
+			data, _ := json.Marshal(forbidden)
+			return fmt.Sprintf("must not be %s", data)
+		}
+	}
+	if s, ok := f.value.(string); ok && c.pattern != nil && c.pattern.MatchString(s) {
+		return fmt.Sprintf("%q must not match %q", s, c.rule.ForbiddenPattern)
+	}
+	return ""
+}
+
+// forbiddenKindsChecker forbids every resource of the kinds of its rule.
+type forbiddenKindsChecker struct {
+	rule *Rule
+}
+
+func newForbiddenKindsChecker(rule *Rule) (Checker, error) {
+	if len(rule.Kinds) == 0 {
+		return nil, errors.New("no kinds")
+	}
+	return forbiddenKindsChecker{rule: rule}, nil
+}
+
+func (c forbiddenKindsChecker) Check(obj *Object) []string {
+	if c.rule.Message != "" {
+		return []string{c.rule.Message}
+	}
+	return []string{fmt.Sprintf("kind %s is forbidden", obj.Kind)}
+}
+
+// requiredLabelsChecker requires resources to be labelled.
+type requiredLabelsChecker struct {
+	rule *Rule
+}
+
+func newRequiredLabelsChecker(rule *Rule) (Checker, error) {
+	if len(rule.Labels) == 0 {
+		return nil, errors.New("no labels")
+	}
+	return requiredLabelsChecker{rule: rule}, nil
+}
+
+func (c requiredLabelsChecker) Check(obj *Object) []string {
+	metadata, _ := obj.Content["metadata"].(map[string]interface{})
+	labels, _ := metadata["labels"].(map[string]interface{})
+
+	var msgs []string
+	for _, l := range c.rule.Labels {
+		if _, ok := labels[l]; ok {
+			continue
+		}
+		msg := fmt.Sprintf("label %s is required", l)
+		if c.rule.Message != "" {
+			msg = fmt.Sprintf("label %s: %s", l, c.rule.Message)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
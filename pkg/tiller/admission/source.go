@@ -0,0 +1,74 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission // import "k8s.io/helm/pkg/tiller/admission"
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// ConfigMapKey is the key of the policy in the data of a ConfigMap.
+const ConfigMapKey = "policy.yaml"
+
+// Source loads an admission policy. It is loaded before every install or
+// upgrade, so that changes to the policy apply right away.
+type Source interface {
+	Load() (*Policy, error)
+}
+
+// FileSource loads the policy of a file.
+type FileSource string
+
+// Load reads and parses the policy file.
+func (f FileSource) Load() (*Policy, error) {
+	data, err := ioutil.ReadFile(string(f))
+	if err != nil {
+		return nil, err
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid admission policy %s: %s", f, err)
+	}
+	return p, nil
+}
+
+// ConfigMapSource loads the policy held by a ConfigMap under ConfigMapKey.
+type ConfigMapSource struct {
+	ConfigMaps corev1.ConfigMapsGetter
+	Namespace  string
+	Name       string
+}
+
+// Load gets and parses the policy of the ConfigMap.
+func (c *ConfigMapSource) Load() (*Policy, error) {
+	cm, err := c.ConfigMaps.ConfigMaps(c.Namespace).Get(c.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	data, ok := cm.Data[ConfigMapKey]
+	if !ok {
+		return nil, fmt.Errorf("configmap %s/%s has no %s key", c.Namespace, c.Name, ConfigMapKey)
+	}
+	p, err := Parse([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid admission policy in configmap %s/%s: %s", c.Namespace, c.Name, err)
+	}
+	return p, nil
+}
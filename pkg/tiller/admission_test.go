@@ -0,0 +1,116 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/admission"
+)
+
+// staticPolicy is an admission policy source returning a fixed policy.
+type staticPolicy string
+
+func (p staticPolicy) Load() (*admission.Policy, error) {
+	return admission.Parse([]byte(p))
+}
+
+const testAdmissionPolicy = `
+rules:
+- name: no-latest
+  type: field
+  kinds: [Pod]
+  paths: ["spec.containers[*].image"]
+  forbiddenPattern: ":latest$"
+- name: team-label
+  type: requiredLabels
+  labels: [team]
+`
+
+var manifestWithLatestPod = `kind: Pod
+metadata:
+  name: web
+  labels:
+    team: a
+spec:
+  containers:
+  - name: nginx
+    image: nginx:latest`
+
+func withTemplate(name, data string) chartOption {
+	return func(opts *chartOptions) {
+		opts.Templates = append(opts.Templates, &chart.Template{Name: name, Data: []byte(data)})
+	}
+}
+
+func TestInstallRelease_AdmissionPolicy(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.AdmissionPolicy = staticPolicy(testAdmissionPolicy)
+
+	req := installRequest(withName("web"), withChart(withTemplate("templates/pod", manifestWithLatestPod)))
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected the install to be rejected")
+	}
+
+	for _, violation := range []string{
+		`[no-latest] Pod web (hello/templates/pod): spec.containers[0].image: "nginx:latest" must not match ":latest$"`,
+		`[team-label] ConfigMap test-cm (hello/templates/hooks): label team is required`,
+	} {
+		if !strings.Contains(err.Error(), violation) {
+			t.Errorf("Expected the error to list %q, got %s", violation, err)
+		}
+	}
+	if _, err := rs.env.Releases.Last("web"); err == nil {
+		t.Error("Expected no release to be stored")
+	}
+}
+
+func TestUpdateRelease_AdmissionPolicy(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	rs.AdmissionPolicy = staticPolicy(testAdmissionPolicy)
+
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/pod", Data: []byte(strings.Replace(manifestWithLatestPod, "latest", "1.15", 1))},
+			},
+		},
+	}
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Fatalf("Expected the update to be admitted, got %s", err)
+	}
+
+	req.Chart.Templates[0].Data = []byte(manifestWithLatestPod)
+	req.Force = true
+	if _, err := rs.UpdateRelease(c, req); err == nil || !strings.Contains(err.Error(), "[no-latest]") {
+		t.Errorf("Expected the forced update to be rejected, got %v", err)
+	}
+	if last, _ := rs.env.Releases.Last(rel.Name); last.Version != 2 {
+		t.Errorf("Expected the rejected update not to be stored, got revision %d", last.Version)
+	}
+}
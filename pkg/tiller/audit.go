@@ -0,0 +1,221 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/tiller/audit"
+)
+
+// auditedRPCs are the RPCs changing releases.
+var auditedRPCs = map[string]bool{
+	"InstallRelease":   true,
+	"UpdateRelease":    true,
+	"RollbackRelease":  true,
+	"UninstallRelease": true,
+	"RunReleaseTest":   true,
+	"CancelOperation":  true,
+	"RepairRelease":    true,
+	"CheckDrift":       true,
+	"RenameRelease":    true,
+	"ApplyReleaseSet":  true,
+}
+
+// Auditor records the calls made to Tiller that change releases, whether
+// they succeed, fail or are denied.
+type Auditor struct {
+	w        audit.Writer
+	releases *storage.Storage
+
+	// Log logs the records that cannot be written.
+	Log func(string, ...interface{})
+}
+
+// NewAuditor returns an Auditor writing records to w. The release a call was
+// made on is looked up in releases when the response does not hold it.
+func NewAuditor(w audit.Writer, releases *storage.Storage) *Auditor {
+	return &Auditor{
+		w:        w,
+		releases: releases,
+		Log:      func(_ string, _ ...interface{}) {},
+	}
+}
+
+// audits reports whether a call of rpc with req changes releases. Drift is
+// only audited when reconciled.
+func audits(rpc string, req interface{}) bool {
+	if r, ok := req.(*services.CheckDriftRequest); ok {
+		return r.Reconcile
+	}
+	return auditedRPCs[rpc]
+}
+
+// Record records the call of fullMethod with req by the client of ctx, which
+// started at start and returned resp and err. Calls that do not change
+// releases are not recorded.
+func (a *Auditor) Record(ctx context.Context, fullMethod string, req, resp interface{}, err error, start time.Time) {
+	_, rpc := splitMethod(fullMethod)
+	if req == nil || !audits(rpc, req) {
+		return
+	}
+
+	r := &audit.Record{
+		Time:       start.UTC(),
+		RPC:        rpc,
+		Outcome:    outcome(err),
+		DurationMs: int64(time.Since(start) / time.Millisecond),
+	}
+	r.User, r.Groups = clientIdentity(ctx)
+	if err != nil {
+		r.Error = err.Error()
+	}
+	if set, ok := req.(*services.ApplyReleaseSetRequest); ok {
+		a.recordSet(r, set, resp)
+		return
+	}
+
+	if n, ok := req.(interface{ GetName() string }); ok {
+		r.Release = n.GetName()
+	}
+	if n, ok := req.(interface{ GetNamespace() string }); ok {
+		r.Namespace = n.GetNamespace()
+	}
+	if c, ok := req.(interface{ GetChart() *chart.Chart }); ok {
+		r.Chart, r.ChartVersion = chartNameVersion(c.GetChart())
+	}
+
+	// the release the call created or changed, if returned, holds its
+	// generated name and effective values
+	var rel *release.Release
+	if res, ok := resp.(interface{ GetRelease() *release.Release }); ok {
+		rel = res.GetRelease()
+	}
+	if rel != nil {
+		r.Revision = rel.Version
+		r.ValuesHash = valuesHash(rel.Config)
+	} else {
+		if v, ok := req.(interface{ GetValues() *chart.Config }); ok {
+			r.ValuesHash = valuesHash(v.GetValues())
+		}
+		if r.Release != "" {
+			rel, _ = a.releases.Last(r.Release)
+		}
+	}
+	if rel != nil {
+		r.Release, r.Namespace = rel.Name, rel.Namespace
+		if r.Chart == "" {
+			r.Chart, r.ChartVersion = chartNameVersion(rel.Chart)
+		}
+		if r.ValuesHash == "" {
+			r.ValuesHash = valuesHash(rel.Config)
+		}
+	}
+
+	if rename, ok := req.(*services.RenameReleaseRequest); ok && rename.Name != r.Release {
+		r.RenamedFrom = rename.Name
+	}
+
+	if err := a.w.Write(r); err != nil {
+		a.Log("failed to write audit record of %s on %s: %s", r.RPC, r.Release, err)
+	}
+}
+
+// recordSet records the call applying set, which returned resp, with one
+// record by release of the set based on r. The releases skipped are not
+// recorded.
+func (a *Auditor) recordSet(r *audit.Record, set *services.ApplyReleaseSetRequest, resp interface{}) {
+	results := map[string]*services.ReleaseSetResult{}
+	if res, ok := resp.(*services.ApplyReleaseSetResponse); ok {
+		for _, result := range res.Results {
+			results[result.Name] = result
+		}
+	}
+
+	for _, m := range set.Releases {
+		mr := *r
+		mr.Release, mr.Namespace = m.Name, m.Namespace
+		mr.Chart, mr.ChartVersion = chartNameVersion(m.Chart)
+		mr.ValuesHash = valuesHash(m.Values)
+		if result, ok := results[m.Name]; ok {
+			if result.Status == services.ReleaseSetResult_SKIPPED {
+				continue
+			}
+			if rel := result.Release; rel != nil {
+				mr.Revision, mr.Namespace = rel.Version, rel.Namespace
+			}
+			if result.Error != "" {
+				mr.Outcome, mr.Error = audit.Failure, result.Error
+			}
+		}
+		if err := a.w.Write(&mr); err != nil {
+			a.Log("failed to write audit record of %s on %s: %s", mr.RPC, mr.Release, err)
+		}
+	}
+}
+
+// outcome returns the outcome of a call that failed with err, if any: calls
+// are denied when the client is not allowed to make them.
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return audit.Success
+	case status.Code(err) == codes.PermissionDenied:
+		return audit.Denied
+	}
+	return audit.Failure
+}
+
+func chartNameVersion(ch *chart.Chart) (string, string) {
+	md := ch.GetMetadata()
+	return md.GetName(), md.GetVersion()
+}
+
+// valuesHash returns the SHA-256 hash of the raw values of cfg, or an empty
+// string if there are none.
+func valuesHash(cfg *chart.Config) string {
+	if cfg.GetRaw() == "" {
+		return ""
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(cfg.GetRaw())))
+}
+
+// auditingStream keeps the request received on a server stream, to be
+// audited once the call ends.
+type auditingStream struct {
+	grpc.ServerStream
+	req interface{}
+}
+
+func (s *auditingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if s.req == nil {
+		s.req = m
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*Package audit records the calls made to Tiller that change releases.
+
+Each call is described by a Record, written by a Writer: a FileWriter appends
+records to a file as JSON lines, and an EventWriter records them as Kubernetes
+Events. Other sinks only need to implement Writer.
+*/
+package audit // import "k8s.io/helm/pkg/tiller/audit"
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// The outcomes of a call.
+const (
+	// Success is the outcome of a call that succeeded.
+	Success = "success"
+	// Failure is the outcome of a call that failed.
+	Failure = "failure"
+	// Denied is the outcome of a call the client was not allowed to make.
+	Denied = "denied"
+)
+
+// Record describes a call made to Tiller.
+type Record struct {
+	// Time is the time the call started.
+	Time time.Time `json:"time"`
+	// RPC is the name of the RPC called, e.g. InstallRelease.
+	RPC string `json:"rpc"`
+	// User is the common name of the verified TLS certificate of the caller.
+	User string `json:"user,omitempty"`
+	// Groups are the organizations of the verified TLS certificate of the
+	// caller.
+	Groups    []string `json:"groups,omitempty"`
+	Release   string   `json:"release,omitempty"`
+	Namespace string   `json:"namespace,omitempty"`
+	// RenamedFrom is the previous name of the release renamed by the call.
+	RenamedFrom string `json:"renamedFrom,omitempty"`
+	// Revision is the revision of the release the call created or changed.
+	Revision     int32  `json:"revision,omitempty"`
+	Chart        string `json:"chart,omitempty"`
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// ValuesHash is the SHA-256 hash of the values supplied to the release,
+	// so that they can be compared without being disclosed.
+	ValuesHash string `json:"valuesHash,omitempty"`
+	// Outcome is one of Success, Failure or Denied.
+	Outcome string `json:"outcome"`
+	// Error is the error the call failed with.
+	Error string `json:"error,omitempty"`
+	// DurationMs is the duration of the call in milliseconds.
+	DurationMs int64 `json:"durationMs"`
+}
+
+// Writer writes audit records to a sink.
+type Writer interface {
+	Write(r *Record) error
+}
+
+// MultiWriter returns a Writer writing records to every writer of ws.
+func MultiWriter(ws ...Writer) Writer {
+	return multiWriter(ws)
+}
+
+type multiWriter []Writer
+
+func (ws multiWriter) Write(r *Record) error {
+	var errs []string
+	for _, w := range ws {
+		if err := w.Write(r); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, " && "))
+	}
+	return nil
+}
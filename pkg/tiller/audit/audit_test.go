@@ -0,0 +1,143 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit // import "k8s.io/helm/pkg/tiller/audit"
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testRecord(outcome string) *Record {
+	return &Record{
+		Time:         time.Date(2019, 5, 16, 10, 0, 0, 0, time.UTC),
+		RPC:          "InstallRelease",
+		User:         "alice",
+		Groups:       []string{"team-a"},
+		Release:      "happy-panda",
+		Namespace:    "team-a",
+		Revision:     1,
+		Chart:        "nginx",
+		ChartVersion: "0.1.0",
+		ValuesHash:   "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+		Outcome:      outcome,
+		DurationMs:   1500,
+	}
+}
+
+func TestFileWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-audit-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "audit.log")
+
+	w, err := NewFileWriter(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(testRecord(Success)); err != nil {
+		t.Fatalf("Failed to write record: %s", err)
+	}
+	failed := testRecord(Failure)
+	failed.Error = "timed out waiting for the condition"
+	if err := w.Write(failed); err != nil {
+		t.Fatalf("Failed to write record: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", data)
+	}
+	expected := `{"time":"2019-05-16T10:00:00Z","rpc":"InstallRelease","user":"alice","groups":["team-a"],"release":"happy-panda","namespace":"team-a","revision":1,"chart":"nginx","chartVersion":"0.1.0","valuesHash":"sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae","outcome":"success","durationMs":1500}`
+	if lines[0] != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, lines[0])
+	}
+	var r Record
+	if err := json.Unmarshal([]byte(lines[1]), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Outcome != Failure || r.Error != failed.Error {
+		t.Errorf("Expected the failure to be recorded, got %+v", r)
+	}
+}
+
+func TestEventWriter(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	w := NewEventWriter(client.CoreV1(), "kube-system")
+	if err := w.Write(testRecord(Denied)); err != nil {
+		t.Fatalf("Failed to write record: %s", err)
+	}
+
+	events, err := client.CoreV1().Events("kube-system").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Items) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events.Items))
+	}
+	e := events.Items[0]
+	if e.Reason != "InstallRelease" || e.Type != v1.EventTypeWarning || e.InvolvedObject.Name != "happy-panda" {
+		t.Errorf("Unexpected event %+v", e)
+	}
+	var r Record
+	if err := json.Unmarshal([]byte(e.Message), &r); err != nil || r.Outcome != Denied {
+		t.Errorf("Expected the record as message, got %q", e.Message)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(*Record) error { return errors.New("sink unavailable") }
+
+func TestMultiWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-audit-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fw, err := NewFileWriter(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fw.Close()
+
+	err = MultiWriter(failingWriter{}, fw).Write(testRecord(Success))
+	if err == nil || err.Error() != "sink unavailable" {
+		t.Errorf("Expected the error of the failing writer, got %v", err)
+	}
+	if fi, err := os.Stat(filepath.Join(dir, "audit.log")); err != nil || fi.Size() == 0 {
+		t.Error("Expected the record to be written to the other writers")
+	}
+}
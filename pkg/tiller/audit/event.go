@@ -0,0 +1,76 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit // import "k8s.io/helm/pkg/tiller/audit"
+
+import (
+	"encoding/json"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// EventWriter records audit records as Kubernetes Events, whose message is
+// the JSON encoding of the record.
+//
+// The events are created in the namespace of Tiller, and involve the release
+// the call was made on, as an object of kind Release. Failed and denied calls
+// are recorded as warnings.
+type EventWriter struct {
+	events    corev1.EventsGetter
+	namespace string
+}
+
+// NewEventWriter returns an EventWriter creating events in namespace.
+func NewEventWriter(events corev1.EventsGetter, namespace string) *EventWriter {
+	return &EventWriter{events: events, namespace: namespace}
+}
+
+// Write implements Writer.
+func (w *EventWriter) Write(r *Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	eventType := v1.EventTypeNormal
+	if r.Outcome != Success {
+		eventType = v1.EventTypeWarning
+	}
+	t := metav1.NewTime(r.Time)
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "tiller-audit-",
+			Namespace:    w.namespace,
+			Labels:       map[string]string{"OWNER": "TILLER"},
+		},
+		InvolvedObject: v1.ObjectReference{
+			Kind:      "Release",
+			Name:      r.Release,
+			Namespace: w.namespace,
+		},
+		Reason:         r.RPC,
+		Message:        string(data),
+		Type:           eventType,
+		Source:         v1.EventSource{Component: "tiller"},
+		FirstTimestamp: t,
+		LastTimestamp:  t,
+		Count:          1,
+	}
+	_, err = w.events.Events(w.namespace).Create(event)
+	return err
+}
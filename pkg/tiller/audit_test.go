@@ -0,0 +1,140 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller/audit"
+)
+
+type recordingWriter struct {
+	records []*audit.Record
+}
+
+func (w *recordingWriter) Write(r *audit.Record) error {
+	w.records = append(w.records, r)
+	return nil
+}
+
+func TestAuditorRecord(t *testing.T) {
+	releases := storage.Init(driver.NewMemory())
+	rel := namedReleaseStub("angry-panda", release.Status_DEPLOYED)
+	rel.Namespace = "team-a"
+	releases.Create(rel)
+
+	w := &recordingWriter{}
+	auditor := NewAuditor(w, releases)
+	const prefix = "/hapi.services.tiller.ReleaseService/"
+	start := time.Now().Add(-2 * time.Second)
+
+	// a successful install, with a generated name
+	installReq := &services.InstallReleaseRequest{
+		Namespace: "team-a",
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "nginx", Version: "0.1.0"}},
+		Values:    &chart.Config{Raw: "name: value"},
+	}
+	installRes := &services.InstallReleaseResponse{Release: rel}
+	auditor.Record(clientContext("alice", "team-a"), prefix+"InstallRelease", installReq, installRes, nil, start)
+
+	// a failed upgrade
+	updateReq := &services.UpdateReleaseRequest{Name: "angry-panda", Values: &chart.Config{Raw: "name: other"}}
+	auditor.Record(clientContext("alice", "team-a"), prefix+"UpdateRelease", updateReq, nil, errors.New("timed out waiting for the condition"), start)
+
+	// a denied uninstall
+	denied := status.Error(codes.PermissionDenied, "UninstallRelease is not allowed")
+	auditor.Record(clientContext("mallory"), prefix+"UninstallRelease", &services.UninstallReleaseRequest{Name: "angry-panda"}, nil, denied, start)
+
+	// calls not changing releases are not recorded
+	auditor.Record(clientContext("alice"), prefix+"GetReleaseStatus", &services.GetReleaseStatusRequest{Name: "angry-panda"}, nil, nil, start)
+	auditor.Record(clientContext("alice"), prefix+"CheckDrift", &services.CheckDriftRequest{Name: "angry-panda"}, nil, nil, start)
+
+	if len(w.records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(w.records))
+	}
+
+	install := w.records[0]
+	if install.RPC != "InstallRelease" || install.Outcome != audit.Success || install.User != "alice" {
+		t.Errorf("Unexpected install record %+v", install)
+	}
+	if install.Release != "angry-panda" || install.Namespace != "team-a" || install.Revision != 1 {
+		t.Errorf("Expected the installed release to be recorded, got %+v", install)
+	}
+	if install.Chart != "nginx" || install.ChartVersion != "0.1.0" {
+		t.Errorf("Expected the requested chart to be recorded, got %s-%s", install.Chart, install.ChartVersion)
+	}
+	if install.ValuesHash != valuesHash(rel.Config) || install.ValuesHash == "" {
+		t.Errorf("Expected the hash of the release values, got %q", install.ValuesHash)
+	}
+	if install.DurationMs < 2000 {
+		t.Errorf("Expected a duration of at least 2s, got %dms", install.DurationMs)
+	}
+
+	update := w.records[1]
+	if update.Outcome != audit.Failure || update.Error != "timed out waiting for the condition" {
+		t.Errorf("Expected the failure to be recorded, got %+v", update)
+	}
+	if update.Namespace != "team-a" || update.Chart != "hello" || update.Revision != 0 {
+		t.Errorf("Expected the stored release to be recorded, got %+v", update)
+	}
+	if update.ValuesHash != valuesHash(updateReq.Values) {
+		t.Errorf("Expected the hash of the requested values, got %q", update.ValuesHash)
+	}
+
+	if uninstall := w.records[2]; uninstall.Outcome != audit.Denied || uninstall.User != "mallory" {
+		t.Errorf("Expected the denial to be recorded, got %+v", uninstall)
+	}
+}
+
+func TestAuditorRecordSet(t *testing.T) {
+	w := &recordingWriter{}
+	auditor := NewAuditor(w, storage.Init(driver.NewMemory()))
+	rel := namedReleaseStub("web", release.Status_DEPLOYED)
+	rel.Namespace = "apps"
+
+	req := &services.ApplyReleaseSetRequest{Releases: []*services.ReleaseSetMember{
+		{Name: "web", Namespace: "apps", Chart: rel.Chart},
+		{Name: "database", Namespace: "data"},
+		{Name: "cache", Namespace: "data"},
+	}}
+	res := &services.ApplyReleaseSetResponse{Results: []*services.ReleaseSetResult{
+		{Name: "web", Status: services.ReleaseSetResult_APPLIED, Release: rel},
+		{Name: "database", Status: services.ReleaseSetResult_FAILED, Error: "timed out waiting for the condition"},
+		{Name: "cache", Status: services.ReleaseSetResult_SKIPPED},
+	}}
+	auditor.Record(clientContext("alice"), "/hapi.services.tiller.ReleaseService/ApplyReleaseSet", req, res, nil, time.Now())
+
+	if len(w.records) != 2 {
+		t.Fatalf("Expected a record by release applied, got %d", len(w.records))
+	}
+	if web := w.records[0]; web.Release != "web" || web.Outcome != audit.Success || web.Revision != 1 || web.Chart != "hello" {
+		t.Errorf("Expected the applied release to be recorded, got %+v", web)
+	}
+	if database := w.records[1]; database.Outcome != audit.Failure || database.Error != "timed out waiting for the condition" {
+		t.Errorf("Expected the failed release to be recorded, got %+v", database)
+	}
+}
@@ -0,0 +1,365 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+)
+
+// AuthzPolicy is the authorization policy of the clients of Tiller. A request
+// is allowed if any of the rules allows it.
+type AuthzPolicy struct {
+	Rules []AuthzRule `json:"rules"`
+}
+
+// AuthzRule allows the clients it names to call RPCs on the releases of
+// some namespaces.
+//
+// Clients are identified by their verified TLS certificate: users match its
+// subject common name, and groups its subject organizations. Namespaces,
+// releases and RPCs are shell patterns, as matched by path.Match. Releases and
+// RPCs default to any.
+type AuthzRule struct {
+	Users      []string `json:"users,omitempty"`
+	Groups     []string `json:"groups,omitempty"`
+	Namespaces []string `json:"namespaces"`
+	Releases   []string `json:"releases,omitempty"`
+	RPCs       []string `json:"rpcs,omitempty"`
+}
+
+// LoadAuthzPolicy reads an authorization policy from a YAML file.
+func LoadAuthzPolicy(filename string) (*AuthzPolicy, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	policy := &AuthzPolicy{}
+	if err := yaml.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("cannot parse authorization policy %s: %s", filename, err)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid authorization policy %s: %s", filename, err)
+	}
+	return policy, nil
+}
+
+func (p *AuthzPolicy) validate() error {
+	for i, rule := range p.Rules {
+		if len(rule.Users) == 0 && len(rule.Groups) == 0 {
+			return fmt.Errorf("rule %d names no users or groups", i)
+		}
+		if len(rule.Namespaces) == 0 {
+			return fmt.Errorf("rule %d allows no namespaces", i)
+		}
+		for _, patterns := range [][]string{rule.Namespaces, rule.Releases, rule.RPCs} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fmt.Errorf("rule %d: invalid pattern %q", i, pattern)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// allows reports whether the policy allows the client identified by user and
+// groups to call rpc on target.
+func (p *AuthzPolicy) allows(user string, groups []string, rpc string, t target) bool {
+	for _, rule := range p.Rules {
+		if !rule.names(user, groups) {
+			continue
+		}
+		if !matchAny(rule.Namespaces, t.namespace) {
+			continue
+		}
+		if t.named && len(rule.Releases) > 0 && !matchAny(rule.Releases, t.name) {
+			continue
+		}
+		if len(rule.RPCs) > 0 && !matchAny(rule.RPCs, rpc) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// names reports whether the rule names the client identified by user and
+// groups.
+func (r AuthzRule) names(user string, groups []string) bool {
+	if user == "" {
+		return false
+	}
+	for _, u := range r.Users {
+		if u == user {
+			return true
+		}
+	}
+	for _, g := range r.Groups {
+		for _, group := range groups {
+			if g == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// target is the release an RPC is called on.
+type target struct {
+	namespace string
+	name      string
+	// named is unset for the RPCs called on every release of a namespace.
+	named bool
+}
+
+// Authorizer authorizes the calls of the clients of Tiller according to the
+// policy of a file, reloaded as it changes.
+type Authorizer struct {
+	filename string
+	releases *storage.Storage
+
+	mu      sync.RWMutex
+	policy  *AuthzPolicy
+	modTime time.Time
+	size    int64
+
+	// Log logs the changes of the policy.
+	Log func(string, ...interface{})
+	// AuditLog logs the denied calls.
+	AuditLog func(string, ...interface{})
+}
+
+// NewAuthorizer returns an Authorizer enforcing the policy of filename. The
+// namespace of an existing release is looked up in releases.
+func NewAuthorizer(filename string, releases *storage.Storage) (*Authorizer, error) {
+	a := &Authorizer{
+		filename: filename,
+		releases: releases,
+		Log:      func(_ string, _ ...interface{}) {},
+		AuditLog: func(_ string, _ ...interface{}) {},
+	}
+	if err := a.load(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// load loads the policy file if it changed since it was last loaded.
+func (a *Authorizer) load() error {
+	fi, err := os.Stat(a.filename)
+	if err != nil {
+		return err
+	}
+	a.mu.RLock()
+	unchanged := a.policy != nil && fi.ModTime().Equal(a.modTime) && fi.Size() == a.size
+	a.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	policy, err := LoadAuthzPolicy(a.filename)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.policy, a.modTime, a.size = policy, fi.ModTime(), fi.Size()
+	a.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the policy file every interval if it changed. A policy that
+// cannot be loaded is logged, and the previous one kept.
+func (a *Authorizer) Watch(interval time.Duration) {
+	for range time.Tick(interval) {
+		a.mu.RLock()
+		modTime := a.modTime
+		a.mu.RUnlock()
+
+		if err := a.load(); err != nil {
+			a.Log("failed to reload authorization policy, keeping the previous one: %s", err)
+			continue
+		}
+
+		a.mu.RLock()
+		if !a.modTime.Equal(modTime) {
+			a.Log("reloaded authorization policy %s", a.filename)
+		}
+		a.mu.RUnlock()
+	}
+}
+
+// Authorize returns a PermissionDenied error if the client of ctx is not
+// allowed to call fullMethod with req. GetVersion is always allowed.
+func (a *Authorizer) Authorize(ctx context.Context, fullMethod string, req interface{}) error {
+	_, rpc := splitMethod(fullMethod)
+	if rpc == "GetVersion" {
+		return nil
+	}
+	user, groups := clientIdentity(ctx)
+
+	var (
+		t   target
+		err error
+	)
+	if set, ok := req.(*services.ApplyReleaseSetRequest); ok {
+		// each release of a set must be allowed in the namespace it is
+		// applied in, and in the one it exists in, if any
+		for _, m := range set.Releases {
+			t = target{namespace: m.Namespace, name: m.Name, named: true}
+			if err = a.authorize(user, groups, rpc, t); err != nil {
+				break
+			}
+			if existing := a.releaseTarget(m.Name); existing.namespace != "" && existing.namespace != t.namespace {
+				t = existing
+				if err = a.authorize(user, groups, rpc, t); err != nil {
+					break
+				}
+			}
+		}
+	} else if t, err = a.target(req); err == nil {
+		err = a.authorize(user, groups, rpc, t)
+	}
+	// a release renamed or moved must be allowed under its new name and
+	// namespace as well
+	if r, ok := req.(*services.RenameReleaseRequest); ok && err == nil {
+		t = renamedTarget(r, t)
+		err = a.authorize(user, groups, rpc, t)
+	}
+	if err == nil {
+		return nil
+	}
+
+	a.AuditLog("denied rpc=%s user=%q groups=%q namespace=%q release=%q: %s", rpc, user, groups, t.namespace, t.name, err)
+	if t.named {
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed on release %q in namespace %q", rpc, t.name, t.namespace)
+	}
+	return status.Errorf(codes.PermissionDenied, "%s is not allowed in namespace %q", rpc, t.namespace)
+}
+
+// authorize returns an error if the policy does not allow the client
+// identified by user and groups to call rpc on t.
+func (a *Authorizer) authorize(user string, groups []string, rpc string, t target) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if !a.policy.allows(user, groups, rpc, t) {
+		return errors.New("no rule allows it")
+	}
+	return nil
+}
+
+// renamedTarget returns the target of the release t renamed by r.
+func renamedTarget(r *services.RenameReleaseRequest, t target) target {
+	if r.NewName != "" {
+		t.name = r.NewName
+	}
+	if r.Namespace != "" {
+		t.namespace = r.Namespace
+	}
+	return t
+}
+
+// target returns the release req is made on. The namespace of an existing
+// release is the one it was installed in.
+func (a *Authorizer) target(req interface{}) (target, error) {
+	switch r := req.(type) {
+	case *services.InstallReleaseRequest:
+		return target{namespace: r.Namespace, name: r.Name, named: true}, nil
+	case *services.ListReleasesRequest:
+		return target{namespace: r.Namespace}, nil
+	case *services.WatchReleasesRequest:
+		if r.Name == "" {
+			return target{namespace: r.Namespace}, nil
+		}
+		t := a.releaseTarget(r.Name)
+		if r.Namespace != "" {
+			t.namespace = r.Namespace
+		}
+		return t, nil
+	case interface{ GetName() string }:
+		return a.releaseTarget(r.GetName()), nil
+	}
+	return target{}, fmt.Errorf("unknown request %T", req)
+}
+
+// releaseTarget returns the target of a request on the named release, whose
+// namespace is empty if the release does not exist.
+func (a *Authorizer) releaseTarget(name string) target {
+	t := target{name: name, named: true}
+	if rel, err := a.releases.Last(name); err == nil {
+		t.namespace = rel.Namespace
+	}
+	return t
+}
+
+// clientIdentity returns the user and groups of the verified TLS certificate
+// of the client of ctx, if any.
+func clientIdentity(ctx context.Context) (user string, groups []string) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", nil
+	}
+	chains := tlsInfo.State.VerifiedChains
+	if len(chains) == 0 || len(chains[0]) == 0 {
+		return "", nil
+	}
+	subject := chains[0][0].Subject
+	return subject.CommonName, subject.Organization
+}
+
+// authorizingStream authorizes the requests received on a server stream.
+type authorizingStream struct {
+	grpc.ServerStream
+	authz      *Authorizer
+	fullMethod string
+}
+
+func (s *authorizingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.authz.Authorize(s.Context(), s.fullMethod, m)
+}
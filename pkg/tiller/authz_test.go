@@ -0,0 +1,178 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+const authzPolicy = `
+rules:
+- groups: [team-a]
+  namespaces: [team-a, team-a-*]
+- users: [ci]
+  namespaces: ["*"]
+  releases: [ci-*]
+  rpcs: [InstallRelease, UpdateRelease, GetReleaseStatus]
+`
+
+// clientContext returns a context whose client presented a verified TLS
+// certificate for user and groups.
+func clientContext(user string, groups ...string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: user, Organization: groups}}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func writeAuthzPolicy(t *testing.T, filename, policy string) {
+	if err := ioutil.WriteFile(filename, []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-authz-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "policy.yaml")
+	writeAuthzPolicy(t, filename, authzPolicy)
+
+	releases := storage.Init(driver.NewMemory())
+	rel := namedReleaseStub("team-a-web", release.Status_DEPLOYED)
+	rel.Namespace = "team-a"
+	releases.Create(rel)
+	rel = namedReleaseStub("ci-web", release.Status_DEPLOYED)
+	rel.Namespace = "team-b"
+	releases.Create(rel)
+
+	authz, err := NewAuthorizer(filename, releases)
+	if err != nil {
+		t.Fatalf("Failed to load policy: %s", err)
+	}
+	var denied []string
+	authz.AuditLog = func(format string, args ...interface{}) {
+		denied = append(denied, fmt.Sprintf(format, args...))
+	}
+
+	const prefix = "/hapi.services.tiller.ReleaseService/"
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		method  string
+		req     interface{}
+		allowed bool
+	}{
+		{"install in own namespace", clientContext("alice", "team-a"), "InstallRelease", &services.InstallReleaseRequest{Name: "web", Namespace: "team-a-dev"}, true},
+		{"install in other namespace", clientContext("alice", "team-a"), "InstallRelease", &services.InstallReleaseRequest{Name: "web", Namespace: "kube-system"}, false},
+		{"upgrade of own release", clientContext("alice", "team-a"), "UpdateRelease", &services.UpdateReleaseRequest{Name: "team-a-web"}, true},
+		{"upgrade of other release", clientContext("alice", "team-a"), "UpdateRelease", &services.UpdateReleaseRequest{Name: "ci-web"}, false},
+		{"status of missing release", clientContext("alice", "team-a"), "GetReleaseStatus", &services.GetReleaseStatusRequest{Name: "nope"}, false},
+		{"list own namespace", clientContext("alice", "team-a"), "ListReleases", &services.ListReleasesRequest{Namespace: "team-a"}, true},
+		{"list every namespace", clientContext("alice", "team-a"), "ListReleases", &services.ListReleasesRequest{}, false},
+		{"watch own release", clientContext("alice", "team-a"), "WatchReleases", &services.WatchReleasesRequest{Name: "team-a-web"}, true},
+		{"move own release within own namespaces", clientContext("alice", "team-a"), "RenameRelease", &services.RenameReleaseRequest{Name: "team-a-web", Namespace: "team-a-dev"}, true},
+		{"apply set in own namespaces", clientContext("alice", "team-a"), "ApplyReleaseSet", &services.ApplyReleaseSetRequest{Releases: []*services.ReleaseSetMember{{Name: "team-a-web", Namespace: "team-a"}, {Name: "db", Namespace: "team-a-dev"}}}, true},
+		{"apply set upgrading other release", clientContext("alice", "team-a"), "ApplyReleaseSet", &services.ApplyReleaseSetRequest{Releases: []*services.ReleaseSetMember{{Name: "db", Namespace: "team-a"}, {Name: "ci-web", Namespace: "team-a"}}}, false},
+		{"move own release to other namespace", clientContext("alice", "team-a"), "RenameRelease", &services.RenameReleaseRequest{Name: "team-a-web", Namespace: "kube-system"}, false},
+		{"upgrade by release pattern", clientContext("ci"), "UpdateRelease", &services.UpdateReleaseRequest{Name: "ci-web"}, true},
+		{"install outside release pattern", clientContext("ci"), "InstallRelease", &services.InstallReleaseRequest{Name: "web", Namespace: "team-b"}, false},
+		{"delete outside rpc patterns", clientContext("ci"), "UninstallRelease", &services.UninstallReleaseRequest{Name: "ci-web"}, false},
+		{"unknown client", clientContext("mallory", "team-b"), "ListReleases", &services.ListReleasesRequest{Namespace: "team-a"}, false},
+		{"client without certificate", context.Background(), "ListReleases", &services.ListReleasesRequest{Namespace: "team-a"}, false},
+		{"version", context.Background(), "GetVersion", &services.GetVersionRequest{}, true},
+	}
+	for _, tt := range tests {
+		err := authz.Authorize(tt.ctx, prefix+tt.method, tt.req)
+		if tt.allowed && err != nil {
+			t.Errorf("%s: expected the call to be allowed, got %s", tt.name, err)
+		}
+		if !tt.allowed && status.Code(err) != codes.PermissionDenied {
+			t.Errorf("%s: expected the call to be denied, got %v", tt.name, err)
+		}
+	}
+
+	expected := `denied rpc=InstallRelease user="alice" groups=["team-a"] namespace="kube-system" release="web": no rule allows it`
+	if len(denied) == 0 || denied[0] != expected {
+		t.Errorf("Expected the denial to be audit logged as %q, got %q", expected, denied)
+	}
+}
+
+func TestAuthorizerReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-authz-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "policy.yaml")
+	writeAuthzPolicy(t, filename, authzPolicy)
+
+	authz, err := NewAuthorizer(filename, storage.Init(driver.NewMemory()))
+	if err != nil {
+		t.Fatalf("Failed to load policy: %s", err)
+	}
+
+	method := "/hapi.services.tiller.ReleaseService/ListReleases"
+	req := &services.ListReleasesRequest{Namespace: "team-b"}
+	if err := authz.Authorize(clientContext("bob", "team-b"), method, req); err == nil {
+		t.Fatal("Expected team-b to be denied")
+	}
+
+	writeAuthzPolicy(t, filename, authzPolicy+"- groups: [team-b]\n  namespaces: [team-b]\n")
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(filename, later, later)
+	if err := authz.load(); err != nil {
+		t.Fatalf("Failed to reload policy: %s", err)
+	}
+	if err := authz.Authorize(clientContext("bob", "team-b"), method, req); err != nil {
+		t.Errorf("Expected team-b to be allowed after reload, got %s", err)
+	}
+
+	// an invalid policy is not loaded
+	writeAuthzPolicy(t, filename, "rules:\n- groups: [team-c]\n")
+	later = later.Add(time.Minute)
+	os.Chtimes(filename, later, later)
+	if err := authz.load(); err == nil {
+		t.Error("Expected an error loading a rule without namespaces")
+	}
+	if err := authz.Authorize(clientContext("bob", "team-b"), method, req); err != nil {
+		t.Errorf("Expected the previous policy to be kept, got %s", err)
+	}
+}
@@ -0,0 +1,267 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// durationBuckets are the buckets of the durations of operations and hooks,
+// which may wait for resources for minutes.
+var durationBuckets = []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+var (
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tiller_operation_duration_seconds",
+		Help:    "Duration of the calls made to Tiller, by RPC and outcome.",
+		Buckets: durationBuckets,
+	}, []string{"rpc", "outcome"})
+
+	hookDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tiller_hook_duration_seconds",
+		Help:    "Duration of the hooks run by Tiller, by hook event and outcome.",
+		Buckets: durationBuckets,
+	}, []string{"event", "outcome"})
+
+	waitTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tiller_wait_timeouts_total",
+		Help: "Number of calls made to Tiller that failed waiting for resources or hooks, by RPC.",
+	}, []string{"rpc"})
+
+	releasesDesc = prometheus.NewDesc(
+		"tiller_releases",
+		"Number of releases, by namespace and status of their last revision.",
+		[]string{"namespace", "status"}, nil)
+
+	releaseStatusDesc = prometheus.NewDesc(
+		"tiller_release_status",
+		"Status of the last revision of each release, always 1.",
+		[]string{"release", "namespace", "status"}, nil)
+
+	releaseLastDeployedDesc = prometheus.NewDesc(
+		"tiller_release_last_deployed_timestamp_seconds",
+		"Time at which the last revision of each release was deployed.",
+		[]string{"release", "namespace"}, nil)
+)
+
+// RegisterMetrics registers the metrics of the releases and operations of
+// Tiller with r. The releases are listed from releases when the metrics are
+// first collected and every releaseMetricsResync at most, and the metrics are
+// kept up to date in between by the changes notified by releases.
+func RegisterMetrics(r prometheus.Registerer, releases *storage.Storage) error {
+	for _, c := range []prometheus.Collector{
+		operationDuration,
+		hookDuration,
+		waitTimeouts,
+		newReleaseCollector(releases),
+	} {
+		if err := r.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// observeOperation records the call of fullMethod started at start, which
+// failed with err, if any.
+func observeOperation(fullMethod string, start time.Time, err error) {
+	_, rpc := splitMethod(fullMethod)
+	operationDuration.WithLabelValues(rpc, outcome(err)).Observe(time.Since(start).Seconds())
+	if err != nil && strings.Contains(err.Error(), wait.ErrWaitTimeout.Error()) {
+		waitTimeouts.WithLabelValues(rpc).Inc()
+	}
+}
+
+// observeHook records a hook run for event started at start, which failed
+// with err, if any.
+func observeHook(event release.Hook_Event, start time.Time, err error) {
+	hookDuration.WithLabelValues(hookEventName(event), outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// hookEventName returns the name of event in hook annotations, e.g.
+// post-install.
+func hookEventName(event release.Hook_Event) string {
+	return strings.Replace(strings.ToLower(event.String()), "_", "-", -1)
+}
+
+// releaseMetricsResync is the maximum interval at which the release metrics
+// are listed from storage again, catching up with the changes not notified by
+// the storage, e.g. the ones made by other Tillers or the history moved by a
+// rename.
+const releaseMetricsResync = 10 * time.Minute
+
+// releaseCollector collects the metrics of the last revision of every
+// release. The revisions are read from storage at most every resync, and the
+// changes notified by the storage are applied in between, so that scrapes do
+// not read every release.
+type releaseCollector struct {
+	releases *storage.Storage
+	resync   time.Duration
+
+	// loadMu serializes the reloads of revisions.
+	loadMu sync.Mutex
+
+	mu     sync.Mutex
+	loaded time.Time
+	// revisions are the metrics of the revisions of each release, by name
+	// and version, or nil before they are first loaded.
+	revisions map[string]map[int32]revisionMetrics
+	// changes are the changes notified during a reload, applied again to
+	// the reloaded revisions. It is nil when no reload is in progress.
+	changes []revisionChange
+}
+
+// revisionMetrics holds the values of the metrics of a revision.
+type revisionMetrics struct {
+	namespace string
+	status    string
+	// deployed is the time at which the revision was deployed, in seconds,
+	// or 0 if unknown.
+	deployed float64
+}
+
+// revisionChange is a change notified by the storage.
+type revisionChange struct {
+	change storage.Change
+	rls    *release.Release
+}
+
+// newReleaseCollector returns a collector of the metrics of the releases of
+// releases, notified of their changes.
+func newReleaseCollector(releases *storage.Storage) *releaseCollector {
+	c := &releaseCollector{releases: releases, resync: releaseMetricsResync}
+	addNotify(releases, c.storageChanged)
+	return c
+}
+
+func (c *releaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- releasesDesc
+	ch <- releaseStatusDesc
+	ch <- releaseLastDeployedDesc
+}
+
+func (c *releaseCollector) Collect(ch chan<- prometheus.Metric) {
+	if err := c.reload(); err != nil {
+		ch <- prometheus.NewInvalidMetric(releasesDesc, err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	type key struct{ namespace, status string }
+	counts := map[key]int{}
+	for name, revisions := range c.revisions {
+		var last int32
+		for version := range revisions {
+			if version > last {
+				last = version
+			}
+		}
+		m := revisions[last]
+		counts[key{m.namespace, m.status}]++
+		ch <- prometheus.MustNewConstMetric(releaseStatusDesc, prometheus.GaugeValue, 1, name, m.namespace, m.status)
+		if m.deployed != 0 {
+			ch <- prometheus.MustNewConstMetric(releaseLastDeployedDesc, prometheus.GaugeValue, m.deployed, name, m.namespace)
+		}
+	}
+	for k, n := range counts {
+		ch <- prometheus.MustNewConstMetric(releasesDesc, prometheus.GaugeValue, float64(n), k.namespace, k.status)
+	}
+}
+
+// reload lists the revisions from storage if they were never loaded or were
+// loaded more than resync ago. The storage is read without holding c.mu, so
+// that the changes notified meanwhile do not wait for it.
+func (c *releaseCollector) reload() error {
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	c.mu.Lock()
+	if c.revisions != nil && time.Since(c.loaded) < c.resync {
+		c.mu.Unlock()
+		return nil
+	}
+	c.changes = []revisionChange{}
+	c.mu.Unlock()
+
+	rels, err := c.releases.ListReleases()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changes := c.changes
+	c.changes = nil
+	if err != nil {
+		return err
+	}
+	c.revisions = map[string]map[int32]revisionMetrics{}
+	c.loaded = time.Now()
+	for _, rls := range rels {
+		c.apply(storage.ReleaseCreated, rls)
+	}
+	for _, rc := range changes {
+		c.apply(rc.change, rc.rls)
+	}
+	return nil
+}
+
+// storageChanged applies a change made to rls in storage. It is called by the
+// Notify function of the release storage.
+func (c *releaseCollector) storageChanged(change storage.Change, rls *release.Release) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.changes != nil {
+		c.changes = append(c.changes, revisionChange{change, rls})
+	}
+	if c.revisions != nil {
+		c.apply(change, rls)
+	}
+}
+
+// apply applies a change made to rls to the revisions. c.mu must be held.
+func (c *releaseCollector) apply(change storage.Change, rls *release.Release) {
+	revisions := c.revisions[rls.Name]
+	if change == storage.ReleaseDeleted {
+		delete(revisions, rls.Version)
+		if len(revisions) == 0 {
+			delete(c.revisions, rls.Name)
+		}
+		return
+	}
+
+	if revisions == nil {
+		revisions = map[int32]revisionMetrics{}
+		c.revisions[rls.Name] = revisions
+	}
+	m := revisionMetrics{
+		namespace: rls.Namespace,
+		status:    rls.GetInfo().GetStatus().GetCode().String(),
+	}
+	if deployed := rls.GetInfo().GetLastDeployed(); deployed != nil {
+		m.deployed = float64(timeconv.Time(deployed).Unix())
+	}
+	revisions[rls.Version] = m
+}
@@ -0,0 +1,180 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+// gatherMetrics returns the values of the metrics collected by c, keyed by
+// metric name and labels, e.g. `tiller_releases{namespace="default",status="DEPLOYED"}`.
+func gatherMetrics(t *testing.T, c prometheus.Collector) map[string]float64 {
+	r := prometheus.NewRegistry()
+	if err := r.Register(c); err != nil {
+		t.Fatalf("Failed to register collector: %s", err)
+	}
+	families, err := r.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %s", err)
+	}
+	values := map[string]float64{}
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			key := f.GetName() + "{"
+			for i, l := range m.GetLabel() {
+				if i > 0 {
+					key += ","
+				}
+				key += fmt.Sprintf("%s=%q", l.GetName(), l.GetValue())
+			}
+			key += "}"
+			values[key] = m.GetGauge().GetValue() + m.GetCounter().GetValue()
+		}
+	}
+	return values
+}
+
+func TestReleaseCollector(t *testing.T) {
+	releases := storage.Init(driver.NewMemory())
+	for _, rel := range []*release.Release{
+		namedReleaseStub("angry-panda", release.Status_SUPERSEDED),
+		namedReleaseStub("angry-panda", release.Status_FAILED),
+		namedReleaseStub("web", release.Status_DEPLOYED),
+		namedReleaseStub("db", release.Status_DEPLOYED),
+	} {
+		rel.Namespace = "default"
+		if rel.Name == "angry-panda" && rel.Info.Status.Code == release.Status_FAILED {
+			rel.Version = 2
+		}
+		if err := releases.Create(rel); err != nil {
+			t.Fatalf("Failed to create release: %s", err)
+		}
+	}
+
+	values := gatherMetrics(t, newReleaseCollector(releases))
+	expected := map[string]float64{
+		`tiller_releases{namespace="default",status="DEPLOYED"}`:                                    2,
+		`tiller_releases{namespace="default",status="FAILED"}`:                                      1,
+		`tiller_release_status{namespace="default",release="angry-panda",status="FAILED"}`:          1,
+		`tiller_release_status{namespace="default",release="web",status="DEPLOYED"}`:                1,
+		`tiller_release_status{namespace="default",release="db",status="DEPLOYED"}`:                 1,
+		`tiller_release_last_deployed_timestamp_seconds{namespace="default",release="web"}`:         242085845,
+		`tiller_release_last_deployed_timestamp_seconds{namespace="default",release="db"}`:          242085845,
+		`tiller_release_last_deployed_timestamp_seconds{namespace="default",release="angry-panda"}`: 242085845,
+	}
+	if len(values) != len(expected) {
+		t.Errorf("Expected %d metrics, got %d: %v", len(expected), len(values), values)
+	}
+	for k, v := range expected {
+		if got, ok := values[k]; !ok || got != v {
+			t.Errorf("Expected %s to be %v, got %v", k, v, values)
+		}
+	}
+}
+
+// listCountingDriver counts the calls made to List.
+type listCountingDriver struct {
+	*driver.Memory
+	lists int
+}
+
+func (d *listCountingDriver) List(filter func(*release.Release) bool) ([]*release.Release, error) {
+	d.lists++
+	return d.Memory.List(filter)
+}
+
+func TestReleaseCollectorNotified(t *testing.T) {
+	d := &listCountingDriver{Memory: driver.NewMemory()}
+	releases := storage.Init(d)
+	c := newReleaseCollector(releases)
+
+	web := namedReleaseStub("web", release.Status_DEPLOYED)
+	web.Namespace = "default"
+	if err := releases.Create(web); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	status := func() map[string]float64 {
+		status := map[string]float64{}
+		for k, v := range gatherMetrics(t, c) {
+			if strings.HasPrefix(k, "tiller_release_status{") {
+				status[k] = v
+			}
+		}
+		return status
+	}
+	expectStatus := func(expected string) {
+		t.Helper()
+		if got := status(); len(got) != 1 || got[expected] != 1 {
+			t.Errorf("Expected %s, got %v", expected, got)
+		}
+	}
+	expectStatus(`tiller_release_status{namespace="default",release="web",status="DEPLOYED"}`)
+
+	upgraded := namedReleaseStub("web", release.Status_FAILED)
+	upgraded.Namespace = "default"
+	upgraded.Version = 2
+	if err := releases.Create(upgraded); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	web.Info.Status.Code = release.Status_SUPERSEDED
+	if err := releases.Update(web); err != nil {
+		t.Fatalf("Failed to update release: %s", err)
+	}
+	expectStatus(`tiller_release_status{namespace="default",release="web",status="FAILED"}`)
+
+	if _, err := releases.Delete("web", 2); err != nil {
+		t.Fatalf("Failed to delete release: %s", err)
+	}
+	expectStatus(`tiller_release_status{namespace="default",release="web",status="SUPERSEDED"}`)
+
+	if d.lists != 1 {
+		t.Errorf("Expected the releases to be listed once, got %d", d.lists)
+	}
+
+	c.resync = 0
+	status()
+	if d.lists != 2 {
+		t.Errorf("Expected the releases to be listed again once stale, got %d lists", d.lists)
+	}
+}
+
+func TestObserveOperation(t *testing.T) {
+	const (
+		method = "/hapi.services.tiller.ReleaseService/RollbackRelease"
+		key    = `tiller_wait_timeouts_total{rpc="RollbackRelease"}`
+	)
+	before := gatherMetrics(t, waitTimeouts)[key]
+
+	observeOperation(method, time.Now(), nil)
+	observeOperation(method, time.Now(), errors.New("release angry-panda failed: not found"))
+	observeOperation(method, time.Now(), fmt.Errorf("release angry-panda failed: %s", wait.ErrWaitTimeout))
+
+	if after := gatherMetrics(t, waitTimeouts)[key]; after != before+1 {
+		t.Errorf("Expected one wait timeout to be counted, got %v", after-before)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// adoption is the set of existing resources a release adopts, i.e. the
+// resources of its manifest which already exist in the cluster outside of any
+// release.
+type adoption struct {
+	name      string
+	namespace string
+	resources []*services.AdoptedResource
+	// live are the manifests of the live state of the adopted resources,
+	// restricted to the fields set by the release.
+	live []string
+}
+
+// release returns the release the adopting release is applied from: previous,
+// if any, holding the live state of the adopted resources as well. Applying
+// the release patches the adopted resources from their live state to the
+// rendered one, leaving the fields the release does not set as they are.
+func (a *adoption) release(previous *release.Release) *release.Release {
+	if a == nil || len(a.resources) == 0 {
+		return previous
+	}
+	rel := &release.Release{Name: a.name, Namespace: a.namespace}
+	if previous != nil {
+		copied := *previous
+		rel = &copied
+	}
+	rel.Manifest = strings.TrimSuffix(rel.Manifest, "\n") + "\n" + joinManifests(a.live)
+	return rel
+}
+
+// adoptExisting finds the resources of rel which are not part of previous, if
+// any, but already exist in the cluster. The resources of other releases are
+// never adopted.
+func (s *ReleaseServer) adoptExisting(rel, previous *release.Release) (*adoption, error) {
+	a := &adoption{name: rel.Name, namespace: rel.Namespace}
+
+	resources, err := splitResources(rel.Manifest, rel.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	if previous != nil {
+		current, err := splitResources(previous.Manifest, previous.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range current {
+			existing[r.key()] = true
+		}
+	}
+
+	owners, err := s.resourceOwners(rel.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeCli := s.kubeClient(rel.Name)
+	for _, r := range resources {
+		if existing[r.key()] {
+			continue
+		}
+		if owner, ok := owners[r.key()]; ok {
+			return nil, fmt.Errorf("cannot adopt %s %q in %s: it belongs to release %q", r.kind, r.name, r.namespace, owner)
+		}
+
+		infos, err := kubeCli.Build(r.namespace, strings.NewReader(r.content))
+		if err != nil {
+			return nil, err
+		}
+		if len(infos) == 0 {
+			continue
+		}
+		info := infos[0]
+		if err := info.Get(); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("could not get live state of %s %q: %s", r.kind, r.name, err)
+			}
+			continue
+		}
+
+		// the live object is looked up by the kind and name of the manifest,
+		// which the cluster may resolve to another resource, e.g. through a
+		// deprecated API group
+		accessor, err := meta.Accessor(info.Object)
+		if err != nil {
+			return nil, err
+		}
+		if kind := info.Mapping.GroupVersionKind.Kind; kind != r.kind || accessor.GetName() != r.name {
+			return nil, fmt.Errorf("cannot adopt %s %q in %s: the existing resource is %s %q", r.kind, r.name, r.namespace, kind, accessor.GetName())
+		}
+
+		desired, err := decodeManifest(r.content)
+		if err != nil {
+			return nil, err
+		}
+		live, err := runtime.DefaultUnstructuredConverter.ToUnstructured(info.Object)
+		if err != nil {
+			return nil, err
+		}
+		// the type of a typed object is not kept when decoding it
+		live["apiVersion"], live["kind"] = desired["apiVersion"], desired["kind"]
+		liveYAML, err := yaml.Marshal(pruneLive(live, desired))
+		if err != nil {
+			return nil, err
+		}
+
+		s.Log("adopting %s %q in %s into %s", r.kind, r.name, r.namespace, rel.Name)
+		a.resources = append(a.resources, &services.AdoptedResource{Kind: r.kind, Name: r.name, Namespace: r.namespace})
+		a.live = append(a.live, string(liveYAML))
+	}
+	return a, nil
+}
+
+// resourceOwners returns the names of the releases other than the named one
+// by the keys of their resources, according to the last revision of each
+// release not deleted.
+func (s *ReleaseServer) resourceOwners(name string) (map[string]string, error) {
+	rels, err := s.env.Releases.ListReleases()
+	if err != nil {
+		return nil, err
+	}
+	last := map[string]*release.Release{}
+	for _, r := range rels {
+		if r.Name == name {
+			continue
+		}
+		if l, ok := last[r.Name]; !ok || r.Version > l.Version {
+			last[r.Name] = r
+		}
+	}
+
+	owners := map[string]string{}
+	for _, r := range last {
+		if r.Info.Status.Code == release.Status_DELETED {
+			continue
+		}
+		resources, err := splitResources(r.Manifest, r.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range resources {
+			owners[res.key()] = r.Name
+		}
+	}
+	return owners, nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+var manifestWithSharedConfigMap = `kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: shared
+data:
+  name: value`
+
+func withAdoptExisting() installOption {
+	return func(opts *installOptions) {
+		opts.AdoptExisting = true
+	}
+}
+
+func TestInstallRelease_AdoptOtherRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	owner := namedReleaseStub("shy-koala", release.Status_DEPLOYED)
+	owner.Namespace = "spaced"
+	owner.Manifest = "---\n" + manifestWithSharedConfigMap
+	rs.env.Releases.Create(owner)
+
+	req := installRequest(withName("web"), withAdoptExisting(), withChart(withTemplate("templates/shared", manifestWithSharedConfigMap)))
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected the resource of another release not to be adopted")
+	}
+	if !strings.Contains(err.Error(), `belongs to release "shy-koala"`) {
+		t.Errorf("Expected the owner of the resource in the error, got %q", err)
+	}
+	if _, err := rs.env.Releases.Get("web", 1); err == nil {
+		t.Error("Expected no release to be recorded")
+	}
+
+	// the resources of deleted releases are free to adopt
+	owner.Info.Status.Code = release.Status_DELETED
+	rs.env.Releases.Update(owner)
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if res.Release.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected a deployed release, got %s", res.Release.Info.Status.Code)
+	}
+}
+
+func TestInstallRelease_AdoptDryRun(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := installRequest(withName("web"), withDryRun(), withAdoptExisting(), withChart(withTemplate("templates/shared", manifestWithSharedConfigMap)))
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	// none of the resources exist in the mock cluster
+	if len(res.Adopted) != 0 {
+		t.Errorf("Expected no resource to adopt, got %v", res.Adopted)
+	}
+	if res.Release.Info.Description != "Dry run complete" {
+		t.Errorf("Expected a dry run, got %q", res.Release.Info.Description)
+	}
+}
+
+func TestUpdateRelease_AdoptOtherRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	owner := namedReleaseStub("shy-koala", release.Status_FAILED)
+	owner.Manifest = "---\n" + manifestWithSharedConfigMap
+	rs.env.Releases.Create(owner)
+
+	req := &services.UpdateReleaseRequest{
+		Name:          rel.Name,
+		Chart:         buildChart(withTemplate("templates/shared", manifestWithSharedConfigMap)),
+		AdoptExisting: true,
+	}
+	_, err := rs.UpdateRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), `belongs to release "shy-koala"`) {
+		t.Errorf("Expected the resource of another release not to be adopted, got %v", err)
+	}
+	if _, err := rs.env.Releases.Get(rel.Name, 2); err == nil {
+		t.Error("Expected no revision to be recorded")
+	}
+}
+
+func TestAdoptionRelease(t *testing.T) {
+	var none *adoption
+	if none.release(nil) != nil {
+		t.Error("Expected no release without adoption")
+	}
+
+	previous := releaseStub()
+	previous.Manifest = "---\n# Source: hello/templates/hello\nhello: world\n"
+	a := &adoption{
+		name:      previous.Name,
+		resources: []*services.AdoptedResource{{Kind: "ConfigMap", Name: "shared"}},
+		live:      []string{manifestWithSharedConfigMap},
+	}
+
+	rel := a.release(previous)
+	if rel == previous || previous.Manifest != "---\n# Source: hello/templates/hello\nhello: world\n" {
+		t.Fatal("Expected the previous release to be left as is")
+	}
+	resources, err := splitResources(rel.Manifest, "spaced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 1 || resources[0].key() != "ConfigMap/spaced/shared" {
+		t.Errorf("Expected the adopted resource in the manifest, got %v", resources)
+	}
+
+	// installs apply from an empty release
+	if rel := a.release(nil); rel.Name != previous.Name || rel.Version != 0 || !strings.Contains(rel.Manifest, "name: shared") {
+		t.Errorf("Expected a release holding the adopted resources only, got %+v", rel)
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// CancelOperation cancels the install, upgrade or rollback in progress on a
+// release, and waits for it to end.
+//
+// The waits of the operation for its resources and hooks are interrupted, and
+// the revision it created is marked as failed. With req.Cleanup, the resources
+// the operation created are deleted, as they would be with cleanup_on_fail.
+func (s *ReleaseServer) CancelOperation(c ctx.Context, req *services.CancelOperationRequest) (*services.CancelOperationResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("cancelOperation: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+
+	op := s.operations.cancel(req.Name, req.Cleanup)
+	if op == nil {
+		return nil, fmt.Errorf("no operation is in progress for release %q", req.Name)
+	}
+	s.Log("cancelling the operation in progress for %s", req.Name)
+
+	select {
+	case <-op.done:
+	case <-c.Done():
+		return nil, c.Err()
+	}
+
+	rel, err := s.env.Releases.Last(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &services.CancelOperationResponse{Release: rel}, nil
+}
+
+// operation is an install, upgrade or rollback of a release in progress.
+type operation struct {
+	name string
+	// ctx is done once the operation is cancelled.
+	ctx    ctx.Context
+	cancel ctx.CancelFunc
+	// done is closed once the operation ended.
+	done chan struct{}
+	// cleanup is set if the resources created by the cancelled operation
+	// are to be deleted.
+	cleanup bool
+}
+
+// operations are the operations in progress, by release name.
+type operations struct {
+	mu  sync.Mutex
+	ops map[string]*operation
+}
+
+func newOperations() *operations {
+	return &operations{ops: map[string]*operation{}}
+}
+
+// start registers the operation starting on the named release. It must be
+// ended once done.
+func (o *operations) start(name string) *operation {
+	c, cancel := ctx.WithCancel(ctx.Background())
+	op := &operation{name: name, ctx: c, cancel: cancel, done: make(chan struct{})}
+	o.mu.Lock()
+	o.ops[name] = op
+	o.mu.Unlock()
+	return op
+}
+
+// end unregisters op, which ended.
+func (o *operations) end(op *operation) {
+	o.mu.Lock()
+	if o.ops[op.name] == op {
+		delete(o.ops, op.name)
+	}
+	o.mu.Unlock()
+	op.cancel()
+	close(op.done)
+}
+
+// get returns the operation in progress on the named release, if any.
+func (o *operations) get(name string) *operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.ops[name]
+}
+
+// cancel cancels the operation in progress on the named release and returns
+// it, or nil if there is none.
+func (o *operations) cancel(name string, cleanup bool) *operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	op := o.ops[name]
+	if op == nil {
+		return nil
+	}
+	op.cleanup = op.cleanup || cleanup
+	op.cancel()
+	return op
+}
+
+// cancelled reports whether op was cancelled and whether its resources are
+// to be deleted.
+func (o *operations) cancelled(op *operation) (cancelled, cleanup bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return op.ctx.Err() != nil, op.cleanup
+}
+
+// kubeClient returns the KubeClient used for the named release. While an
+// operation is in progress on the release, the waits of the client are
+// interrupted once it is cancelled. The waits of the Rudder service and of
+// clients other than a *kube.Client cannot be interrupted. While the release
+// is traced, the calls made to the client are traced too.
+func (s *ReleaseServer) kubeClient(name string) environment.KubeClient {
+	kubeCli := s.env.KubeClient
+	if c, ok := kubeCli.(*kube.Client); ok {
+		if op := s.operations.get(name); op != nil {
+			kubeCli = c.WithContext(op.ctx)
+		}
+	}
+	if span := s.spans.get(name); span != nil {
+		kubeCli = &tracedKubeClient{KubeClient: kubeCli, span: span}
+	}
+	return kubeCli
+}
+
+// kubeEnv returns the environment of the operations on the named release, its
+// KubeClient being the one of kubeClient.
+func (s *ReleaseServer) kubeEnv(name string) *environment.Environment {
+	env := *s.env
+	env.KubeClient = s.kubeClient(name)
+	return &env
+}
+
+// operationFailed returns the error of op, which failed with err. If op was
+// cancelled, the revision rel it created is marked as failed, the resources
+// rel adds to previous are deleted on request, and the returned error tells
+// that the operation was cancelled.
+func (s *ReleaseServer) operationFailed(op *operation, action string, previous, rel *release.Release, err error) error {
+	cancelled, cleanup := s.operations.cancelled(op)
+	if !cancelled {
+		return err
+	}
+	s.Log("%s of %s cancelled: %s", strings.ToLower(action), op.name, err)
+	err = fmt.Errorf("%s of release %q cancelled", strings.ToLower(action), op.name)
+	if rel == nil {
+		return err
+	}
+
+	// the revision is not recorded yet if the operation was cancelled early
+	if _, getErr := s.env.Releases.Get(rel.Name, rel.Version); getErr == nil {
+		rel.Info.Status.Code = release.Status_FAILED
+		rel.Info.Description = fmt.Sprintf("%s cancelled", action)
+		s.recordRelease(rel, true)
+	}
+
+	if cleanup {
+		if errs := s.cleanupOperation(previous, rel); len(errs) > 0 {
+			return fmt.Errorf("%s, cleanup failed: %s", err, strings.Join(errs, " && "))
+		}
+	}
+	return err
+}
+
+// cleanupOperation deletes the resources of rel which previous does not have,
+// i.e. the resources created by the operation going from previous to rel.
+// previous is nil for installs.
+func (s *ReleaseServer) cleanupOperation(previous, rel *release.Release) []string {
+	created, err := splitResources(rel.Manifest, rel.Namespace)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	existing := map[string]bool{}
+	if previous != nil {
+		resources, err := splitResources(previous.Manifest, previous.Namespace)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		for _, r := range resources {
+			existing[r.key()] = true
+		}
+	}
+
+	var errs []string
+	for _, r := range created {
+		if existing[r.key()] {
+			continue
+		}
+		s.Log("deleting %s %q in %s created by the cancelled operation on %s", r.kind, r.name, r.namespace, rel.Name)
+		if err := s.env.KubeClient.Delete(r.namespace, strings.NewReader(r.content)); err != nil && err != kube.ErrNoObjectsVisited {
+			errs = append(errs, err.Error())
+		}
+	}
+	return errs
+}
@@ -0,0 +1,133 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// waitingKubeClient waits for hooks until the operation in progress on the
+// named release is cancelled, as a *kube.Client bound to it does.
+type waitingKubeClient struct {
+	environment.PrintingKubeClient
+	rs      *ReleaseServer
+	name    string
+	waiting chan struct{}
+	deleted []string
+}
+
+func newWaitingKubeClient(rs *ReleaseServer, name string) *waitingKubeClient {
+	return &waitingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+		rs:                 rs,
+		name:               name,
+		waiting:            make(chan struct{}, 1),
+	}
+}
+
+func (k *waitingKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	op := k.rs.operations.get(k.name)
+	if op == nil {
+		return errors.New("no operation in progress")
+	}
+	k.waiting <- struct{}{}
+	<-op.ctx.Done()
+	return errors.New("wait interrupted: context canceled")
+}
+
+func (k *waitingKubeClient) Delete(ns string, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	k.deleted = append(k.deleted, string(b))
+	return nil
+}
+
+func TestCancelOperation(t *testing.T) {
+	rs := rsFixture()
+	kubeClient := newWaitingKubeClient(rs, "stuck-panda")
+	rs.env.KubeClient = kubeClient
+
+	ch := buildChart()
+	ch.Templates = append(ch.Templates, &chart.Template{Name: "templates/cm", Data: []byte("kind: ConfigMap\nmetadata:\n  name: cm\n")})
+	req := &services.InstallReleaseRequest{Name: "stuck-panda", Namespace: "spaced", Chart: ch}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := rs.InstallRelease(helm.NewContext(), req)
+		done <- err
+	}()
+
+	// the post-install hook waits
+	select {
+	case <-kubeClient.waiting:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the install to wait for its hook")
+	}
+
+	res, err := rs.CancelOperation(helm.NewContext(), &services.CancelOperationRequest{Name: "stuck-panda", Cleanup: true})
+	if err != nil {
+		t.Fatalf("Failed cancel: %s", err)
+	}
+	if code := res.Release.Info.Status.Code; code != release.Status_FAILED {
+		t.Errorf("Expected FAILED release, got %s", code)
+	}
+	if desc := res.Release.Info.Description; desc != "Install cancelled" {
+		t.Errorf("Expected description %q, got %q", "Install cancelled", desc)
+	}
+
+	err = <-done
+	if err == nil || !strings.Contains(err.Error(), `install of release "stuck-panda" cancelled`) {
+		t.Errorf("Expected the install to be cancelled, got %v", err)
+	}
+	if len(kubeClient.deleted) != 1 || !strings.Contains(kubeClient.deleted[0], "name: cm") {
+		t.Errorf("Expected the ConfigMap to be cleaned up, got %q", kubeClient.deleted)
+	}
+
+	if _, err := rs.CancelOperation(helm.NewContext(), &services.CancelOperationRequest{Name: "stuck-panda"}); err == nil {
+		t.Error("Expected an error cancelling a release without operation in progress")
+	}
+}
+
+func TestOperationFailedNotCancelled(t *testing.T) {
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	op := rs.operations.start(rel.Name)
+	defer rs.operations.end(op)
+
+	failure := errors.New("Failed update in kube client")
+	if err := rs.operationFailed(op, "Upgrade", nil, rel, failure); err != failure {
+		t.Errorf("Expected the error of the operation, got %v", err)
+	}
+	if code := rel.Info.Status.Code; code != release.Status_DEPLOYED {
+		t.Errorf("Expected the release to be left as is, got %s", code)
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pmezard/go-difflib/difflib"
+	ctx "golang.org/x/net/context"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+// DiffRelease compares the deployed release with the release an update to the
+// requested chart and values would create, resource by resource.
+func (s *ReleaseServer) DiffRelease(c ctx.Context, req *services.DiffReleaseRequest) (*services.DiffReleaseResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("diffRelease: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+
+	s.Log("preparing diff for %s", req.Name)
+	currentRelease, updatedRelease, err := s.prepareUpdate(&services.UpdateReleaseRequest{
+		Name:        req.Name,
+		Chart:       req.Chart,
+		Values:      req.Values,
+		ResetValues: req.ResetValues,
+		ReuseValues: req.ReuseValues,
+	})
+	if err != nil {
+		return nil, err
+	}
+	updatedRelease.Info.Description = "Diff complete"
+
+	current, err := splitResources(currentRelease.Manifest, currentRelease.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	proposed, err := splitResources(updatedRelease.Manifest, updatedRelease.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	context := int(req.Context)
+	if context < 0 {
+		context = 0
+	}
+	diffs := diffResources(current, proposed, context)
+	if req.Live {
+		// the diffs of the proposed resources come first, in the same order
+		for i, r := range proposed {
+			if err := s.diffLive(diffs[i], r, context); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &services.DiffReleaseResponse{Release: updatedRelease, Resources: diffs}, nil
+}
+
+// resourceManifest is the manifest of a single resource of a release.
+type resourceManifest struct {
+	kind      string
+	name      string
+	namespace string
+	content   string
+}
+
+// key identifies a resource across revisions of a release.
+func (r resourceManifest) key() string {
+	return r.kind + "/" + r.namespace + "/" + r.name
+}
+
+// splitResources splits the manifest of a release into the manifests of its
+// resources, in order. Resources without a namespace are in namespace.
+func splitResources(manifest, namespace string) ([]resourceManifest, error) {
+	docs := relutil.SplitManifests(manifest)
+	resources := make([]resourceManifest, 0, len(docs))
+	for i := 0; i < len(docs); i++ {
+		content := docs[fmt.Sprintf("manifest-%d", i)]
+		var head struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		if err := yaml.Unmarshal([]byte(content), &head); err != nil {
+			return nil, fmt.Errorf("YAML parse error on manifest %d: %s", i, err)
+		}
+		// documents holding nothing but comments
+		if head.Kind == "" {
+			continue
+		}
+		r := resourceManifest{
+			kind:      head.Kind,
+			name:      head.Metadata.Name,
+			namespace: head.Metadata.Namespace,
+			content:   content,
+		}
+		if r.namespace == "" {
+			r.namespace = namespace
+		}
+		resources = append(resources, r)
+	}
+	return resources, nil
+}
+
+// diffResources compares the current resources of a release with the proposed
+// ones. The diffs of the proposed resources come first, in order, followed by
+// the resources the update removes.
+func diffResources(current, proposed []resourceManifest, context int) []*services.ResourceDiff {
+	previous := make(map[string]resourceManifest, len(current))
+	for _, r := range current {
+		previous[r.key()] = r
+	}
+
+	diffs := make([]*services.ResourceDiff, 0, len(proposed))
+	for _, r := range proposed {
+		d := &services.ResourceDiff{
+			Kind:      r.kind,
+			Name:      r.name,
+			Namespace: r.namespace,
+			Change:    services.ResourceDiff_ADDED,
+		}
+		prev, ok := previous[r.key()]
+		if ok {
+			delete(previous, r.key())
+			d.Change = services.ResourceDiff_MODIFIED
+			if prev.content == r.content {
+				d.Change = services.ResourceDiff_UNCHANGED
+			}
+		}
+		d.Diff = unifiedDiff(prev.content, r.content, "deployed", "proposed", context)
+		diffs = append(diffs, d)
+	}
+
+	for _, r := range current {
+		if _, ok := previous[r.key()]; !ok {
+			continue
+		}
+		diffs = append(diffs, &services.ResourceDiff{
+			Kind:      r.kind,
+			Name:      r.name,
+			Namespace: r.namespace,
+			Change:    services.ResourceDiff_REMOVED,
+			Diff:      unifiedDiff(r.content, "", "deployed", "proposed", context),
+		})
+	}
+	return diffs
+}
+
+// diffLive compares the proposed resource r with its state in the cluster and
+// records the result in d. Only the fields set by the manifest of r are
+// compared, so that fields defaulted or maintained by the cluster, like the
+// status of a resource, are not reported as changes.
+func (s *ReleaseServer) diffLive(d *services.ResourceDiff, r resourceManifest, context int) error {
+	infos, err := s.env.KubeClient.BuildUnstructured(r.namespace, strings.NewReader(r.content))
+	if err != nil {
+		return err
+	}
+	if len(infos) == 0 {
+		return nil
+	}
+
+	desired, err := decodeManifest(r.content)
+	if err != nil {
+		return err
+	}
+	desiredYAML, err := yaml.Marshal(desired)
+	if err != nil {
+		return err
+	}
+
+	info := infos[0]
+	if err := info.Get(); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("could not get live state of %s %q: %s", r.kind, r.name, err)
+		}
+		d.LiveChange = services.ResourceDiff_ADDED
+		d.LiveDiff = unifiedDiff("", string(desiredYAML), "live", "proposed", context)
+		return nil
+	}
+
+	live, err := runtime.DefaultUnstructuredConverter.ToUnstructured(info.Object)
+	if err != nil {
+		return err
+	}
+	liveYAML, err := yaml.Marshal(pruneLive(live, desired))
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(liveYAML, desiredYAML) {
+		d.LiveChange = services.ResourceDiff_UNCHANGED
+		return nil
+	}
+	d.LiveChange = services.ResourceDiff_MODIFIED
+	d.LiveDiff = unifiedDiff(string(liveYAML), string(desiredYAML), "live", "proposed", context)
+	return nil
+}
+
+// decodeManifest decodes the manifest of a resource, keeping numbers as they
+// are written so that they compare equal to the integers of the live state.
+func decodeManifest(content string) (map[string]interface{}, error) {
+	data, err := yaml.YAMLToJSON([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// pruneLive returns the parts of the live value that are also set in the
+// desired one.
+func pruneLive(live, desired interface{}) interface{} {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		pruned := make(map[string]interface{}, len(d))
+		for k, v := range d {
+			if lv, ok := l[k]; ok {
+				pruned[k] = pruneLive(lv, v)
+			}
+		}
+		return pruned
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			return live
+		}
+		pruned := make([]interface{}, len(l))
+		for i, lv := range l {
+			pruned[i] = lv
+			if i < len(d) {
+				pruned[i] = pruneLive(lv, d[i])
+			}
+		}
+		return pruned
+	}
+	return live
+}
+
+// unifiedDiff returns the unified diff from a to b, or an empty string if they
+// are equal.
+func unifiedDiff(a, b, fromFile, toFile string, context int) string {
+	if a == b {
+		return ""
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(a),
+		B:        diffLines(b),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  context,
+	})
+	return diff
+}
+
+// diffLines splits s into lines for difflib, an empty s having no lines.
+func diffLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return difflib.SplitLines(strings.TrimSuffix(s, "\n"))
+}
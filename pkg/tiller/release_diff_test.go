@@ -0,0 +1,157 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+var deployedManifest = `---
+# Source: hello/templates/cm
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  a: one
+---
+# Source: hello/templates/secret
+kind: Secret
+metadata:
+  name: old
+`
+
+func TestDiffRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = deployedManifest
+	rs.env.Releases.Create(rel)
+
+	req := &services.DiffReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/cm", Data: []byte("kind: ConfigMap\nmetadata:\n  name: cm\ndata:\n  a: two\n")},
+				{Name: "templates/svc", Data: []byte("kind: Service\nmetadata:\n  name: svc\n")},
+				{Name: "templates/hooks", Data: []byte(manifestWithHook)},
+			},
+		},
+		Context: 3,
+	}
+	res, err := rs.DiffRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed diff: %s", err)
+	}
+
+	if res.Release.Version != rel.Version+1 {
+		t.Errorf("Expected proposed revision %d, got %d", rel.Version+1, res.Release.Version)
+	}
+
+	expected := []struct {
+		kind   string
+		name   string
+		change services.ResourceDiff_Change
+	}{
+		{"ConfigMap", "cm", services.ResourceDiff_MODIFIED},
+		{"Service", "svc", services.ResourceDiff_ADDED},
+		{"Secret", "old", services.ResourceDiff_REMOVED},
+	}
+	if len(res.Resources) != len(expected) {
+		t.Fatalf("Expected %d resource diffs, got %d", len(expected), len(res.Resources))
+	}
+	for i, e := range expected {
+		d := res.Resources[i]
+		if d.Kind != e.kind || d.Name != e.name || d.Change != e.change {
+			t.Errorf("Expected %s %s %s, got %s %s %s", e.change, e.kind, e.name, d.Change, d.Kind, d.Name)
+		}
+		if d.Namespace != rel.Namespace {
+			t.Errorf("Expected namespace %q for %s %s, got %q", rel.Namespace, d.Kind, d.Name, d.Namespace)
+		}
+	}
+
+	if diff := res.Resources[0].Diff; !strings.Contains(diff, "-  a: one\n") || !strings.Contains(diff, "+  a: two\n") {
+		t.Errorf("Unexpected diff of the config map:\n%s", diff)
+	}
+	if diff := res.Resources[2].Diff; !strings.Contains(diff, "-  name: old\n") {
+		t.Errorf("Unexpected diff of the secret:\n%s", diff)
+	}
+
+	// a diff does not record a release
+	h, err := rs.env.Releases.History(rel.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h) != 1 {
+		t.Errorf("Expected 1 release in history, got %d", len(h))
+	}
+}
+
+func TestDiffRelease_Unchanged(t *testing.T) {
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = "---\n# Source: hello/templates/hello\nhello: world\n"
+	rs.env.Releases.Create(rel)
+
+	res, err := rs.DiffRelease(helm.NewContext(), &services.DiffReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/cm", Data: []byte("kind: ConfigMap\nmetadata:\n  name: cm\n")},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed diff: %s", err)
+	}
+
+	rel.Manifest = res.Release.Manifest
+	rs.env.Releases.Update(rel)
+
+	res, err = rs.DiffRelease(helm.NewContext(), &services.DiffReleaseRequest{
+		Name:  rel.Name,
+		Chart: res.Release.Chart,
+		Live:  true,
+	})
+	if err != nil {
+		t.Fatalf("Failed diff: %s", err)
+	}
+	if len(res.Resources) != 1 {
+		t.Fatalf("Expected 1 resource diff, got %d", len(res.Resources))
+	}
+	if d := res.Resources[0]; d.Change != services.ResourceDiff_UNCHANGED || d.Diff != "" {
+		t.Errorf("Expected unchanged config map, got %s:\n%s", d.Change, d.Diff)
+	}
+}
+
+func TestDiffRelease_InvalidName(t *testing.T) {
+	rs := rsFixture()
+
+	_, err := rs.DiffRelease(helm.NewContext(), &services.DiffReleaseRequest{
+		Name:  "Invalid_Name!",
+		Chart: buildChart(),
+	})
+	if err == nil {
+		t.Error("Expected an error diffing a release with an invalid name")
+	}
+}
@@ -0,0 +1,312 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	ctx "golang.org/x/net/context"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// serverFields are the fields of a resource populated by the cluster, which
+// are never reported as drifted even if a manifest sets them.
+var serverFields = map[string]bool{
+	"status":                     true,
+	"metadata.creationTimestamp": true,
+	"metadata.deletionTimestamp": true,
+	"metadata.generation":        true,
+	"metadata.managedFields":     true,
+	"metadata.resourceVersion":   true,
+	"metadata.selfLink":          true,
+	"metadata.uid":               true,
+}
+
+// CheckDrift compares the manifest of the deployed release with the state of
+// its resources in the cluster, field by field. Only the fields set by the
+// manifest are compared, so that the fields defaulted or maintained by the
+// cluster are not reported. With req.Reconcile, the manifest is applied again
+// to the resources that drifted.
+func (s *ReleaseServer) CheckDrift(c ctx.Context, req *services.CheckDriftRequest) (*services.CheckDriftResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("checkDrift: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+
+	if req.Reconcile {
+		unlock, err := s.lockRelease(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	rel, err := s.env.Releases.Deployed(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := splitResources(rel.Manifest, rel.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Log("checking drift of %s", rel.Name)
+	res := &services.CheckDriftResponse{Release: rel}
+	var live, target []string
+	for _, r := range resources {
+		d, err := s.checkDrift(r)
+		if err != nil {
+			return nil, err
+		}
+		if d == nil {
+			continue
+		}
+		res.Checked++
+		if d.drift == nil {
+			continue
+		}
+		res.Resources = append(res.Resources, d.drift)
+		if !d.drift.Missing {
+			live = append(live, d.live)
+		}
+		target = append(target, d.desired)
+	}
+
+	if req.Reconcile && len(res.Resources) > 0 {
+		s.Log("reconciling %d resources of %s", len(res.Resources), rel.Name)
+		// patching the live state of the drifted fields into the manifest
+		// sets them back, and creates the missing resources
+		err := s.env.KubeClient.UpdateWithOptions(
+			rel.Namespace,
+			bytes.NewBufferString(joinManifests(live)),
+			bytes.NewBufferString(joinManifests(target)),
+			kube.UpdateOptions{},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("reconciling release %q failed: %s", rel.Name, err)
+		}
+		res.Reconciled = true
+	}
+	return res, nil
+}
+
+// resourceDrift is the drift of a resource, along with the manifests of its
+// desired and live states restricted to the fields set by the release.
+type resourceDrift struct {
+	drift   *services.ResourceDrift
+	desired string
+	live    string
+}
+
+// checkDrift compares the resource r with its state in the cluster. It returns
+// nil if r cannot be checked, and a resourceDrift with a nil drift if it did
+// not drift.
+func (s *ReleaseServer) checkDrift(r resourceManifest) (*resourceDrift, error) {
+	infos, err := s.env.KubeClient.Build(r.namespace, strings.NewReader(r.content))
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, nil
+	}
+
+	desired, err := decodeManifest(r.content)
+	if err != nil {
+		return nil, err
+	}
+	for path := range serverFields {
+		deleteField(desired, path)
+	}
+	desiredYAML, err := yaml.Marshal(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &resourceDrift{desired: string(desiredYAML)}
+	drift := &services.ResourceDrift{Kind: r.kind, Name: r.name, Namespace: r.namespace}
+	info := infos[0]
+	if err := info.Get(); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("could not get live state of %s %q: %s", r.kind, r.name, err)
+		}
+		drift.Missing = true
+		d.drift = drift
+		return d, nil
+	}
+
+	live, err := runtime.DefaultUnstructuredConverter.ToUnstructured(info.Object)
+	if err != nil {
+		return nil, err
+	}
+	// the type of a typed object is not kept when decoding it
+	live["apiVersion"], live["kind"] = desired["apiVersion"], desired["kind"]
+	drift.Fields = driftFields("", desired, live)
+	if len(drift.Fields) == 0 {
+		return d, nil
+	}
+
+	liveYAML, err := yaml.Marshal(pruneLive(live, desired))
+	if err != nil {
+		return nil, err
+	}
+	d.drift = drift
+	d.live = string(liveYAML)
+	return d, nil
+}
+
+// driftFields returns the fields under path whose live value differs from the
+// desired one. Fields set in the live value only are ignored, but for the
+// elements of lists.
+func driftFields(path string, desired, live interface{}) []*services.FieldDrift {
+	if serverFields[path] || desired == nil {
+		return nil
+	}
+
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var fields []*services.FieldDrift
+		for _, k := range keys {
+			fields = append(fields, driftFields(fieldPath(path, k), d[k], l[k])...)
+		}
+		return fields
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			break
+		}
+		var fields []*services.FieldDrift
+		for i := 0; i < len(d) || i < len(l); i++ {
+			elem := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(l):
+				fields = append(fields, &services.FieldDrift{Path: elem, Expected: encodeField(d[i])})
+			case i >= len(d):
+				fields = append(fields, &services.FieldDrift{Path: elem, Live: encodeField(l[i])})
+			default:
+				fields = append(fields, driftFields(elem, d[i], l[i])...)
+			}
+		}
+		return fields
+	}
+
+	expected, actual := encodeField(desired), encodeField(live)
+	if expected == actual || quantityEqual(desired, live) {
+		return nil
+	}
+	return []*services.FieldDrift{{Path: path, Expected: expected, Live: actual}}
+}
+
+// simpleField matches the field names that need no quoting in a path.
+var simpleField = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// fieldPath returns the path of the field named key under path.
+func fieldPath(path, key string) string {
+	if !simpleField.MatchString(key) {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// quantityEqual reports whether the desired value and the live one are the
+// same quantity, the cluster writing quantities in their canonical form, e.g.
+// 0.5 as "500m".
+func quantityEqual(desired, live interface{}) bool {
+	l, ok := live.(string)
+	if !ok {
+		return false
+	}
+	var d string
+	switch v := desired.(type) {
+	case string:
+		d = v
+	case json.Number:
+		d = v.String()
+	default:
+		return false
+	}
+	dq, err := resource.ParseQuantity(d)
+	if err != nil {
+		return false
+	}
+	lq, err := resource.ParseQuantity(l)
+	if err != nil {
+		return false
+	}
+	return dq.Cmp(lq) == 0
+}
+
+// encodeField returns the JSON encoding of a field value, or an empty string
+// if it is not set.
+func encodeField(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// deleteField deletes the field at the dotted path from obj, if set.
+func deleteField(obj map[string]interface{}, path string) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := obj[k].(map[string]interface{})
+		if !ok {
+			return
+		}
+		obj = next
+	}
+	delete(obj, keys[len(keys)-1])
+}
+
+// joinManifests joins the manifests of resources into a multi-document
+// manifest.
+func joinManifests(manifests []string) string {
+	var b bytes.Buffer
+	for _, m := range manifests {
+		b.WriteString("---\n")
+		b.WriteString(m)
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestDriftFields(t *testing.T) {
+	desired, err := decodeManifest(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  creationTimestamp: null
+  annotations:
+    example.com/owner: web-team
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:1.0
+        resources:
+          requests:
+            cpu: 0.5
+status: {}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":              "web",
+			"uid":               "6a3bc5a2",
+			"creationTimestamp": "2019-05-16T10:00:00Z",
+			"annotations": map[string]interface{}{
+				"example.com/owner":                                "ops-team",
+				"deployment.kubernetes.io/revision":                "4",
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(5),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":            "web",
+							"image":           "web:1.0",
+							"imagePullPolicy": "IfNotPresent",
+							"resources": map[string]interface{}{
+								"requests": map[string]interface{}{"cpu": "500m"},
+							},
+						},
+						map[string]interface{}{"name": "debug", "image": "busybox"},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{"replicas": int64(5)},
+	}
+
+	expected := []*services.FieldDrift{
+		{Path: `metadata.annotations["example.com/owner"]`, Expected: `"web-team"`, Live: `"ops-team"`},
+		{Path: "spec.replicas", Expected: "3", Live: "5"},
+		{Path: "spec.template.spec.containers[1]", Live: `{"image":"busybox","name":"debug"}`},
+	}
+	if fields := driftFields("", desired, live); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected drifted fields %v, got %v", expected, fields)
+	}
+
+	delete(live, "spec")
+	fields := driftFields("", desired, live)
+	if len(fields) != 2 || fields[1].Path != "spec" || fields[1].Live != "" {
+		t.Errorf("Expected the spec to be missing, got %v", fields)
+	}
+}
+
+func TestCheckDrift(t *testing.T) {
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	res, err := rs.CheckDrift(helm.NewContext(), &services.CheckDriftRequest{Name: rel.Name, Reconcile: true})
+	if err != nil {
+		t.Fatalf("Failed drift check: %s", err)
+	}
+	if res.Release.Name != rel.Name || res.Release.Version != rel.Version {
+		t.Errorf("Expected the deployed release to be checked, got %s revision %d", res.Release.Name, res.Release.Version)
+	}
+	if len(res.Resources) != 0 || res.Reconciled {
+		t.Errorf("Expected no drift to reconcile, got %v", res.Resources)
+	}
+}
+
+func TestCheckDrift_ReconcilePending(t *testing.T) {
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	pending := upgradeReleaseVersion(rel)
+	pending.Info.Status.Code = release.Status_PENDING_UPGRADE
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(pending)
+
+	// drift is checked against the deployed revision
+	if _, err := rs.CheckDrift(helm.NewContext(), &services.CheckDriftRequest{Name: rel.Name}); err == nil {
+		t.Error("Expected an error checking a release without deployed revision")
+	}
+
+	_, err := rs.CheckDrift(helm.NewContext(), &services.CheckDriftRequest{Name: rel.Name, Reconcile: true})
+	if err == nil || !strings.Contains(err.Error(), "another operation (upgrade) is in progress") {
+		t.Errorf("Expected reconciling to be refused during an upgrade, got %v", err)
+	}
+}
+
+func TestCheckDrift_InvalidName(t *testing.T) {
+	rs := rsFixture()
+
+	if _, err := rs.CheckDrift(helm.NewContext(), &services.CheckDriftRequest{Name: "Invalid_Name!"}); err == nil {
+		t.Error("Expected an error checking a release with an invalid name")
+	}
+}
@@ -28,12 +28,24 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/services"
 	relutil "k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/timeconv"
+	"k8s.io/helm/pkg/tracing"
 )
 
 // InstallRelease installs a release and stores the release record.
 func (s *ReleaseServer) InstallRelease(c ctx.Context, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
+	// generated names are checked for uniqueness instead
+	if req.Name != "" {
+		unlock, err := s.lockRelease(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
 	s.Log("preparing install for %s", req.Name)
+	render := tracing.SpanFromContext(c).Child("render")
 	rel, err := s.prepareRelease(req)
+	render.Finish(err)
 	if err != nil {
 		s.Log("failed install prepare step: %s", err)
 		res := &services.InstallReleaseResponse{Release: rel}
@@ -46,10 +58,29 @@ func (s *ReleaseServer) InstallRelease(c ctx.Context, req *services.InstallRelea
 		return res, err
 	}
 
+	op := s.operations.start(rel.Name)
+	defer s.operations.end(op)
+	defer s.traceRelease(c, rel.Name)()
+
+	var adopted *adoption
+	if req.AdoptExisting {
+		// the resources of a release replaced are updated, not adopted
+		var previous *release.Release
+		if req.ReuseName {
+			previous, _ = s.env.Releases.Last(rel.Name)
+		}
+		s.Log("looking for existing resources of %s", rel.Name)
+		if adopted, err = s.adoptExisting(rel, previous); err != nil {
+			s.Log("failed install adopt step: %s", err)
+			return &services.InstallReleaseResponse{Release: rel}, err
+		}
+	}
+
 	s.Log("performing install for %s", req.Name)
-	res, err := s.performRelease(rel, req)
+	res, err := s.performRelease(rel, req, adopted)
 	if err != nil {
 		s.Log("failed install perform step: %s", err)
+		err = s.operationFailed(op, "Install", adopted.release(nil), rel, err)
 	}
 	return res, err
 }
@@ -127,7 +158,7 @@ func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*re
 		rel.Info.Status.Notes = notesTxt
 	}
 
-	return rel, nil
+	return rel, s.admit(rel)
 }
 
 func hasCRDHook(hs []*release.Hook) bool {
@@ -141,9 +172,12 @@ func hasCRDHook(hs []*release.Hook) bool {
 	return false
 }
 
-// performRelease runs a release.
-func (s *ReleaseServer) performRelease(r *release.Release, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
+// performRelease runs a release, adopting the resources of adopted, if any.
+func (s *ReleaseServer) performRelease(r *release.Release, req *services.InstallReleaseRequest, adopted *adoption) (*services.InstallReleaseResponse, error) {
 	res := &services.InstallReleaseResponse{Release: r}
+	if adopted != nil {
+		res.Adopted = adopted.resources
+	}
 	manifestDoc := []byte(r.Manifest)
 
 	if req.DryRun {
@@ -212,7 +246,7 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 			Timeout:  req.Timeout,
 		}
 		s.recordRelease(r, false)
-		if err := s.ReleaseModule.Update(old, r, updateReq, s.env); err != nil {
+		if err := s.ReleaseModule.Update(adopted.release(old), r, updateReq, s.kubeEnv(r.Name)); err != nil {
 			msg := fmt.Sprintf("Release replace %q failed: %s", r.Name, err)
 			s.Log("warning: %s", msg)
 			old.Info.Status.Code = release.Status_SUPERSEDED
@@ -223,11 +257,28 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 			return res, err
 		}
 
+	case len(res.Adopted) > 0:
+		// the adopted resources are patched to their rendered state, the
+		// others are created
+		updateReq := &services.UpdateReleaseRequest{
+			Wait:    req.Wait,
+			Timeout: req.Timeout,
+		}
+		s.recordRelease(r, false)
+		if err := s.ReleaseModule.Update(adopted.release(nil), r, updateReq, s.kubeEnv(r.Name)); err != nil {
+			msg := fmt.Sprintf("Release %q failed: %s", r.Name, err)
+			s.Log("warning: %s", msg)
+			r.Info.Status.Code = release.Status_FAILED
+			r.Info.Description = msg
+			s.recordRelease(r, true)
+			return res, fmt.Errorf("release %s failed: %s", r.Name, err)
+		}
+
 	default:
 		// nothing to replace, create as normal
 		// regular manifests
 		s.recordRelease(r, false)
-		if err := s.ReleaseModule.Create(r, req, s.env); err != nil {
+		if err := s.ReleaseModule.Create(r, req, s.kubeEnv(r.Name)); err != nil {
 			msg := fmt.Sprintf("Release %q failed: %s", r.Name, err)
 			s.Log("warning: %s", msg)
 			r.Info.Status.Code = release.Status_FAILED
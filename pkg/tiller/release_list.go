@@ -17,13 +17,10 @@ limitations under the License.
 package tiller
 
 import (
-	"fmt"
-	"regexp"
-
 	"github.com/golang/protobuf/proto"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
-	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/storage/driver"
 )
 
 // ListReleases lists the releases found by the server.
@@ -32,90 +29,30 @@ func (s *ReleaseServer) ListReleases(req *services.ListReleasesRequest, stream s
 		req.StatusCodes = []release.Status_Code{release.Status_DEPLOYED}
 	}
 
-	//rels, err := s.env.Releases.ListDeployed()
-	rels, err := s.env.Releases.ListFilterAll(func(r *release.Release) bool {
-		for _, sc := range req.StatusCodes {
-			if sc == r.Info.Status.Code {
-				return true
-			}
-		}
-		return false
-	})
-	if err != nil {
-		return err
-	}
-
-	if req.Namespace != "" {
-		rels, err = filterByNamespace(req.Namespace, rels)
-		if err != nil {
-			return err
-		}
-	}
-
-	if len(req.Filter) != 0 {
-		rels, err = filterReleases(req.Filter, rels)
-		if err != nil {
-			return err
-		}
-	}
-
-	total := int64(len(rels))
-
-	switch req.SortBy {
-	case services.ListSort_NAME:
-		relutil.SortByName(rels)
-	case services.ListSort_LAST_RELEASED:
-		relutil.SortByDate(rels)
-	case services.ListSort_CHART_NAME:
-		relutil.SortByChartName(rels)
-	}
-
-	if req.SortOrder == services.ListSort_DESC {
-		ll := len(rels)
-		rr := make([]*release.Release, ll)
-		for i, item := range rels {
-			rr[ll-i-1] = item
-		}
-		rels = rr
-	}
-
-	l := int64(len(rels))
-	if req.Offset != "" {
-
-		i := -1
-		for ii, cur := range rels {
-			if cur.Name == req.Offset {
-				i = ii
-			}
-		}
-		if i == -1 {
-			return fmt.Errorf("offset %q not found", req.Offset)
-		}
-
-		if len(rels) < i {
-			return fmt.Errorf("no items after %q", req.Offset)
-		}
-
-		rels = rels[i:]
-		l = int64(len(rels))
-	}
-
 	if req.Limit == 0 {
 		req.Limit = ListDefaultLimit
 	}
 
-	next := ""
-	if l > req.Limit {
-		next = rels[req.Limit].Name
-		rels = rels[0:req.Limit]
-		l = int64(len(rels))
+	page, err := s.env.Releases.ListPage(driver.ListOptions{
+		StatusCodes: req.StatusCodes,
+		Namespace:   req.Namespace,
+		Filter:      req.Filter,
+		SortBy:      listSortBy(req.SortBy),
+		SortDesc:    req.SortOrder == services.ListSort_DESC,
+		Offset:      req.Offset,
+		Limit:       int(req.Limit),
+	})
+	if err != nil {
+		return err
 	}
+	rels := page.Releases
+
 	res := &services.ListReleasesResponse{
-		Next:  next,
-		Count: l,
-		Total: total,
+		Next:  page.Next,
+		Count: int64(len(rels)),
+		Total: int64(page.Total),
 	}
-	chunks := s.partition(rels[:min(len(rels), int(req.Limit))], maxMsgSize-proto.Size(res))
+	chunks := s.partition(rels, maxMsgSize-proto.Size(res))
 	for res.Releases = range chunks {
 		if err := stream.Send(res); err != nil {
 			for range chunks { // drain
@@ -156,26 +93,17 @@ func (s *ReleaseServer) partition(rels []*release.Release, cap int) <-chan []*re
 	return chunks
 }
 
-func filterByNamespace(namespace string, rels []*release.Release) ([]*release.Release, error) {
-	matches := []*release.Release{}
-	for _, r := range rels {
-		if namespace == r.Namespace {
-			matches = append(matches, r)
-		}
-	}
-	return matches, nil
-}
-
-func filterReleases(filter string, rels []*release.Release) ([]*release.Release, error) {
-	preg, err := regexp.Compile(filter)
-	if err != nil {
-		return rels, err
-	}
-	matches := []*release.Release{}
-	for _, r := range rels {
-		if preg.MatchString(r.Name) {
-			matches = append(matches, r)
-		}
+// listSortBy converts the sort key of a ListReleasesRequest to the one of the
+// storage.
+func listSortBy(sortBy services.ListSort_SortBy) driver.ListSortBy {
+	switch sortBy {
+	case services.ListSort_NAME:
+		return driver.ListSortByName
+	case services.ListSort_LAST_RELEASED:
+		return driver.ListSortByLastReleased
+	case services.ListSort_CHART_NAME:
+		return driver.ListSortByChartName
+	default:
+		return driver.ListUnsorted
 	}
-	return matches, nil
 }
@@ -0,0 +1,356 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	ctx "golang.org/x/net/context"
+	"k8s.io/client-go/discovery"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// RenameRelease renames a release, or moves it to another namespace. The
+// stored revisions of the release are moved under its new name, and the
+// deployed revision is rendered again with the new name and namespace, then
+// applied as an upgrade.
+func (s *ReleaseServer) RenameRelease(c ctx.Context, req *services.RenameReleaseRequest) (*services.RenameReleaseResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("renameRelease: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+	newName := req.NewName
+	if newName == "" {
+		newName = req.Name
+	}
+	if err := validateReleaseName(newName); err != nil {
+		s.Log("renameRelease: Release name is invalid: %s", newName)
+		return nil, err
+	}
+
+	unlock, err := s.lockRelease(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if newName != req.Name {
+		unlockNew, err := s.lockRelease(newName)
+		if err != nil {
+			return nil, err
+		}
+		defer unlockNew()
+		if h, err := s.env.Releases.History(newName); err == nil && len(h) > 0 {
+			return nil, fmt.Errorf("cannot rename %q: a release named %q already exists", req.Name, newName)
+		}
+	}
+
+	s.Log("preparing rename of %s", req.Name)
+	current, renamed, err := s.prepareRename(req, newName)
+	if err != nil {
+		return nil, err
+	}
+	if current.Name == renamed.Name && current.Namespace == renamed.Namespace {
+		return nil, fmt.Errorf("release %q is already named %q in namespace %q", req.Name, newName, current.Namespace)
+	}
+
+	res := &services.RenameReleaseResponse{Release: renamed}
+	if req.DryRun {
+		s.Log("dry run for %s", renamed.Name)
+		renamed.Info.Description = "Dry run complete"
+		return res, nil
+	}
+
+	if newName != req.Name {
+		s.Log("moving history of %s to %s", req.Name, newName)
+		if err := s.env.Releases.Rename(req.Name, newName); err != nil {
+			return nil, err
+		}
+		if current, err = s.env.Releases.Get(newName, current.Version); err != nil {
+			return nil, err
+		}
+	}
+
+	op := s.operations.start(newName)
+	defer s.operations.end(op)
+	defer s.traceRelease(c, newName)()
+
+	s.Log("performing rename of %s", req.Name)
+	if err := s.env.Releases.Create(renamed); err != nil {
+		if newName != req.Name {
+			s.Log("moving history of %s back to %s", newName, req.Name)
+			if err := s.env.Releases.Rename(newName, req.Name); err != nil {
+				s.Log("warning: cannot move history of %s back to %s: %s", newName, req.Name, err)
+			}
+		}
+		return nil, err
+	}
+	if err := s.performRename(current, renamed, req); err != nil {
+		return res, s.operationFailed(op, "Rename", current, renamed, err)
+	}
+	return res, nil
+}
+
+// prepareRename returns the deployed revision of the release renamed by req,
+// and the revision rendering its chart and values under newName and the new
+// namespace.
+func (s *ReleaseServer) prepareRename(req *services.RenameReleaseRequest, newName string) (*release.Release, *release.Release, error) {
+	current, err := s.env.Releases.Deployed(req.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	last, err := s.env.Releases.Last(req.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = current.Namespace
+	}
+
+	revision := last.Version + 1
+	ts := timeconv.Now()
+	options := chartutil.ReleaseOptions{
+		Name:      newName,
+		Time:      ts,
+		Namespace: namespace,
+		IsUpgrade: true,
+		Revision:  int(revision),
+	}
+
+	caps, err := capabilities(s.clientset.Discovery())
+	if err != nil {
+		return nil, nil, err
+	}
+	valuesToRender, err := chartutil.ToRenderValuesCaps(current.Chart, current.Config, options, caps)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hooks, manifestDoc, notesTxt, err := s.renderResources(current.Chart, valuesToRender, false, caps.APIVersions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	renamed := &release.Release{
+		Name:      newName,
+		Namespace: namespace,
+		Chart:     current.Chart,
+		Config:    current.Config,
+		Info: &release.Info{
+			FirstDeployed: current.Info.FirstDeployed,
+			LastDeployed:  ts,
+			Status:        &release.Status{Code: release.Status_PENDING_UPGRADE},
+			Description:   "Preparing rename", // This should be overwritten later.
+		},
+		Version:  revision,
+		Manifest: manifestDoc.String(),
+		Hooks:    hooks,
+	}
+	if len(notesTxt) > 0 {
+		renamed.Info.Status.Notes = notesTxt
+	}
+	if err := s.admit(renamed); err != nil {
+		return current, renamed, err
+	}
+	err = validateManifest(s.env.KubeClient, namespace, manifestDoc.Bytes())
+	return current, renamed, err
+}
+
+// performRename applies renamed over current, the deployed revision of the
+// release, as an upgrade.
+func (s *ReleaseServer) performRename(current, renamed *release.Release, req *services.RenameReleaseRequest) error {
+	// pre-upgrade hooks
+	if !req.DisableHooks {
+		if err := s.execHook(renamed.Hooks, renamed.Name, renamed.Namespace, hooks.PreUpgrade, req.Timeout); err != nil {
+			renamed.Info.Status.Code = release.Status_FAILED
+			renamed.Info.Description = fmt.Sprintf("Rename %q failed pre-upgrade: %s", renamed.Name, err)
+			s.recordRelease(renamed, true)
+			return err
+		}
+	} else {
+		s.Log("rename hooks disabled for %s", renamed.Name)
+	}
+
+	// the resources of the release are applied in its new namespace, so that
+	// the ones of the previous namespace need to name it
+	from := current
+	var clusterScoped map[string]bool
+	if current.Namespace != renamed.Namespace {
+		var err error
+		if clusterScoped, err = clusterScopedKinds(s.clientset.Discovery()); err != nil {
+			return err
+		}
+		manifest, err := pinNamespace(current.Manifest, current.Namespace, clusterScoped)
+		if err != nil {
+			return err
+		}
+		moved := *current
+		moved.Manifest = manifest
+		from = &moved
+	}
+
+	updateReq := &services.UpdateReleaseRequest{
+		Wait:    req.Wait,
+		Timeout: req.Timeout,
+	}
+	if err := s.ReleaseModule.Update(from, renamed, updateReq, s.kubeEnv(renamed.Name)); err != nil {
+		msg := fmt.Sprintf("Rename %q failed: %s", renamed.Name, err)
+		s.Log("warning: %s", msg)
+		renamed.Info.Status.Code = release.Status_FAILED
+		renamed.Info.Description = msg
+		s.recordRelease(renamed, true)
+		return err
+	}
+	if from != current {
+		s.deleteLeftBehind(from, renamed, clusterScoped)
+	}
+
+	// post-upgrade hooks
+	if !req.DisableHooks {
+		if err := s.execHook(renamed.Hooks, renamed.Name, renamed.Namespace, hooks.PostUpgrade, req.Timeout); err != nil {
+			msg := fmt.Sprintf("Rename %q failed post-upgrade: %s", renamed.Name, err)
+			s.Log("warning: %s", msg)
+			renamed.Info.Status.Code = release.Status_FAILED
+			renamed.Info.Description = msg
+			s.recordRelease(renamed, true)
+			return err
+		}
+	}
+
+	current.Info.Status.Code = release.Status_SUPERSEDED
+	s.recordRelease(current, true)
+
+	renamed.Info.Status.Code = release.Status_DEPLOYED
+	renamed.Info.Description = renameDescription(current, renamed)
+	s.recordRelease(renamed, true)
+	return nil
+}
+
+// renameDescription returns the description of the revision renaming or
+// moving the release of current.
+func renameDescription(current, renamed *release.Release) string {
+	var changes []string
+	if current.Name != renamed.Name {
+		changes = append(changes, fmt.Sprintf("renamed from %q", current.Name))
+	}
+	if current.Namespace != renamed.Namespace {
+		changes = append(changes, fmt.Sprintf("moved from namespace %q", current.Namespace))
+	}
+	d := strings.Join(changes, ", ")
+	return strings.ToUpper(d[:1]) + d[1:]
+}
+
+// deleteLeftBehind deletes the resources of from, the previous revision of a
+// release moved to the namespace of renamed, which renamed does not apply in
+// their namespace. Updates only delete the resources whose kind and name are
+// no longer applied, whatever their namespace. Cluster-scoped resources are
+// shared by both revisions and left as they are. Failures are logged, the
+// release being deployed in its new namespace.
+func (s *ReleaseServer) deleteLeftBehind(from, renamed *release.Release, clusterScoped map[string]bool) {
+	previous, err := splitResources(from.Manifest, from.Namespace)
+	if err != nil {
+		s.Log("warning: cannot delete the resources left in namespace %q: %s", from.Namespace, err)
+		return
+	}
+	applied, err := splitResources(renamed.Manifest, renamed.Namespace)
+	if err != nil {
+		s.Log("warning: cannot delete the resources left in namespace %q: %s", from.Namespace, err)
+		return
+	}
+	keys := make(map[string]bool, len(applied))
+	for _, r := range applied {
+		keys[r.key()] = true
+	}
+
+	var docs []string
+	for _, r := range previous {
+		if clusterScoped[r.kind] || keys[r.key()] {
+			continue
+		}
+		docs = append(docs, strings.TrimSuffix(r.content, "\n")+"\n")
+	}
+	if len(docs) == 0 {
+		return
+	}
+
+	left := *from
+	left.Manifest = joinManifests(docs)
+	s.Log("deleting the resources of %s left in namespace %s", renamed.Name, from.Namespace)
+	req := &services.UninstallReleaseRequest{Name: renamed.Name}
+	_, errs := s.ReleaseModule.Delete(&left, req, s.kubeEnv(renamed.Name))
+	for _, err := range errs {
+		s.Log("warning: cannot delete a resource left in namespace %q: %s", from.Namespace, err)
+	}
+}
+
+// clusterScopedKinds returns the kinds of the cluster-scoped resources served
+// by the cluster.
+func clusterScopedKinds(client discovery.ServerResourcesInterface) (map[string]bool, error) {
+	_, resources, err := client.ServerGroupsAndResources()
+	if err != nil {
+		return nil, fmt.Errorf("Could not get API resources from Kubernetes: %s", err)
+	}
+	kinds := map[string]bool{}
+	for _, list := range resources {
+		for _, r := range list.APIResources {
+			if !r.Namespaced {
+				kinds[r.Kind] = true
+			}
+		}
+	}
+	return kinds, nil
+}
+
+// pinNamespace sets the namespace of the resources of manifest which do not
+// name one to namespace. Resources of the cluster-scoped kinds are left as
+// they are.
+func pinNamespace(manifest, namespace string, clusterScoped map[string]bool) (string, error) {
+	resources, err := splitResources(manifest, "")
+	if err != nil {
+		return "", err
+	}
+	docs := make([]string, 0, len(resources))
+	for _, r := range resources {
+		if r.namespace != "" || clusterScoped[r.kind] {
+			docs = append(docs, strings.TrimSuffix(r.content, "\n")+"\n")
+			continue
+		}
+		obj, err := decodeManifest(r.content)
+		if err != nil {
+			return "", err
+		}
+		metadata, ok := obj["metadata"].(map[string]interface{})
+		if !ok {
+			metadata = map[string]interface{}{}
+			obj["metadata"] = metadata
+		}
+		metadata["namespace"] = namespace
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, string(data))
+	}
+	return joinManifests(docs), nil
+}
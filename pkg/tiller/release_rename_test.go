@@ -0,0 +1,248 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// createFailingDriver is an in-memory driver failing to create a given key.
+type createFailingDriver struct {
+	*driver.Memory
+	key string
+}
+
+func (d *createFailingDriver) Create(key string, rls *release.Release) error {
+	if key == d.key {
+		return errors.New("storage is full")
+	}
+	return d.Memory.Create(key, rls)
+}
+
+// deleteRecordingKubeClient records the resources it deletes.
+type deleteRecordingKubeClient struct {
+	environment.PrintingKubeClient
+	deleted []string
+}
+
+func (k *deleteRecordingKubeClient) Delete(ns string, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	resources, err := splitResources(string(b), ns)
+	if err != nil {
+		return err
+	}
+	for _, res := range resources {
+		k.deleted = append(k.deleted, res.key())
+	}
+	return nil
+}
+
+func TestRenameRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Chart = buildChart(withTemplate("templates/named", "name: {{ .Release.Name }}"))
+	rs.env.Releases.Create(rel)
+	upgraded := upgradeReleaseVersion(rel)
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgraded)
+
+	req := &services.RenameReleaseRequest{Name: rel.Name, NewName: "web"}
+	res, err := rs.RenameRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed rename: %s", err)
+	}
+	if res.Release.Name != "web" || res.Release.Version != 3 {
+		t.Errorf("Expected revision 3 of web, got revision %d of %s", res.Release.Version, res.Release.Name)
+	}
+	if !strings.Contains(res.Release.Manifest, "name: web") {
+		t.Errorf("Expected the manifest to be rendered with the new name, got %q", res.Release.Manifest)
+	}
+	if res.Release.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected a deployed release, got %s", res.Release.Info.Status.Code)
+	}
+	if res.Release.Info.Description != `Renamed from "angry-panda"` {
+		t.Errorf("Expected the rename to be described, got %q", res.Release.Info.Description)
+	}
+
+	history, err := rs.env.Releases.History("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Errorf("Expected the history to be kept under the new name, got %d revisions", len(history))
+	}
+	if h, _ := rs.env.Releases.History(rel.Name); len(h) != 0 {
+		t.Errorf("Expected no revision left under the old name, got %d", len(h))
+	}
+	if previous, err := rs.env.Releases.Get("web", 2); err != nil || previous.Info.Status.Code != release.Status_SUPERSEDED {
+		t.Errorf("Expected the previous revision to be superseded, got %v", previous)
+	}
+}
+
+func TestRenameRelease_Namespace(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Namespace = "spaced"
+	rs.env.Releases.Create(rel)
+
+	req := &services.RenameReleaseRequest{Name: rel.Name, Namespace: "production"}
+	res, err := rs.RenameRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed rename: %s", err)
+	}
+	if res.Release.Name != rel.Name || res.Release.Namespace != "production" {
+		t.Errorf("Expected %s in production, got %s in %s", rel.Name, res.Release.Name, res.Release.Namespace)
+	}
+	if res.Release.Info.Description != `Moved from namespace "spaced"` {
+		t.Errorf("Expected the move to be described, got %q", res.Release.Info.Description)
+	}
+
+	// moving a release to its namespace changes nothing
+	if _, err := rs.RenameRelease(c, req); err == nil {
+		t.Error("Expected a rename changing nothing to fail")
+	}
+}
+
+func TestRenameRelease_NamespaceDeletesLeftBehind(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap", Namespaced: true}}},
+		{GroupVersion: "rbac.authorization.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "clusterroles", Kind: "ClusterRole"}}},
+	}
+	kubeClient := &deleteRecordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rs.env.KubeClient = kubeClient
+
+	rel := releaseStub()
+	rel.Namespace = "spaced"
+	rel.Chart = buildChart(
+		withTemplate("templates/cm", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}-cm\n"),
+		withTemplate("templates/role", "apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRole\nmetadata:\n  name: {{ .Release.Name }}-reader\n"),
+	)
+	rel.Manifest = "---\n# Source: hello/templates/cm\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: angry-panda-cm\n" +
+		"---\n# Source: hello/templates/role\napiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRole\nmetadata:\n  name: angry-panda-reader\n"
+	rs.env.Releases.Create(rel)
+
+	req := &services.RenameReleaseRequest{Name: rel.Name, Namespace: "production"}
+	if _, err := rs.RenameRelease(c, req); err != nil {
+		t.Fatalf("Failed rename: %s", err)
+	}
+
+	// the config map of the previous namespace is deleted, the cluster role
+	// shared by both namespaces is kept
+	if expected := []string{"ConfigMap/spaced/angry-panda-cm"}; !reflect.DeepEqual(kubeClient.deleted, expected) {
+		t.Errorf("Expected %v to be deleted, got %v", expected, kubeClient.deleted)
+	}
+}
+
+func TestRenameRelease_CreateFailure(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Releases = storage.Init(&createFailingDriver{Memory: driver.NewMemory(), key: "web.v2"})
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	req := &services.RenameReleaseRequest{Name: rel.Name, NewName: "web"}
+	if _, err := rs.RenameRelease(c, req); err == nil {
+		t.Fatal("Expected the rename to fail")
+	}
+
+	// the history is moved back under the previous name
+	if h, _ := rs.env.Releases.History("web"); len(h) != 0 {
+		t.Errorf("Expected no revision left under the new name, got %d", len(h))
+	}
+	if deployed, err := rs.env.Releases.Deployed(rel.Name); err != nil || deployed.Name != rel.Name {
+		t.Errorf("Expected %s to be deployed under its name, got %v", rel.Name, deployed)
+	}
+}
+
+func TestRenameRelease_NameTaken(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	rs.env.Releases.Create(namedReleaseStub("web", release.Status_DELETED))
+
+	req := &services.RenameReleaseRequest{Name: rel.Name, NewName: "web"}
+	_, err := rs.RenameRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), `a release named "web" already exists`) {
+		t.Errorf("Expected the rename to fail, got %v", err)
+	}
+	if _, err := rs.env.Releases.Get(rel.Name, 1); err != nil {
+		t.Errorf("Expected the release to be left as is: %s", err)
+	}
+}
+
+func TestRenameRelease_DryRun(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	req := &services.RenameReleaseRequest{Name: rel.Name, NewName: "web", DryRun: true}
+	res, err := rs.RenameRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed rename: %s", err)
+	}
+	if res.Release.Name != "web" || res.Release.Info.Description != "Dry run complete" {
+		t.Errorf("Expected a dry run of web, got %s: %q", res.Release.Name, res.Release.Info.Description)
+	}
+	if h, _ := rs.env.Releases.History("web"); len(h) != 0 {
+		t.Errorf("Expected no revision to be recorded, got %d", len(h))
+	}
+}
+
+func TestPinNamespace(t *testing.T) {
+	manifest := "---\n# Source: hello/templates/a\nkind: ConfigMap\napiVersion: v1\nmetadata:\n  name: a\n" +
+		"---\n# Source: hello/templates/b\nkind: ConfigMap\napiVersion: v1\nmetadata:\n  name: b\n  namespace: other\n" +
+		"---\n# Source: hello/templates/c\nkind: ClusterRole\napiVersion: rbac.authorization.k8s.io/v1\nmetadata:\n  name: c\n"
+	pinned, err := pinNamespace(manifest, "spaced", map[string]bool{"ClusterRole": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources, err := splitResources(pinned, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, r := range resources {
+		keys = append(keys, r.key())
+	}
+	if strings.Join(keys, " ") != "ConfigMap/spaced/a ConfigMap/other/b ClusterRole//c" {
+		t.Errorf("Expected the namespace of a to be pinned only, got %v", keys)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+)
+
+// RepairRelease marks the revision of a release left pending by an
+// interrupted operation as failed. If no revision of the release is deployed
+// any more, the revision it superseded is deployed again.
+//
+// Releases with an operation in progress are locked and cannot be repaired.
+func (s *ReleaseServer) RepairRelease(c ctx.Context, req *services.RepairReleaseRequest) (*services.RepairReleaseResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("repairRelease: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+
+	unlock, err := s.env.Releases.LockRelease(req.Name)
+	if err != nil {
+		s.Log("failed to lock release %s: %s", req.Name, err)
+		return nil, err
+	}
+	defer unlock()
+
+	last, err := s.env.Releases.Last(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !storage.IsPending(last) {
+		return nil, fmt.Errorf("release %q is not pending at revision %d: nothing to repair", req.Name, last.Version)
+	}
+
+	s.Log("repairing release %s", req.Name)
+	restored, err := s.env.Releases.Repair(last, "repaired on request")
+	if err != nil {
+		return nil, err
+	}
+	return &services.RepairReleaseResponse{Release: last, Restored: restored}, nil
+}
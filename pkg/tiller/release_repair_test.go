@@ -0,0 +1,97 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestRepairRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	pending := upgradeReleaseVersion(rel)
+	pending.Info.Status.Code = release.Status_PENDING_UPGRADE
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(pending)
+
+	res, err := rs.RepairRelease(c, &services.RepairReleaseRequest{Name: rel.Name})
+	if err != nil {
+		t.Fatalf("Failed repair: %s", err)
+	}
+	if res.Release.Version != 2 || res.Release.Info.Status.Code != release.Status_FAILED {
+		t.Errorf("Expected revision 2 to be failed, got revision %d %s", res.Release.Version, res.Release.Info.Status.Code)
+	}
+	if desc := res.Release.Info.Description; desc != "Upgrade interrupted: repaired on request" {
+		t.Errorf("Expected description %q, got %q", "Upgrade interrupted: repaired on request", desc)
+	}
+	if res.Restored == nil || res.Restored.Version != 1 {
+		t.Fatalf("Expected revision 1 to be restored, got %v", res.Restored)
+	}
+
+	deployed, err := rs.env.Releases.Deployed(rel.Name)
+	if err != nil {
+		t.Fatalf("Expected a deployed release: %s", err)
+	}
+	if deployed.Version != 1 {
+		t.Errorf("Expected revision 1 to be deployed, got %d", deployed.Version)
+	}
+
+	// the release can be upgraded again
+	req := &services.UpdateReleaseRequest{Name: rel.Name, Chart: rel.GetChart()}
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Errorf("Failed upgrade after repair: %s", err)
+	}
+}
+
+func TestRepairReleaseNotPending(t *testing.T) {
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	_, err := rs.RepairRelease(helm.NewContext(), &services.RepairReleaseRequest{Name: rel.Name})
+	if err == nil || !strings.Contains(err.Error(), "is not pending") {
+		t.Errorf("Expected an error repairing a deployed release, got %v", err)
+	}
+}
+
+func TestRepairReleaseLocked(t *testing.T) {
+	rs := rsFixture()
+	rel := namedReleaseStub("busy-bees", release.Status_PENDING_INSTALL)
+	rs.env.Releases.Create(rel)
+
+	// the install is in progress
+	unlock, err := rs.env.Releases.LockRelease(rel.Name)
+	if err != nil {
+		t.Fatalf("Failed to lock release: %s", err)
+	}
+	defer unlock()
+
+	_, err = rs.RepairRelease(helm.NewContext(), &services.RepairReleaseRequest{Name: rel.Name})
+	if err == nil || !strings.Contains(err.Error(), "is locked") {
+		t.Errorf("Expected an error repairing a locked release, got %v", err)
+	}
+	if code := rel.Info.Status.Code; code != release.Status_PENDING_INSTALL {
+		t.Errorf("Expected the release to be left pending, got %s", code)
+	}
+}
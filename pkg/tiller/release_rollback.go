@@ -31,6 +31,19 @@ import (
 
 // RollbackRelease rolls back to a previous version of the given release.
 func (s *ReleaseServer) RollbackRelease(c ctx.Context, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("rollbackRelease: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+	unlock, err := s.lockRelease(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	op := s.operations.start(req.Name)
+	defer s.operations.end(op)
+	defer s.traceRelease(c, req.Name)()
+
 	s.Log("preparing rollback of %s", req.Name)
 	currentRelease, targetRelease, err := s.prepareRollback(req)
 	if err != nil {
@@ -46,7 +59,7 @@ func (s *ReleaseServer) RollbackRelease(c ctx.Context, req *services.RollbackRel
 	s.Log("performing rollback of %s", req.Name)
 	res, err := s.performRollback(currentRelease, targetRelease, req)
 	if err != nil {
-		return res, err
+		return res, s.operationFailed(op, "Rollback", currentRelease, targetRelease, err)
 	}
 
 	if !req.DryRun {
@@ -62,11 +75,6 @@ func (s *ReleaseServer) RollbackRelease(c ctx.Context, req *services.RollbackRel
 // prepareRollback finds the previous release and prepares a new release object with
 // the previous release's configuration
 func (s *ReleaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*release.Release, *release.Release, error) {
-	if err := validateReleaseName(req.Name); err != nil {
-		s.Log("prepareRollback: Release name is invalid: %s", req.Name)
-		return nil, nil, err
-	}
-
 	if req.Version < 0 {
 		return nil, nil, errInvalidRevision
 	}
@@ -135,7 +143,7 @@ func (s *ReleaseServer) performRollback(currentRelease, targetRelease *release.R
 		s.Log("rollback hooks disabled for %s", req.Name)
 	}
 
-	if err := s.ReleaseModule.Rollback(currentRelease, targetRelease, req, s.env); err != nil {
+	if err := s.ReleaseModule.Rollback(currentRelease, targetRelease, req, s.kubeEnv(req.Name)); err != nil {
 		msg := fmt.Sprintf("Rollback %q failed: %s", targetRelease.Name, err)
 		s.Log("warning: %s", msg)
 		currentRelease.Info.Status.Code = release.Status_SUPERSEDED
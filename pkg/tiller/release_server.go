@@ -36,6 +36,8 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/tiller/admission"
 	"k8s.io/helm/pkg/tiller/environment"
 	"k8s.io/helm/pkg/timeconv"
 	"k8s.io/helm/pkg/version"
@@ -84,9 +86,15 @@ var ValidName = regexp.MustCompile("^(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])+
 // ReleaseServer implements the server-side gRPC endpoint for the HAPI services.
 type ReleaseServer struct {
 	ReleaseModule
-	env       *environment.Environment
-	clientset kubernetes.Interface
-	Log       func(string, ...interface{})
+	env        *environment.Environment
+	clientset  kubernetes.Interface
+	watchers   *releaseWatchers
+	operations *operations
+	spans      *releaseSpans
+	Log        func(string, ...interface{})
+	// AdmissionPolicy is the source of the policy the resources of releases
+	// are checked against before they are installed or upgraded, if any.
+	AdmissionPolicy admission.Source
 }
 
 // NewReleaseServer creates a new release server.
@@ -100,14 +108,61 @@ func NewReleaseServer(env *environment.Environment, clientset kubernetes.Interfa
 		}
 	}
 
+	watchers := newReleaseWatchers()
+	if env.Releases != nil {
+		addNotify(env.Releases, watchers.storageChanged)
+	}
+
 	return &ReleaseServer{
 		env:           env,
 		clientset:     clientset,
+		watchers:      watchers,
+		operations:    newOperations(),
+		spans:         newReleaseSpans(),
 		ReleaseModule: releaseModule,
 		Log:           func(_ string, _ ...interface{}) {},
 	}
 }
 
+// addNotify makes releases call notify with every change made through it,
+// after the Notify function set before, if any.
+func addNotify(releases *storage.Storage, notify func(storage.Change, *release.Release)) {
+	previous := releases.Notify
+	if previous == nil {
+		releases.Notify = notify
+		return
+	}
+	releases.Notify = func(change storage.Change, rls *release.Release) {
+		previous(change, rls)
+		notify(change, rls)
+	}
+}
+
+// lockRelease locks the named release for the duration of an operation
+// creating a new revision of it. The operation is refused while the last
+// revision of the release is pending, i.e. while another install, upgrade or
+// rollback of the release is in progress, or was interrupted. The returned
+// function releases the lock.
+func (s *ReleaseServer) lockRelease(name string) (func(), error) {
+	unlock, err := s.env.Releases.LockRelease(name)
+	if err != nil {
+		s.Log("failed to lock release %s: %s", name, err)
+		return nil, err
+	}
+	if last, err := s.env.Releases.Last(name); err == nil && storage.IsPending(last) {
+		unlock()
+		return nil, errOperationInProgress(last)
+	}
+	return unlock, nil
+}
+
+// errOperationInProgress returns the error refusing an operation on a release
+// whose last revision is still pending.
+func errOperationInProgress(rel *release.Release) error {
+	operation := strings.ToLower(strings.TrimPrefix(rel.Info.Status.Code.String(), "PENDING_"))
+	return fmt.Errorf("another operation (%s) is in progress for release %q at revision %d", operation, rel.Name, rel.Version)
+}
+
 // reuseValues copies values from the current release to a new release if the
 // new release does not have any values.
 //
@@ -423,7 +478,7 @@ func (s *ReleaseServer) recordRelease(r *release.Release, reuse bool) {
 }
 
 func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook string, timeout int64) error {
-	kubeCli := s.env.KubeClient
+	kubeCli := s.kubeClient(name)
 	code, ok := events[hook]
 	if !ok {
 		return fmt.Errorf("unknown hook %s", hook)
@@ -445,10 +500,12 @@ func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 		if err := s.deleteHookByPolicy(h, hooks.BeforeHookCreation, name, namespace, hook, kubeCli); err != nil {
 			return err
 		}
+		start := time.Now()
 
 		b := bytes.NewBufferString(h.Manifest)
 		if err := kubeCli.Create(namespace, b, timeout, false); err != nil {
 			s.Log("warning: Release %s %s %s failed: %s", name, hook, h.Path, err)
+			s.hookRan(name, namespace, h, code, start, err)
 			return err
 		}
 		// No way to rewind a bytes.Buffer()?
@@ -459,6 +516,7 @@ func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 		if hook != hooks.CRDInstall {
 			if err := kubeCli.WatchUntilReady(namespace, b, timeout, false); err != nil {
 				s.Log("warning: Release %s %s %s could not complete: %s", name, hook, h.Path, err)
+				s.hookRan(name, namespace, h, code, start, err)
 				// If a hook is failed, checkout the annotation of the hook to determine whether the hook should be deleted
 				// under failed condition. If so, then clear the corresponding resource object in the hook
 				if err := s.deleteHookByPolicy(h, hooks.HookFailed, name, namespace, hook, kubeCli); err != nil {
@@ -469,9 +527,11 @@ func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 		} else {
 			if err := kubeCli.WaitUntilCRDEstablished(b, time.Duration(timeout)*time.Second); err != nil {
 				s.Log("warning: Release %s %s %s could not complete: %s", name, hook, h.Path, err)
+				s.hookRan(name, namespace, h, code, start, err)
 				return err
 			}
 		}
+		s.hookRan(name, namespace, h, code, start, nil)
 	}
 
 	s.Log("hooks complete for %s %s", hook, name)
@@ -487,6 +547,14 @@ func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 	return nil
 }
 
+// hookRan records that hook h of the named release, started at start, ran for
+// event, err being the error it failed with, if any.
+func (s *ReleaseServer) hookRan(name, namespace string, h *release.Hook, event release.Hook_Event, start time.Time, err error) {
+	observeHook(event, start, err)
+	s.spans.get(name).Record("hook "+h.Name, start, err, "event", hookEventName(event), "kind", h.Kind)
+	s.watchers.hookRan(name, namespace, h, event, err)
+}
+
 func validateManifest(c environment.KubeClient, ns string, manifest []byte) error {
 	r := bytes.NewReader(manifest)
 	return c.Validate(ns, r)
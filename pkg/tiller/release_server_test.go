@@ -692,9 +692,12 @@ func deletePolicyStub(kubeClient *mockHooksKubeClient) *ReleaseServer {
 		ReleaseModule: &LocalReleaseModule{
 			clientset: clientset,
 		},
-		env:       e,
-		clientset: clientset,
-		Log:       func(_ string, _ ...interface{}) {},
+		env:        e,
+		clientset:  clientset,
+		watchers:   newReleaseWatchers(),
+		operations: newOperations(),
+		spans:      newReleaseSpans(),
+		Log:        func(_ string, _ ...interface{}) {},
 	}
 }
 
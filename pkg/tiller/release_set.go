@@ -0,0 +1,286 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tracing"
+)
+
+// appliedMember is a release of a set which was applied, or failed to be.
+type appliedMember struct {
+	result *services.ReleaseSetResult
+	// last is the last revision of the release before it was applied, or
+	// zero if it had no history.
+	last int32
+	// deployed is the revision the release was upgraded from.
+	deployed int32
+}
+
+// ApplyReleaseSet installs the releases of a set which are not deployed, and
+// upgrades the others. Each release is applied once the releases it depends
+// on are, so that the releases not depending on each other are applied in
+// parallel. Once a release fails, the releases not started yet are skipped,
+// and, if the request is atomic, the releases applied are rolled back.
+func (s *ReleaseServer) ApplyReleaseSet(c ctx.Context, req *services.ApplyReleaseSetRequest) (*services.ApplyReleaseSetResponse, error) {
+	if err := validateReleaseSet(req.Releases); err != nil {
+		s.Log("applyReleaseSet: release set is invalid: %s", err)
+		return nil, err
+	}
+
+	var (
+		mu     sync.Mutex
+		failed string
+		// applied are the releases applied or failed, in the order they
+		// were applied
+		applied []*appliedMember
+	)
+	results := make([]*services.ReleaseSetResult, len(req.Releases))
+	done := make(map[string]chan struct{}, len(req.Releases))
+	for _, m := range req.Releases {
+		done[m.Name] = make(chan struct{})
+	}
+	var slots chan struct{}
+	if req.Parallelism > 0 {
+		slots = make(chan struct{}, req.Parallelism)
+	}
+
+	var wg sync.WaitGroup
+	for i, m := range req.Releases {
+		wg.Add(1)
+		go func(i int, m *services.ReleaseSetMember) {
+			defer wg.Done()
+			defer close(done[m.Name])
+			for _, d := range m.DependsOn {
+				<-done[d]
+			}
+			if slots != nil {
+				slots <- struct{}{}
+				defer func() { <-slots }()
+			}
+
+			mu.Lock()
+			stop := failed
+			mu.Unlock()
+			if stop != "" {
+				results[i] = &services.ReleaseSetResult{
+					Name:   m.Name,
+					Status: services.ReleaseSetResult_SKIPPED,
+					Error:  fmt.Sprintf("release %q failed", stop),
+				}
+				return
+			}
+
+			a := s.applySetMember(c, m, req)
+			mu.Lock()
+			defer mu.Unlock()
+			results[i] = a.result
+			applied = append(applied, a)
+			if a.result.Status == services.ReleaseSetResult_FAILED && failed == "" {
+				failed = m.Name
+			}
+		}(i, m)
+	}
+	wg.Wait()
+
+	if failed != "" && req.Atomic && !req.DryRun {
+		s.Log("rolling back release set, %s failed", failed)
+		for i := len(applied) - 1; i >= 0; i-- {
+			a := applied[i]
+			if err := s.rollbackSetMember(c, a, req); err != nil {
+				s.Log("warning: failed to roll back %s: %s", a.result.Name, err)
+				msg := fmt.Sprintf("rollback failed: %s", err)
+				if a.result.Error != "" {
+					msg = a.result.Error + "; " + msg
+				}
+				a.result.Error = msg
+				continue
+			}
+			if a.result.Status == services.ReleaseSetResult_APPLIED {
+				a.result.Status = services.ReleaseSetResult_ROLLED_BACK
+				a.result.Error = fmt.Sprintf("release %q failed", failed)
+			}
+		}
+	}
+
+	return &services.ApplyReleaseSetResponse{Results: results}, nil
+}
+
+// applySetMember installs the release m of the set applied by req if it is
+// not deployed, and upgrades it otherwise.
+func (s *ReleaseServer) applySetMember(c ctx.Context, m *services.ReleaseSetMember, req *services.ApplyReleaseSetRequest) *appliedMember {
+	a := &appliedMember{result: &services.ReleaseSetResult{Name: m.Name}}
+
+	span := tracing.SpanFromContext(c).Child("apply " + m.Name)
+	if span != nil {
+		c = tracing.ContextWithSpan(c, span)
+	}
+
+	var deployed *release.Release
+	h, err := s.env.Releases.History(m.Name)
+	if err == nil {
+		for _, r := range h {
+			if r.Version > a.last {
+				a.last = r.Version
+			}
+			if r.Info.Status.Code == release.Status_DEPLOYED {
+				deployed = r
+			}
+		}
+	}
+
+	var rel *release.Release
+	switch {
+	case deployed == nil:
+		s.Log("installing %s as part of a release set", m.Name)
+		a.result.Installed = true
+		var res *services.InstallReleaseResponse
+		res, err = s.InstallRelease(c, &services.InstallReleaseRequest{
+			Chart:     m.Chart,
+			Values:    m.Values,
+			Name:      m.Name,
+			Namespace: m.Namespace,
+			DryRun:    req.DryRun,
+			ReuseName: len(h) > 0,
+			Timeout:   req.Timeout,
+			Wait:      req.Wait,
+		})
+		rel = res.GetRelease()
+	case m.Namespace != "" && m.Namespace != deployed.Namespace:
+		err = fmt.Errorf("release %q is deployed in namespace %q, not %q", m.Name, deployed.Namespace, m.Namespace)
+	default:
+		s.Log("upgrading %s as part of a release set", m.Name)
+		a.deployed = deployed.Version
+		var res *services.UpdateReleaseResponse
+		res, err = s.UpdateRelease(c, &services.UpdateReleaseRequest{
+			Name:    m.Name,
+			Chart:   m.Chart,
+			Values:  m.Values,
+			DryRun:  req.DryRun,
+			Timeout: req.Timeout,
+			Wait:    req.Wait,
+		})
+		rel = res.GetRelease()
+	}
+	span.Finish(err)
+
+	a.result.Release = rel
+	if err != nil {
+		a.result.Status = services.ReleaseSetResult_FAILED
+		a.result.Error = err.Error()
+		return a
+	}
+	a.result.Status = services.ReleaseSetResult_APPLIED
+	return a
+}
+
+// rollbackSetMember undoes the changes made to a release of a set: a release
+// upgraded is rolled back to the revision it was upgraded from, and a release
+// installed is deleted, and purged unless it was installed over a previous
+// history. Releases without a new revision are left as they are.
+func (s *ReleaseServer) rollbackSetMember(c ctx.Context, a *appliedMember, req *services.ApplyReleaseSetRequest) error {
+	name := a.result.Name
+	if last, err := s.env.Releases.Last(name); err != nil || last.Version <= a.last {
+		return nil
+	}
+
+	if a.result.Installed {
+		s.Log("deleting %s, installed as part of a failed release set", name)
+		_, err := s.UninstallRelease(c, &services.UninstallReleaseRequest{
+			Name:    name,
+			Purge:   a.last == 0,
+			Timeout: req.Timeout,
+		})
+		return err
+	}
+
+	s.Log("rolling back %s to revision %d, upgraded as part of a failed release set", name, a.deployed)
+	_, err := s.RollbackRelease(c, &services.RollbackReleaseRequest{
+		Name:        name,
+		Version:     a.deployed,
+		Timeout:     req.Timeout,
+		Wait:        req.Wait,
+		Description: "Rollback of a failed release set",
+	})
+	return err
+}
+
+// validateReleaseSet checks that the releases of a set have valid and unique
+// names, and depend on releases of the set only, without cycles.
+func validateReleaseSet(members []*services.ReleaseSetMember) error {
+	if len(members) == 0 {
+		return errors.New("no release to apply")
+	}
+
+	pending := make(map[string]int, len(members))
+	for _, m := range members {
+		if err := validateReleaseName(m.Name); err != nil {
+			return fmt.Errorf("release %q: %s", m.Name, err)
+		}
+		if _, ok := pending[m.Name]; ok {
+			return fmt.Errorf("release %q is listed more than once", m.Name)
+		}
+		pending[m.Name] = len(m.DependsOn)
+	}
+
+	dependents := map[string][]string{}
+	for _, m := range members {
+		for _, d := range m.DependsOn {
+			if _, ok := pending[d]; !ok {
+				return fmt.Errorf("release %q depends on %q, which is not in the set", m.Name, d)
+			}
+			dependents[d] = append(dependents[d], m.Name)
+		}
+	}
+
+	// the releases are removed once all the releases they depend on are,
+	// leaving the releases depending on each other
+	var ready []string
+	for name, n := range pending {
+		if n == 0 {
+			ready = append(ready, name)
+		}
+	}
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+		delete(pending, name)
+		for _, d := range dependents[name] {
+			if pending[d]--; pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+	if len(pending) > 0 {
+		cycle := make([]string, 0, len(pending))
+		for name := range pending {
+			cycle = append(cycle, name)
+		}
+		sort.Strings(cycle)
+		return fmt.Errorf("releases %s depend on each other", strings.Join(cycle, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func setMember(name string, dependsOn ...string) *services.ReleaseSetMember {
+	return &services.ReleaseSetMember{
+		Name:      name,
+		Namespace: "spaced",
+		Chart:     chartStub(),
+		DependsOn: dependsOn,
+	}
+}
+
+func TestValidateReleaseSet(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		members []*services.ReleaseSetMember
+		err     string
+	}{
+		{"valid", []*services.ReleaseSetMember{setMember("web", "db"), setMember("db")}, ""},
+		{"empty", nil, "no release to apply"},
+		{"duplicate", []*services.ReleaseSetMember{setMember("db"), setMember("db")}, `release "db" is listed more than once`},
+		{"unknown dependency", []*services.ReleaseSetMember{setMember("web", "db")}, `release "web" depends on "db", which is not in the set`},
+		{"cycle", []*services.ReleaseSetMember{setMember("web", "api"), setMember("api", "web"), setMember("db")}, "releases api, web depend on each other"},
+		{"self dependency", []*services.ReleaseSetMember{setMember("web", "web")}, "releases web depend on each other"},
+	} {
+		err := validateReleaseSet(tt.members)
+		if tt.err == "" && err != nil {
+			t.Errorf("%s: expected a valid set, got %s", tt.name, err)
+		}
+		if tt.err != "" && (err == nil || err.Error() != tt.err) {
+			t.Errorf("%s: expected %q, got %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestApplyReleaseSet(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Namespace = "spaced"
+	rs.env.Releases.Create(rel)
+
+	req := &services.ApplyReleaseSetRequest{Releases: []*services.ReleaseSetMember{
+		setMember("web", rel.Name),
+		setMember(rel.Name),
+	}}
+	res, err := rs.ApplyReleaseSet(c, req)
+	if err != nil {
+		t.Fatalf("Failed apply: %s", err)
+	}
+	if len(res.Results) != 2 {
+		t.Fatalf("Expected a result by release, got %d", len(res.Results))
+	}
+
+	web, upgraded := res.Results[0], res.Results[1]
+	if web.Name != "web" || web.Status != services.ReleaseSetResult_APPLIED || !web.Installed {
+		t.Errorf("Expected web to be installed, got %+v", web)
+	}
+	if upgraded.Status != services.ReleaseSetResult_APPLIED || upgraded.Installed || upgraded.Release.Version != 2 {
+		t.Errorf("Expected %s to be upgraded, got %+v", rel.Name, upgraded)
+	}
+	if installed, err := rs.env.Releases.Deployed("web"); err != nil || installed.Namespace != "spaced" {
+		t.Errorf("Expected web to be deployed in spaced, got %v", installed)
+	}
+}
+
+func TestApplyReleaseSet_Atomic(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Namespace = "spaced"
+	rs.env.Releases.Create(rel)
+
+	broken := setMember("web", "db", rel.Name)
+	broken.Chart = nil
+	req := &services.ApplyReleaseSetRequest{
+		Releases: []*services.ReleaseSetMember{
+			setMember("db"),
+			setMember(rel.Name),
+			broken,
+			setMember("api", "web"),
+		},
+		Atomic:      true,
+		Parallelism: 1,
+	}
+	res, err := rs.ApplyReleaseSet(c, req)
+	if err != nil {
+		t.Fatalf("Failed apply: %s", err)
+	}
+
+	statuses := map[string]services.ReleaseSetResult_Status{}
+	for _, r := range res.Results {
+		statuses[r.Name] = r.Status
+	}
+	for name, status := range map[string]services.ReleaseSetResult_Status{
+		"db":     services.ReleaseSetResult_ROLLED_BACK,
+		rel.Name: services.ReleaseSetResult_ROLLED_BACK,
+		"web":    services.ReleaseSetResult_FAILED,
+		"api":    services.ReleaseSetResult_SKIPPED,
+	} {
+		if statuses[name] != status {
+			t.Errorf("Expected %s to be %s, got %s", name, status, statuses[name])
+		}
+	}
+	if skipped := res.Results[3]; !strings.Contains(skipped.Error, `release "web" failed`) {
+		t.Errorf("Expected the failed release in the error, got %q", skipped.Error)
+	}
+
+	// the release installed is purged, the release upgraded rolled back
+	if h, _ := rs.env.Releases.History("db"); len(h) != 0 {
+		t.Errorf("Expected db to be purged, got %d revisions", len(h))
+	}
+	deployed, err := rs.env.Releases.Deployed(rel.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deployed.Version != 3 || deployed.Info.Description != "Rollback of a failed release set" {
+		t.Errorf("Expected revision 1 to be rolled back to as revision 3, got revision %d: %q", deployed.Version, deployed.Info.Description)
+	}
+}
+
+func TestApplyReleaseSet_NamespaceMismatch(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := namedReleaseStub("web", release.Status_DEPLOYED)
+	rel.Namespace = "other"
+	rs.env.Releases.Create(rel)
+
+	res, err := rs.ApplyReleaseSet(c, &services.ApplyReleaseSetRequest{Releases: []*services.ReleaseSetMember{setMember("web")}})
+	if err != nil {
+		t.Fatalf("Failed apply: %s", err)
+	}
+	if r := res.Results[0]; r.Status != services.ReleaseSetResult_FAILED || !strings.Contains(r.Error, `deployed in namespace "other"`) {
+		t.Errorf("Expected the release of another namespace not to be upgraded, got %+v", r)
+	}
+}
@@ -35,6 +35,15 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 		s.Log("uninstallRelease: Release name is invalid: %s", req.Name)
 		return nil, err
 	}
+	// Unlike other operations, deleting a release whose last revision is
+	// pending is allowed, so that interrupted operations can be cleaned up.
+	unlock, err := s.env.Releases.LockRelease(req.Name)
+	if err != nil {
+		s.Log("uninstall: failed to lock release %s: %s", req.Name, err)
+		return nil, err
+	}
+	defer unlock()
+	defer s.traceRelease(c, req.Name)()
 
 	rels, err := s.env.Releases.History(req.Name)
 	if err != nil {
@@ -81,7 +90,7 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 		s.Log("uninstall: Failed to store updated release: %s", err)
 	}
 
-	kept, errs := s.ReleaseModule.Delete(rel, req, s.env)
+	kept, errs := s.ReleaseModule.Delete(rel, req, s.kubeEnv(rel.Name))
 	res.Info = kept
 
 	es := make([]string, 0, len(errs))
@@ -27,6 +27,7 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/timeconv"
+	"k8s.io/helm/pkg/tracing"
 )
 
 // UpdateRelease takes an existing release and new information, and upgrades the release.
@@ -35,18 +36,42 @@ func (s *ReleaseServer) UpdateRelease(c ctx.Context, req *services.UpdateRelease
 		s.Log("updateRelease: Release name is invalid: %s", req.Name)
 		return nil, err
 	}
+	unlock, err := s.lockRelease(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	op := s.operations.start(req.Name)
+	defer s.operations.end(op)
+	defer s.traceRelease(c, req.Name)()
+
 	s.Log("preparing update for %s", req.Name)
+	render := tracing.SpanFromContext(c).Child("render")
 	currentRelease, updatedRelease, err := s.prepareUpdate(req)
+	render.Finish(err)
 	if err != nil {
 		s.Log("failed to prepare update: %s", err)
 		if req.Force {
 			// Use the --force, Luke.
 			s.Log("performing force update for %s", req.Name)
-			return s.performUpdateForce(req)
+			res, err := s.performUpdateForce(req)
+			if err != nil {
+				err = s.operationFailed(op, "Upgrade", nil, res.GetRelease(), err)
+			}
+			return res, err
 		}
 		return nil, err
 	}
 
+	var adopted *adoption
+	if req.AdoptExisting {
+		s.Log("looking for existing resources of %s", req.Name)
+		if adopted, err = s.adoptExisting(updatedRelease, currentRelease); err != nil {
+			s.Log("failed to adopt existing resources: %s", err)
+			return nil, err
+		}
+	}
+
 	if !req.DryRun {
 		s.Log("creating updated release for %s", req.Name)
 		if err := s.env.Releases.Create(updatedRelease); err != nil {
@@ -55,9 +80,9 @@ func (s *ReleaseServer) UpdateRelease(c ctx.Context, req *services.UpdateRelease
 	}
 
 	s.Log("performing update for %s", req.Name)
-	res, err := s.performUpdate(currentRelease, updatedRelease, req)
+	res, err := s.performUpdate(currentRelease, updatedRelease, req, adopted)
 	if err != nil {
-		return res, err
+		return res, s.operationFailed(op, "Upgrade", adopted.release(currentRelease), updatedRelease, err)
 	}
 
 	if !req.DryRun {
@@ -140,6 +165,9 @@ func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 	if len(notesTxt) > 0 {
 		updatedRelease.Info.Status.Notes = notesTxt
 	}
+	if err := s.admit(updatedRelease); err != nil {
+		return currentRelease, updatedRelease, err
+	}
 	err = validateManifest(s.env.KubeClient, currentRelease.Namespace, manifestDoc.Bytes())
 	return currentRelease, updatedRelease, err
 }
@@ -202,7 +230,7 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 	}
 
 	// delete manifests from the old release
-	_, errs := s.ReleaseModule.Delete(oldRelease, nil, s.env)
+	_, errs := s.ReleaseModule.Delete(oldRelease, nil, s.kubeEnv(oldRelease.Name))
 
 	oldRelease.Info.Status.Code = release.Status_DELETED
 	oldRelease.Info.Description = "Deletion complete"
@@ -232,7 +260,7 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 	}
 
 	s.recordRelease(newRelease, false)
-	if err := s.ReleaseModule.Update(oldRelease, newRelease, req, s.env); err != nil {
+	if err := s.ReleaseModule.Update(oldRelease, newRelease, req, s.kubeEnv(req.Name)); err != nil {
 		msg := fmt.Sprintf("Upgrade %q failed: %s", newRelease.Name, err)
 		s.Log("warning: %s", msg)
 		newRelease.Info.Status.Code = release.Status_FAILED
@@ -264,8 +292,11 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 	return res, nil
 }
 
-func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.Release, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
+func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.Release, req *services.UpdateReleaseRequest, adopted *adoption) (*services.UpdateReleaseResponse, error) {
 	res := &services.UpdateReleaseResponse{Release: updatedRelease}
+	if adopted != nil {
+		res.Adopted = adopted.resources
+	}
 
 	if req.DryRun {
 		s.Log("dry run for %s", updatedRelease.Name)
@@ -281,7 +312,7 @@ func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.R
 	} else {
 		s.Log("update hooks disabled for %s", req.Name)
 	}
-	if err := s.ReleaseModule.Update(originalRelease, updatedRelease, req, s.env); err != nil {
+	if err := s.ReleaseModule.Update(adopted.release(originalRelease), updatedRelease, req, s.kubeEnv(req.Name)); err != nil {
 		msg := fmt.Sprintf("Upgrade %q failed: %s", updatedRelease.Name, err)
 		s.Log("warning: %s", msg)
 		updatedRelease.Info.Status.Code = release.Status_FAILED
@@ -604,13 +604,63 @@ func TestUpdateReleasePendingInstall_Force(t *testing.T) {
 		t.Error("Expected failed update")
 	}
 
-	expectedError := "a release named forceful-luke is in use, cannot re-use a name that is still in use"
+	expectedError := `another operation (install) is in progress for release "forceful-luke" at revision 1`
 	got := err.Error()
 	if err.Error() != expectedError {
 		t.Errorf("Expected error %q, got %q", expectedError, got)
 	}
 }
 
+func TestUpdateReleasePendingUpgrade(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	pending := upgradeReleaseVersion(rel)
+	pending.Info.Status.Code = release.Status_PENDING_UPGRADE
+	rs.env.Releases.Create(pending)
+
+	req := &services.UpdateReleaseRequest{
+		Name:  rel.Name,
+		Chart: rel.GetChart(),
+	}
+
+	_, err := rs.UpdateRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected failed update")
+	}
+	expectedError := `another operation (upgrade) is in progress for release "angry-panda" at revision 2`
+	if err.Error() != expectedError {
+		t.Errorf("Expected error %q, got %q", expectedError, err)
+	}
+}
+
+func TestUpdateReleaseLocked(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	// another operation on the release is in progress
+	unlock, err := rs.env.Releases.LockRelease(rel.Name)
+	if err != nil {
+		t.Fatalf("Failed to lock release: %s", err)
+	}
+
+	req := &services.UpdateReleaseRequest{
+		Name:  rel.Name,
+		Chart: rel.GetChart(),
+	}
+	if _, err := rs.UpdateRelease(c, req); err == nil || !strings.Contains(err.Error(), "another operation is in progress") {
+		t.Errorf("Expected a locked release error, got %v", err)
+	}
+
+	unlock()
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Errorf("Failed updated: %s", err)
+	}
+}
+
 func compareStoredAndReturnedRelease(t *testing.T, rs ReleaseServer, res services.UpdateReleaseResponse) *release.Release {
 	storedRelease, err := rs.env.Releases.Get(res.Release.Name, res.Release.Version)
 	if err != nil {
@@ -0,0 +1,204 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// watchBuffer is the number of events buffered for each watcher. A watcher
+// falling further behind is dropped.
+const watchBuffer = 256
+
+// errWatchBehind ends the watch of a client not receiving events fast enough.
+var errWatchBehind = errors.New("release watch dropped: events were not received fast enough")
+
+// WatchReleases streams the events of the releases matching the request, until
+// the client goes away.
+func (s *ReleaseServer) WatchReleases(req *services.WatchReleasesRequest, stream services.ReleaseService_WatchReleasesServer) error {
+	w := s.watchers.add(req.Name, req.Namespace)
+	defer s.watchers.remove(w)
+
+	for {
+		select {
+		case ev, ok := <-w.events:
+			if !ok {
+				s.Log("dropping release watch: %s", errWatchBehind)
+				return errWatchBehind
+			}
+			if err := stream.Send(&services.WatchReleasesResponse{Event: ev}); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// releaseWatcher is a client watching the releases matching a name and a
+// namespace, empty values matching every release.
+type releaseWatcher struct {
+	name      string
+	namespace string
+	events    chan *services.ReleaseEvent
+}
+
+func (w *releaseWatcher) matches(ev *services.ReleaseEvent) bool {
+	return (w.name == "" || w.name == ev.Name) && (w.namespace == "" || w.namespace == ev.Namespace)
+}
+
+// releaseWatchers broadcasts the events of releases to their watchers.
+type releaseWatchers struct {
+	mu       sync.Mutex
+	watchers map[*releaseWatcher]struct{}
+	// statuses are the status codes of the last revision seen of each
+	// release while there are watchers, by release name, so that updates
+	// leaving the status of the last revision unchanged are not reported.
+	statuses map[string]revisionStatus
+}
+
+// revisionStatus is the status code of a revision of a release.
+type revisionStatus struct {
+	version int32
+	code    release.Status_Code
+}
+
+func newReleaseWatchers() *releaseWatchers {
+	return &releaseWatchers{
+		watchers: map[*releaseWatcher]struct{}{},
+		statuses: map[string]revisionStatus{},
+	}
+}
+
+// add registers a watcher of the releases matching name and namespace.
+func (rw *releaseWatchers) add(name, namespace string) *releaseWatcher {
+	w := &releaseWatcher{
+		name:      name,
+		namespace: namespace,
+		events:    make(chan *services.ReleaseEvent, watchBuffer),
+	}
+	rw.mu.Lock()
+	rw.watchers[w] = struct{}{}
+	rw.mu.Unlock()
+	return w
+}
+
+// remove unregisters w, if it was not dropped already.
+func (rw *releaseWatchers) remove(w *releaseWatcher) {
+	rw.mu.Lock()
+	delete(rw.watchers, w)
+	rw.mu.Unlock()
+}
+
+// send sends ev to the watchers it matches, dropping the ones whose buffer is
+// full. rw.mu must be held.
+func (rw *releaseWatchers) send(ev *services.ReleaseEvent) {
+	for w := range rw.watchers {
+		if !w.matches(ev) {
+			continue
+		}
+		select {
+		case w.events <- ev:
+		default:
+			delete(rw.watchers, w)
+			close(w.events)
+		}
+	}
+}
+
+// storageChanged publishes the event of a change made to rls in storage. It
+// is the Notify function of the release storage.
+func (rw *releaseWatchers) storageChanged(change storage.Change, rls *release.Release) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if len(rw.watchers) == 0 {
+		// statuses seen before would be stale once there are watchers again
+		if len(rw.statuses) > 0 {
+			rw.statuses = map[string]revisionStatus{}
+		}
+		return
+	}
+
+	code := rls.GetInfo().GetStatus().GetCode()
+	last, seen := rw.statuses[rls.Name]
+	ev := &services.ReleaseEvent{
+		Name:        rls.Name,
+		Namespace:   rls.Namespace,
+		Version:     rls.Version,
+		Status:      code,
+		Description: rls.GetInfo().GetDescription(),
+		Time:        timeconv.Now(),
+	}
+
+	switch change {
+	case storage.ReleaseCreated:
+		ev.Type = services.ReleaseEvent_CREATED
+		if !seen || rls.Version >= last.version {
+			rw.statuses[rls.Name] = revisionStatus{rls.Version, code}
+		}
+	case storage.ReleaseUpdated:
+		if seen && last.version == rls.Version && last.code == code {
+			return
+		}
+		if !seen || rls.Version >= last.version {
+			rw.statuses[rls.Name] = revisionStatus{rls.Version, code}
+		}
+		switch code {
+		case release.Status_SUPERSEDED:
+			ev.Type = services.ReleaseEvent_SUPERSEDED
+		case release.Status_DELETED:
+			ev.Type = services.ReleaseEvent_DELETED
+		default:
+			ev.Type = services.ReleaseEvent_STATUS_CHANGED
+		}
+	case storage.ReleaseDeleted:
+		if seen && last.version == rls.Version {
+			delete(rw.statuses, rls.Name)
+		}
+		ev.Type = services.ReleaseEvent_PURGED
+	}
+	rw.send(ev)
+}
+
+// hookRan publishes the event of hook h of the named release having run for
+// event, err being the error it failed with, if any.
+func (rw *releaseWatchers) hookRan(name, namespace string, h *release.Hook, event release.Hook_Event, err error) {
+	ev := &services.ReleaseEvent{
+		Type:        services.ReleaseEvent_HOOK_RAN,
+		Name:        name,
+		Namespace:   namespace,
+		Description: "Hook succeeded",
+		Hook:        h.Name,
+		HookEvent:   event,
+		Time:        timeconv.Now(),
+	}
+	if err != nil {
+		ev.Description = fmt.Sprintf("Hook failed: %s", err)
+	}
+
+	rw.mu.Lock()
+	rw.send(ev)
+	rw.mu.Unlock()
+}
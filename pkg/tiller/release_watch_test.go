@@ -0,0 +1,198 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+)
+
+type mockWatchServer struct {
+	ctx    context.Context
+	events chan *services.ReleaseEvent
+}
+
+func (w *mockWatchServer) Send(res *services.WatchReleasesResponse) error {
+	w.events <- res.Event
+	return nil
+}
+
+func (w *mockWatchServer) SetHeader(m metadata.MD) error  { return nil }
+func (w *mockWatchServer) SendHeader(m metadata.MD) error { return nil }
+func (w *mockWatchServer) SetTrailer(m metadata.MD)       {}
+func (w *mockWatchServer) SendMsg(v interface{}) error    { return nil }
+func (w *mockWatchServer) RecvMsg(v interface{}) error    { return nil }
+func (w *mockWatchServer) Context() context.Context       { return w.ctx }
+
+// watchReleases starts watching the releases of rs matching req. Cancel the
+// returned context to stop watching.
+func watchReleases(t *testing.T, rs *ReleaseServer, req *services.WatchReleasesRequest) (*mockWatchServer, context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(helm.NewContext())
+	stream := &mockWatchServer{ctx: ctx, events: make(chan *services.ReleaseEvent, 16)}
+	done := make(chan error, 1)
+	go func() { done <- rs.WatchReleases(req, stream) }()
+
+	// wait for the watcher to be registered
+	for i := 0; ; i++ {
+		rs.watchers.mu.Lock()
+		n := len(rs.watchers.watchers)
+		rs.watchers.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		if i == 100 {
+			t.Fatal("Watcher was not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return stream, cancel, done
+}
+
+func TestWatchReleases(t *testing.T) {
+	rs := rsFixture()
+	stream, cancel, done := watchReleases(t, rs, &services.WatchReleasesRequest{Name: "watched"})
+
+	for _, name := range []string{"other", "watched"} {
+		req := &services.InstallReleaseRequest{Name: name, Namespace: "spaced", Chart: buildChart()}
+		if _, err := rs.InstallRelease(helm.NewContext(), req); err != nil {
+			t.Fatalf("Failed install: %s", err)
+		}
+	}
+
+	expected := []struct {
+		typ    services.ReleaseEvent_Type
+		status release.Status_Code
+		hook   string
+	}{
+		{services.ReleaseEvent_CREATED, release.Status_PENDING_INSTALL, ""},
+		{services.ReleaseEvent_HOOK_RAN, release.Status_UNKNOWN, "test-cm"},
+		{services.ReleaseEvent_STATUS_CHANGED, release.Status_DEPLOYED, ""},
+	}
+	for _, e := range expected {
+		select {
+		case ev := <-stream.events:
+			if ev.Type != e.typ || ev.Status != e.status || ev.Hook != e.hook {
+				t.Errorf("Expected %s event with status %s and hook %q, got %s event with status %s and hook %q", e.typ, e.status, e.hook, ev.Type, ev.Status, ev.Hook)
+			}
+			if ev.Name != "watched" || ev.Namespace != "spaced" {
+				t.Errorf("Expected an event of spaced/watched, got %s/%s", ev.Namespace, ev.Name)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for a %s event", e.typ)
+		}
+	}
+	select {
+	case ev := <-stream.events:
+		t.Errorf("Unexpected %s event of %s", ev.Type, ev.Name)
+	default:
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Expected watch to end without error, got %s", err)
+	}
+}
+
+func TestReleaseWatchers(t *testing.T) {
+	rw := newReleaseWatchers()
+	rel := namedReleaseStub("angry-panda", release.Status_DEPLOYED)
+
+	// no watcher, nothing to report
+	rw.storageChanged(storage.ReleaseCreated, rel)
+
+	w := rw.add("", "")
+	rw.storageChanged(storage.ReleaseUpdated, rel)
+	// the status is unchanged
+	rw.storageChanged(storage.ReleaseUpdated, rel)
+	rel.Info.Status.Code = release.Status_SUPERSEDED
+	rw.storageChanged(storage.ReleaseUpdated, rel)
+	rw.storageChanged(storage.ReleaseDeleted, rel)
+
+	expected := []services.ReleaseEvent_Type{
+		services.ReleaseEvent_STATUS_CHANGED,
+		services.ReleaseEvent_SUPERSEDED,
+		services.ReleaseEvent_PURGED,
+	}
+	if len(w.events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(w.events))
+	}
+	for _, e := range expected {
+		if ev := <-w.events; ev.Type != e {
+			t.Errorf("Expected %s event, got %s", e, ev.Type)
+		}
+	}
+
+	// a watcher falling behind is dropped
+	for i := 0; i <= watchBuffer; i++ {
+		rw.storageChanged(storage.ReleaseCreated, rel)
+	}
+	for range w.events {
+	}
+	if len(rw.watchers) != 0 {
+		t.Errorf("Expected the watcher to be dropped")
+	}
+}
+
+func TestReleaseWatchersStatuses(t *testing.T) {
+	rw := newReleaseWatchers()
+	w := rw.add("", "")
+
+	// only the status of the last revision of each release is kept
+	for v := int32(1); v <= 10; v++ {
+		rel := namedReleaseStub("angry-panda", release.Status_PENDING_UPGRADE)
+		rel.Version = v
+		rw.storageChanged(storage.ReleaseCreated, rel)
+		rel.Info.Status.Code = release.Status_DEPLOYED
+		rw.storageChanged(storage.ReleaseUpdated, rel)
+		rw.storageChanged(storage.ReleaseUpdated, rel)
+		if v > 1 {
+			prev := namedReleaseStub("angry-panda", release.Status_SUPERSEDED)
+			prev.Version = v - 1
+			rw.storageChanged(storage.ReleaseUpdated, prev)
+		}
+	}
+	if len(rw.statuses) != 1 || rw.statuses["angry-panda"] != (revisionStatus{10, release.Status_DEPLOYED}) {
+		t.Errorf("Expected the status of revision 10 only, got %v", rw.statuses)
+	}
+	// each revision is created, deployed and, but for the last one, superseded
+	if n := len(w.events); n != 29 {
+		t.Errorf("Expected 29 events, got %d", n)
+	}
+
+	// purging an older revision keeps the status of the last one
+	old := namedReleaseStub("angry-panda", release.Status_SUPERSEDED)
+	old.Version = 9
+	rw.storageChanged(storage.ReleaseDeleted, old)
+	if len(rw.statuses) != 1 {
+		t.Errorf("Expected the status of revision 10 to be kept, got %v", rw.statuses)
+	}
+
+	last := namedReleaseStub("angry-panda", release.Status_DEPLOYED)
+	last.Version = 10
+	rw.storageChanged(storage.ReleaseDeleted, last)
+	if len(rw.statuses) != 0 {
+		t.Errorf("Expected no status once the release is purged, got %v", rw.statuses)
+	}
+}
@@ -20,12 +20,14 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	goprom "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 
+	"k8s.io/helm/pkg/tracing"
 	"k8s.io/helm/pkg/version"
 )
 
@@ -35,11 +37,27 @@ const maxMsgSize = 1024 * 1024 * 20
 
 // DefaultServerOpts returns the set of default grpc ServerOption's that Tiller requires.
 func DefaultServerOpts() []grpc.ServerOption {
+	return serverOpts(ServerConfig{})
+}
+
+// ServerConfig configures the optional checks of the calls made to Tiller.
+type ServerConfig struct {
+	// Authorizer, if set, authorizes the calls.
+	Authorizer *Authorizer
+	// Auditor, if set, records the calls changing releases.
+	Auditor *Auditor
+	// Notifier, if set, notifies webhooks of the calls changing releases.
+	Notifier *Notifier
+	// Tracer, if set, traces the calls.
+	Tracer *tracing.Tracer
+}
+
+func serverOpts(cfg ServerConfig) []grpc.ServerOption {
 	return []grpc.ServerOption{
 		grpc.MaxRecvMsgSize(maxMsgSize),
 		grpc.MaxSendMsgSize(maxMsgSize),
-		grpc.UnaryInterceptor(newUnaryInterceptor()),
-		grpc.StreamInterceptor(newStreamInterceptor()),
+		grpc.UnaryInterceptor(newUnaryInterceptor(cfg)),
+		grpc.StreamInterceptor(newStreamInterceptor(cfg)),
 	}
 }
 
@@ -48,7 +66,13 @@ func NewServer(opts ...grpc.ServerOption) *grpc.Server {
 	return grpc.NewServer(append(DefaultServerOpts(), opts...)...)
 }
 
-func newUnaryInterceptor() grpc.UnaryServerInterceptor {
+// NewServerWithConfig creates a new grpc server whose calls are checked as
+// configured by cfg.
+func NewServerWithConfig(cfg ServerConfig, opts ...grpc.ServerOption) *grpc.Server {
+	return grpc.NewServer(append(serverOpts(cfg), opts...)...)
+}
+
+func newUnaryInterceptor(cfg ServerConfig) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		if err := checkClientVersion(ctx); err != nil {
 			// whitelist GetVersion() from the version check
@@ -57,17 +81,61 @@ func newUnaryInterceptor() grpc.UnaryServerInterceptor {
 				return nil, err
 			}
 		}
-		return goprom.UnaryServerInterceptor(ctx, req, info, handler)
+
+		start := time.Now()
+		var span *tracing.Span
+		if cfg.Tracer != nil {
+			ctx, span = startSpan(ctx, cfg.Tracer, info.FullMethod, req)
+		}
+		if cfg.Authorizer != nil {
+			err = cfg.Authorizer.Authorize(ctx, info.FullMethod, req)
+		}
+		if err == nil {
+			resp, err = goprom.UnaryServerInterceptor(ctx, req, info, handler)
+			if cfg.Notifier != nil {
+				cfg.Notifier.Notify(info.FullMethod, req, resp, err)
+			}
+		}
+		if cfg.Auditor != nil {
+			cfg.Auditor.Record(ctx, info.FullMethod, req, resp, err, start)
+		}
+		observeOperation(info.FullMethod, start, err)
+		span.Finish(err)
+		return resp, err
 	}
 }
 
-func newStreamInterceptor() grpc.StreamServerInterceptor {
+func newStreamInterceptor(cfg ServerConfig) grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		if err := checkClientVersion(ss.Context()); err != nil {
 			log.Println(err)
 			return err
 		}
-		return goprom.StreamServerInterceptor(srv, ss, info, handler)
+
+		var span *tracing.Span
+		if cfg.Tracer != nil {
+			var ctx context.Context
+			ctx, span = startSpan(ss.Context(), cfg.Tracer, info.FullMethod, nil)
+			ss = &tracedStream{ServerStream: ss, ctx: ctx}
+		}
+		// the request of a stream is checked as it is received
+		if cfg.Authorizer != nil {
+			ss = &authorizingStream{ServerStream: ss, authz: cfg.Authorizer, fullMethod: info.FullMethod}
+		}
+		var audited *auditingStream
+		if cfg.Auditor != nil {
+			audited = &auditingStream{ServerStream: ss}
+			ss = audited
+		}
+
+		start := time.Now()
+		err := goprom.StreamServerInterceptor(srv, ss, info, handler)
+		if audited != nil {
+			cfg.Auditor.Record(ss.Context(), info.FullMethod, audited.req, nil, err, start)
+		}
+		observeOperation(info.FullMethod, start, err)
+		span.Finish(err)
+		return err
 	}
 }
 
@@ -0,0 +1,195 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	v1 "k8s.io/api/core/v1"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller/environment"
+	"k8s.io/helm/pkg/tracing"
+)
+
+// startSpan starts the span of the call of fullMethod with req, child of the
+// span the client made the call in, if any, and returns a copy of ctx
+// carrying it.
+func startSpan(ctx context.Context, tracer *tracing.Tracer, fullMethod string, req interface{}) (context.Context, *tracing.Span) {
+	var parent tracing.SpanContext
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md[tracing.MetadataKey]; len(v) > 0 {
+			parent, _ = tracing.ParseTraceParent(v[0])
+		}
+	}
+
+	_, rpc := splitMethod(fullMethod)
+	span := tracer.Start(rpc, parent)
+	span.SetAttribute("rpc.method", fullMethod)
+	if r, ok := req.(interface{ GetName() string }); ok && r.GetName() != "" {
+		span.SetAttribute("release", r.GetName())
+	}
+	return tracing.ContextWithSpan(ctx, span), span
+}
+
+// tracedStream is a server stream whose context carries the span of the call.
+type tracedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedStream) Context() context.Context {
+	return s.ctx
+}
+
+// releaseSpans are the spans of the calls in progress changing releases, by
+// release name. The work done on a release, e.g. its hooks or the calls made
+// to Kubernetes and to the storage, is traced as children of its span.
+type releaseSpans struct {
+	mu    sync.Mutex
+	spans map[string]*tracing.Span
+}
+
+func newReleaseSpans() *releaseSpans {
+	return &releaseSpans{spans: map[string]*tracing.Span{}}
+}
+
+// trace registers span as the span of the named release until the returned
+// function is called.
+func (r *releaseSpans) trace(name string, span *tracing.Span) func() {
+	if span == nil {
+		return func() {}
+	}
+	r.mu.Lock()
+	r.spans[name] = span
+	r.mu.Unlock()
+	return func() {
+		r.mu.Lock()
+		if r.spans[name] == span {
+			delete(r.spans, name)
+		}
+		r.mu.Unlock()
+	}
+}
+
+// get returns the span of the named release, or nil if it is not traced.
+func (r *releaseSpans) get(name string) *tracing.Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.spans[name]
+}
+
+// traceRelease traces the work done on the named release under the span of
+// the call c, if any, until the returned function is called.
+func (s *ReleaseServer) traceRelease(c context.Context, name string) func() {
+	return s.spans.trace(name, tracing.SpanFromContext(c))
+}
+
+// TraceStorage traces the calls made to the storage of the releases of s
+// while they are traced.
+func (s *ReleaseServer) TraceStorage() {
+	s.env.Releases.Driver = driver.NewObserved(s.env.Releases.Driver, s.traceStorage)
+}
+
+// storageKeyVersion matches the version suffix of storage keys, e.g. ".v2".
+var storageKeyVersion = regexp.MustCompile(`\.v[0-9]+$`)
+
+func (s *ReleaseServer) traceStorage(op, key string, elapsed time.Duration) {
+	if key == "" {
+		return
+	}
+	span := s.spans.get(storageKeyVersion.ReplaceAllString(key, ""))
+	span.Record("storage "+op, time.Now().Add(-elapsed), nil, "key", key)
+}
+
+// tracedKubeClient is a KubeClient tracing the calls made to it as children
+// of a span.
+type tracedKubeClient struct {
+	environment.KubeClient
+	span *tracing.Span
+}
+
+func (c *tracedKubeClient) record(method, namespace string, start time.Time, err *error) {
+	c.span.Record("kube "+method, start, *err, "namespace", namespace)
+}
+
+func (c *tracedKubeClient) Create(namespace string, reader io.Reader, timeout int64, shouldWait bool) (err error) {
+	defer c.record("Create", namespace, time.Now(), &err)
+	return c.KubeClient.Create(namespace, reader, timeout, shouldWait)
+}
+
+func (c *tracedKubeClient) Get(namespace string, reader io.Reader) (_ string, err error) {
+	defer c.record("Get", namespace, time.Now(), &err)
+	return c.KubeClient.Get(namespace, reader)
+}
+
+func (c *tracedKubeClient) Delete(namespace string, reader io.Reader) (err error) {
+	defer c.record("Delete", namespace, time.Now(), &err)
+	return c.KubeClient.Delete(namespace, reader)
+}
+
+func (c *tracedKubeClient) DeleteWithTimeout(namespace string, reader io.Reader, timeout int64, shouldWait bool) (err error) {
+	defer c.record("DeleteWithTimeout", namespace, time.Now(), &err)
+	return c.KubeClient.DeleteWithTimeout(namespace, reader, timeout, shouldWait)
+}
+
+func (c *tracedKubeClient) WatchUntilReady(namespace string, reader io.Reader, timeout int64, shouldWait bool) (err error) {
+	defer c.record("WatchUntilReady", namespace, time.Now(), &err)
+	return c.KubeClient.WatchUntilReady(namespace, reader, timeout, shouldWait)
+}
+
+func (c *tracedKubeClient) Update(namespace string, originalReader, modifiedReader io.Reader, force bool, recreate bool, timeout int64, shouldWait bool) (err error) {
+	defer c.record("Update", namespace, time.Now(), &err)
+	return c.KubeClient.Update(namespace, originalReader, modifiedReader, force, recreate, timeout, shouldWait)
+}
+
+func (c *tracedKubeClient) UpdateWithOptions(namespace string, originalReader, modifiedReader io.Reader, opts kube.UpdateOptions) (err error) {
+	defer c.record("UpdateWithOptions", namespace, time.Now(), &err)
+	return c.KubeClient.UpdateWithOptions(namespace, originalReader, modifiedReader, opts)
+}
+
+func (c *tracedKubeClient) Build(namespace string, reader io.Reader) (_ kube.Result, err error) {
+	defer c.record("Build", namespace, time.Now(), &err)
+	return c.KubeClient.Build(namespace, reader)
+}
+
+func (c *tracedKubeClient) BuildUnstructured(namespace string, reader io.Reader) (_ kube.Result, err error) {
+	defer c.record("BuildUnstructured", namespace, time.Now(), &err)
+	return c.KubeClient.BuildUnstructured(namespace, reader)
+}
+
+func (c *tracedKubeClient) Validate(namespace string, reader io.Reader) (err error) {
+	defer c.record("Validate", namespace, time.Now(), &err)
+	return c.KubeClient.Validate(namespace, reader)
+}
+
+func (c *tracedKubeClient) WaitAndGetCompletedPodPhase(namespace string, reader io.Reader, timeout time.Duration) (_ v1.PodPhase, err error) {
+	defer c.record("WaitAndGetCompletedPodPhase", namespace, time.Now(), &err)
+	return c.KubeClient.WaitAndGetCompletedPodPhase(namespace, reader, timeout)
+}
+
+func (c *tracedKubeClient) WaitUntilCRDEstablished(reader io.Reader, timeout time.Duration) (err error) {
+	defer c.record("WaitUntilCRDEstablished", "", time.Now(), &err)
+	return c.KubeClient.WaitUntilCRDEstablished(reader, timeout)
+}
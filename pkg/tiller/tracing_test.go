@@ -0,0 +1,116 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tracing"
+)
+
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*tracing.Span
+}
+
+func (r *spanRecorder) Export(span *tracing.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+func (r *spanRecorder) Flush() error { return nil }
+
+func TestStartSpan(t *testing.T) {
+	tracer := tracing.NewTracer("tiller", &spanRecorder{})
+	parent := tracing.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	md := metadata.Pairs(tracing.MetadataKey, parent.TraceParent())
+
+	c, span := startSpan(metadata.NewIncomingContext(context.Background(), md), tracer,
+		"/hapi.services.tiller.ReleaseService/UpdateRelease", &services.UpdateReleaseRequest{Name: "angry-panda"})
+	if tracing.SpanFromContext(c) != span {
+		t.Error("Expected the context to carry the span")
+	}
+	if span.Name != "UpdateRelease" || span.TraceID != parent.TraceID || span.ParentID != parent.SpanID {
+		t.Errorf("Expected a child span of the client, got %+v", span)
+	}
+	if span.Attributes["release"] != "angry-panda" {
+		t.Errorf("Expected the release to be an attribute of the span, got %v", span.Attributes)
+	}
+
+	// a new trace is started for clients not tracing
+	_, span = startSpan(context.Background(), tracer, "/hapi.services.tiller.ReleaseService/GetVersion", &services.GetVersionRequest{})
+	if span.ParentID != "" || !span.Context().IsValid() {
+		t.Errorf("Expected a new trace, got %+v", span)
+	}
+}
+
+func TestUpdateRelease_Tracing(t *testing.T) {
+	rs := rsFixture()
+	rs.TraceStorage()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	recorder := &spanRecorder{}
+	root := tracing.NewTracer("tiller", recorder).Start("UpdateRelease", tracing.SpanContext{})
+	c := tracing.ContextWithSpan(helm.NewContext(), root)
+
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/hello", Data: []byte("hello: world")},
+				{Name: "templates/hooks", Data: []byte(manifestWithUpgradeHooks)},
+			},
+		},
+	}
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+
+	names := map[string]int{}
+	for _, span := range recorder.spans {
+		if span.TraceID != root.TraceID || span.ParentID != root.SpanID {
+			t.Errorf("Expected span %s to be a child of the call, got %+v", span.Name, span)
+		}
+		names[span.Name]++
+	}
+	for name, n := range map[string]int{
+		"render":                 1,
+		"hook test-cm":           2,
+		"kube UpdateWithOptions": 1,
+		"storage create":         1,
+		"storage update":         1,
+	} {
+		if names[name] < n {
+			t.Errorf("Expected %d %q spans, got %v", n, name, names)
+		}
+	}
+
+	// the release is no longer traced once the call returned
+	if rs.spans.get(rel.Name) != nil {
+		t.Error("Expected the span of the release to be unregistered")
+	}
+}
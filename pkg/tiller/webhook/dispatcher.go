@@ -0,0 +1,188 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook // import "k8s.io/helm/pkg/tiller/webhook"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultQueueSize is the default number of payloads queued for each
+	// endpoint.
+	DefaultQueueSize = 100
+	// DefaultMaxAttempts is the default number of times the delivery of a
+	// payload is attempted.
+	DefaultMaxAttempts = 5
+	// DefaultBackoff is the default delay before the first retry of a
+	// delivery, doubled for each of the next ones.
+	DefaultBackoff = time.Second
+
+	maxBackoff = time.Minute
+)
+
+// Sender sends payloads.
+type Sender interface {
+	Send(p *Payload)
+}
+
+// delivery is a payload to deliver.
+type delivery struct {
+	event string
+	body  []byte
+}
+
+// endpointQueue holds the payloads to deliver to an endpoint, delivered in
+// order by a worker of its own so that a slow endpoint does not delay the
+// others.
+type endpointQueue struct {
+	endpoint   Endpoint
+	deliveries chan delivery
+}
+
+// Dispatcher delivers payloads to endpoints in the background.
+type Dispatcher struct {
+	// Client is the client POSTing payloads.
+	Client *http.Client
+	// MaxAttempts is the number of times the delivery of a payload is
+	// attempted before it is dropped.
+	MaxAttempts int
+	// Backoff is the delay before the first retry of a delivery, doubled for
+	// each of the next ones, up to a minute.
+	Backoff time.Duration
+
+	Log func(string, ...interface{})
+
+	queues []*endpointQueue
+}
+
+// NewDispatcher returns a Dispatcher delivering payloads to the endpoints of
+// cfg, queuing up to queueSize payloads for each of them.
+func NewDispatcher(cfg *Config, queueSize int) *Dispatcher {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	d := &Dispatcher{
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     DefaultBackoff,
+		Log:         func(_ string, _ ...interface{}) {},
+	}
+	for _, e := range cfg.Endpoints {
+		d.queues = append(d.queues, &endpointQueue{
+			endpoint:   e,
+			deliveries: make(chan delivery, queueSize),
+		})
+	}
+	return d
+}
+
+// Start starts delivering payloads, until stop is closed.
+func (d *Dispatcher) Start(stop <-chan struct{}) {
+	for _, q := range d.queues {
+		go d.run(q, stop)
+	}
+}
+
+// Send queues p for delivery to the endpoints notified of its event. It
+// never blocks: the payload is dropped for the endpoints whose queue is full.
+func (d *Dispatcher) Send(p *Payload) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		d.Log("failed to encode webhook payload: %s", err)
+		return
+	}
+	for _, q := range d.queues {
+		if !q.endpoint.notifies(p.Event) {
+			continue
+		}
+		select {
+		case q.deliveries <- delivery{event: p.Event, body: body}:
+		default:
+			d.Log("dropping %s webhook of %s for %s: queue is full", p.Event, p.Release, q.endpoint.URL)
+		}
+	}
+}
+
+func (d *Dispatcher) run(q *endpointQueue, stop <-chan struct{}) {
+	for {
+		select {
+		case dl := <-q.deliveries:
+			d.deliver(q.endpoint, dl, stop)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// deliver POSTs dl to e, retrying with an exponential backoff on network
+// errors and on server errors.
+func (d *Dispatcher) deliver(e Endpoint, dl delivery, stop <-chan struct{}) {
+	backoff := d.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := d.post(e, dl)
+		if err == nil {
+			return
+		}
+		if !retry || attempt >= d.MaxAttempts {
+			d.Log("failed to deliver %s webhook to %s after %d attempts: %s", dl.event, e.URL, attempt, err)
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-stop:
+			return
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// post POSTs dl to e, returning whether a failed delivery may be retried.
+func (d *Dispatcher) post(e Endpoint, dl delivery) (bool, error) {
+	req, err := http.NewRequest("POST", e.URL, bytes.NewReader(dl.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, dl.event)
+	if e.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(e.Secret, dl.body))
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("server responded with %s", resp.Status)
+	default:
+		return false, fmt.Errorf("server responded with %s", resp.Status)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*Package webhook notifies HTTP endpoints of the installs, upgrades, rollbacks,
+deletions and renames of releases.
+
+Each operation is described by a Payload, POSTed as JSON to the endpoints of
+a Config by a Dispatcher. Payloads are signed with the HMAC-SHA256 of the
+secret of the endpoint, and delivered in the background, with retries.
+*/
+package webhook // import "k8s.io/helm/pkg/tiller/webhook"
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+// Events are the operations on releases endpoints are notified of.
+const (
+	Install  = "install"
+	Upgrade  = "upgrade"
+	Rollback = "rollback"
+	Delete   = "delete"
+	Rename   = "rename"
+)
+
+// Outcomes of the operations.
+const (
+	Success = "success"
+	Failure = "failure"
+)
+
+const (
+	// EventHeader is the header holding the event of a payload.
+	EventHeader = "X-Helm-Event"
+	// SignatureHeader is the header holding the signature of a payload, as
+	// returned by Sign.
+	SignatureHeader = "X-Helm-Signature"
+)
+
+// Payload describes an operation on a release.
+type Payload struct {
+	Event        string    `json:"event"`
+	Outcome      string    `json:"outcome"`
+	Release      string    `json:"release"`
+	RenamedFrom  string    `json:"renamedFrom,omitempty"`
+	Revision     int32     `json:"revision,omitempty"`
+	Namespace    string    `json:"namespace,omitempty"`
+	Chart        string    `json:"chart,omitempty"`
+	ChartVersion string    `json:"chartVersion,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	Time         time.Time `json:"time"`
+}
+
+// Endpoint is an HTTP endpoint payloads are POSTed to.
+type Endpoint struct {
+	URL string `json:"url"`
+	// Secret is the key the payloads are signed with. Payloads are not signed
+	// if it is empty.
+	Secret string `json:"secret,omitempty"`
+	// Events are the events the endpoint is notified of, all if empty.
+	Events []string `json:"events,omitempty"`
+}
+
+// notifies reports whether the endpoint is notified of event.
+func (e *Endpoint) notifies(event string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, ev := range e.Events {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Config lists the endpoints to notify.
+type Config struct {
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// LoadConfig reads the webhook configuration of a YAML file.
+func LoadConfig(filename string) (*Config, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("cannot parse webhook configuration %s: %s", filename, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid webhook configuration %s: %s", filename, err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	for i, e := range c.Endpoints {
+		u, err := url.Parse(e.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("endpoint %d: invalid URL %q", i, e.URL)
+		}
+		for _, ev := range e.Events {
+			switch ev {
+			case Install, Upgrade, Rollback, Delete, Rename:
+			default:
+				return fmt.Errorf("endpoint %d: unknown event %q", i, ev)
+			}
+		}
+	}
+	return nil
+}
+
+// Sign returns the signature of body with secret, as sha256= followed by the
+// hex encoded HMAC-SHA256 of body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
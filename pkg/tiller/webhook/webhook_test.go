@@ -0,0 +1,207 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook // import "k8s.io/helm/pkg/tiller/webhook"
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testPayload(event string) *Payload {
+	return &Payload{
+		Event:        event,
+		Outcome:      Success,
+		Release:      "happy-panda",
+		Revision:     2,
+		Namespace:    "default",
+		Chart:        "nginx",
+		ChartVersion: "0.1.0",
+		Status:       "DEPLOYED",
+		Description:  "Upgrade complete",
+		Time:         time.Date(2019, 5, 16, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+// receiver records the requests it receives, failing the first ones with
+// the status codes of fail.
+type receiver struct {
+	mu       sync.Mutex
+	fail     []int
+	requests []*http.Request
+	bodies   [][]byte
+	received chan struct{}
+}
+
+func newReceiver(fail ...int) *receiver {
+	return &receiver{fail: fail, received: make(chan struct{}, 100)}
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	r.mu.Lock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	var code int
+	if len(r.fail) > 0 {
+		code, r.fail = r.fail[0], r.fail[1:]
+	}
+	r.mu.Unlock()
+	if code != 0 {
+		w.WriteHeader(code)
+	}
+	r.received <- struct{}{}
+}
+
+func (r *receiver) wait(t *testing.T, n int) {
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for request %d", i+1)
+		}
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	signed := newReceiver(http.StatusServiceUnavailable, http.StatusInternalServerError)
+	signedSrv := httptest.NewServer(signed)
+	defer signedSrv.Close()
+	upgrades := newReceiver(http.StatusBadRequest)
+	upgradesSrv := httptest.NewServer(upgrades)
+	defer upgradesSrv.Close()
+
+	d := NewDispatcher(&Config{Endpoints: []Endpoint{
+		{URL: signedSrv.URL, Secret: "s3cr3t"},
+		{URL: upgradesSrv.URL, Events: []string{Upgrade}},
+	}}, 0)
+	d.Backoff = time.Millisecond
+	stop := make(chan struct{})
+	defer close(stop)
+	d.Start(stop)
+
+	d.Send(testPayload(Install))
+	d.Send(testPayload(Upgrade))
+
+	// the install is retried twice, then the upgrade is delivered
+	signed.wait(t, 4)
+	// the upgrade is rejected, and not retried
+	upgrades.wait(t, 1)
+
+	for i, req := range signed.requests {
+		if req.Method != "POST" || req.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request %s with content type %q", req.Method, req.Header.Get("Content-Type"))
+		}
+		if sig := req.Header.Get(SignatureHeader); sig != Sign("s3cr3t", signed.bodies[i]) {
+			t.Errorf("Unexpected signature %q", sig)
+		}
+	}
+	if ev := signed.requests[3].Header.Get(EventHeader); ev != Upgrade {
+		t.Errorf("Expected the last event to be an upgrade, got %q", ev)
+	}
+	var p Payload
+	if err := json.Unmarshal(signed.bodies[0], &p); err != nil {
+		t.Fatalf("Failed to decode payload: %s", err)
+	}
+	if p != *testPayload(Install) {
+		t.Errorf("Expected payload %+v, got %+v", testPayload(Install), p)
+	}
+
+	if sig := upgrades.requests[0].Header.Get(SignatureHeader); sig != "" {
+		t.Errorf("Expected an unsigned payload, got signature %q", sig)
+	}
+	select {
+	case <-upgrades.received:
+		t.Error("Expected the rejected upgrade not to be retried")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDispatcherQueueFull(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer srv.Close()
+	defer close(block)
+
+	d := NewDispatcher(&Config{Endpoints: []Endpoint{{URL: srv.URL}}}, 2)
+	var dropped int
+	var mu sync.Mutex
+	d.Log = func(_ string, _ ...interface{}) {
+		mu.Lock()
+		dropped++
+		mu.Unlock()
+	}
+
+	// without workers, sending never blocks, dropping the payloads that do
+	// not fit in the queue
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			d.Send(testPayload(Install))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected sending to never block")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if dropped != 3 {
+		t.Errorf("Expected 3 payloads to be dropped, got %d", dropped)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-webhook-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "webhooks.yaml")
+
+	tests := []struct {
+		config string
+		valid  bool
+	}{
+		{"endpoints:\n- url: https://hooks.example.com/helm\n  secret: s3cr3t\n  events: [install, upgrade]\n", true},
+		{"endpoints:\n- url: hooks.example.com/helm\n", false},
+		{"endpoints:\n- url: https://hooks.example.com/helm\n  events: [rename, delete]\n", true},
+		{"endpoints:\n- url: https://hooks.example.com/helm\n  events: [purge]\n", false},
+	}
+	for _, tt := range tests {
+		if err := ioutil.WriteFile(filename, []byte(tt.config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadConfig(filename)
+		if tt.valid && (err != nil || len(cfg.Endpoints) != 1) {
+			t.Errorf("Expected\n%s\nto load, got %v", tt.config, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("Expected an error loading\n%s", tt.config)
+		}
+	}
+}
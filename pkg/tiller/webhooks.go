@@ -0,0 +1,134 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"time"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/tiller/webhook"
+)
+
+// webhookEvents are the webhook events of the RPCs changing releases.
+var webhookEvents = map[string]string{
+	"InstallRelease":   webhook.Install,
+	"UpdateRelease":    webhook.Upgrade,
+	"RollbackRelease":  webhook.Rollback,
+	"UninstallRelease": webhook.Delete,
+	"RenameRelease":    webhook.Rename,
+}
+
+// Notifier notifies webhooks of the installs, upgrades, rollbacks, deletions
+// and renames of releases. The releases of a set applied are notified one by
+// one.
+type Notifier struct {
+	sender   webhook.Sender
+	releases *storage.Storage
+}
+
+// NewNotifier returns a Notifier sending payloads with sender. The release a
+// call was made on is looked up in releases when the response does not hold
+// it.
+func NewNotifier(sender webhook.Sender, releases *storage.Storage) *Notifier {
+	return &Notifier{sender: sender, releases: releases}
+}
+
+// Notify sends the payload of the call of fullMethod with req, which returned
+// resp and err. Dry runs and calls not changing releases are not notified.
+func (n *Notifier) Notify(fullMethod string, req, resp interface{}, err error) {
+	_, rpc := splitMethod(fullMethod)
+	if r, ok := req.(interface{ GetDryRun() bool }); ok && r.GetDryRun() {
+		return
+	}
+	if res, ok := resp.(*services.ApplyReleaseSetResponse); ok {
+		n.notifySet(res)
+		return
+	}
+	event, ok := webhookEvents[rpc]
+	if !ok {
+		return
+	}
+
+	p := &webhook.Payload{
+		Event:   event,
+		Outcome: webhook.Success,
+		Time:    time.Now().UTC(),
+	}
+	if err != nil {
+		p.Outcome = webhook.Failure
+		p.Error = err.Error()
+	}
+
+	var rel *release.Release
+	if res, ok := resp.(interface{ GetRelease() *release.Release }); ok {
+		rel = res.GetRelease()
+	}
+	if r, ok := req.(interface{ GetName() string }); ok && rel == nil {
+		p.Release = r.GetName()
+		if p.Release != "" {
+			rel, _ = n.releases.Last(p.Release)
+		}
+	}
+	if rel != nil {
+		setPayloadRelease(p, rel)
+	}
+	if c, ok := req.(interface{ GetChart() *chart.Chart }); ok && c.GetChart() != nil {
+		p.Chart, p.ChartVersion = chartNameVersion(c.GetChart())
+	}
+	if r, ok := req.(*services.RenameReleaseRequest); ok && r.Name != p.Release {
+		p.RenamedFrom = r.Name
+	}
+
+	n.sender.Send(p)
+}
+
+// notifySet sends the payload of each release of a set installed or upgraded
+// by res. Releases rolled back because the set failed are notified as failed.
+func (n *Notifier) notifySet(res *services.ApplyReleaseSetResponse) {
+	for _, result := range res.Results {
+		if result.Status == services.ReleaseSetResult_SKIPPED {
+			continue
+		}
+		p := &webhook.Payload{
+			Event:   webhook.Upgrade,
+			Outcome: webhook.Success,
+			Time:    time.Now().UTC(),
+			Release: result.Name,
+		}
+		if result.Installed {
+			p.Event = webhook.Install
+		}
+		if result.Status != services.ReleaseSetResult_APPLIED {
+			p.Outcome, p.Error = webhook.Failure, result.Error
+		}
+		if result.Release != nil {
+			setPayloadRelease(p, result.Release)
+		}
+		n.sender.Send(p)
+	}
+}
+
+// setPayloadRelease sets the release fields of p to the ones of rel.
+func setPayloadRelease(p *webhook.Payload, rel *release.Release) {
+	p.Release, p.Revision, p.Namespace = rel.Name, rel.Version, rel.Namespace
+	p.Chart, p.ChartVersion = chartNameVersion(rel.Chart)
+	p.Status = rel.GetInfo().GetStatus().GetCode().String()
+	p.Description = rel.GetInfo().GetDescription()
+}
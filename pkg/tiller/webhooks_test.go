@@ -0,0 +1,105 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller/webhook"
+)
+
+type recordingSender struct {
+	payloads []*webhook.Payload
+}
+
+func (s *recordingSender) Send(p *webhook.Payload) {
+	s.payloads = append(s.payloads, p)
+}
+
+func TestNotifierNotify(t *testing.T) {
+	releases := storage.Init(driver.NewMemory())
+	rel := namedReleaseStub("angry-panda", release.Status_DEPLOYED)
+	releases.Create(rel)
+
+	s := &recordingSender{}
+	n := NewNotifier(s, releases)
+	const prefix = "/hapi.services.tiller.ReleaseService/"
+
+	// a successful upgrade
+	upgraded := upgradeReleaseVersion(rel)
+	upgraded.Info.Description = "Upgrade complete"
+	n.Notify(prefix+"UpdateRelease", &services.UpdateReleaseRequest{Name: "angry-panda"}, &services.UpdateReleaseResponse{Release: upgraded}, nil)
+
+	// a failed rollback, without a response
+	n.Notify(prefix+"RollbackRelease", &services.RollbackReleaseRequest{Name: "angry-panda"}, nil, errors.New("timed out waiting for the condition"))
+
+	// dry runs and calls not changing releases are not notified
+	n.Notify(prefix+"InstallRelease", &services.InstallReleaseRequest{Name: "web", DryRun: true, Chart: &chart.Chart{}}, nil, nil)
+	n.Notify(prefix+"GetReleaseStatus", &services.GetReleaseStatusRequest{Name: "angry-panda"}, nil, nil)
+
+	if len(s.payloads) != 2 {
+		t.Fatalf("Expected 2 payloads, got %d", len(s.payloads))
+	}
+
+	upgrade := s.payloads[0]
+	if upgrade.Event != webhook.Upgrade || upgrade.Outcome != webhook.Success {
+		t.Errorf("Expected a successful upgrade, got %s %s", upgrade.Outcome, upgrade.Event)
+	}
+	if upgrade.Release != "angry-panda" || upgrade.Revision != 2 || upgrade.Chart != "hello" {
+		t.Errorf("Expected the upgraded release to be described, got %+v", upgrade)
+	}
+	if upgrade.Status != "DEPLOYED" || upgrade.Description != "Upgrade complete" {
+		t.Errorf("Expected the status of the upgraded release, got %s: %s", upgrade.Status, upgrade.Description)
+	}
+
+	rollback := s.payloads[1]
+	if rollback.Event != webhook.Rollback || rollback.Outcome != webhook.Failure || rollback.Error != "timed out waiting for the condition" {
+		t.Errorf("Expected a failed rollback, got %+v", rollback)
+	}
+	if rollback.Release != "angry-panda" || rollback.Revision != 1 {
+		t.Errorf("Expected the stored release to be described, got %+v", rollback)
+	}
+}
+
+func TestNotifierNotifySet(t *testing.T) {
+	s := &recordingSender{}
+	n := NewNotifier(s, storage.Init(driver.NewMemory()))
+
+	rel := namedReleaseStub("web", release.Status_DEPLOYED)
+	res := &services.ApplyReleaseSetResponse{Results: []*services.ReleaseSetResult{
+		{Name: "web", Status: services.ReleaseSetResult_ROLLED_BACK, Installed: true, Release: rel, Error: `release "database" failed`},
+		{Name: "database", Status: services.ReleaseSetResult_FAILED, Error: "timed out waiting for the condition"},
+		{Name: "cache", Status: services.ReleaseSetResult_SKIPPED},
+	}}
+	n.Notify("/hapi.services.tiller.ReleaseService/ApplyReleaseSet", &services.ApplyReleaseSetRequest{}, res, nil)
+
+	if len(s.payloads) != 2 {
+		t.Fatalf("Expected a payload by release applied, got %d", len(s.payloads))
+	}
+	if web := s.payloads[0]; web.Event != webhook.Install || web.Outcome != webhook.Failure || web.Chart != "hello" {
+		t.Errorf("Expected a rolled back install, got %+v", web)
+	}
+	if database := s.payloads[1]; database.Event != webhook.Upgrade || database.Release != "database" || database.Error == "" {
+		t.Errorf("Expected a failed upgrade, got %+v", database)
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing // import "k8s.io/helm/pkg/tracing"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFlushInterval is the default interval at which a
+	// CollectorExporter sends the spans it holds.
+	DefaultFlushInterval = 5 * time.Second
+	// DefaultMaxSpans is the default number of spans a CollectorExporter
+	// holds until they are sent. Further spans are dropped.
+	DefaultMaxSpans = 10000
+)
+
+// FileExporter writes spans to a file, as one JSON object per line.
+type FileExporter struct {
+	mu   sync.Mutex
+	file *os.File
+	Log  func(string, ...interface{})
+}
+
+// NewFileExporter returns an exporter appending spans to the named file,
+// which is created if it does not exist.
+func NewFileExporter(filename string) (*FileExporter, error) {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileExporter{file: f, Log: func(_ string, _ ...interface{}) {}}, nil
+}
+
+// Export writes span to the file.
+func (e *FileExporter) Export(span *Span) {
+	b, err := json.Marshal(span)
+	if err != nil {
+		e.Log("failed to encode span %s: %s", span.Name, err)
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, err := e.file.Write(append(b, '\n')); err != nil {
+		e.Log("failed to write span %s: %s", span.Name, err)
+	}
+}
+
+// Flush does nothing, as spans are written as they are exported.
+func (e *FileExporter) Flush() error {
+	return nil
+}
+
+// Close closes the file.
+func (e *FileExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.file.Close()
+}
+
+// CollectorExporter sends spans to a collector in batches. Each batch is
+// POSTed to the URL of the collector as a JSON object holding the spans in
+// its "spans" field.
+type CollectorExporter struct {
+	URL    string
+	Client *http.Client
+	// MaxSpans is the number of spans held until they are sent. Further
+	// spans are dropped.
+	MaxSpans int
+	Log      func(string, ...interface{})
+
+	mu      sync.Mutex
+	spans   []*Span
+	dropped int
+}
+
+// NewCollectorExporter returns an exporter sending spans to the collector at
+// url.
+func NewCollectorExporter(url string) *CollectorExporter {
+	return &CollectorExporter{
+		URL:      url,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		MaxSpans: DefaultMaxSpans,
+		Log:      func(_ string, _ ...interface{}) {},
+	}
+}
+
+// Export holds span until the next flush.
+func (e *CollectorExporter) Export(span *Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.spans) >= e.MaxSpans {
+		e.dropped++
+		return
+	}
+	e.spans = append(e.spans, span)
+}
+
+// Flush sends the spans held to the collector.
+func (e *CollectorExporter) Flush() error {
+	e.mu.Lock()
+	spans, dropped := e.spans, e.dropped
+	e.spans, e.dropped = nil, 0
+	e.mu.Unlock()
+
+	if dropped > 0 {
+		e.Log("dropped %d spans exceeding the limit of %d", dropped, e.MaxSpans)
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(struct {
+		Spans []*Span `json:"spans"`
+	}{spans})
+	if err != nil {
+		return err
+	}
+	resp, err := e.Client.Post(e.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector %s responded with %s", e.URL, resp.Status)
+	}
+	return nil
+}
+
+// Start flushes the spans every interval, or DefaultFlushInterval if interval
+// is 0 or less, until stop is closed. The spans held are flushed once more
+// before returning.
+func (e *CollectorExporter) Start(interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			if err := e.Flush(); err != nil {
+				e.Log("failed to send spans: %s", err)
+			}
+			return
+		}
+		if err := e.Flush(); err != nil {
+			e.Log("failed to send spans: %s", err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*Package tracing records the spans of the work done by Helm and Tiller.
+
+A span is a named and timed piece of work, e.g. an RPC, a hook or a call made
+to Kubernetes. Spans form traces: the helm CLI starts a trace for each command,
+whose context is sent to Tiller in the metadata of the gRPC calls, and the
+spans of Tiller are children of it.
+
+Finished spans are handed to an Exporter, which writes them to a file or sends
+them to a collector. Methods of a nil *Span do nothing, so that code can be
+traced without checking whether tracing is enabled.
+*/
+package tracing // import "k8s.io/helm/pkg/tracing"
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// MetadataKey is the key of the gRPC metadata carrying the context of the
+// span a call is made in, in the W3C traceparent format.
+const MetadataKey = "traceparent"
+
+// SpanContext identifies a span within a trace.
+type SpanContext struct {
+	// TraceID is the hex encoded 16 bytes ID of the trace.
+	TraceID string
+	// SpanID is the hex encoded 8 bytes ID of the span.
+	SpanID string
+}
+
+// IsValid reports whether c identifies a span.
+func (c SpanContext) IsValid() bool {
+	return isHexID(c.TraceID, 16) && isHexID(c.SpanID, 8)
+}
+
+// TraceParent returns c in the W3C traceparent format, e.g.
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.
+func (c SpanContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-01", c.TraceID, c.SpanID)
+}
+
+// ParseTraceParent parses a span context in the W3C traceparent format.
+func ParseTraceParent(s string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", s)
+	}
+	c := SpanContext{TraceID: parts[1], SpanID: parts[2]}
+	if !c.IsValid() {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", s)
+	}
+	return c, nil
+}
+
+func isHexID(id string, size int) bool {
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) != size {
+		return false
+	}
+	for _, c := range b {
+		if c != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func newID(size int) string {
+	b := make([]byte, size)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Exporter exports finished spans.
+type Exporter interface {
+	// Export exports span, which is finished. It must not block.
+	Export(span *Span)
+	// Flush exports the spans not exported yet, if any.
+	Flush() error
+}
+
+// Tracer starts the spans of a service.
+type Tracer struct {
+	// Service is the name of the service doing the traced work, e.g. tiller.
+	Service  string
+	Exporter Exporter
+}
+
+// NewTracer returns a tracer of service exporting spans with exporter.
+func NewTracer(service string, exporter Exporter) *Tracer {
+	return &Tracer{Service: service, Exporter: exporter}
+}
+
+// Start starts a span named name, child of the span identified by parent. A
+// new trace is started if parent is not valid.
+func (t *Tracer) Start(name string, parent SpanContext) *Span {
+	s := &Span{
+		SpanID:  newID(8),
+		Service: t.Service,
+		Name:    name,
+		Start:   time.Now(),
+		tracer:  t,
+	}
+	if parent.IsValid() {
+		s.TraceID, s.ParentID = parent.TraceID, parent.SpanID
+	} else {
+		s.TraceID = newID(16)
+	}
+	return s
+}
+
+// Span is a named and timed piece of work.
+type Span struct {
+	TraceID    string            `json:"traceId"`
+	SpanID     string            `json:"spanId"`
+	ParentID   string            `json:"parentSpanId,omitempty"`
+	Service    string            `json:"service"`
+	Name       string            `json:"name"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Error is the error the work failed with, if any.
+	Error string `json:"error,omitempty"`
+
+	tracer *Tracer
+	mu     sync.Mutex
+}
+
+// Context returns the context of s, or an invalid context if s is nil.
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return SpanContext{TraceID: s.TraceID, SpanID: s.SpanID}
+}
+
+// SetAttribute sets the attribute key of s to value.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Attributes == nil {
+		s.Attributes = map[string]string{}
+	}
+	s.Attributes[key] = value
+}
+
+// Child starts a span named name, child of s. It returns nil if s is nil.
+func (s *Span) Child(name string) *Span {
+	if s == nil {
+		return nil
+	}
+	return s.tracer.Start(name, s.Context())
+}
+
+// Finish ends s, which failed with err, if any, and exports it.
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.End = time.Now()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	s.mu.Unlock()
+	if s.tracer.Exporter != nil {
+		s.tracer.Exporter.Export(s)
+	}
+}
+
+// Record exports a span named name, child of s, for work that already ended.
+// The work started at start and failed with err, if any. attrs are pairs of
+// attribute keys and values.
+func (s *Span) Record(name string, start time.Time, err error, attrs ...string) {
+	child := s.Child(name)
+	if child == nil {
+		return
+	}
+	child.Start = start
+	for i := 0; i+1 < len(attrs); i += 2 {
+		child.SetAttribute(attrs[i], attrs[i+1])
+	}
+	child.Finish(err)
+}
+
+type spanKey struct{}
+
+// ContextWithSpan returns a copy of ctx carrying span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the span carried by ctx, or nil if there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
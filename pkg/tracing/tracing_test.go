@@ -0,0 +1,213 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+type recordingExporter struct {
+	spans []*Span
+}
+
+func (e *recordingExporter) Export(span *Span) { e.spans = append(e.spans, span) }
+func (e *recordingExporter) Flush() error      { return nil }
+
+func TestParseTraceParent(t *testing.T) {
+	c, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || c.SpanID != "00f067aa0ba902b7" {
+		t.Errorf("Unexpected span context %+v", c)
+	}
+	if got := c.TraceParent(); got != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("Unexpected traceparent %s", got)
+	}
+
+	for _, s := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba9-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		if _, err := ParseTraceParent(s); err == nil {
+			t.Errorf("Expected traceparent %q to be invalid", s)
+		}
+	}
+}
+
+func TestSpans(t *testing.T) {
+	e := &recordingExporter{}
+	tracer := NewTracer("tiller", e)
+
+	parent := SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	root := tracer.Start("UpdateRelease", parent)
+	root.SetAttribute("release", "angry-panda")
+
+	ctx := ContextWithSpan(context.Background(), root)
+	child := SpanFromContext(ctx).Child("render")
+	child.Finish(nil)
+	root.Record("storage get", time.Now().Add(-time.Second), errors.New("not found"), "key", "angry-panda.v1")
+	root.Finish(nil)
+
+	if len(e.spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(e.spans))
+	}
+	for _, s := range e.spans {
+		if s.TraceID != parent.TraceID || s.Service != "tiller" {
+			t.Errorf("Expected span %s to be part of the trace of tiller, got %+v", s.Name, s)
+		}
+		if s.End.Before(s.Start) {
+			t.Errorf("Expected span %s to end after it started", s.Name)
+		}
+	}
+	if root.ParentID != parent.SpanID || root.Attributes["release"] != "angry-panda" {
+		t.Errorf("Unexpected root span %+v", root)
+	}
+	if child.ParentID != root.SpanID || child.Name != "render" {
+		t.Errorf("Expected render to be a child of the root span, got %+v", child)
+	}
+	record := e.spans[1]
+	if record.ParentID != root.SpanID || record.Error != "not found" || record.Attributes["key"] != "angry-panda.v1" {
+		t.Errorf("Unexpected recorded span %+v", record)
+	}
+	if record.End.Sub(record.Start) < time.Second {
+		t.Errorf("Expected the recorded span to last a second, got %s", record.End.Sub(record.Start))
+	}
+
+	// a new trace is started without a parent
+	if s := tracer.Start("helm upgrade", SpanContext{}); !s.Context().IsValid() || s.ParentID != "" || s.TraceID == parent.TraceID {
+		t.Errorf("Expected a new trace, got %+v", s)
+	}
+}
+
+func TestNilSpan(t *testing.T) {
+	var s *Span
+	s.SetAttribute("release", "angry-panda")
+	s.Record("storage get", time.Now(), nil)
+	s.Finish(nil)
+	if c := s.Child("render"); c != nil {
+		t.Errorf("Expected the child of a nil span to be nil, got %+v", c)
+	}
+	if s.Context().IsValid() {
+		t.Error("Expected the context of a nil span to be invalid")
+	}
+	if SpanFromContext(context.Background()) != nil {
+		t.Error("Expected no span in an empty context")
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-tracing-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "spans.json")
+	e, err := NewFileExporter(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer := NewTracer("helm", e)
+	root := tracer.Start("helm upgrade", SpanContext{})
+	root.Child("UpdateRelease").Finish(errors.New("timed out waiting for the condition"))
+	root.Finish(nil)
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var spans []*Span
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		s := &Span{}
+		if err := json.Unmarshal(scanner.Bytes(), s); err != nil {
+			t.Fatalf("Failed to decode span %q: %s", scanner.Text(), err)
+		}
+		spans = append(spans, s)
+	}
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "UpdateRelease" || spans[0].ParentID != root.SpanID || spans[0].Error != "timed out waiting for the condition" {
+		t.Errorf("Unexpected span %+v", spans[0])
+	}
+	if spans[1].Name != "helm upgrade" || spans[1].TraceID != root.TraceID {
+		t.Errorf("Unexpected span %+v", spans[1])
+	}
+}
+
+func TestCollectorExporter(t *testing.T) {
+	var batches [][]*Span
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch struct {
+			Spans []*Span `json:"spans"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("Failed to decode batch: %s", err)
+		}
+		batches = append(batches, batch.Spans)
+	}))
+	defer srv.Close()
+
+	e := NewCollectorExporter(srv.URL)
+	e.MaxSpans = 2
+	tracer := NewTracer("tiller", e)
+	for _, name := range []string{"render", "kube Create", "storage update"} {
+		tracer.Start(name, SpanContext{}).Finish(nil)
+	}
+	if err := e.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	// nothing is sent without spans
+	if err := e.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batches) != 1 {
+		t.Fatalf("Expected 1 batch, got %d", len(batches))
+	}
+	if len(batches[0]) != 2 || batches[0][0].Name != "render" || batches[0][1].Name != "kube Create" {
+		t.Errorf("Expected the spans within the limit to be sent, got %+v", batches[0])
+	}
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	tracer.Start("render", SpanContext{}).Finish(nil)
+	if err := e.Flush(); err == nil {
+		t.Error("Expected an error from a failing collector")
+	}
+}